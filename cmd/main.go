@@ -21,10 +21,11 @@ func startServer(e *echo.Echo) {
 	}()
 }
 
-func shutDownServer(e *echo.Echo) {
+func shutDownServer(e *echo.Echo, stopJobs context.CancelFunc) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
+	stopJobs()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := e.Shutdown(ctx); err != nil {
@@ -44,6 +45,9 @@ func main() {
 	srv := server.NewServer(cfg, sqlConn)
 	srv.ConfigureRoutes()
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	srv.StartJobs(jobCtx)
+
 	startServer(srv.Echo)
-	shutDownServer(srv.Echo)
+	shutDownServer(srv.Echo, stopJobs)
 }
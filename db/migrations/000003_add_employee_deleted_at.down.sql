@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS employees_deleted_at_idx;
+
+ALTER TABLE employees
+    DROP COLUMN deleted_at;
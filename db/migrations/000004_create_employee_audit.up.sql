@@ -0,0 +1,18 @@
+-- employee_id has no foreign key so the trail outlives purged employees
+CREATE TABLE employee_audit
+(
+    id          BIGSERIAL PRIMARY KEY,
+    employee_id INTEGER     NOT NULL,
+    action      TEXT        NOT NULL,
+    actor_id    TEXT,
+    actor_name  TEXT,
+    actor_role  TEXT,
+    request_id  TEXT,
+    before      JSONB,
+    after       JSONB,
+    diff        JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX employee_audit_employee_id_idx ON employee_audit (employee_id, created_at DESC);
+CREATE INDEX employee_audit_created_at_idx ON employee_audit (created_at DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS employees_email_key;
@@ -0,0 +1,2 @@
+-- soft deleted employees keep their email reserved so they can be restored
+CREATE UNIQUE INDEX employees_email_key ON employees (LOWER(email));
@@ -0,0 +1,2 @@
+ALTER TABLE employees
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS employees_department_id_idx;
+
+ALTER TABLE employees
+    DROP COLUMN department_id;
+
+DROP TABLE departments;
@@ -0,0 +1,16 @@
+CREATE TABLE departments
+(
+    id          SERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX departments_name_key ON departments (LOWER(name));
+
+-- a department cannot be deleted while employees, deleted ones included, still reference it
+ALTER TABLE employees
+    ADD COLUMN department_id INTEGER CONSTRAINT employees_department_id_fkey REFERENCES departments (id) ON DELETE RESTRICT;
+
+CREATE INDEX employees_department_id_idx ON employees (department_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS employees_manager_id_idx;
+
+ALTER TABLE employees
+    DROP COLUMN manager_id;
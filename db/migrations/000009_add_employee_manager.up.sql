@@ -0,0 +1,4 @@
+ALTER TABLE employees
+    ADD COLUMN manager_id INTEGER REFERENCES employees (id) ON DELETE SET NULL;
+
+CREATE INDEX employees_manager_id_idx ON employees (manager_id);
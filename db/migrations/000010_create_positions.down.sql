@@ -0,0 +1,8 @@
+DROP TABLE employee_positions;
+
+DROP INDEX IF EXISTS employees_position_id_idx;
+
+ALTER TABLE employees
+    DROP COLUMN position_id;
+
+DROP TABLE positions;
@@ -0,0 +1,34 @@
+CREATE TABLE positions
+(
+    id         SERIAL PRIMARY KEY,
+    title      TEXT        NOT NULL,
+    grade      INTEGER     NOT NULL,
+    job_family TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX positions_title_key ON positions (LOWER(title));
+CREATE INDEX positions_job_family_idx ON positions (job_family, grade);
+
+ALTER TABLE employees
+    ADD COLUMN position_id INTEGER CONSTRAINT employees_position_id_fkey REFERENCES positions (id) ON DELETE RESTRICT;
+
+CREATE INDEX employees_position_id_idx ON employees (position_id);
+
+-- one row per position an employee held, the current one has no end_date.
+-- department_id is a snapshot taken at the change, departments keep no history.
+CREATE TABLE employee_positions
+(
+    id             BIGSERIAL PRIMARY KEY,
+    employee_id    INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    position_id    INTEGER     NOT NULL CONSTRAINT employee_positions_position_id_fkey REFERENCES positions (id) ON DELETE RESTRICT,
+    department_id  INTEGER,
+    change         TEXT        NOT NULL,
+    effective_date DATE        NOT NULL,
+    end_date       DATE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX employee_positions_current_key ON employee_positions (employee_id) WHERE end_date IS NULL;
+CREATE INDEX employee_positions_employee_id_idx ON employee_positions (employee_id, effective_date DESC);
@@ -0,0 +1,17 @@
+-- amount is in minor units of currency (cents for USD), effective_to is
+-- inclusive and NULL while the compensation is current.
+CREATE TABLE compensations
+(
+    id             BIGSERIAL PRIMARY KEY,
+    employee_id    INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    amount         BIGINT      NOT NULL CHECK (amount > 0),
+    currency       CHAR(3)     NOT NULL,
+    pay_frequency  TEXT        NOT NULL,
+    effective_from DATE        NOT NULL,
+    effective_to   DATE,
+    created_by     TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX compensations_employee_id_idx ON compensations (employee_id, effective_from DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS employees_status_idx;
+
+ALTER TABLE employees
+    DROP COLUMN termination_reason,
+    DROP COLUMN termination_date,
+    DROP COLUMN status;
@@ -0,0 +1,7 @@
+ALTER TABLE employees
+    ADD COLUMN status             TEXT NOT NULL DEFAULT 'active'
+        CONSTRAINT employees_status_check CHECK (status IN ('probation', 'active', 'on_leave', 'terminated', 'rehired')),
+    ADD COLUMN termination_date   DATE,
+    ADD COLUMN termination_reason TEXT;
+
+CREATE INDEX employees_status_idx ON employees (status);
@@ -0,0 +1,5 @@
+DROP TABLE leave_requests;
+
+DROP TABLE leave_balances;
+
+DROP TABLE leave_types;
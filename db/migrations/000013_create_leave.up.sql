@@ -0,0 +1,44 @@
+-- accrual_days are credited every accrual_period since the hire date, a
+-- balance never accrues past max_balance when it is set.
+CREATE TABLE leave_types
+(
+    id             SERIAL PRIMARY KEY,
+    name           TEXT          NOT NULL,
+    accrual_days   NUMERIC(6, 2) NOT NULL DEFAULT 0 CHECK (accrual_days >= 0),
+    accrual_period TEXT          NOT NULL CHECK (accrual_period IN ('monthly', 'yearly')),
+    max_balance    NUMERIC(7, 2) CHECK (max_balance IS NULL OR max_balance > 0),
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX leave_types_name_key ON leave_types (LOWER(name));
+
+-- accrued_through is the date the balance was last credited up to.
+CREATE TABLE leave_balances
+(
+    employee_id     INTEGER       NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    leave_type_id   INTEGER       NOT NULL REFERENCES leave_types (id) ON DELETE CASCADE,
+    balance         NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    accrued_through DATE          NOT NULL,
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (employee_id, leave_type_id)
+);
+
+-- end_date is inclusive, days counts the working days between both dates.
+CREATE TABLE leave_requests
+(
+    id            BIGSERIAL PRIMARY KEY,
+    employee_id   INTEGER       NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    leave_type_id INTEGER       NOT NULL CONSTRAINT leave_requests_leave_type_id_fkey REFERENCES leave_types (id) ON DELETE RESTRICT,
+    start_date    DATE          NOT NULL,
+    end_date      DATE          NOT NULL,
+    days          NUMERIC(6, 2) NOT NULL CHECK (days > 0),
+    reason        TEXT          NOT NULL DEFAULT '',
+    status        TEXT          NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by    TEXT,
+    decided_at    TIMESTAMPTZ,
+    decision_note TEXT,
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX leave_requests_employee_id_idx ON leave_requests (employee_id, start_date DESC);
@@ -0,0 +1,17 @@
+-- work_date is the day of clock_in in the timezone of the work schedule,
+-- clock_out stays NULL while the employee is clocked in.
+CREATE TABLE attendance
+(
+    id          BIGSERIAL PRIMARY KEY,
+    employee_id INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    work_date   DATE        NOT NULL,
+    clock_in    TIMESTAMPTZ NOT NULL,
+    clock_out   TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (clock_out IS NULL OR clock_out >= clock_in)
+);
+
+-- an employee has at most one open session, a second clock-in fails on it
+CREATE UNIQUE INDEX attendance_open_key ON attendance (employee_id) WHERE clock_out IS NULL;
+
+CREATE INDEX attendance_work_date_idx ON attendance (work_date, employee_id);
@@ -0,0 +1,15 @@
+-- the file itself is kept by the document storage under storage_key
+CREATE TABLE employee_documents
+(
+    id           BIGSERIAL PRIMARY KEY,
+    employee_id  INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    category     TEXT        NOT NULL CHECK (category IN ('contract', 'id', 'certificate', 'other')),
+    file_name    TEXT        NOT NULL,
+    content_type TEXT        NOT NULL,
+    size         BIGINT      NOT NULL CHECK (size > 0),
+    storage_key  TEXT        NOT NULL UNIQUE,
+    uploaded_by  TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX employee_documents_employee_id_idx ON employee_documents (employee_id, created_at DESC);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS employees_national_id_key;
+
+ALTER TABLE employees
+    DROP COLUMN work_location,
+    DROP COLUMN national_id,
+    DROP COLUMN emergency_contacts,
+    DROP COLUMN address,
+    DROP COLUMN date_of_birth,
+    DROP COLUMN phone;
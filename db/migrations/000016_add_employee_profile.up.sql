@@ -0,0 +1,9 @@
+ALTER TABLE employees
+    ADD COLUMN phone              TEXT,
+    ADD COLUMN date_of_birth      DATE,
+    ADD COLUMN address            JSONB,
+    ADD COLUMN emergency_contacts JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN national_id        TEXT,
+    ADD COLUMN work_location      TEXT;
+
+CREATE UNIQUE INDEX employees_national_id_key ON employees (national_id) WHERE national_id IS NOT NULL;
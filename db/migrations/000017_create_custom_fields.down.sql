@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS employees_custom_fields_idx;
+
+ALTER TABLE employees
+    DROP COLUMN custom_fields;
+
+DROP TABLE IF EXISTS custom_fields;
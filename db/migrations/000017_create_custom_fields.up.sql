@@ -0,0 +1,19 @@
+-- name is the key of the value in employees.custom_fields, options lists the
+-- allowed values of an enum field.
+CREATE TABLE custom_fields
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    type       TEXT        NOT NULL CHECK (type IN ('text', 'number', 'boolean', 'date', 'enum')),
+    required   BOOLEAN     NOT NULL DEFAULT FALSE,
+    options    TEXT[]      NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX custom_fields_name_key ON custom_fields (name);
+
+ALTER TABLE employees
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX employees_custom_fields_idx ON employees USING GIN (custom_fields jsonb_path_ops);
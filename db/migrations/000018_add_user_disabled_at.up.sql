@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN disabled_at TIMESTAMPTZ;
+
+-- accounts of employees already deleted or terminated lose access too
+UPDATE users
+SET disabled_at = NOW()
+WHERE employee_id IN (SELECT id FROM employees WHERE deleted_at IS NOT NULL OR status = 'terminated');
+
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL
+  AND user_id IN (SELECT id FROM users WHERE disabled_at IS NOT NULL);
@@ -0,0 +1,125 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Codes are part of the API contract, clients switch on them instead of the message.
+const (
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeValidation   = "validation_failed"
+	CodeForbidden    = "forbidden"
+	CodeUnauthorized = "unauthorized"
+	CodeUnavailable  = "unavailable"
+	CodeInternal     = "internal_error"
+
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+	CodeUnprocessable        = "unprocessable"
+)
+
+var statusByCode = map[string]int{
+	CodeNotFound:     http.StatusNotFound,
+	CodeConflict:     http.StatusConflict,
+	CodeValidation:   http.StatusBadRequest,
+	CodeForbidden:    http.StatusForbidden,
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeUnavailable:  http.StatusServiceUnavailable,
+	CodeInternal:     http.StatusInternalServerError,
+
+	CodePreconditionFailed:   http.StatusPreconditionFailed,
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+	CodeUnprocessable:        http.StatusUnprocessableEntity,
+}
+
+// Error is the domain error shared by the repository, use case and handler
+// layers. Field names the request field the error is about, if any.
+type Error struct {
+	Code    string
+	Message string
+	Field   string
+	Details []FieldError
+	Err     error
+}
+
+// FieldError is one rule a request field failed.
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns the HTTP status the error is rendered with.
+func (e *Error) Status() int {
+	if status, ok := statusByCode[e.Code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+func NotFound(message string) *Error {
+	return &Error{Code: CodeNotFound, Message: message}
+}
+
+func Conflict(field string, message string) *Error {
+	return &Error{Code: CodeConflict, Field: field, Message: message}
+}
+
+func Validation(field string, message string) *Error {
+	return &Error{Code: CodeValidation, Field: field, Message: message}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Code: CodeForbidden, Message: message}
+}
+
+func Unauthorized(message string) *Error {
+	return &Error{Code: CodeUnauthorized, Message: message}
+}
+
+// PreconditionFailed reports a conditional request whose condition no longer holds,
+// e.g. an If-Match on a version that has since changed.
+func PreconditionFailed(message string) *Error {
+	return &Error{Code: CodePreconditionFailed, Message: message}
+}
+
+func PreconditionRequired(message string) *Error {
+	return &Error{Code: CodePreconditionRequired, Message: message}
+}
+
+// Unprocessable reports a well formed request that cannot be honoured as sent,
+// e.g. an idempotency key reused with another body.
+func Unprocessable(field string, message string) *Error {
+	return &Error{Code: CodeUnprocessable, Field: field, Message: message}
+}
+
+// Unavailable wraps an infrastructure failure the client may retry.
+func Unavailable(err error) *Error {
+	return &Error{Code: CodeUnavailable, Message: "service temporarily unavailable", Err: err}
+}
+
+// As returns the domain error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+
+	return nil, false
+}
+
+// IsCode reports whether err carries a domain error with the given code.
+func IsCode(err error, code string) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Code == code
+}
@@ -6,12 +6,56 @@ import (
 	"github.com/spf13/viper"
 )
 
+// PlaceholderPassword is the sample secret of the docs, it is never accepted
+// as the bootstrap admin password.
+const PlaceholderPassword = "change-me-in-production"
+
+// MinSecretLength is the shortest JWT_SECRET and DOCUMENT_URL_SECRET accepted,
+// the HMAC-SHA256 key size.
+const MinSecretLength = 32
+
+// secretKeys are read from the environment as well, so that secrets do not
+// have to be written to .env.
+var secretKeys = []string{"JWT_SECRET", "ADMIN_EMAIL", "ADMIN_PASSWORD", "DOCUMENT_URL_SECRET"}
+
 type Config struct {
 	DBHost     string `mapstructure:"DB_HOST" default:"localhost"`
 	DBName     string `mapstructure:"DB_NAME" default:"postgres"`
 	DBPort     string `mapstructure:"DB_PORT" default:"5432"`
 	DBPassword string `mapstructure:"DB_PASSWORD" default:"postgres"`
 	DBUser     string `mapstructure:"DB_USER" default:"postgres"`
+	JWTSecret  string `mapstructure:"JWT_SECRET"`
+
+	RefreshTokenTTLHours int    `mapstructure:"REFRESH_TOKEN_TTL_HOURS" default:"720"`
+	AdminEmail           string `mapstructure:"ADMIN_EMAIL"`
+	AdminPassword        string `mapstructure:"ADMIN_PASSWORD"`
+
+	PasswordLength     int    `mapstructure:"PASSWORD_LENGTH" default:"16"`
+	PasswordPassphrase bool   `mapstructure:"PASSWORD_PASSPHRASE" default:"false"`
+	PasswordWords      int    `mapstructure:"PASSWORD_WORDS" default:"4"`
+	PasswordSeparator  string `mapstructure:"PASSWORD_SEPARATOR" default:"-"`
+
+	PurgeRetentionDays int `mapstructure:"PURGE_RETENTION_DAYS" default:"30"`
+	PurgeIntervalHours int `mapstructure:"PURGE_INTERVAL_HOURS" default:"24"`
+
+	IdempotencyTTLHours int `mapstructure:"IDEMPOTENCY_TTL_HOURS" default:"24"`
+
+	WorkStart        string `mapstructure:"WORK_START" default:"09:00"`
+	WorkDays         string `mapstructure:"WORK_DAYS" default:"mon,tue,wed,thu,fri"`
+	WorkGraceMinutes int    `mapstructure:"WORK_GRACE_MINUTES" default:"0"`
+	WorkTimezone     string `mapstructure:"WORK_TIMEZONE" default:"UTC"`
+
+	StorageDriver string `mapstructure:"STORAGE_DRIVER" default:"local"`
+	StorageDir    string `mapstructure:"STORAGE_DIR" default:"./storage"`
+	S3Endpoint    string `mapstructure:"S3_ENDPOINT"`
+	S3Region      string `mapstructure:"S3_REGION" default:"us-east-1"`
+	S3Bucket      string `mapstructure:"S3_BUCKET"`
+	S3AccessKey   string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey   string `mapstructure:"S3_SECRET_KEY"`
+	S3PathStyle   bool   `mapstructure:"S3_PATH_STYLE" default:"false"`
+
+	DocumentURLSecret     string `mapstructure:"DOCUMENT_URL_SECRET"`
+	DocumentURLTTLMinutes int    `mapstructure:"DOCUMENT_URL_TTL_MINUTES" default:"15"`
 }
 
 func NewConfig() *Config {
@@ -22,6 +66,9 @@ func NewConfig() *Config {
 	viper.SetConfigFile(".env")
 	viper.SetConfigType("env")
 	err := viper.ReadInConfig()
+	for _, key := range secretKeys {
+		_ = viper.BindEnv(key)
+	}
 
 	if err != nil {
 		fmt.Println(err)
@@ -32,5 +79,21 @@ func NewConfig() *Config {
 		panic(err)
 	}
 
+	if cfg.JWTSecret == "" || cfg.JWTSecret == PlaceholderPassword || len(cfg.JWTSecret) < MinSecretLength {
+		log.Fatalf("JWT_SECRET must be set to a random secret of at least %d bytes", MinSecretLength)
+	}
+
+	if cfg.DocumentURLSecret == "" || cfg.DocumentURLSecret == PlaceholderPassword || len(cfg.DocumentURLSecret) < MinSecretLength {
+		log.Fatalf("DOCUMENT_URL_SECRET must be set to a random secret of at least %d bytes", MinSecretLength)
+	}
+
+	if cfg.DocumentURLSecret == cfg.JWTSecret {
+		log.Fatal("DOCUMENT_URL_SECRET must differ from JWT_SECRET")
+	}
+
+	if cfg.AdminEmail != "" && (cfg.AdminPassword == "" || cfg.AdminPassword == PlaceholderPassword) {
+		log.Fatal("ADMIN_PASSWORD must be set to a real password when ADMIN_EMAIL is set")
+	}
+
 	return cfg
 }
@@ -3,3 +3,13 @@ package constant
 const MsgInvalidToken = "invalid access token"
 const MsgParseErr = "could not parse claims"
 const MsgTokenExpired = "token expired"
+const MsgForbidden = "you are not allowed to access this resource"
+
+const ContextKeyClaims = "claims"
+
+const (
+	RoleAdmin   = "admin"
+	RoleHR      = "hr"
+	RoleManager = "manager"
+	RoleViewer  = "viewer"
+)
@@ -0,0 +1,163 @@
+package attendance
+
+import (
+	"employee/internal/policy"
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/attendance"
+	"employee/internal/usecase/employee"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.attendance")
+)
+
+type Handler struct {
+	uc         attendance.UseCaseAttendance
+	employeeUC employee.UseCaseEmployee
+}
+
+func NewAttendanceHandler(attendanceUC attendance.UseCaseAttendance, employeeUC employee.UseCaseEmployee) *Handler {
+	return &Handler{uc: attendanceUC, employeeUC: employeeUC}
+}
+
+func (h *Handler) ClockIn(c echo.Context) error {
+	hLog := logger.WithField("handler", "ClockIn")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.employeeUC); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.ClockIn(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.ClockIn got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) ClockOut(c echo.Context) error {
+	hLog := logger.WithField("handler", "ClockOut")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.employeeUC); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.ClockOut(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.ClockOut got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetDailyAttendance(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetDailyAttendance")
+
+	ctx := c.Request().Context()
+
+	employeeID, payload, err := h.bindRange(c)
+	if err != nil {
+		hLog.Errorf("error when bind range got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetDailyAttendance(ctx, employeeID, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetDailyAttendance got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetWeeklyAttendance(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetWeeklyAttendance")
+
+	ctx := c.Request().Context()
+
+	employeeID, payload, err := h.bindRange(c)
+	if err != nil {
+		hLog.Errorf("error when bind range got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetWeeklyAttendance(ctx, employeeID, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetWeeklyAttendance got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetReport(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetReport")
+
+	payload := new(transport.AttendanceReportReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetReport(c.Request().Context(), payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetReport got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+// bindRange reads the employee of the path, checked against the scope of
+// the caller, and the date range of the query.
+func (h *Handler) bindRange(c echo.Context) (int, *transport.AttendanceRangeReq, error) {
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		return 0, nil, err
+	}
+
+	if err := policy.CheckEmployee(c.Request().Context(), params.EmployeeID, h.employeeUC); err != nil {
+		return 0, nil, err
+	}
+
+	payload := new(transport.AttendanceRangeReq)
+
+	if err := c.Bind(payload); err != nil {
+		return 0, nil, err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		return 0, nil, err
+	}
+
+	return params.EmployeeID, payload, nil
+}
@@ -0,0 +1,219 @@
+package attendance
+
+import (
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
+	aRepo "employee/internal/repository/attendance"
+	"employee/internal/response"
+	"employee/internal/transport"
+	attendanceUCMock "employee/internal/usecase/attendance/mock"
+	employeeUCMock "employee/internal/usecase/employee/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockIn(t *testing.T) {
+	clockIn := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "forbidden when a viewer clocks in someone else",
+			employeeID: "4",
+			buildStub:  func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:       "conflict when already clocked in",
+			employeeID: "3",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock) {
+				attendanceUCMock.On("ClockIn", mock.Anything, 3).Return((*transport.AttendanceRes)(nil), aRepo.ErrAlreadyClockedIn)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"clock_in"`)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: "3",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock) {
+				attendanceUCMock.On("ClockIn", mock.Anything, 3).
+					Return(&transport.AttendanceRes{ID: 7, EmployeeID: 3, WorkDate: "2024-06-03", ClockIn: clockIn}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"clock_out":null`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleViewer, EmployeeID: 3})
+			req = req.WithContext(policy.ContextWithScope(ctx, policy.ScopeSelf))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			attendanceUC := new(attendanceUCMock.AttendanceUseCaseMock)
+			tc.buildStub(attendanceUC)
+
+			h := NewAttendanceHandler(attendanceUC, new(employeeUCMock.EmployeeUseCaseMock))
+			if err := h.ClockIn(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetDailyAttendance(t *testing.T) {
+	managerID := 2
+	otherManagerID := 5
+
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "forbidden when employee does not report to the manager",
+			query: "from=2024-06-03&to=2024-06-09",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 4, false).Return(&transport.EmployeeRes{ID: 4, ManagerID: &otherManagerID}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:  "failed when from is missing",
+			query: "to=2024-06-09",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 4, false).Return(&transport.EmployeeRes{ID: 4, ManagerID: &managerID}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"from"`)
+			},
+		},
+		{
+			name:  "success for a direct report",
+			query: "from=2024-06-03&to=2024-06-09",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 4, false).Return(&transport.EmployeeRes{ID: 4, ManagerID: &managerID}, nil)
+				attendanceUCMock.On("GetDailyAttendance", mock.Anything, 4, &transport.AttendanceRangeReq{From: "2024-06-03", To: "2024-06-09"}).
+					Return(&transport.ListAttendanceDays{EmployeeID: 4, Days: []*transport.AttendanceDayRes{
+						{Date: "2024-06-03", Absent: true},
+					}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"absent":true`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/employees/4/attendance/daily?"+tc.query, nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: managerID})
+			req = req.WithContext(policy.ContextWithScope(ctx, policy.ScopeReports))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("4")
+
+			attendanceUC := new(attendanceUCMock.AttendanceUseCaseMock)
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(attendanceUC, employeeUC)
+
+			h := NewAttendanceHandler(attendanceUC, employeeUC)
+			if err := h.GetDailyAttendance(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when to is not a date",
+			query:     "from=2024-06-03&to=06/09/2024",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"to"`)
+			},
+		},
+		{
+			name:  "success",
+			query: "from=2024-06-03&to=2024-06-09&department_id=2",
+			buildStub: func(attendanceUCMock *attendanceUCMock.AttendanceUseCaseMock) {
+				attendanceUCMock.On("GetReport", mock.Anything, &transport.AttendanceReportReq{From: "2024-06-03", To: "2024-06-09", DepartmentID: 2}).
+					Return(&transport.AttendanceReport{From: "2024-06-03", To: "2024-06-09", Employees: []*transport.AttendanceSummaryRes{
+						{EmployeeID: 3, WorkedHours: 38.25, DaysPresent: 4, LateDays: 1, AbsentDays: 1},
+					}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"worked_hours":38.25`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/attendance/report?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			attendanceUC := new(attendanceUCMock.AttendanceUseCaseMock)
+			tc.buildStub(attendanceUC)
+
+			h := NewAttendanceHandler(attendanceUC, new(employeeUCMock.EmployeeUseCaseMock))
+			if err := h.GetReport(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
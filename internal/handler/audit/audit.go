@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/audit"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.audit")
+)
+
+type Handler struct {
+	uc audit.UseCaseAudit
+}
+
+func NewAuditHandler(auditUC audit.UseCaseAudit) *Handler {
+	return &Handler{uc: auditUC}
+}
+
+func (h *Handler) GetAudits(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetAudits")
+
+	return h.listAudits(c, hLog, 0)
+}
+
+// GetEmployeeAudits lists the audit trail of one employee, entries are kept
+// after the employee is purged so the employee is not looked up.
+func (h *Handler) GetEmployeeAudits(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetEmployeeAudits")
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	return h.listAudits(c, hLog, params.EmployeeID)
+}
+
+func (h *Handler) listAudits(c echo.Context, hLog *log.Entry, employeeID int) error {
+	ctx := c.Request().Context()
+
+	payload := new(transport.ListAuditReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetAudits(ctx, employeeID, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetAudits got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
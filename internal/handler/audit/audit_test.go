@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"database/sql"
+	"employee/internal/response"
+	"employee/internal/transport"
+	auditUCMock "employee/internal/usecase/audit/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAudits(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(auditUCMock *auditUCMock.AuditUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			query:     "?action=unknown",
+			buildStub: func(auditUCMock *auditUCMock.AuditUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:      "failed when date is invalid",
+			query:     "?from=01-05-2023",
+			buildStub: func(auditUCMock *auditUCMock.AuditUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when get audits",
+			query: "",
+			buildStub: func(auditUCMock *auditUCMock.AuditUseCaseMock) {
+				auditUCMock.On("GetAudits", mock.Anything, 0, mock.Anything).Return((*transport.ListAudits)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:  "success get audits",
+			query: "?actor_id=2&action=update&from=2023-05-01&to=2023-05-31",
+			buildStub: func(auditUCMock *auditUCMock.AuditUseCaseMock) {
+				auditUCMock.On("GetAudits", mock.Anything, 0, &transport.ListAuditReq{
+					ActorID: "2",
+					Action:  "update",
+					From:    "2023-05-01",
+					To:      "2023-05-31",
+				}).Return(&transport.ListAudits{Audits: []*transport.AuditRes{{ID: 1}}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/audit"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			auditUC := new(auditUCMock.AuditUseCaseMock)
+			tc.buildStub(auditUC)
+
+			h := NewAuditHandler(auditUC)
+			if err := h.GetAudits(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetEmployeeAudits(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(auditUCMock *auditUCMock.AuditUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is invalid",
+			employeeID: "abc",
+			buildStub:  func(auditUCMock *auditUCMock.AuditUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "success get employee audits",
+			employeeID: "1",
+			buildStub: func(auditUCMock *auditUCMock.AuditUseCaseMock) {
+				auditUCMock.On("GetAudits", mock.Anything, 1, mock.Anything).Return(&transport.ListAudits{}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/employees/:employee_id/audit")
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			auditUC := new(auditUCMock.AuditUseCaseMock)
+			tc.buildStub(auditUC)
+
+			h := NewAuditHandler(auditUC)
+			if err := h.GetEmployeeAudits(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
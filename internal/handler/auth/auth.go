@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"employee/internal/config"
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/auth"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.auth")
+)
+
+type Handler struct {
+	uc  auth.UseCaseAuth
+	cfg config.Config
+}
+
+func NewAuthHandler(authUC auth.UseCaseAuth, cfg config.Config) *Handler {
+	return &Handler{uc: authUC, cfg: cfg}
+}
+
+func (h *Handler) Login(c echo.Context) error {
+	hLog := logger.WithField("handler", "Login")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.LoginReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.Login(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call uc.Login got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) Refresh(c echo.Context) error {
+	hLog := logger.WithField("handler", "Refresh")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.RefreshTokenReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.Refresh(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call uc.Refresh got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) Logout(c echo.Context) error {
+	hLog := logger.WithField("handler", "Logout")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.RefreshTokenReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	if err := h.uc.Logout(ctx, payload); err != nil {
+		hLog.Errorf("error when call uc.Logout got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
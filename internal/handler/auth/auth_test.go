@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"database/sql"
+	"employee/internal/config"
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/auth"
+	authUCMock "employee/internal/usecase/auth/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogin(t *testing.T) {
+
+	completePayload := `{"email":"test@mail.com","password":"secret"}`
+	incompletePayload := `{"email":"test@mail.com"}`
+
+	testCases := []struct {
+		name        string
+		payload     string
+		buildStub   func(authUCMock *authUCMock.AuthUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when marshall json",
+			payload:   `{invalid json}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:      "failed when doing validation",
+			payload:   incompletePayload,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:    "failed when credentials are wrong",
+			payload: completePayload,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Login", mock.Anything, mock.Anything).Return((*transport.TokenRes)(nil), auth.ErrInvalidCredentials)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+			},
+		},
+		{
+			name:    "failed when login",
+			payload: completePayload,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Login", mock.Anything, mock.Anything).Return((*transport.TokenRes)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:    "success login",
+			payload: completePayload,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Login", mock.Anything, mock.Anything).Return(&transport.TokenRes{AccessToken: "token"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), "token")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			authUC := new(authUCMock.AuthUseCaseMock)
+			tc.buildStub(authUC)
+
+			h := NewAuthHandler(authUC, config.Config{})
+			if err := h.Login(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		payload     string
+		buildStub   func(authUCMock *authUCMock.AuthUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			payload:   `{}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:    "failed when refresh token is invalid",
+			payload: `{"refresh_token":"token"}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Refresh", mock.Anything, mock.Anything).Return((*transport.TokenRes)(nil), auth.ErrInvalidRefreshToken)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+			},
+		},
+		{
+			name:    "success refresh",
+			payload: `{"refresh_token":"token"}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Refresh", mock.Anything, mock.Anything).Return(&transport.TokenRes{}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			authUC := new(authUCMock.AuthUseCaseMock)
+			tc.buildStub(authUC)
+
+			h := NewAuthHandler(authUC, config.Config{})
+			if err := h.Refresh(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		payload     string
+		buildStub   func(authUCMock *authUCMock.AuthUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "failed when logout",
+			payload: `{"refresh_token":"token"}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Logout", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:    "success logout",
+			payload: `{"refresh_token":"token"}`,
+			buildStub: func(authUCMock *authUCMock.AuthUseCaseMock) {
+				authUCMock.On("Logout", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			authUC := new(authUCMock.AuthUseCaseMock)
+			tc.buildStub(authUC)
+
+			h := NewAuthHandler(authUC, config.Config{})
+			if err := h.Logout(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package compensation
+
+import (
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/compensation"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.compensation")
+)
+
+type Handler struct {
+	uc compensation.UseCaseCompensation
+}
+
+func NewCompensationHandler(compensationUC compensation.UseCaseCompensation) *Handler {
+	return &Handler{uc: compensationUC}
+}
+
+func (h *Handler) CreateCompensation(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreateCompensation")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.CreateCompensationReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreateCompensation(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreateCompensation got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetCompensations(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetCompensations")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetCompensations(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetCompensations got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
@@ -0,0 +1,162 @@
+package compensation
+
+import (
+	"employee/internal/apperror"
+	"employee/internal/response"
+	"employee/internal/transport"
+	compensationUCMock "employee/internal/usecase/compensation/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateCompensation(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(compensationUCMock *compensationUCMock.CompensationUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when currency is unknown",
+			body:      `{"amount":8500000,"currency":"XYZ","pay_frequency":"annual","effective_from":"2024-01-01"}`,
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"currency"`)
+			},
+		},
+		{
+			name:      "failed when pay frequency is unknown",
+			body:      `{"amount":8500000,"currency":"USD","pay_frequency":"daily","effective_from":"2024-01-01"}`,
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "failed when ranges overlap",
+			body: `{"amount":8500000,"currency":"USD","pay_frequency":"annual","effective_from":"2024-01-01"}`,
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {
+				compensationUCMock.On("CreateCompensation", mock.Anything, mock.Anything).
+					Return((*transport.CompensationRes)(nil), apperror.Conflict("effective_from", "compensation overlaps an existing compensation"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name: "success",
+			body: `{"amount":8500000,"currency":"USD","pay_frequency":"annual","effective_from":"2024-01-01"}`,
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {
+				compensationUCMock.On("CreateCompensation", mock.Anything, &transport.CreateCompensationReq{
+					EmployeeID:    3,
+					Amount:        8500000,
+					Currency:      "USD",
+					PayFrequency:  "annual",
+					EffectiveFrom: "2024-01-01",
+				}).Return(&transport.CompensationRes{ID: 1, Amount: 8500000, Currency: "USD"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"amount":8500000`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			compensationUC := new(compensationUCMock.CompensationUseCaseMock)
+			tc.buildStub(compensationUC)
+
+			h := NewCompensationHandler(compensationUC)
+			if err := h.CreateCompensation(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetCompensations(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(compensationUCMock *compensationUCMock.CompensationUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when employee not found",
+			employeeID: "3",
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {
+				compensationUCMock.On("GetCompensations", mock.Anything, 3).Return((*transport.ListCompensations)(nil), apperror.NotFound("employee not found"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: "3",
+			buildStub: func(compensationUCMock *compensationUCMock.CompensationUseCaseMock) {
+				compensationUCMock.On("GetCompensations", mock.Anything, 3).Return(&transport.ListCompensations{Compensations: []*transport.CompensationRes{
+					{ID: 1, Amount: 8500000, Currency: "USD", PayFrequency: "annual", EffectiveFrom: "2024-01-01"},
+				}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"effective_to":null`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			compensationUC := new(compensationUCMock.CompensationUseCaseMock)
+			tc.buildStub(compensationUC)
+
+			h := NewCompensationHandler(compensationUC)
+			if err := h.GetCompensations(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
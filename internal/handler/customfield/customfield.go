@@ -0,0 +1,134 @@
+package customfield
+
+import (
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/customfield"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.customfield")
+)
+
+type Handler struct {
+	uc customfield.UseCaseCustomField
+}
+
+func NewCustomFieldHandler(customFieldUC customfield.UseCaseCustomField) *Handler {
+	return &Handler{uc: customFieldUC}
+}
+
+func (h *Handler) CreateCustomField(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreateCustomField")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.CreateCustomFieldReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreateCustomField(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreateCustomField got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetCustomFields(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetCustomFields")
+
+	ctx := c.Request().Context()
+
+	res, err := h.uc.GetCustomFields(ctx)
+	if err != nil {
+		hLog.Errorf("error when call u.GetCustomFields got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetCustomFieldByID(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetCustomFieldByID")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.CustomFieldIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetCustomFieldByID(ctx, params.CustomFieldID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetCustomFieldByID got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) UpdateCustomField(c echo.Context) error {
+	hLog := logger.WithField("handler", "UpdateCustomField")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.CustomFieldIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.UpdateCustomFieldReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.ID = params.CustomFieldID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.UpdateCustomField(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.UpdateCustomField got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) DeleteCustomField(c echo.Context) error {
+	hLog := logger.WithField("handler", "DeleteCustomField")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.CustomFieldIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := h.uc.DeleteCustomField(ctx, params.CustomFieldID); err != nil {
+		hLog.Errorf("error when call uc.DeleteCustomField got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
@@ -0,0 +1,265 @@
+package customfield
+
+import (
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/response"
+	"employee/internal/transport"
+	customFieldUCMock "employee/internal/usecase/customfield/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateCustomField(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when name is not an identifier",
+			body:      `{"name":"Cost Center","type":"text"}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"rule":"identifier"`)
+			},
+		},
+		{
+			name:      "failed when type is unknown",
+			body:      `{"name":"cost_center","type":"json"}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:      "failed when options repeat",
+			body:      `{"name":"shirt_size","type":"enum","options":["S","S"]}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "success create custom field",
+			body: `{"name":"shirt_size","type":"enum","required":true,"options":["S","M"]}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("CreateCustomField", mock.Anything, &transport.CreateCustomFieldReq{Name: "shirt_size", Type: "enum", Required: true, Options: []string{"S", "M"}}).
+					Return(&transport.CustomFieldRes{ID: 1, Name: "shirt_size"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/custom-fields", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			customFieldUC := new(customFieldUCMock.CustomFieldUseCaseMock)
+			tc.buildStub(customFieldUC)
+
+			h := NewCustomFieldHandler(customFieldUC)
+			if err := h.CreateCustomField(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetCustomFields(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name: "failed when get custom fields",
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("GetCustomFields", mock.Anything).Return((*transport.ListCustomFields)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name: "success get custom fields",
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("GetCustomFields", mock.Anything).
+					Return(&transport.ListCustomFields{CustomFields: []*transport.CustomFieldRes{{ID: 1, Name: "cost_center"}}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"name":"cost_center"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/custom-fields", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			customFieldUC := new(customFieldUCMock.CustomFieldUseCaseMock)
+			tc.buildStub(customFieldUC)
+
+			h := NewCustomFieldHandler(customFieldUC)
+			if err := h.GetCustomFields(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestUpdateCustomField(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		customFieldID string
+		body          string
+		buildStub     func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock)
+		checkReturn   func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "failed when custom field id is invalid",
+			customFieldID: "abc",
+			body:          `{"required":true}`,
+			buildStub:     func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:          "failed when a removed option is held",
+			customFieldID: "1",
+			body:          `{"options":["S"]}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("UpdateCustomField", mock.Anything, mock.Anything).
+					Return((*transport.CustomFieldRes)(nil), apperror.Conflict("options", "a removed option is still held by employees"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name:          "success update custom field",
+			customFieldID: "1",
+			body:          `{"required":true,"options":["S","M"]}`,
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("UpdateCustomField", mock.Anything, &transport.UpdateCustomFieldReq{ID: 1, Required: true, Options: []string{"S", "M"}}).
+					Return(&transport.CustomFieldRes{ID: 1, Required: true}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/custom-fields/:custom_field_id")
+			c.SetParamNames("custom_field_id")
+			c.SetParamValues(tc.customFieldID)
+
+			customFieldUC := new(customFieldUCMock.CustomFieldUseCaseMock)
+			tc.buildStub(customFieldUC)
+
+			h := NewCustomFieldHandler(customFieldUC)
+			if err := h.UpdateCustomField(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestDeleteCustomField(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		customFieldID string
+		buildStub     func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock)
+		checkReturn   func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "failed when custom field not found",
+			customFieldID: "1",
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("DeleteCustomField", mock.Anything, 1).Return(apperror.NotFound("custom field not found"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:          "success delete custom field",
+			customFieldID: "1",
+			buildStub: func(customFieldUCMock *customFieldUCMock.CustomFieldUseCaseMock) {
+				customFieldUCMock.On("DeleteCustomField", mock.Anything, 1).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/custom-fields/:custom_field_id")
+			c.SetParamNames("custom_field_id")
+			c.SetParamValues(tc.customFieldID)
+
+			customFieldUC := new(customFieldUCMock.CustomFieldUseCaseMock)
+			tc.buildStub(customFieldUC)
+
+			h := NewCustomFieldHandler(customFieldUC)
+			if err := h.DeleteCustomField(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
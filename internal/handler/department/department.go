@@ -0,0 +1,146 @@
+package department
+
+import (
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/department"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.department")
+)
+
+type Handler struct {
+	uc department.UseCaseDepartment
+}
+
+func NewDepartmentHandler(departmentUC department.UseCaseDepartment) *Handler {
+	return &Handler{uc: departmentUC}
+}
+
+func (h *Handler) CreateDepartment(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreateDepartment")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.CreateDepartmentReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreateDepartment(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreateDepartment got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetDepartments(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetDepartments")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.ListDepartmentsReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetDepartments(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetDepartments got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetDepartmentByID(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetDepartmentByID")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.DepartmentIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetDepartmentByID(ctx, params.DepartmentID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetDepartmentByID got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) UpdateDepartment(c echo.Context) error {
+	hLog := logger.WithField("handler", "UpdateDepartment")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.DepartmentIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.UpdateDepartmentReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.ID = params.DepartmentID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.UpdateDepartment(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.UpdateDepartment got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) DeleteDepartment(c echo.Context) error {
+	hLog := logger.WithField("handler", "DeleteDepartment")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.DepartmentIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := h.uc.DeleteDepartment(ctx, params.DepartmentID); err != nil {
+		hLog.Errorf("error when call uc.DeleteDepartment got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
@@ -0,0 +1,323 @@
+package department
+
+import (
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/response"
+	"employee/internal/transport"
+	departmentUCMock "employee/internal/usecase/department/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateDepartment(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			body:      `{"description":"no name"}`,
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "failed when name is taken",
+			body: `{"name":"Engineering"}`,
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("CreateDepartment", mock.Anything, mock.Anything).
+					Return((*transport.DepartmentRes)(nil), apperror.Conflict("name", "name already exists"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name: "success create department",
+			body: `{"name":"Engineering","description":"builds things"}`,
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("CreateDepartment", mock.Anything, &transport.CreateDepartmentReq{Name: "Engineering", Description: "builds things"}).
+					Return(&transport.DepartmentRes{ID: 1, Name: "Engineering"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/departments", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			departmentUC := new(departmentUCMock.DepartmentUseCaseMock)
+			tc.buildStub(departmentUC)
+
+			h := NewDepartmentHandler(departmentUC)
+			if err := h.CreateDepartment(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetDepartments(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			query:     "?limit=1000",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when get departments",
+			query: "",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("GetDepartments", mock.Anything, mock.Anything).Return((*transport.ListDepartments)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:  "success get departments",
+			query: "?name=eng&limit=10",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("GetDepartments", mock.Anything, &transport.ListDepartmentsReq{Name: "eng", Limit: 10}).
+					Return(&transport.ListDepartments{Departments: []*transport.DepartmentRes{{ID: 1}}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/departments"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			departmentUC := new(departmentUCMock.DepartmentUseCaseMock)
+			tc.buildStub(departmentUC)
+
+			h := NewDepartmentHandler(departmentUC)
+			if err := h.GetDepartments(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetDepartmentByID(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		departmentID string
+		buildStub    func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock)
+		checkReturn  func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "failed when department id is invalid",
+			departmentID: "abc",
+			buildStub:    func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:         "failed when department not found",
+			departmentID: "1",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("GetDepartmentByID", mock.Anything, 1).Return((*transport.DepartmentRes)(nil), apperror.NotFound("department not found"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:         "success get department",
+			departmentID: "1",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("GetDepartmentByID", mock.Anything, 1).Return(&transport.DepartmentRes{ID: 1, Name: "Engineering"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"name":"Engineering"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/departments/:department_id")
+			c.SetParamNames("department_id")
+			c.SetParamValues(tc.departmentID)
+
+			departmentUC := new(departmentUCMock.DepartmentUseCaseMock)
+			tc.buildStub(departmentUC)
+
+			h := NewDepartmentHandler(departmentUC)
+			if err := h.GetDepartmentByID(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestUpdateDepartment(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		departmentID string
+		body         string
+		buildStub    func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock)
+		checkReturn  func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "failed when doing validation",
+			departmentID: "1",
+			body:         `{"name":""}`,
+			buildStub:    func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:         "success update department",
+			departmentID: "1",
+			body:         `{"name":"Platform"}`,
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("UpdateDepartment", mock.Anything, &transport.UpdateDepartmentReq{ID: 1, Name: "Platform"}).
+					Return(&transport.DepartmentRes{ID: 1, Name: "Platform"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/departments/:department_id")
+			c.SetParamNames("department_id")
+			c.SetParamValues(tc.departmentID)
+
+			departmentUC := new(departmentUCMock.DepartmentUseCaseMock)
+			tc.buildStub(departmentUC)
+
+			h := NewDepartmentHandler(departmentUC)
+			if err := h.UpdateDepartment(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestDeleteDepartment(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		departmentID string
+		buildStub    func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock)
+		checkReturn  func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "failed when department still has members",
+			departmentID: "1",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("DeleteDepartment", mock.Anything, 1).Return(apperror.Conflict("", "department still has members"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"conflict"`)
+			},
+		},
+		{
+			name:         "success delete department",
+			departmentID: "1",
+			buildStub: func(departmentUCMock *departmentUCMock.DepartmentUseCaseMock) {
+				departmentUCMock.On("DeleteDepartment", mock.Anything, 1).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/departments/:department_id")
+			c.SetParamNames("department_id")
+			c.SetParamValues(tc.departmentID)
+
+			departmentUC := new(departmentUCMock.DepartmentUseCaseMock)
+			tc.buildStub(departmentUC)
+
+			h := NewDepartmentHandler(departmentUC)
+			if err := h.DeleteDepartment(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package document
+
+import (
+	"employee/internal/apperror"
+	"employee/internal/policy"
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/document"
+	"errors"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+var (
+	logger = log.WithField("handler", "handler.document")
+)
+
+type Handler struct {
+	uc document.UseCaseDocument
+}
+
+func NewDocumentHandler(documentUC document.UseCaseDocument) *Handler {
+	return &Handler{uc: documentUC}
+}
+
+// UploadDocument reads the multipart field file with the form field category.
+// The media type is sniffed from the content, the one sent is ignored.
+func (h *Handler) UploadDocument(c echo.Context) error {
+	hLog := logger.WithField("handler", "UploadDocument")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, transport.MaxDocumentRequestBytes)
+
+	payload := new(transport.UploadDocumentReq)
+
+	err := c.Bind(payload)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		hLog.Errorf("upload is larger than %d bytes", tooLarge.Limit)
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+	if err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		hLog.Errorf("error when read file got %s", err.Error())
+		return apperror.Validation("file", "file is required")
+	}
+
+	if fileHeader.Size > transport.MaxDocumentBytes {
+		hLog.Errorf("document is larger than %d bytes", transport.MaxDocumentBytes)
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+
+	if fileHeader.Size == 0 {
+		return apperror.Validation("file", "file is empty")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		hLog.Errorf("error when open file got %s", err.Error())
+		return err
+	}
+	defer file.Close()
+
+	payload.ContentType, err = transport.SniffDocumentType(file)
+	if err != nil {
+		hLog.Errorf("error when sniff document type got %s", err)
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+	payload.FileName = transport.DocumentFileName(fileHeader.Filename)
+	payload.Size = fileHeader.Size
+	payload.Content = file
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.UploadDocument(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.UploadDocument got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetDocuments(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetDocuments")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, nil); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetDocuments(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetDocuments got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetDocument(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetDocument")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.DocumentIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, nil); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetDocument(ctx, params.EmployeeID, params.DocumentID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetDocument got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) DeleteDocument(c echo.Context) error {
+	hLog := logger.WithField("handler", "DeleteDocument")
+
+	params := new(transport.DocumentIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := h.uc.DeleteDocument(c.Request().Context(), params.EmployeeID, params.DocumentID); err != nil {
+		hLog.Errorf("error when call u.DeleteDocument got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// DownloadDocument serves the file of a signed download link, the link is
+// the credential so the route takes no token.
+func (h *Handler) DownloadDocument(c echo.Context) error {
+	hLog := logger.WithField("handler", "DownloadDocument")
+
+	payload := new(transport.DownloadDocumentReq)
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.BindPath(c, payload); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	res, content, err := h.uc.OpenDocument(c.Request().Context(), payload)
+	if err != nil {
+		hLog.Errorf("error when call u.OpenDocument got %s", err.Error())
+		return err
+	}
+	defer content.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": res.FileName}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(res.Size, 10))
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+	header.Set("Cache-Control", "private, no-store")
+
+	return c.Stream(http.StatusOK, res.ContentType, content)
+}
@@ -0,0 +1,265 @@
+package document
+
+import (
+	"bytes"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
+	"employee/internal/response"
+	"employee/internal/transport"
+	documentUCMock "employee/internal/usecase/document/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func multipartBody(t *testing.T, category string, fileName string, content []byte) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	if category != "" {
+		require.NoError(t, writer.WriteField("category", category))
+	}
+
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadDocument(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	testCases := []struct {
+		name        string
+		category    string
+		fileName    string
+		content     []byte
+		buildStub   func(documentUCMock *documentUCMock.DocumentUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when category is unknown",
+			category:  "selfie",
+			fileName:  "contract.pdf",
+			content:   pdf,
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"category"`)
+			},
+		},
+		{
+			name:      "failed when file is missing",
+			category:  "contract",
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"file"`)
+			},
+		},
+		{
+			name:      "unsupported when content is html",
+			category:  "contract",
+			fileName:  "contract.pdf",
+			content:   []byte("<html><body>not a pdf</body></html>"),
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+			},
+		},
+		{
+			name:      "too large when file exceeds the limit",
+			category:  "contract",
+			fileName:  "contract.pdf",
+			content:   append(append([]byte{}, pdf...), make([]byte, transport.MaxDocumentBytes)...),
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			},
+		},
+		{
+			name:      "too large when request exceeds the limit",
+			category:  "contract",
+			fileName:  "contract.pdf",
+			content:   append(append([]byte{}, pdf...), make([]byte, transport.MaxDocumentRequestBytes)...),
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			},
+		},
+		{
+			name:     "success",
+			category: "contract",
+			fileName: "../contract.pdf",
+			content:  pdf,
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {
+				documentUCMock.On("UploadDocument", mock.Anything, mock.MatchedBy(func(payload *transport.UploadDocumentReq) bool {
+					content, _ := io.ReadAll(payload.Content)
+					return payload.EmployeeID == 3 && payload.FileName == "contract.pdf" && payload.ContentType == "application/pdf" &&
+						payload.Size == int64(len(pdf)) && bytes.Equal(content, pdf)
+				})).Return(&transport.DocumentRes{ID: 7, EmployeeID: 3, FileName: "contract.pdf"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"file_name":"contract.pdf"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			body, contentType := multipartBody(t, tc.category, tc.fileName, tc.content)
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set(echo.HeaderContentType, contentType)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			documentUC := new(documentUCMock.DocumentUseCaseMock)
+			tc.buildStub(documentUC)
+
+			h := NewDocumentHandler(documentUC)
+			if err := h.UploadDocument(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+			documentUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDocuments(t *testing.T) {
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(documentUCMock *documentUCMock.DocumentUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "forbidden when a viewer lists documents of someone else",
+			employeeID: "4",
+			buildStub:  func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:       "success for their own documents",
+			employeeID: "3",
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {
+				documentUCMock.On("GetDocuments", mock.Anything, 3).Return(&transport.ListDocuments{Documents: []*transport.DocumentRes{
+					{ID: 7, EmployeeID: 3, DownloadURL: "/documents/7/download?expires=1&signature=ab"},
+				}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"download_url":"/documents/7/download`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleViewer, EmployeeID: 3})
+			req = req.WithContext(policy.ContextWithScope(ctx, policy.ScopeSelf))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			documentUC := new(documentUCMock.DocumentUseCaseMock)
+			tc.buildStub(documentUC)
+
+			h := NewDocumentHandler(documentUC)
+			if err := h.GetDocuments(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestDownloadDocument(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(documentUCMock *documentUCMock.DocumentUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when signature is missing",
+			query:     "expires=1717405200",
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"signature"`)
+			},
+		},
+		{
+			name:  "success",
+			query: "expires=1717405200&signature=9f86d081",
+			buildStub: func(documentUCMock *documentUCMock.DocumentUseCaseMock) {
+				documentUCMock.On("OpenDocument", mock.Anything, &transport.DownloadDocumentReq{DocumentID: 7, Expires: 1717405200, Signature: "9f86d081"}).
+					Return(&transport.DocumentRes{ID: 7, FileName: "contrat signé.pdf", ContentType: "application/pdf", Size: 4},
+						io.NopCloser(strings.NewReader("%PDF")), nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, "%PDF", resp.Body.String())
+				assert.Equal(t, "application/pdf", resp.Header().Get(echo.HeaderContentType))
+				assert.Equal(t, "attachment; filename*=utf-8''contrat%20sign%C3%A9.pdf", resp.Header().Get(echo.HeaderContentDisposition))
+				assert.Equal(t, "nosniff", resp.Header().Get(echo.HeaderXContentTypeOptions))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/documents/7/download?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("document_id")
+			c.SetParamValues("7")
+
+			documentUC := new(documentUCMock.DocumentUseCaseMock)
+			tc.buildStub(documentUC)
+
+			h := NewDocumentHandler(documentUC)
+			if err := h.DownloadDocument(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
@@ -1,16 +1,24 @@
 package employee
 
 import (
+	"bufio"
 	"context"
+	"employee/internal/apperror"
 	"employee/internal/config"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
 	"employee/internal/response"
 	"employee/internal/transport"
 	"employee/internal/usecase/employee"
 	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 var (
@@ -29,24 +37,71 @@ func NewEmployeeHandler(employeeUC employee.UseCaseEmployee, cfg config.Config)
 func (h *Handler) CreateEmployee(c echo.Context) error {
 	hLog := logger.WithField("handler", "CreateEmployee")
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	payload := new(transport.CreateEmployeeReq)
 
 	if err := c.Bind(payload); err != nil {
 		hLog.Errorf("echo bind got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return err
 	}
 
 	if err := transport.ValidateStruct(payload); err != nil {
 		hLog.Errorf("error when validate body, got %s", err)
-		return response.ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return err
 	}
 
 	res, err := h.uc.CreateEmployee(ctx, payload)
 	if err != nil {
 		hLog.Errorf("error when call u.CreateEmployee got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+// ImportEmployees creates employees from a CSV or JSON Lines body, picked by
+// the Content-Type, and reports the rows that could not be imported.
+func (h *Handler) ImportEmployees(c echo.Context) error {
+	hLog := logger.WithField("handler", "ImportEmployees")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.ImportEmployeesReq)
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	mediaType, err := transport.ParseImportType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		hLog.Errorf("error when parse content type, got %s", err)
+		return err
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, transport.MaxImportBytes)
+
+	payload.Rows, err = transport.ParseImport(mediaType, body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		hLog.Errorf("import is larger than %d bytes", tooLarge.Limit)
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+	if err != nil {
+		hLog.Errorf("error when parse import, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.ImportEmployees(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.ImportEmployees got %s", err.Error())
+		return err
 	}
 
 	return response.SuccessResponse(c, res)
@@ -55,91 +110,583 @@ func (h *Handler) CreateEmployee(c echo.Context) error {
 func (h *Handler) GetEmployee(c echo.Context) error {
 	hLog := logger.WithField("handler", "GetEmployee")
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
+
+	payload := new(transport.ListEmployeesReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.CustomFields = transport.CustomFieldFilters(c.QueryParams())
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	if payload.IncludeDeleted && !isAdmin(ctx) {
+		hLog.Error("include_deleted requested by a non admin")
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	if policy.ScopeFromContext(ctx) == policy.ScopeReports {
+		claims := pkg.ClaimsFromContext(ctx)
+		if claims == nil || claims.EmployeeID == 0 {
+			hLog.Error("manager token has no employee id")
+			return apperror.Forbidden(constant.MsgForbidden)
+		}
+		payload.ManagerID = claims.EmployeeID
+	}
 
-	res, err := h.uc.GetEmployees(ctx)
+	res, err := h.uc.GetEmployees(ctx, payload)
 	if err != nil {
 		hLog.Errorf("error when call u.GetEmployees got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return err
 	}
 
 	return response.SuccessResponse(c, res)
 }
 
+// ExportEmployees streams the employees matching the list filters as a CSV,
+// JSON Lines or XLSX attachment. Errors raised once the first bytes were sent
+// can only be logged, the client sees a truncated file.
+func (h *Handler) ExportEmployees(c echo.Context) error {
+	hLog := logger.WithField("handler", "ExportEmployees")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.ExportEmployeesReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.CustomFields = transport.CustomFieldFilters(c.QueryParams())
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	columns, err := transport.ParseExportColumns(payload.Columns)
+	if err != nil {
+		hLog.Errorf("error when parse columns, got %s", err)
+		return err
+	}
+
+	if payload.IncludeDeleted && !isAdmin(ctx) {
+		hLog.Error("include_deleted requested by a non admin")
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	if policy.ScopeFromContext(ctx) == policy.ScopeReports {
+		claims := pkg.ClaimsFromContext(ctx)
+		if claims == nil || claims.EmployeeID == 0 {
+			hLog.Error("manager token has no employee id")
+			return apperror.Forbidden(constant.MsgForbidden)
+		}
+		payload.ManagerID = claims.EmployeeID
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, transport.ExportContentType(payload.Format))
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="employees-%s.%s"`,
+		time.Now().UTC().Format("20060102"), payload.Format))
+
+	// buffer the start of the file so an error on the first rows is still rendered as json
+	buffered := bufio.NewWriterSize(c.Response(), 32<<10)
+
+	writer, err := transport.NewExportWriter(payload.Format, buffered, columns)
+	if err == nil {
+		err = h.uc.ExportEmployees(ctx, payload, writer.Write)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+
+	if err != nil {
+		if c.Response().Committed {
+			hLog.Errorf("error when stream export got %s", err.Error())
+			return nil
+		}
+
+		header.Del(echo.HeaderContentType)
+		header.Del(echo.HeaderContentDisposition)
+		hLog.Errorf("error when call u.ExportEmployees got %s", err.Error())
+		return err
+	}
+
+	// an empty csv or ndjson export has not written the status yet
+	if !c.Response().Committed {
+		c.Response().WriteHeader(http.StatusOK)
+	}
+
+	return nil
+}
+
 func (h *Handler) GetEmployeeByID(c echo.Context) error {
 	hLog := logger.WithField("handler", "GetEmployeeByID")
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
-	employeeIDStr := c.Param("employee_id")
-	employeeID, _ := strconv.Atoi(employeeIDStr)
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
 
-	res, err := h.uc.GetEmployeeByID(ctx, employeeID)
+	includeDeleted := false
+	if param := c.QueryParam("include_deleted"); param != "" {
+		var err error
+		includeDeleted, err = strconv.ParseBool(param)
+		if err != nil {
+			hLog.Errorf("error when parse include_deleted got %s", err.Error())
+			return apperror.Validation("include_deleted", "Field 'include_deleted' boolean")
+		}
+	}
 
-	if res == nil {
-		err := errors.New("employee not found")
-		hLog.Errorf("error when call u.GetEmployeeByID got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusNotFound)
+	if includeDeleted && !isAdmin(ctx) {
+		hLog.Error("include_deleted requested by a non admin")
+		return apperror.Forbidden(constant.MsgForbidden)
 	}
 
+	res, err := h.uc.GetEmployeeByID(ctx, params.EmployeeID, includeDeleted)
 	if err != nil {
 		hLog.Errorf("error when call u.GetEmployeeByID got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return err
 	}
 
+	if !isVisible(ctx, res) {
+		hLog.Errorf("employee %d is outside the caller's scope", res.ID)
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	redactProfile(ctx, res)
+
+	c.Response().Header().Set(transport.HeaderETag, transport.ETag(res.Version))
+
 	return response.SuccessResponse(c, res)
 }
 
 func (h *Handler) UpdateEmployee(c echo.Context) error {
 	hLog := logger.WithField("handler", "UpdateEmployee")
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
-	employeeIDStr := c.Param("employee_id")
-	employeeID, _ := strconv.Atoi(employeeIDStr)
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	version, err := transport.ParseIfMatch(c.Request().Header.Get(transport.HeaderIfMatch))
+	if err != nil {
+		hLog.Errorf("error when parse If-Match, got %s", err)
+		return err
+	}
 
 	payload := new(transport.UpdateEmployeeReq)
-	payload.ID = employeeID
 
 	if err := c.Bind(payload); err != nil {
 		hLog.Errorf("echo bind got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return err
 	}
 
+	payload.ID = params.EmployeeID
+	payload.Version = version
+
 	if err := transport.ValidateStruct(payload); err != nil {
 		hLog.Errorf("error when validate body, got %s", err)
-		return response.ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return err
 	}
 
-	err := h.uc.UpdateEmployee(ctx, payload)
+	if err := h.uc.UpdateEmployee(ctx, payload); err != nil {
+		hLog.Errorf("error when call u.UpdateEmployee got %s", err.Error())
+		return err
+	}
 
-	if err != nil && err.Error() == "employee not found" {
-		hLog.Errorf("employee not found got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusNotFound)
+	return response.SuccessResponse(c, nil)
+}
+
+// PatchEmployee applies a JSON Merge Patch or a JSON Patch, picked by the
+// Content-Type, to the employee, the use case validates only the fields it changed.
+func (h *Handler) PatchEmployee(c echo.Context) error {
+	hLog := logger.WithField("handler", "PatchEmployee")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
 	}
 
+	version, err := transport.ParseIfMatch(c.Request().Header.Get(transport.HeaderIfMatch))
 	if err != nil {
-		hLog.Errorf("error when call u.UpdateEmployee got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		hLog.Errorf("error when parse If-Match, got %s", err)
+		return err
 	}
 
-	return response.SuccessResponse(c, nil)
+	patchType, err := transport.ParsePatchType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		hLog.Errorf("error when parse content type, got %s", err)
+		return err
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		hLog.Errorf("error when read body, got %s", err)
+		return err
+	}
+
+	payload := &transport.PatchEmployeeReq{
+		ID:      params.EmployeeID,
+		Type:    patchType,
+		Patch:   patch,
+		Version: version,
+	}
+
+	res, err := h.uc.PatchEmployee(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.PatchEmployee got %s", err.Error())
+		return err
+	}
+
+	c.Response().Header().Set(transport.HeaderETag, transport.ETag(res.Version))
+
+	return response.SuccessResponse(c, res)
 }
 
 func (h *Handler) DeleteEmployee(c echo.Context) error {
 	hLog := logger.WithField("handler", "DeleteEmployee")
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
-	employeeIDStr := c.Param("employee_id")
-	employeeID, _ := strconv.Atoi(employeeIDStr)
-
-	err := h.uc.DeleteEmployee(ctx, employeeID)
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
 
+	version, err := transport.ParseIfMatch(c.Request().Header.Get(transport.HeaderIfMatch))
 	if err != nil {
+		hLog.Errorf("error when parse If-Match, got %s", err)
+		return err
+	}
+
+	if err := h.uc.DeleteEmployee(ctx, params.EmployeeID, version); err != nil {
 		hLog.Errorf("error when call uc.DeleteEmployee got %s", err.Error())
-		return response.ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+func (h *Handler) RestoreEmployee(c echo.Context) error {
+	hLog := logger.WithField("handler", "RestoreEmployee")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := h.uc.RestoreEmployee(ctx, params.EmployeeID); err != nil {
+		hLog.Errorf("error when call uc.RestoreEmployee got %s", err.Error())
+		return err
 	}
 
 	return response.SuccessResponse(c, nil)
 }
+
+// GetReports lists the employees below the employee, ?depth levels deep.
+func (h *Handler) GetReports(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetReports")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.ListReportsReq)
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.uc); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetReports(ctx, params.EmployeeID, payload.Depth)
+	if err != nil {
+		hLog.Errorf("error when call u.GetReports got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+// GetManagementChain lists the managers of the employee up to the root.
+func (h *Handler) GetManagementChain(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetManagementChain")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.uc); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetManagementChain(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetManagementChain got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetPositionHistory(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetPositionHistory")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.uc); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetPositionHistory(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetPositionHistory got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) AssignPosition(c echo.Context) error {
+	hLog := logger.WithField("handler", "AssignPosition")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.AssignPositionReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.AssignPosition(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.AssignPosition got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) ChangeStatus(c echo.Context) error {
+	hLog := logger.WithField("handler", "ChangeStatus")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.ChangeStatusReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.ChangeStatus(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.ChangeStatus got %s", err.Error())
+		return err
+	}
+
+	c.Response().Header().Set(transport.HeaderETag, transport.ETag(res.Version))
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) TerminateEmployee(c echo.Context) error {
+	hLog := logger.WithField("handler", "TerminateEmployee")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.TerminateEmployeeReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.TerminateEmployee(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.TerminateEmployee got %s", err.Error())
+		return err
+	}
+
+	c.Response().Header().Set(transport.HeaderETag, transport.ETag(res.Version))
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) RehireEmployee(c echo.Context) error {
+	hLog := logger.WithField("handler", "RehireEmployee")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.RehireEmployeeReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.RehireEmployee(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.RehireEmployee got %s", err.Error())
+		return err
+	}
+
+	c.Response().Header().Set(transport.HeaderETag, transport.ETag(res.Version))
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetOrgChart(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetOrgChart")
+
+	res, err := h.uc.GetOrgChart(c.Request().Context())
+	if err != nil {
+		hLog.Errorf("error when call u.GetOrgChart got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func isAdmin(ctx context.Context) bool {
+	claims := pkg.ClaimsFromContext(ctx)
+	return claims != nil && claims.Role == constant.RoleAdmin
+}
+
+// isVisible reports whether the scope granted by the policy lets the caller
+// see the given employee, under ScopeReports their own record and the records
+// of their direct reports like policy.CheckEmployee.
+func isVisible(ctx context.Context, employee *transport.EmployeeRes) bool {
+	if policy.ScopeFromContext(ctx) != policy.ScopeReports {
+		return true
+	}
+
+	claims := pkg.ClaimsFromContext(ctx)
+	if claims == nil || claims.EmployeeID == 0 {
+		return false
+	}
+
+	if employee.ID == claims.EmployeeID {
+		return true
+	}
+
+	return employee.ManagerID != nil && *employee.ManagerID == claims.EmployeeID
+}
+
+// redactProfile hides the personal fields of the profile from everyone but
+// admins, HR and the employee themselves, the work location stays visible.
+func redactProfile(ctx context.Context, employee *transport.EmployeeRes) {
+	claims := pkg.ClaimsFromContext(ctx)
+	if claims != nil && (claims.Role == constant.RoleAdmin || claims.Role == constant.RoleHR ||
+		(claims.EmployeeID != 0 && claims.EmployeeID == employee.ID)) {
+		return
+	}
+
+	employee.Phone = nil
+	employee.DateOfBirth = nil
+	employee.Address = nil
+	employee.EmergencyContacts = nil
+	employee.NationalID = nil
+}
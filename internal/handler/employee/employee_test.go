@@ -2,12 +2,21 @@ package employee
 
 import (
 	"database/sql"
+	"employee/internal/apperror"
 	"employee/internal/config"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
+	eRepo "employee/internal/repository/employee"
+	"employee/internal/response"
 	"employee/internal/transport"
+	"employee/internal/usecase/employee"
 	employeeUCMock "employee/internal/usecase/employee/mock"
+	"encoding/json"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -68,6 +77,17 @@ func TestCreateEmployee(t *testing.T) {
 				assert.Equal(t, http.StatusInternalServerError, resp.Code)
 			},
 		},
+		{
+			name:    "failed when email is taken",
+			payload: completePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("CreateEmployee", mock.Anything, mock.Anything).Return((*transport.EmployeeRes)(nil), apperror.Conflict("email", "email already exists"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"email"`)
+			},
+		},
 		{
 			name:    "success create employee",
 			payload: completePayload,
@@ -83,6 +103,7 @@ func TestCreateEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
 
 			req := httptest.NewRequest(http.MethodPost, "/employees", strings.NewReader(tc.payload))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -95,50 +116,83 @@ func TestCreateEmployee(t *testing.T) {
 
 			cfg := new(config.Config)
 			h := NewEmployeeHandler(employeeUC, *cfg)
-			_ = h.CreateEmployee(c)
+			if err := h.CreateEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
 
 			tc.checkReturn(rec)
 		})
 	}
 }
 
-func TestGetEmployee(t *testing.T) {
+func TestImportEmployees(t *testing.T) {
 
-	mockEmployeeResult := &transport.ListEmployees{
-		Employees: []*transport.EmployeeRes{
-			{
-				ID:        1,
-				FirstName: "test",
-				LastName:  "test",
-				Email:     "test@mail.com",
-				HireDate:  "2023-05-01",
-			},
-		}}
+	csvPayload := "first_name,email,hire_date\njohn,john@mail.com,2023-04-05\njane,jane@mail.com,05-04-2023\n"
 
 	testCases := []struct {
-		name      string
-		buildStub func(
+		name        string
+		query       string
+		contentType string
+		payload     string
+		buildStub   func(
 			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
 		)
 		checkReturn func(resp *httptest.ResponseRecorder)
 	}{
-
 		{
-			name: "failed when get employee",
+			name:        "failed when mode is unknown",
+			query:       "?mode=some",
+			contentType: transport.MIMECSV,
+			payload:     csvPayload,
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"mode"`)
+			},
+		},
+		{
+			name:        "failed when content type is not supported",
+			contentType: echo.MIMEApplicationJSON,
+			payload:     `[]`,
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+			},
+		},
+		{
+			name:        "failed when csv header is unknown",
+			contentType: transport.MIMECSV,
+			payload:     "name,email\njohn,john@mail.com\n",
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:        "failed when import employees",
+			contentType: transport.MIMECSV,
+			payload:     csvPayload,
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("GetEmployees", mock.Anything).Return(&transport.ListEmployees{}, sql.ErrConnDone)
+				employeeUCMock.On("ImportEmployees", mock.Anything, mock.Anything).Return((*transport.ImportEmployeesRes)(nil), sql.ErrConnDone)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, resp.Code)
 			},
 		},
 		{
-			name: "success create employee",
+			name:        "success import employees",
+			query:       "?mode=best_effort&dry_run=true",
+			contentType: transport.MIMECSV + "; charset=utf-8",
+			payload:     csvPayload,
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("GetEmployees", mock.Anything).Return(mockEmployeeResult, nil)
+				employeeUCMock.On("ImportEmployees", mock.Anything, mock.MatchedBy(func(payload *transport.ImportEmployeesReq) bool {
+					return payload.Mode == transport.ImportModeBestEffort && payload.DryRun &&
+						len(payload.Rows) == 2 && payload.Rows[0].Valid() && !payload.Rows[1].Valid()
+				})).Return(&transport.ImportEmployeesRes{Mode: transport.ImportModeBestEffort, DryRun: true, Total: 2, Imported: 1, Failed: 1}, nil)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"imported":1`)
 			},
 		},
 	}
@@ -146,9 +200,10 @@ func TestGetEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
 
-			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req := httptest.NewRequest(http.MethodPost, "/employees/import"+tc.query, strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
 			rec := httptest.NewRecorder()
 
 			c := e.NewContext(req, rec)
@@ -158,25 +213,31 @@ func TestGetEmployee(t *testing.T) {
 
 			cfg := new(config.Config)
 			h := NewEmployeeHandler(employeeUC, *cfg)
-			_ = h.GetEmployee(c)
+			if err := h.ImportEmployees(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
 
 			tc.checkReturn(rec)
 		})
 	}
 }
 
-func TestGetEmployeeByID(t *testing.T) {
+func TestGetEmployee(t *testing.T) {
 
-	mockEmployeeResult := &transport.EmployeeRes{
-		ID:        1,
-		FirstName: "test",
-		LastName:  "test",
-		Email:     "test@mail.com",
-		HireDate:  "2023-05-01",
-	}
+	mockEmployeeResult := &transport.ListEmployees{
+		Employees: []*transport.EmployeeRes{
+			{
+				ID:        1,
+				FirstName: "test",
+				LastName:  "test",
+				Email:     "test@mail.com",
+				HireDate:  "2023-05-01",
+			},
+		}}
 
 	testCases := []struct {
 		name      string
+		query     string
 		buildStub func(
 			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
 		)
@@ -184,9 +245,9 @@ func TestGetEmployeeByID(t *testing.T) {
 	}{
 
 		{
-			name: "failed when get employee by id",
+			name: "failed when get employee",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(&transport.EmployeeRes{}, sql.ErrConnDone)
+				employeeUCMock.On("GetEmployees", mock.Anything, mock.Anything).Return(&transport.ListEmployees{}, sql.ErrConnDone)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, resp.Code)
@@ -195,19 +256,103 @@ func TestGetEmployeeByID(t *testing.T) {
 		{
 			name: "success create employee",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeeResult, nil)
+				employeeUCMock.On("GetEmployees", mock.Anything, mock.Anything).Return(mockEmployeeResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:  "success with pagination and filter",
+			query: "?limit=10&sort=-hire_date&first_name=jo&hire_date_from=2023-01-01",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployees", mock.Anything, &transport.ListEmployeesReq{
+					Limit:        10,
+					Sort:         "-hire_date",
+					FirstName:    "jo",
+					HireDateFrom: "2023-01-01",
+				}).Return(mockEmployeeResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:  "success filtered by custom fields",
+			query: "?cf.cost_center=CC-12&cf.remote=true&first_name=jo",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployees", mock.Anything, &transport.ListEmployeesReq{
+					FirstName:    "jo",
+					CustomFields: map[string]string{"cost_center": "CC-12", "remote": "true"},
+				}).Return(mockEmployeeResult, nil)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, resp.Code)
 			},
 		},
+		{
+			name:  "success filtered by department",
+			query: "?department_id=3",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployees", mock.Anything, &transport.ListEmployeesReq{DepartmentID: 3}).Return(mockEmployeeResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:      "failed when department id is invalid",
+			query:     "?department_id=-1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when sort is not whitelisted",
+			query: "?sort=password",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when non admin asks for deleted employees",
+			query: "?include_deleted=true",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:  "failed when limit is out of range",
+			query: "?limit=1000",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when cursor is invalid",
+			query: "?cursor=abc",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployees", mock.Anything, mock.Anything).Return(&transport.ListEmployees{}, employee.ErrInvalidCursor)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
 
-			req := httptest.NewRequest(http.MethodGet, "/employees/:employee_id", nil)
+			req := httptest.NewRequest(http.MethodGet, "/employees"+tc.query, nil)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rec := httptest.NewRecorder()
 
@@ -218,75 +363,85 @@ func TestGetEmployeeByID(t *testing.T) {
 
 			cfg := new(config.Config)
 			h := NewEmployeeHandler(employeeUC, *cfg)
-			_ = h.GetEmployeeByID(c)
+			if err := h.GetEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
 
 			tc.checkReturn(rec)
 		})
 	}
 }
 
-func TestUpdateEmployee(t *testing.T) {
-
-	completePayload := `{
-    "first_name":"farid",
-    "last_name":"widyatama",
-    "email":"email@mil.com",
-    "hire_date":"2023-04-05"
-	}`
+func TestExportEmployees(t *testing.T) {
 
-	incompletePayload := `
-	{
-    "first_name":"farid",
-    "last_name":"widyatama",
-    "hire_date":"2023-04-05"
-	}`
-
-	invalidPayload := `{invalid json}`
+	exported := []*transport.EmployeeRes{
+		{ID: 1, FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01"},
+	}
 
 	testCases := []struct {
-		name      string
-		payload   string
-		buildStub func(
-			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
-		)
+		name        string
+		query       string
+		scope       policy.Scope
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
 		checkReturn func(resp *httptest.ResponseRecorder)
 	}{
 		{
-			name:    "failed when marshall json",
-			payload: invalidPayload,
-			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			name:      "failed when format is missing",
+			query:     "",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"format"`)
 			},
+		},
+		{
+			name:      "failed when column is unknown",
+			query:     "?format=csv&columns=id,salary",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"columns"`)
 			},
 		},
 		{
-			name:    "failed when doing validation",
-			payload: incompletePayload,
+			name:  "failed before the first row is rendered as json",
+			query: "?format=xlsx",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("ExportEmployees", mock.Anything, mock.Anything).Return([]*transport.EmployeeRes{}, sql.ErrConnDone)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Empty(t, resp.Header().Get(echo.HeaderContentDisposition))
+				assert.Contains(t, resp.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
 			},
 		},
 		{
-			name:    "failed when create employee",
-			payload: completePayload,
+			name:  "success csv export",
+			query: "?format=csv&columns=id,email&first_name=jo",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(&transport.EmployeeRes{}, sql.ErrConnDone)
+				employeeUCMock.On("ExportEmployees", mock.Anything, mock.MatchedBy(func(payload *transport.ExportEmployeesReq) bool {
+					return payload.FirstName == "jo"
+				})).Return(exported, nil)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, "id,email\n1,john@mail.com\n", resp.Body.String())
+				assert.Contains(t, resp.Header().Get(echo.HeaderContentType), transport.MIMECSV)
+				assert.Regexp(t, `^attachment; filename="employees-\d{8}\.csv"$`, resp.Header().Get(echo.HeaderContentDisposition))
 			},
 		},
 		{
-			name:    "success create employee",
-			payload: completePayload,
+			name:  "manager only exports reports",
+			query: "?format=ndjson",
+			scope: policy.ScopeReports,
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(&transport.EmployeeRes{}, nil)
+				employeeUCMock.On("ExportEmployees", mock.Anything, mock.MatchedBy(func(payload *transport.ExportEmployeesReq) bool {
+					return payload.ManagerID == 7
+				})).Return([]*transport.EmployeeRes{}, nil)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Empty(t, resp.Body.String())
 			},
 		},
 	}
@@ -294,9 +449,11 @@ func TestUpdateEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
 
-			req := httptest.NewRequest(http.MethodPut, "/employees", strings.NewReader(tc.payload))
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req := httptest.NewRequest(http.MethodGet, "/employees/export"+tc.query, nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: 7})
+			req = req.WithContext(policy.ContextWithScope(ctx, tc.scope))
 			rec := httptest.NewRecorder()
 
 			c := e.NewContext(req, rec)
@@ -306,41 +463,163 @@ func TestUpdateEmployee(t *testing.T) {
 
 			cfg := new(config.Config)
 			h := NewEmployeeHandler(employeeUC, *cfg)
-			_ = h.CreateEmployee(c)
+			if err := h.ExportEmployees(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
 
 			tc.checkReturn(rec)
 		})
 	}
 }
 
-func TestDeleteEmployee(t *testing.T) {
+func TestGetEmployeeByID(t *testing.T) {
+
+	mockEmployeeResult := &transport.EmployeeRes{
+		ID:        1,
+		FirstName: "test",
+		LastName:  "test",
+		Email:     "test@mail.com",
+		HireDate:  "2023-05-01",
+		Version:   4,
+	}
+
+	managerID := 7
+	mockReportResult := &transport.EmployeeRes{
+		ID:        2,
+		FirstName: "test",
+		Email:     "test@mail.com",
+		HireDate:  "2023-05-01",
+		ManagerID: &managerID,
+	}
+
+	phone := "+14155550123"
+	nationalID := "AB123456"
+	workLocation := "Berlin"
 
 	testCases := []struct {
 		name       string
-		employeeID int
+		employeeID string
+		scope      policy.Scope
 		buildStub  func(
 			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
 		)
 		checkReturn func(resp *httptest.ResponseRecorder)
 	}{
+
 		{
-			name:       "failed delete employee",
-			employeeID: 1,
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"employee_id"`)
+			},
+		},
+		{
+			name:       "failed when employee id is not positive",
+			employeeID: "0",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"rule":"gt"`)
+			},
+		},
+		{
+			name:       "failed when get employee by id",
+			employeeID: "1",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("DeleteEmployee", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(&transport.EmployeeRes{}, sql.ErrConnDone)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.NotContains(t, resp.Body.String(), sql.ErrConnDone.Error())
 			},
 		},
 		{
-			name:       "success delete employee",
-			employeeID: 1,
+			name:       "failed when employee not found",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return((*transport.EmployeeRes)(nil), employee.ErrEmployeeNotFound)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:       "success create employee",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeeResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, `"4"`, resp.Header().Get(transport.HeaderETag))
+			},
+		},
+		{
+			name:       "forbidden when employee does not report to the manager",
+			employeeID: "1",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeeResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:       "success when employee reports to the manager",
+			employeeID: "1",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockReportResult, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:       "success when the manager reads their own record",
+			employeeID: "7",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return(&transport.EmployeeRes{
+					ID: managerID, Phone: &phone,
+				}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"phone":"+14155550123"`)
+			},
+		},
+		{
+			name:       "success hides the personal profile of another employee",
+			employeeID: "2",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 2, false).Return(&transport.EmployeeRes{
+					ID: 2, ManagerID: &managerID, Phone: &phone, NationalID: &nationalID, WorkLocation: &workLocation,
+				}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.NotContains(t, resp.Body.String(), "phone")
+				assert.NotContains(t, resp.Body.String(), "national_id")
+				assert.Contains(t, resp.Body.String(), `"work_location":"Berlin"`)
+			},
+		},
+		{
+			name:       "success shows the own profile",
+			employeeID: "7",
 			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
-				employeeUCMock.On("DeleteEmployee", mock.Anything, mock.Anything).Return(nil)
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return(&transport.EmployeeRes{
+					ID: managerID, Phone: &phone, NationalID: &nationalID,
+				}, nil)
 			},
 			checkReturn: func(resp *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"phone":"+14155550123"`)
+				assert.Contains(t, resp.Body.String(), `"national_id":"AB123456"`)
 			},
 		},
 	}
@@ -348,21 +627,1064 @@ func TestDeleteEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
 
-			req := httptest.NewRequest(http.MethodDelete, "/employees/:employee_id", nil)
+			req := httptest.NewRequest(http.MethodGet, "/employees/:employee_id", nil)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: managerID})
+			req = req.WithContext(policy.ContextWithScope(ctx, tc.scope))
 			rec := httptest.NewRecorder()
 
 			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
 
 			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
 			tc.buildStub(employeeUC)
 
 			cfg := new(config.Config)
 			h := NewEmployeeHandler(employeeUC, *cfg)
-			_ = h.DeleteEmployee(c)
+			if err := h.GetEmployeeByID(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
 
 			tc.checkReturn(rec)
 		})
 	}
 }
+
+func TestUpdateEmployee(t *testing.T) {
+
+	completePayload := `{
+    "first_name":"farid",
+    "last_name":"widyatama",
+    "email":"email@mil.com",
+    "hire_date":"2023-04-05"
+	}`
+
+	incompletePayload := `
+	{
+    "first_name":"farid",
+    "last_name":"widyatama",
+    "hire_date":"2023-04-05"
+	}`
+
+	invalidPayload := `{invalid json}`
+
+	testCases := []struct {
+		name       string
+		employeeID string
+		ifMatch    string
+		payload    string
+		buildStub  func(
+			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
+		)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"employee_id"`)
+			},
+		},
+		{
+			name:       "failed when employee id is not positive",
+			employeeID: "0",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"rule":"gt"`)
+			},
+		},
+		{
+			name:       "failed when If-Match is missing",
+			employeeID: "1",
+			payload:    completePayload,
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"precondition_required"`)
+			},
+		},
+		{
+			name:       "failed when If-Match is malformed",
+			employeeID: "1",
+			ifMatch:    "2",
+			payload:    completePayload,
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"If-Match"`)
+			},
+		},
+		{
+			name:       "failed when version does not match",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    completePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("UpdateEmployee", mock.Anything, mock.Anything).Return(eRepo.ErrStaleVersion)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"precondition_failed"`)
+			},
+		},
+		{
+			name:       "failed when marshall json",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    invalidPayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when doing validation",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    incompletePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when update employee",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    completePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("UpdateEmployee", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:       "failed when email is taken",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    completePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("UpdateEmployee", mock.Anything, mock.Anything).Return(apperror.Conflict("email", "email already exists"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"email"`)
+			},
+		},
+		{
+			name:       "success update employee",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			payload:    completePayload,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("UpdateEmployee", mock.Anything, mock.MatchedBy(func(payload *transport.UpdateEmployeeReq) bool {
+					return payload.ID == 1 && payload.Version == 2
+				})).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPut, "/employees", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(transport.HeaderIfMatch, tc.ifMatch)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.UpdateEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestPatchEmployee(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		ifMatch     string
+		contentType string
+		payload     string
+		buildStub   func(
+			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
+		)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "failed when employee id is not a number",
+			employeeID:  "abc",
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:        "failed when If-Match is missing",
+			employeeID:  "1",
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+			},
+		},
+		{
+			name:        "failed when content type is not a patch",
+			employeeID:  "1",
+			ifMatch:     `"2"`,
+			contentType: echo.MIMEApplicationJSON,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub:   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+			},
+		},
+		{
+			name:        "failed when employee not found",
+			employeeID:  "1",
+			ifMatch:     `"2"`,
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.Anything).Return((*transport.EmployeeRes)(nil), employee.ErrEmployeeNotFound)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:        "failed when patched field is invalid",
+			employeeID:  "1",
+			ifMatch:     `"2"`,
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"hire_date":"01-05-2023"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.Anything).Return((*transport.EmployeeRes)(nil), apperror.Validation("hire_date", "date"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"hire_date"`)
+			},
+		},
+		{
+			name:        "success with merge patch",
+			employeeID:  "1",
+			ifMatch:     `"2"`,
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.MatchedBy(func(payload *transport.PatchEmployeeReq) bool {
+					return payload.ID == 1 && payload.Type == transport.MIMEMergePatch && string(payload.Patch) == `{"email":"new@mail.com"}` && payload.Version == 2
+				})).Return(&transport.EmployeeRes{ID: 1, Email: "new@mail.com", Version: 3}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), "new@mail.com")
+				assert.Equal(t, `"3"`, resp.Header().Get(transport.HeaderETag))
+			},
+		},
+		{
+			name:        "wildcard If-Match leaves the version check to the locked row",
+			employeeID:  "1",
+			ifMatch:     "*",
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.MatchedBy(func(payload *transport.PatchEmployeeReq) bool {
+					return payload.Version == 0
+				})).Return(&transport.EmployeeRes{ID: 1, Email: "new@mail.com", Version: 3}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:        "failed when version does not match",
+			employeeID:  "1",
+			ifMatch:     `"1"`,
+			contentType: transport.MIMEMergePatch,
+			payload:     `{"email":"new@mail.com"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.Anything).Return((*transport.EmployeeRes)(nil), eRepo.ErrStaleVersion)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+			},
+		},
+		{
+			name:        "success with json patch",
+			employeeID:  "1",
+			ifMatch:     `"2"`,
+			contentType: transport.MIMEJSONPatch,
+			payload:     `[{"op":"replace","path":"/last_name","value":"mayer"}]`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("PatchEmployee", mock.Anything, mock.MatchedBy(func(payload *transport.PatchEmployeeReq) bool {
+					return payload.Type == transport.MIMEJSONPatch
+				})).Return(&transport.EmployeeRes{ID: 1, LastName: "mayer"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPatch, "/employees", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			req.Header.Set(transport.HeaderIfMatch, tc.ifMatch)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.PatchEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestDeleteEmployee(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		employeeID string
+		ifMatch    string
+		buildStub  func(
+			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
+		)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"employee_id"`)
+			},
+		},
+		{
+			name:       "failed when employee id is not positive",
+			employeeID: "0",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"rule":"gt"`)
+			},
+		},
+		{
+			name:       "failed when If-Match is missing",
+			employeeID: "1",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+			},
+		},
+		{
+			name:       "failed when version does not match",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("DeleteEmployee", mock.Anything, 1, 2).Return(eRepo.ErrStaleVersion)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+			},
+		},
+		{
+			name:       "failed delete employee",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("DeleteEmployee", mock.Anything, mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:       "failed when employee not found",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("DeleteEmployee", mock.Anything, mock.Anything, mock.Anything).Return(employee.ErrEmployeeNotFound)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"not_found"`)
+			},
+		},
+		{
+			name:       "success delete employee",
+			employeeID: "1",
+			ifMatch:    `"2"`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("DeleteEmployee", mock.Anything, 1, 2).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodDelete, "/employees/:employee_id", nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(transport.HeaderIfMatch, tc.ifMatch)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.DeleteEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestRestoreEmployee(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		employeeID string
+		buildStub  func(
+			employeeUCMock *employeeUCMock.EmployeeUseCaseMock,
+		)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"employee_id"`)
+			},
+		},
+		{
+			name:       "failed when employee id is not positive",
+			employeeID: "0",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"rule":"gt"`)
+			},
+		},
+		{
+			name:       "failed when employee not found",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("RestoreEmployee", mock.Anything, mock.Anything).Return(employee.ErrEmployeeNotFound)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:       "failed when employee is not deleted",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("RestoreEmployee", mock.Anything, mock.Anything).Return(employee.ErrEmployeeNotDeleted)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name:       "success restore employee",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("RestoreEmployee", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/employees/:employee_id/restore", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.RestoreEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetReports(t *testing.T) {
+
+	managerID := 7
+	mockReports := &transport.ListReports{Reports: []*transport.HierarchyEmployeeRes{
+		{EmployeeRes: transport.EmployeeRes{ID: 2, ManagerID: &managerID}, Depth: 1},
+	}}
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		query       string
+		scope       policy.Scope
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when depth is out of range",
+			employeeID: "7",
+			query:      "?depth=50",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"depth"`)
+			},
+		},
+		{
+			name:       "failed when employee not found",
+			employeeID: "1",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetReports", mock.Anything, 1, 0).Return((*transport.ListReports)(nil), employee.ErrEmployeeNotFound)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:       "success with depth",
+			employeeID: "1",
+			query:      "?depth=3",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetReports", mock.Anything, 1, 3).Return(mockReports, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"depth":1`)
+			},
+		},
+		{
+			name:       "success when manager reads their own reports",
+			employeeID: "7",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetReports", mock.Anything, 7, 0).Return(mockReports, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+		{
+			name:       "forbidden when employee does not report to the manager",
+			employeeID: "1",
+			scope:      policy.ScopeReports,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 1, false).Return(&transport.EmployeeRes{ID: 1}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/employees/"+tc.employeeID+"/reports"+tc.query, nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: managerID})
+			req = req.WithContext(policy.ContextWithScope(ctx, tc.scope))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.GetReports(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetManagementChain(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: "3",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetManagementChain", mock.Anything, 3).Return(&transport.ManagementChain{Chain: []*transport.HierarchyEmployeeRes{
+					{EmployeeRes: transport.EmployeeRes{ID: 1}, Depth: 1},
+				}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"chain":[{"id":1`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			cfg := new(config.Config)
+			h := NewEmployeeHandler(employeeUC, *cfg)
+			if err := h.GetManagementChain(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetPositionHistory(t *testing.T) {
+	endDate := "2024-01-01"
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when employee id is not a number",
+			employeeID: "abc",
+			buildStub:  func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: "3",
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetPositionHistory", mock.Anything, 3).Return(&transport.PositionHistory{Positions: []*transport.PositionAssignmentRes{
+					{ID: 2, PositionID: 2, Change: "promotion", EffectiveDate: "2024-01-01"},
+					{ID: 1, PositionID: 1, Change: "initial", EffectiveDate: "2023-05-03", EndDate: &endDate},
+				}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+
+				var body struct {
+					Data transport.PositionHistory `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+				require.Len(t, body.Data.Positions, 2)
+				assert.Equal(t, "promotion", body.Data.Positions[0].Change)
+				assert.Nil(t, body.Data.Positions[0].EndDate)
+				assert.Equal(t, "2023-05-03", body.Data.Positions[1].EffectiveDate)
+				assert.Equal(t, endDate, *body.Data.Positions[1].EndDate)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			h := NewEmployeeHandler(employeeUC, config.Config{})
+			if err := h.GetPositionHistory(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestAssignPosition(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when effective date is invalid",
+			body:      `{"position_id":2,"effective_date":"01/01/2024"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "failed when effective date precedes the current position",
+			body: `{"position_id":2,"effective_date":"2024-01-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("AssignPosition", mock.Anything, mock.Anything).
+					Return((*transport.PositionAssignmentRes)(nil), apperror.Unprocessable("effective_date", "effective_date must be after the effective_date of the current position"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			},
+		},
+		{
+			name: "failed when effective date is in the future",
+			body: `{"position_id":2,"effective_date":"2999-01-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("AssignPosition", mock.Anything, mock.Anything).
+					Return((*transport.PositionAssignmentRes)(nil), apperror.Unprocessable("effective_date", "effective_date must not be in the future"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"effective_date"`)
+			},
+		},
+		{
+			name: "success",
+			body: `{"position_id":2,"effective_date":"2024-01-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("AssignPosition", mock.Anything, &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2024-01-01"}).
+					Return(&transport.PositionAssignmentRes{ID: 11, PositionID: 2, Change: "promotion", EffectiveDate: "2024-01-01"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"change":"promotion"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			h := NewEmployeeHandler(employeeUC, config.Config{})
+			if err := h.AssignPosition(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when status is terminated",
+			body:      `{"status":"terminated"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "success",
+			body: `{"status":"on_leave"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("ChangeStatus", mock.Anything, &transport.ChangeStatusReq{EmployeeID: 3, Status: "on_leave"}).
+					Return(&transport.EmployeeRes{ID: 3, Status: "on_leave", Version: 2}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, `"2"`, resp.Header().Get(transport.HeaderETag))
+				assert.Contains(t, resp.Body.String(), `"status":"on_leave"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			h := NewEmployeeHandler(employeeUC, config.Config{})
+			if err := h.ChangeStatus(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestTerminateEmployee(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when reason is missing",
+			body:      `{"termination_date":"2024-03-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "failed when status transition is not allowed",
+			body: `{"termination_date":"2024-03-01","reason":"resigned"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("TerminateEmployee", mock.Anything, mock.Anything).
+					Return((*transport.EmployeeRes)(nil), apperror.Unprocessable("status", "status transition is not allowed"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			},
+		},
+		{
+			name: "success",
+			body: `{"termination_date":"2024-03-01","reason":"resigned"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				terminationDate := "2024-03-01"
+				employeeUCMock.On("TerminateEmployee", mock.Anything, &transport.TerminateEmployeeReq{EmployeeID: 3, TerminationDate: "2024-03-01", Reason: "resigned"}).
+					Return(&transport.EmployeeRes{ID: 3, Status: "terminated", TerminationDate: &terminationDate, Version: 2}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"termination_date":"2024-03-01"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			h := NewEmployeeHandler(employeeUC, config.Config{})
+			if err := h.TerminateEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestRehireEmployee(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when rehire date is invalid",
+			body:      `{"rehire_date":"2024-13-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "success",
+			body: `{"rehire_date":"2024-06-01"}`,
+			buildStub: func(employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("RehireEmployee", mock.Anything, &transport.RehireEmployeeReq{EmployeeID: 3, RehireDate: "2024-06-01"}).
+					Return(&transport.EmployeeRes{ID: 3, HireDate: "2024-06-01", Status: "rehired", Version: 3}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"status":"rehired"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("3")
+
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(employeeUC)
+
+			h := NewEmployeeHandler(employeeUC, config.Config{})
+			if err := h.RehireEmployee(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetOrgChart(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = response.HTTPErrorHandler
+
+	req := httptest.NewRequest(http.MethodGet, "/org-chart", nil)
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+
+	employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+	employeeUC.On("GetOrgChart", mock.Anything).Return(&transport.OrgChart{Total: 2, Roots: []*transport.OrgChartNode{
+		{ID: 1, Reports: []*transport.OrgChartNode{{ID: 2, Reports: []*transport.OrgChartNode{}}}},
+	}}, nil)
+
+	h := NewEmployeeHandler(employeeUC, config.Config{})
+	if err := h.GetOrgChart(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reports":[{"id":2`)
+}
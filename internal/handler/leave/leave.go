@@ -0,0 +1,218 @@
+package leave
+
+import (
+	"employee/internal/policy"
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/employee"
+	"employee/internal/usecase/leave"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.leave")
+)
+
+type Handler struct {
+	uc         leave.UseCaseLeave
+	employeeUC employee.UseCaseEmployee
+}
+
+func NewLeaveHandler(leaveUC leave.UseCaseLeave, employeeUC employee.UseCaseEmployee) *Handler {
+	return &Handler{uc: leaveUC, employeeUC: employeeUC}
+}
+
+func (h *Handler) CreateLeaveType(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreateLeaveType")
+
+	payload := new(transport.CreateLeaveTypeReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreateLeaveType(c.Request().Context(), payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreateLeaveType got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetLeaveTypes(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetLeaveTypes")
+
+	res, err := h.uc.GetLeaveTypes(c.Request().Context())
+	if err != nil {
+		hLog.Errorf("error when call u.GetLeaveTypes got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetBalances(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetBalances")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.employeeUC); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.GetBalances(ctx, params.EmployeeID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetBalances got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) CreateLeaveRequest(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreateLeaveRequest")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.employeeUC); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	payload := new(transport.CreateLeaveRequestReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.EmployeeID = params.EmployeeID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreateLeaveRequest(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreateLeaveRequest got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetLeaveRequests(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetLeaveRequests")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.EmployeeIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := policy.CheckEmployee(ctx, params.EmployeeID, h.employeeUC); err != nil {
+		hLog.Errorf("error when check scope got %s", err.Error())
+		return err
+	}
+
+	payload := new(transport.ListLeaveRequestsReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetLeaveRequests(ctx, params.EmployeeID, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetLeaveRequests got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) ApproveLeaveRequest(c echo.Context) error {
+	hLog := logger.WithField("handler", "ApproveLeaveRequest")
+
+	payload, err := bindDecision(c)
+	if err != nil {
+		hLog.Errorf("error when bind decision got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.ApproveLeaveRequest(c.Request().Context(), payload)
+	if err != nil {
+		hLog.Errorf("error when call u.ApproveLeaveRequest got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) RejectLeaveRequest(c echo.Context) error {
+	hLog := logger.WithField("handler", "RejectLeaveRequest")
+
+	payload, err := bindDecision(c)
+	if err != nil {
+		hLog.Errorf("error when bind decision got %s", err.Error())
+		return err
+	}
+
+	res, err := h.uc.RejectLeaveRequest(c.Request().Context(), payload)
+	if err != nil {
+		hLog.Errorf("error when call u.RejectLeaveRequest got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func bindDecision(c echo.Context) (*transport.DecideLeaveRequestReq, error) {
+	params := new(transport.LeaveRequestIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		return nil, err
+	}
+
+	payload := new(transport.DecideLeaveRequestReq)
+
+	if err := c.Bind(payload); err != nil {
+		return nil, err
+	}
+
+	payload.LeaveRequestID = params.LeaveRequestID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,328 @@
+package leave
+
+import (
+	"employee/internal/apperror"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
+	"employee/internal/response"
+	"employee/internal/transport"
+	employeeUCMock "employee/internal/usecase/employee/mock"
+	leaveUCMock "employee/internal/usecase/leave/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateLeaveType(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(leaveUCMock *leaveUCMock.LeaveUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when accrual period is unknown",
+			body:      `{"name":"Annual","accrual_days":1.5,"accrual_period":"weekly"}`,
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"field":"accrual_period"`)
+			},
+		},
+		{
+			name: "success",
+			body: `{"name":"Annual","accrual_days":1.5,"accrual_period":"monthly"}`,
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock) {
+				leaveUCMock.On("CreateLeaveType", mock.Anything, &transport.CreateLeaveTypeReq{Name: "Annual", AccrualDays: 1.5, AccrualPeriod: "monthly"}).
+					Return(&transport.LeaveTypeRes{ID: 1, Name: "Annual", AccrualDays: 1.5, AccrualPeriod: "monthly"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"accrual_days":1.5`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/leave-types", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			leaveUC := new(leaveUCMock.LeaveUseCaseMock)
+			tc.buildStub(leaveUC)
+
+			h := NewLeaveHandler(leaveUC, new(employeeUCMock.EmployeeUseCaseMock))
+			if err := h.CreateLeaveType(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestCreateLeaveRequest(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		employeeID  string
+		role        string
+		scope       policy.Scope
+		body        string
+		buildStub   func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "forbidden when a viewer files leave for someone else",
+			employeeID: "4",
+			role:       constant.RoleViewer,
+			scope:      policy.ScopeSelf,
+			body:       `{"leave_type_id":1,"start_date":"2024-07-01","end_date":"2024-07-05"}`,
+			buildStub:  func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:       "failed when end date is invalid",
+			employeeID: "3",
+			role:       constant.RoleViewer,
+			scope:      policy.ScopeSelf,
+			body:       `{"leave_type_id":1,"start_date":"2024-07-01","end_date":"07/05/2024"}`,
+			buildStub:  func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when it overlaps approved leave",
+			employeeID: "3",
+			role:       constant.RoleViewer,
+			scope:      policy.ScopeSelf,
+			body:       `{"leave_type_id":1,"start_date":"2024-07-01","end_date":"2024-07-05"}`,
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				leaveUCMock.On("CreateLeaveRequest", mock.Anything, mock.Anything).
+					Return((*transport.LeaveRequestRes)(nil), apperror.Conflict("start_date", "leave overlaps approved leave"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name:       "success for the own employee",
+			employeeID: "3",
+			role:       constant.RoleViewer,
+			scope:      policy.ScopeSelf,
+			body:       `{"leave_type_id":1,"start_date":"2024-07-01","end_date":"2024-07-05","reason":"holiday"}`,
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				leaveUCMock.On("CreateLeaveRequest", mock.Anything, &transport.CreateLeaveRequestReq{EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-01", EndDate: "2024-07-05", Reason: "holiday"}).
+					Return(&transport.LeaveRequestRes{ID: 10, EmployeeID: 3, Days: 5, Status: "pending"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"status":"pending"`)
+			},
+		},
+		{
+			name:       "success when hr files leave for an employee",
+			employeeID: "4",
+			role:       constant.RoleHR,
+			scope:      policy.ScopeAll,
+			body:       `{"leave_type_id":1,"start_date":"2024-07-01","end_date":"2024-07-05"}`,
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				leaveUCMock.On("CreateLeaveRequest", mock.Anything, mock.Anything).Return(&transport.LeaveRequestRes{ID: 11, EmployeeID: 4}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: tc.role, EmployeeID: 3})
+			req = req.WithContext(policy.ContextWithScope(ctx, tc.scope))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues(tc.employeeID)
+
+			leaveUC := new(leaveUCMock.LeaveUseCaseMock)
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(leaveUC, employeeUC)
+
+			h := NewLeaveHandler(leaveUC, employeeUC)
+			if err := h.CreateLeaveRequest(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	managerID := 2
+	otherManagerID := 5
+
+	testCases := []struct {
+		name        string
+		buildStub   func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name: "forbidden when employee does not report to the manager",
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 4, false).Return(&transport.EmployeeRes{ID: 4, ManagerID: &otherManagerID}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name: "success for a direct report",
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock, employeeUCMock *employeeUCMock.EmployeeUseCaseMock) {
+				employeeUCMock.On("GetEmployeeByID", mock.Anything, 4, false).Return(&transport.EmployeeRes{ID: 4, ManagerID: &managerID}, nil)
+				leaveUCMock.On("GetBalances", mock.Anything, 4).Return(&transport.ListLeaveBalances{Balances: []*transport.LeaveBalanceRes{
+					{LeaveTypeID: 1, LeaveType: "Annual", Balance: 12.5, AccruedThrough: "2024-06-03"},
+				}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"balance":12.5`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/employees/4/leave-balances", nil)
+			ctx := pkg.ContextWithClaims(req.Context(), &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: managerID})
+			req = req.WithContext(policy.ContextWithScope(ctx, policy.ScopeReports))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("employee_id")
+			c.SetParamValues("4")
+
+			leaveUC := new(leaveUCMock.LeaveUseCaseMock)
+			employeeUC := new(employeeUCMock.EmployeeUseCaseMock)
+			tc.buildStub(leaveUC, employeeUC)
+
+			h := NewLeaveHandler(leaveUC, employeeUC)
+			if err := h.GetBalances(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetLeaveRequests(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = response.HTTPErrorHandler
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/3/leave-requests?status=cancelled", nil)
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetParamNames("employee_id")
+	c.SetParamValues("3")
+
+	h := NewLeaveHandler(new(leaveUCMock.LeaveUseCaseMock), new(employeeUCMock.EmployeeUseCaseMock))
+	if err := h.GetLeaveRequests(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"status"`)
+}
+
+func TestApproveLeaveRequest(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		requestID   string
+		buildStub   func(leaveUCMock *leaveUCMock.LeaveUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when leave request id is not an integer",
+			requestID: "abc",
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:      "forbidden when caller is not the manager",
+			requestID: "10",
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock) {
+				leaveUCMock.On("ApproveLeaveRequest", mock.Anything, mock.Anything).
+					Return((*transport.LeaveRequestRes)(nil), apperror.Forbidden("only the manager of the employee can decide on the leave request"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			},
+		},
+		{
+			name:      "success",
+			requestID: "10",
+			buildStub: func(leaveUCMock *leaveUCMock.LeaveUseCaseMock) {
+				leaveUCMock.On("ApproveLeaveRequest", mock.Anything, &transport.DecideLeaveRequestReq{LeaveRequestID: 10, Note: "enjoy"}).
+					Return(&transport.LeaveRequestRes{ID: 10, Status: "approved"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"status":"approved"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"note":"enjoy"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("leave_request_id")
+			c.SetParamValues(tc.requestID)
+
+			leaveUC := new(leaveUCMock.LeaveUseCaseMock)
+			tc.buildStub(leaveUC)
+
+			h := NewLeaveHandler(leaveUC, new(employeeUCMock.EmployeeUseCaseMock))
+			if err := h.ApproveLeaveRequest(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package position
+
+import (
+	"employee/internal/response"
+	"employee/internal/transport"
+	"employee/internal/usecase/position"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("handler", "handler.position")
+)
+
+type Handler struct {
+	uc position.UseCasePosition
+}
+
+func NewPositionHandler(positionUC position.UseCasePosition) *Handler {
+	return &Handler{uc: positionUC}
+}
+
+func (h *Handler) CreatePosition(c echo.Context) error {
+	hLog := logger.WithField("handler", "CreatePosition")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.CreatePositionReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.CreatePosition(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.CreatePosition got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetPositions(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetPositions")
+
+	ctx := c.Request().Context()
+
+	payload := new(transport.ListPositionsReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate query, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetPositions(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.GetPositions got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) GetPositionByID(c echo.Context) error {
+	hLog := logger.WithField("handler", "GetPositionByID")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.PositionIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.GetPositionByID(ctx, params.PositionID)
+	if err != nil {
+		hLog.Errorf("error when call u.GetPositionByID got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) UpdatePosition(c echo.Context) error {
+	hLog := logger.WithField("handler", "UpdatePosition")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.PositionIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	payload := new(transport.UpdatePositionReq)
+
+	if err := c.Bind(payload); err != nil {
+		hLog.Errorf("echo bind got %s", err.Error())
+		return err
+	}
+
+	payload.ID = params.PositionID
+
+	if err := transport.ValidateStruct(payload); err != nil {
+		hLog.Errorf("error when validate body, got %s", err)
+		return err
+	}
+
+	res, err := h.uc.UpdatePosition(ctx, payload)
+	if err != nil {
+		hLog.Errorf("error when call u.UpdatePosition got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, res)
+}
+
+func (h *Handler) DeletePosition(c echo.Context) error {
+	hLog := logger.WithField("handler", "DeletePosition")
+
+	ctx := c.Request().Context()
+
+	params := new(transport.PositionIDParam)
+	if err := transport.BindPath(c, params); err != nil {
+		hLog.Errorf("error when bind path params, got %s", err)
+		return err
+	}
+
+	if err := h.uc.DeletePosition(ctx, params.PositionID); err != nil {
+		hLog.Errorf("error when call uc.DeletePosition got %s", err.Error())
+		return err
+	}
+
+	return response.SuccessResponse(c, nil)
+}
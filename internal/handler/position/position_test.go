@@ -0,0 +1,343 @@
+package position
+
+import (
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/response"
+	"employee/internal/transport"
+	positionUCMock "employee/internal/usecase/position/mock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreatePosition(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		body        string
+		buildStub   func(positionUCMock *positionUCMock.PositionUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			body:      `{"title":"Software Engineer II","grade":0,"job_family":"Engineering"}`,
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name: "failed when title is taken",
+			body: `{"title":"Software Engineer II","grade":3,"job_family":"Engineering"}`,
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("CreatePosition", mock.Anything, mock.Anything).
+					Return((*transport.PositionRes)(nil), apperror.Conflict("title", "title already exists"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+			},
+		},
+		{
+			name: "success create position",
+			body: `{"title":"Software Engineer II","grade":3,"job_family":"Engineering"}`,
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("CreatePosition", mock.Anything, &transport.CreatePositionReq{Title: "Software Engineer II", Grade: 3, JobFamily: "Engineering"}).
+					Return(&transport.PositionRes{ID: 1, Title: "Software Engineer II"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/positions", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			positionUC := new(positionUCMock.PositionUseCaseMock)
+			tc.buildStub(positionUC)
+
+			h := NewPositionHandler(positionUC)
+			if err := h.CreatePosition(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetPositions(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		query       string
+		buildStub   func(positionUCMock *positionUCMock.PositionUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "failed when doing validation",
+			query:     "?limit=1000",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:  "failed when get positions",
+			query: "",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("GetPositions", mock.Anything, mock.Anything).Return((*transport.ListPositions)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			},
+		},
+		{
+			name:  "success get positions",
+			query: "?job_family=engineering&limit=10",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("GetPositions", mock.Anything, &transport.ListPositionsReq{JobFamily: "engineering", Limit: 10}).
+					Return(&transport.ListPositions{Positions: []*transport.PositionRes{{ID: 1}}}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/positions"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			positionUC := new(positionUCMock.PositionUseCaseMock)
+			tc.buildStub(positionUC)
+
+			h := NewPositionHandler(positionUC)
+			if err := h.GetPositions(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestGetPositionByID(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		positionID  string
+		buildStub   func(positionUCMock *positionUCMock.PositionUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when position id is invalid",
+			positionID: "abc",
+			buildStub:  func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when position not found",
+			positionID: "1",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("GetPositionByID", mock.Anything, 1).Return((*transport.PositionRes)(nil), apperror.NotFound("position not found"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+			},
+		},
+		{
+			name:       "success get position",
+			positionID: "1",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("GetPositionByID", mock.Anything, 1).Return(&transport.PositionRes{ID: 1, Title: "Software Engineer II"}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"title":"Software Engineer II"`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/positions/:position_id")
+			c.SetParamNames("position_id")
+			c.SetParamValues(tc.positionID)
+
+			positionUC := new(positionUCMock.PositionUseCaseMock)
+			tc.buildStub(positionUC)
+
+			h := NewPositionHandler(positionUC)
+			if err := h.GetPositionByID(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestUpdatePosition(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		positionID  string
+		body        string
+		buildStub   func(positionUCMock *positionUCMock.PositionUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when doing validation",
+			positionID: "1",
+			body:       `{"title":"","grade":3,"job_family":"Engineering"}`,
+			buildStub:  func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			name:       "failed when grade is out of range",
+			positionID: "1",
+			body:       `{"title":"Senior Software Engineer","grade":100,"job_family":"Engineering"}`,
+			buildStub:  func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"grade"`)
+			},
+		},
+		{
+			name:       "failed when job family is missing",
+			positionID: "1",
+			body:       `{"title":"Senior Software Engineer","grade":4}`,
+			buildStub:  func(positionUCMock *positionUCMock.PositionUseCaseMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"job_family"`)
+			},
+		},
+		{
+			name:       "success update position",
+			positionID: "1",
+			body:       `{"title":"Senior Software Engineer","grade":4,"job_family":"Engineering"}`,
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("UpdatePosition", mock.Anything, &transport.UpdatePositionReq{ID: 1, Title: "Senior Software Engineer", Grade: 4, JobFamily: "Engineering"}).
+					Return(&transport.PositionRes{ID: 1, Title: "Senior Software Engineer", Grade: 4}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/positions/:position_id")
+			c.SetParamNames("position_id")
+			c.SetParamValues(tc.positionID)
+
+			positionUC := new(positionUCMock.PositionUseCaseMock)
+			tc.buildStub(positionUC)
+
+			h := NewPositionHandler(positionUC)
+			if err := h.UpdatePosition(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
+
+func TestDeletePosition(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		positionID  string
+		buildStub   func(positionUCMock *positionUCMock.PositionUseCaseMock)
+		checkReturn func(resp *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "failed when position is still referenced",
+			positionID: "1",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("DeletePosition", mock.Anything, 1).Return(apperror.Conflict("", "position is still referenced by employees"))
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"conflict"`)
+			},
+		},
+		{
+			name:       "success delete position",
+			positionID: "1",
+			buildStub: func(positionUCMock *positionUCMock.PositionUseCaseMock) {
+				positionUCMock.On("DeletePosition", mock.Anything, 1).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/positions/:position_id")
+			c.SetParamNames("position_id")
+			c.SetParamValues(tc.positionID)
+
+			positionUC := new(positionUCMock.PositionUseCaseMock)
+			tc.buildStub(positionUC)
+
+			h := NewPositionHandler(positionUC)
+			if err := h.DeletePosition(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"employee/internal/repository/idempotency"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	keysLogger = log.WithField("job", "job.ExpireIdempotencyKeys")
+)
+
+// ExpireIdempotencyKeys periodically deletes idempotency keys older than the ttl,
+// once expired a key is no longer replayed.
+type ExpireIdempotencyKeys struct {
+	repo     idempotency.IdempotencyRepo
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func NewExpireIdempotencyKeys(repo idempotency.IdempotencyRepo, ttl time.Duration, interval time.Duration) *ExpireIdempotencyKeys {
+	return &ExpireIdempotencyKeys{repo: repo, ttl: ttl, interval: interval}
+}
+
+// Start runs the cleanup once right away and then on every interval until ctx is done.
+func (j *ExpireIdempotencyKeys) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				keysLogger.Info("idempotency key job stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *ExpireIdempotencyKeys) run(ctx context.Context) {
+	deleted, err := j.repo.DeleteExpiredKeys(ctx, time.Now().Add(-j.ttl))
+	if err != nil {
+		keysLogger.Errorf("error when call repo.DeleteExpiredKeys got %s", err.Error())
+		return
+	}
+
+	keysLogger.WithField("deleted", deleted).Info("deleted expired idempotency keys")
+}
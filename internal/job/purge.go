@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"employee/internal/usecase/employee"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	logger = log.WithField("job", "job.PurgeEmployees")
+)
+
+// PurgeEmployees periodically hard deletes employees that stayed soft deleted
+// longer than the retention period.
+type PurgeEmployees struct {
+	uc        employee.UseCaseEmployee
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewPurgeEmployees(employeeUC employee.UseCaseEmployee, retention time.Duration, interval time.Duration) *PurgeEmployees {
+	return &PurgeEmployees{uc: employeeUC, retention: retention, interval: interval}
+}
+
+// Start runs the purge once right away and then on every interval until ctx is done.
+func (j *PurgeEmployees) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				logger.Info("purge job stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *PurgeEmployees) run(ctx context.Context) {
+	purged, err := j.uc.PurgeDeletedEmployees(ctx, j.retention)
+	if err != nil {
+		logger.Errorf("error when call uc.PurgeDeletedEmployees got %s", err.Error())
+		return
+	}
+
+	logger.WithField("purged", purged).Info("purged soft deleted employees")
+}
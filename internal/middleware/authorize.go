@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"employee/internal/apperror"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/policy"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	authzLog = log.WithField("middleware", "AuthorizeMiddleware")
+)
+
+// AuthorizeMiddleware checks the caller's role against the policy table and
+// stores the granted scope in the request context. It must run after JWTMiddleware.
+func AuthorizeMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		claims := pkg.ClaimsFromContext(ctx)
+		if claims == nil {
+			authzLog.Error("missing claims in request context")
+			return apperror.Forbidden(constant.MsgForbidden)
+		}
+
+		scope, ok := policy.Authorize(claims.Role, c.Request().Method, c.Path())
+		if !ok {
+			authzLog.Errorf("role %q denied on %s %s", claims.Role, c.Request().Method, c.Path())
+			return apperror.Forbidden(constant.MsgForbidden)
+		}
+
+		c.SetRequest(c.Request().WithContext(policy.ContextWithScope(ctx, scope)))
+
+		return next(c)
+	}
+}
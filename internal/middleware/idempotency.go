@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository/idempotency"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyLease is how long a key stays in progress before a repeat of
+	// the request can take it over, it outlasts any request the server serves.
+	idempotencyLease = time.Minute
+)
+
+var (
+	idempotencyLog = log.WithField("middleware", "IdempotencyMiddleware")
+)
+
+// IdempotencyMiddleware makes a request carrying an Idempotency-Key header
+// safe to retry: the first response is stored and replayed for every repeat
+// of the key within ttl, a repeat with another body is rejected with 422.
+// Keys are scoped to the caller, so it must run after JWTMiddleware. Server
+// errors are not stored so the client can retry them, nor is a request that
+// did not complete within idempotencyLease. Bodies over maxBytes are rejected
+// with 413.
+func IdempotencyMiddleware(repo idempotency.IdempotencyRepo, ttl time.Duration, maxBytes int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				idempotencyLog.Errorf("idempotency key is %d characters long", len(key))
+				return apperror.Validation(HeaderIdempotencyKey, "Idempotency-Key must be at most 255 characters")
+			}
+
+			ctx := c.Request().Context()
+
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				idempotencyLog.Errorf("request is larger than %d bytes", tooLarge.Limit)
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+			if err != nil {
+				idempotencyLog.Errorf("error when read body got %s", err.Error())
+				return err
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			reserved := &model.IdempotencyKey{
+				ActorID:     actorID(c),
+				Key:         key,
+				Method:      c.Request().Method,
+				Path:        c.Path(),
+				RequestHash: requestHash(c.Request().Method, c.Request().URL.RequestURI(), body),
+			}
+
+			ok, err := repo.ReserveKey(ctx, reserved)
+			if err != nil {
+				idempotencyLog.Errorf("error when call repo.ReserveKey got %s", err.Error())
+				return err
+			}
+
+			if !ok {
+				stored, err := repo.GetKey(ctx, reserved.ActorID, key)
+				if err != nil {
+					idempotencyLog.Errorf("error when call repo.GetKey got %s", err.Error())
+					return err
+				}
+
+				// the key expired or was released since the reservation failed, claim it again
+				if stored == nil || time.Since(stored.CreatedAt) > ttl {
+					if err := repo.DeleteKey(ctx, reserved.ActorID, key); err != nil {
+						idempotencyLog.Errorf("error when call repo.DeleteKey got %s", err.Error())
+						return err
+					}
+
+					if ok, err = repo.ReserveKey(ctx, reserved); err != nil {
+						idempotencyLog.Errorf("error when call repo.ReserveKey got %s", err.Error())
+						return err
+					}
+
+					// a concurrent request claimed the key first, replay what it stored
+					if !ok {
+						if stored, err = repo.GetKey(ctx, reserved.ActorID, key); err != nil {
+							idempotencyLog.Errorf("error when call repo.GetKey got %s", err.Error())
+							return err
+						}
+					}
+				} else if !stored.Completed() && time.Since(stored.CreatedAt) > idempotencyLease {
+					// the request holding the key did not complete in time, e.g. it crashed
+					if ok, err = repo.ReclaimKey(ctx, reserved, time.Now().Add(-idempotencyLease)); err != nil {
+						idempotencyLog.Errorf("error when call repo.ReclaimKey got %s", err.Error())
+						return err
+					}
+
+					if !ok {
+						if stored, err = repo.GetKey(ctx, reserved.ActorID, key); err != nil {
+							idempotencyLog.Errorf("error when call repo.GetKey got %s", err.Error())
+							return err
+						}
+					}
+				}
+
+				if !ok {
+					return replay(c, reserved, stored)
+				}
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			// render the error here so its response is the one stored
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			if c.Response().Status >= http.StatusInternalServerError {
+				if err := repo.DeleteKey(ctx, reserved.ActorID, key); err != nil {
+					idempotencyLog.Errorf("error when call repo.DeleteKey got %s", err.Error())
+				}
+				return nil
+			}
+
+			reserved.StatusCode = c.Response().Status
+			reserved.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+			reserved.ResponseBody = redactResponse(recorder.body.Bytes())
+
+			if err := repo.CompleteKey(ctx, reserved); err != nil {
+				idempotencyLog.Errorf("error when call repo.CompleteKey got %s", err.Error())
+			}
+
+			return nil
+		}
+	}
+}
+
+func replay(c echo.Context, request *model.IdempotencyKey, stored *model.IdempotencyKey) error {
+	// the key was claimed and released again before it could be read
+	if stored == nil {
+		idempotencyLog.Errorf("idempotency key %q is still in progress", request.Key)
+		return apperror.Conflict(HeaderIdempotencyKey, "a request with this Idempotency-Key is still in progress")
+	}
+
+	if stored.RequestHash != request.RequestHash {
+		idempotencyLog.Errorf("idempotency key %q reused with another request", request.Key)
+		return apperror.Unprocessable(HeaderIdempotencyKey, "Idempotency-Key was already used with another request")
+	}
+
+	if !stored.Completed() {
+		idempotencyLog.Errorf("idempotency key %q is still in progress", request.Key)
+		return apperror.Conflict(HeaderIdempotencyKey, "a request with this Idempotency-Key is still in progress")
+	}
+
+	c.Response().Header().Set(HeaderIdempotencyReplayed, "true")
+
+	return c.Blob(stored.StatusCode, stored.ContentType, stored.ResponseBody)
+}
+
+// redactResponse blanks the initial password of a provisioned account before
+// the response is stored, a replay does not return it again.
+func redactResponse(body []byte) []byte {
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body
+	}
+
+	data, _ := envelope["data"].(map[string]interface{})
+	account, _ := data["account"].(map[string]interface{})
+	if _, ok := account["initial_password"]; !ok {
+		return body
+	}
+	account["initial_password"] = ""
+
+	redacted, err := json.Marshal(envelope)
+	if err != nil {
+		idempotencyLog.Errorf("error when marshal redacted response got %s", err.Error())
+		return nil
+	}
+
+	return redacted
+}
+
+// actorID scopes keys to the caller so two clients cannot collide on a key.
+func actorID(c echo.Context) string {
+	claims := pkg.ClaimsFromContext(c.Request().Context())
+	if claims == nil {
+		return ""
+	}
+
+	return claims.Subject
+}
+
+func requestHash(method string, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
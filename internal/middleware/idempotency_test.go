@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	idempotencyRepoMock "employee/internal/repository/idempotency/mock"
+	"employee/internal/response"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	payload := `{"first_name":"farid","email":"farid@mail.com","hire_date":"2023-04-05"}`
+	hash := requestHash(http.MethodPost, "/employees", []byte(payload))
+
+	completed := &model.IdempotencyKey{
+		ActorID:      "1",
+		Key:          "key-1",
+		RequestHash:  hash,
+		StatusCode:   http.StatusOK,
+		ContentType:  echo.MIMEApplicationJSON,
+		ResponseBody: []byte(`{"id":1}`),
+		CreatedAt:    time.Now(),
+	}
+
+	testCases := []struct {
+		name        string
+		key         string
+		payload     string
+		handler     echo.HandlerFunc
+		buildStub   func(repo *idempotencyRepoMock.DBMock)
+		checkReturn func(resp *httptest.ResponseRecorder, calls int)
+	}{
+		{
+			name:      "requests without a key are not tracked",
+			payload:   payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:      "failed when key is too long",
+			key:       strings.Repeat("k", 256),
+			payload:   payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "first request stores the response",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+					return key.ActorID == "1" && key.Key == "key-1" && key.RequestHash == hash
+				})).Return(true, nil)
+				repo.On("CompleteKey", mock.Anything, mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+					return key.StatusCode == http.StatusOK && strings.Contains(string(key.ResponseBody), `"id":1`)
+				})).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:    "initial password is not stored",
+			key:     "key-1",
+			payload: payload,
+			handler: func(c echo.Context) error {
+				return response.SuccessResponse(c, map[string]interface{}{
+					"id":      1,
+					"account": map[string]interface{}{"user_id": 2, "initial_password": "s3cret-Passw0rd"},
+				})
+			},
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil)
+				repo.On("CompleteKey", mock.Anything, mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+					body := string(key.ResponseBody)
+					return strings.Contains(body, `"initial_password":""`) && strings.Contains(body, `"user_id":2`) &&
+						!strings.Contains(body, "s3cret-Passw0rd")
+				})).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), "s3cret-Passw0rd")
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:    "repeated request replays the stored response",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return(completed, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, `{"id":1}`, resp.Body.String())
+				assert.Equal(t, "true", resp.Header().Get(HeaderIdempotencyReplayed))
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "failed when key is reused with another body",
+			key:     "key-1",
+			payload: `{"first_name":"someone else"}`,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return(completed, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"code":"unprocessable"`)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "failed when first request is still in progress",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return(&model.IdempotencyKey{RequestHash: hash, CreatedAt: time.Now()}, nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "key left in progress past its lease is reclaimed",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").
+					Return(&model.IdempotencyKey{RequestHash: hash, CreatedAt: time.Now().Add(-2 * idempotencyLease)}, nil)
+				repo.On("ReclaimKey", mock.Anything, mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+					return key.RequestHash == hash
+				}), mock.Anything).Return(true, nil)
+				repo.On("CompleteKey", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:    "failed when a stale key is reclaimed by a concurrent request",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").
+					Return(&model.IdempotencyKey{RequestHash: hash, CreatedAt: time.Now().Add(-2 * idempotencyLease)}, nil).Once()
+				repo.On("ReclaimKey", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").
+					Return(&model.IdempotencyKey{RequestHash: hash, CreatedAt: time.Now()}, nil).Once()
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:      "failed when body is too large",
+			key:       "key-1",
+			payload:   `{"first_name":"` + strings.Repeat("a", 2048) + `"}`,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "expired key is claimed again",
+			key:     "key-1",
+			payload: `{"first_name":"someone else"}`,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				expired := *completed
+				expired.CreatedAt = time.Now().Add(-48 * time.Hour)
+
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return(&expired, nil)
+				repo.On("DeleteKey", mock.Anything, "1", "key-1").Return(nil)
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil).Once()
+				repo.On("CompleteKey", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:    "replays the key claimed by a concurrent request",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Twice()
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return((*model.IdempotencyKey)(nil), nil).Once()
+				repo.On("DeleteKey", mock.Anything, "1", "key-1").Return(nil)
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return(completed, nil).Once()
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, `{"id":1}`, resp.Body.String())
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "failed when key is claimed and released by a concurrent request",
+			key:     "key-1",
+			payload: payload,
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Twice()
+				repo.On("GetKey", mock.Anything, "1", "key-1").Return((*model.IdempotencyKey)(nil), nil).Twice()
+				repo.On("DeleteKey", mock.Anything, "1", "key-1").Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Equal(t, 0, calls)
+			},
+		},
+		{
+			name:    "server errors release the key",
+			key:     "key-1",
+			payload: payload,
+			handler: func(c echo.Context) error {
+				return sql.ErrConnDone
+			},
+			buildStub: func(repo *idempotencyRepoMock.DBMock) {
+				repo.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil)
+				repo.On("DeleteKey", mock.Anything, "1", "key-1").Return(nil)
+			},
+			checkReturn: func(resp *httptest.ResponseRecorder, calls int) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Equal(t, 1, calls)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodPost, "/employees", strings.NewReader(tc.payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.key != "" {
+				req.Header.Set(HeaderIdempotencyKey, tc.key)
+			}
+			claims := &pkg.JWTClaim{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}}
+			req = req.WithContext(pkg.ContextWithClaims(req.Context(), claims))
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/employees")
+
+			repo := new(idempotencyRepoMock.DBMock)
+			tc.buildStub(repo)
+
+			calls := 0
+			handler := tc.handler
+			if handler == nil {
+				handler = func(c echo.Context) error {
+					return response.SuccessResponse(c, map[string]int{"id": 1})
+				}
+			}
+			next := func(c echo.Context) error {
+				calls++
+				return handler(c)
+			}
+
+			if err := IdempotencyMiddleware(repo, 24*time.Hour, 1024)(next)(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec, calls)
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"employee/internal/apperror"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	uRepo "employee/internal/repository/user"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+)
+
+var (
+	jwtLog = log.WithField("middleware", "JWTMiddleware")
+)
+
+// JWTMiddleware rejects requests without a valid bearer token and stores the
+// verified claims in both the echo and the request context. The account of the
+// token is read on every request, so a token of a deleted or disabled account
+// stops working at once instead of when it expires.
+func JWTMiddleware(jwtKey string, userRepo uRepo.UserRepo) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			tokenString, found := strings.CutPrefix(header, "Bearer ")
+			if !found || tokenString == "" {
+				jwtLog.Error("missing bearer token")
+				return apperror.Unauthorized(constant.MsgInvalidToken)
+			}
+
+			claims, err := pkg.ParseJWT(tokenString, jwtKey)
+			if err != nil {
+				jwtLog.Errorf("error when parse token got %s", err.Error())
+
+				switch {
+				case errors.Is(err, jwt.ErrTokenExpired):
+					return apperror.Unauthorized(constant.MsgTokenExpired)
+				case errors.Is(err, pkg.ErrInvalidClaims):
+					return apperror.Unauthorized(constant.MsgParseErr)
+				default:
+					return apperror.Unauthorized(constant.MsgInvalidToken)
+				}
+			}
+
+			userID, err := strconv.Atoi(claims.Subject)
+			if err != nil {
+				jwtLog.Errorf("token subject %q is not a user id", claims.Subject)
+				return apperror.Unauthorized(constant.MsgInvalidToken)
+			}
+
+			user, err := userRepo.GetUserByID(c.Request().Context(), userID)
+			if err != nil {
+				jwtLog.Errorf("error when call userRepo.GetUserByID got %s", err.Error())
+				return err
+			}
+
+			if user == nil || user.DisabledAt != nil {
+				jwtLog.Errorf("token of user %d that is unknown or disabled", userID)
+				return apperror.Unauthorized(constant.MsgInvalidToken)
+			}
+
+			c.Set(constant.ContextKeyClaims, claims)
+			c.SetRequest(c.Request().WithContext(pkg.ContextWithClaims(c.Request().Context(), claims)))
+
+			return next(c)
+		}
+	}
+}
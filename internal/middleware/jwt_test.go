@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"employee/internal/model"
+	"employee/internal/pkg"
+	userRepoMock "employee/internal/repository/user/mock"
+	"employee/internal/response"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJWTMiddleware(t *testing.T) {
+	jwtKey := "secret"
+
+	validToken, err := pkg.GenerateJWT(map[string]string{"sub": "1", "name": "test", "role": "admin"}, jwtKey)
+	require.NoError(t, err)
+
+	disabledToken, err := pkg.GenerateJWT(map[string]string{"sub": "2", "name": "test", "role": "admin"}, jwtKey)
+	require.NoError(t, err)
+
+	unknownToken, err := pkg.GenerateJWT(map[string]string{"sub": "3", "name": "test", "role": "admin"}, jwtKey)
+	require.NoError(t, err)
+
+	noSubjectToken, err := pkg.GenerateJWT(map[string]string{"name": "test", "role": "admin"}, jwtKey)
+	require.NoError(t, err)
+
+	disabledAt := time.Now()
+	userRepo := new(userRepoMock.DBMock)
+	userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Role: "admin"}, nil)
+	userRepo.On("GetUserByID", mock.Anything, 2).Return(&model.User{ID: 2, Role: "admin", DisabledAt: &disabledAt}, nil)
+	userRepo.On("GetUserByID", mock.Anything, 3).Return((*model.User)(nil), nil)
+
+	wrongKeyToken, err := pkg.GenerateJWT(map[string]string{"name": "test"}, "another-secret")
+	require.NoError(t, err)
+
+	expiredToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, pkg.JWTClaim{
+		Name: "test",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}).SignedString([]byte(jwtKey))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		header      string
+		checkReturn func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim)
+	}{
+		{
+			name:   "failed when token is missing",
+			header: "",
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Contains(t, resp.Body.String(), "invalid access token")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when scheme is not bearer",
+			header: "Basic dGVzdDp0ZXN0",
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when token is signed with another key",
+			header: "Bearer " + wrongKeyToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Contains(t, resp.Body.String(), "invalid access token")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when token is expired",
+			header: "Bearer " + expiredToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Contains(t, resp.Body.String(), "token expired")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when the account of the token is disabled",
+			header: "Bearer " + disabledToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Contains(t, resp.Body.String(), "invalid access token")
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when the account of the token does not exist",
+			header: "Bearer " + unknownToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "failed when token has no subject",
+			header: "Bearer " + noSubjectToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Nil(t, claims)
+			},
+		},
+		{
+			name:   "success",
+			header: "Bearer " + validToken,
+			checkReturn: func(resp *httptest.ResponseRecorder, claims *pkg.JWTClaim) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				require.NotNil(t, claims)
+				assert.Equal(t, "admin", claims.Role)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.HTTPErrorHandler
+
+			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+			if tc.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			var claims *pkg.JWTClaim
+			next := func(c echo.Context) error {
+				claims = pkg.ClaimsFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}
+
+			if err := JWTMiddleware(jwtKey, userRepo)(next)(c); err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+
+			tc.checkReturn(rec, claims)
+		})
+	}
+}
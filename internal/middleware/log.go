@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"employee/internal/pkg"
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
 	"time"
@@ -10,15 +11,19 @@ func LoggingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		start := time.Now()
 
-		res := next(c)
+		// render the error here so the logged status is the one sent to the client
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
 
 		log.WithFields(log.Fields{
 			"method":     c.Request().Method,
 			"path":       c.Path(),
 			"status":     c.Response().Status,
 			"latency_ns": time.Since(start).Nanoseconds(),
+			"request_id": pkg.RequestIDFromContext(c.Request().Context()),
 		}).Info("request details")
 
-		return res
+		return nil
 	}
 }
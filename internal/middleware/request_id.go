@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"employee/internal/pkg"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestIDMiddleware propagates the caller's X-Request-Id, or generates one,
+// into the response header and the request context.
+func RequestIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = pkg.GenerateRequestID()
+		}
+
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+		c.SetRequest(c.Request().WithContext(pkg.ContextWithRequestID(c.Request().Context(), requestID)))
+
+		return next(c)
+	}
+}
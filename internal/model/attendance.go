@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Attendance is a work session from ClockIn to ClockOut, ClockOut is nil while
+// the employee is clocked in. WorkDate is the day of ClockIn in the timezone
+// of the work schedule.
+type Attendance struct {
+	ID         int64
+	EmployeeID int
+	WorkDate   string
+	ClockIn    time.Time
+	ClockOut   *time.Time
+	CreatedAt  time.Time
+}
+
+// AttendanceDay sums the closed sessions of an employee on a work date, Open
+// is set while one of them is still running.
+type AttendanceDay struct {
+	EmployeeID    int
+	WorkDate      string
+	FirstClockIn  time.Time
+	LastClockOut  *time.Time
+	WorkedSeconds float64
+	Open          bool
+}
+
+// AttendanceWeek sums the closed sessions of an employee in the week starting
+// on the Monday WeekStart.
+type AttendanceWeek struct {
+	EmployeeID    int
+	WeekStart     string
+	WorkedSeconds float64
+	DaysWorked    int
+}
+
+// AttendanceFilter selects the work dates from From to the inclusive To, a
+// zero EmployeeID or DepartmentID does not filter.
+type AttendanceFilter struct {
+	EmployeeID   int
+	DepartmentID int
+	From         string
+	To           string
+}
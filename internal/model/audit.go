@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate    = "create"
+	AuditActionUpdate    = "update"
+	AuditActionDelete    = "delete"
+	AuditActionRestore   = "restore"
+	AuditActionPurge     = "purge"
+	AuditActionTerminate = "terminate"
+	AuditActionRehire    = "rehire"
+
+	AuditActionApproveLeave = "approve_leave"
+	AuditActionRejectLeave  = "reject_leave"
+)
+
+type Audit struct {
+	ID         int64
+	EmployeeID int
+	Action     string
+	ActorID    string
+	ActorName  string
+	ActorRole  string
+	RequestID  string
+	Before     json.RawMessage
+	After      json.RawMessage
+	Diff       json.RawMessage
+	CreatedAt  time.Time
+}
+
+type AuditFilter struct {
+	EmployeeID int
+	ActorID    string
+	Action     string
+	From       string
+	To         string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Pay frequencies of a compensation.
+const (
+	PayFrequencyHourly   = "hourly"
+	PayFrequencyWeekly   = "weekly"
+	PayFrequencyBiweekly = "biweekly"
+	PayFrequencyMonthly  = "monthly"
+	PayFrequencyAnnual   = "annual"
+)
+
+// Compensation is the pay of an employee over an effective range. Amount is
+// in minor units of Currency, EffectiveTo is inclusive and nil while the
+// compensation is current.
+type Compensation struct {
+	ID            int64
+	EmployeeID    int
+	Amount        int64
+	Currency      string
+	PayFrequency  string
+	EffectiveFrom string
+	EffectiveTo   *string
+	CreatedBy     string
+	CreatedAt     time.Time
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Types of a custom field, they decide which json values an employee may hold.
+const (
+	CustomFieldTypeText    = "text"
+	CustomFieldTypeNumber  = "number"
+	CustomFieldTypeBoolean = "boolean"
+	CustomFieldTypeDate    = "date"
+	CustomFieldTypeEnum    = "enum"
+)
+
+// CustomField defines an attribute the employees can hold under Name in their
+// custom fields. Options lists the allowed values of an enum.
+type CustomField struct {
+	ID        int
+	Name      string
+	Type      string
+	Required  bool
+	Options   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Department struct {
+	ID          int
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type DepartmentFilter struct {
+	Name   string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Categories of an employee document.
+const (
+	DocumentCategoryContract    = "contract"
+	DocumentCategoryID          = "id"
+	DocumentCategoryCertificate = "certificate"
+	DocumentCategoryOther       = "other"
+)
+
+// Document is a file attached to an employee, the content is kept by the
+// document storage under StorageKey.
+type Document struct {
+	ID          int64
+	EmployeeID  int
+	Category    string
+	FileName    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	UploadedBy  string
+	CreatedAt   time.Time
+}
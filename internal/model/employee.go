@@ -1,9 +1,108 @@
 package model
 
+import "time"
+
+// Employment statuses of an employee, the use case decides which transitions
+// between them are allowed.
+const (
+	EmploymentStatusProbation  = "probation"
+	EmploymentStatusActive     = "active"
+	EmploymentStatusOnLeave    = "on_leave"
+	EmploymentStatusTerminated = "terminated"
+	EmploymentStatusRehired    = "rehired"
+)
+
 type Employee struct {
-	ID        int
-	FirstName string
-	LastName  string
-	Email     string
-	HireDate  string
+	ID           int
+	FirstName    string
+	LastName     string
+	Email        string
+	HireDate     string
+	ManagerID    *int
+	DepartmentID *int
+	PositionID   *int
+	Status       string
+	// TerminationDate and TerminationReason are set while the employee is terminated.
+	TerminationDate   *string
+	TerminationReason *string
+	// The profile is optional, it is only read with a single employee.
+	Phone             *string
+	DateOfBirth       *string
+	Address           *Address
+	EmergencyContacts []EmergencyContact
+	NationalID        *string
+	WorkLocation      *string
+	// CustomFields holds the values of the custom fields by name.
+	CustomFields map[string]interface{}
+	DeletedAt    *time.Time
+	// Version is bumped by every write, conditional writes compare it.
+	Version int
+}
+
+// Address is the postal address of an employee, stored as json.
+type Address struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+}
+
+// EmergencyContact is a person to call for an employee, stored as json.
+type EmergencyContact struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone"`
+}
+
+// EmployeeFilter narrows and orders the employees returned by the repository.
+type EmployeeFilter struct {
+	FirstName    string
+	LastName     string
+	Email        string
+	HireDateFrom string
+	HireDateTo   string
+	ManagerID    int
+	DepartmentID int
+	Status       string
+	// CustomFields keeps the employees holding every one of these values.
+	CustomFields map[string]interface{}
+	// IncludeDeleted also returns soft deleted employees.
+	IncludeDeleted bool
+	Sort           string
+	Limit          int
+	Offset         int
+	Cursor         *EmployeeCursor
+}
+
+// EmployeeCursor is the keyset position of the last employee on the previous page.
+type EmployeeCursor struct {
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// EmployeePatch holds the columns a partial update writes, nil fields are left
+// untouched. Version is the version the employee must still be at.
+type EmployeePatch struct {
+	FirstName *string
+	LastName  *string
+	Email     *string
+	HireDate  *string
+	// Profile replaces the whole profile with the one of this employee when it is not nil.
+	Profile *Employee
+	// CustomFields replaces the whole set of custom fields when it is not nil.
+	CustomFields map[string]interface{}
+	Version      int
+}
+
+func (p *EmployeePatch) IsEmpty() bool {
+	return p.FirstName == nil && p.LastName == nil && p.Email == nil && p.HireDate == nil && p.Profile == nil && p.CustomFields == nil
+}
+
+// HierarchyEmployee is an employee reached by walking the management tree,
+// Depth counts the levels from the employee the walk started at.
+type HierarchyEmployee struct {
+	Employee
+	Depth int
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IdempotencyKey is a request made with an Idempotency-Key header and, once
+// it completed, the response it got. StatusCode is 0 while it is in progress.
+type IdempotencyKey struct {
+	ActorID      string
+	Key          string
+	Method       string
+	Path         string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
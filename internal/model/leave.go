@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// Accrual periods of a leave type.
+const (
+	AccrualPeriodMonthly = "monthly"
+	AccrualPeriodYearly  = "yearly"
+)
+
+// Statuses of a leave request.
+const (
+	LeaveStatusPending  = "pending"
+	LeaveStatusApproved = "approved"
+	LeaveStatusRejected = "rejected"
+)
+
+// LeaveType credits AccrualDays every AccrualPeriod, a balance does not
+// accrue past MaxBalance when it is set.
+type LeaveType struct {
+	ID            int
+	Name          string
+	AccrualDays   float64
+	AccrualPeriod string
+	MaxBalance    *float64
+	CreatedAt     time.Time
+}
+
+// LeaveBalance is the leave left to an employee, credited up to AccruedThrough.
+type LeaveBalance struct {
+	EmployeeID     int
+	LeaveTypeID    int
+	Balance        float64
+	AccruedThrough string
+}
+
+// LeaveRequest asks for the working days between StartDate and the inclusive
+// EndDate. The decision fields are set once it is approved or rejected.
+type LeaveRequest struct {
+	ID           int64
+	EmployeeID   int
+	LeaveTypeID  int
+	StartDate    string
+	EndDate      string
+	Days         float64
+	Reason       string
+	Status       string
+	DecidedBy    *string
+	DecidedAt    *time.Time
+	DecisionNote *string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Position changes recorded in the position history.
+const (
+	PositionChangeInitial   = "initial"
+	PositionChangePromotion = "promotion"
+	PositionChangeDemotion  = "demotion"
+	PositionChangeTransfer  = "transfer"
+)
+
+type Position struct {
+	ID        int
+	Title     string
+	Grade     int
+	JobFamily string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PositionFilter struct {
+	Title     string
+	JobFamily string
+	Limit     int
+	Offset    int
+}
+
+// PositionAssignment is one entry of the position history of an employee,
+// EndDate is nil on the current position. Title, Grade and JobFamily are read
+// from the position.
+type PositionAssignment struct {
+	ID            int64
+	EmployeeID    int
+	PositionID    int
+	Title         string
+	Grade         int
+	JobFamily     string
+	DepartmentID  *int
+	Change        string
+	EffectiveDate string
+	EndDate       *string
+	CreatedAt     time.Time
+}
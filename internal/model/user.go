@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type User struct {
+	ID           int
+	Email        string
+	PasswordHash string
+	Role         string
+	EmployeeID   *int
+	// DisabledAt is set while the employee is deleted or terminated, a
+	// disabled user cannot log in or refresh a token.
+	DisabledAt *time.Time
+}
+
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
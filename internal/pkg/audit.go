@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"context"
+	"employee/internal/model"
+	"encoding/json"
+)
+
+// NewAudit builds the audit entry of a change to an employee. Before and
+// after are the JSON views of the changed record, nil when it did not exist
+// or no longer exists. The actor and the request are read from the context.
+func NewAudit(ctx context.Context, action string, employeeID int, before interface{}, after interface{}) (*model.Audit, error) {
+	audit := &model.Audit{
+		EmployeeID: employeeID,
+		Action:     action,
+		ActorName:  "system",
+		RequestID:  RequestIDFromContext(ctx),
+	}
+
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		audit.ActorID = claims.Subject
+		audit.ActorName = claims.Name
+		audit.ActorRole = claims.Role
+	}
+
+	var err error
+	if before != nil {
+		if audit.Before, err = json.Marshal(before); err != nil {
+			return nil, err
+		}
+	}
+
+	if after != nil {
+		if audit.After, err = json.Marshal(after); err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := DiffJSON(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if audit.Diff, err = json.Marshal(diff); err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor serializes a keyset position into an opaque, URL safe token.
+func EncodeCursor(cursor interface{}) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor reverses EncodeCursor into the given destination.
+func DecodeCursor(token string, cursor interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, cursor)
+}
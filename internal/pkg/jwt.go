@@ -1,15 +1,26 @@
 package pkg
 
 import (
+	"context"
+	"employee/internal/constant"
+	"errors"
 	"github.com/golang-jwt/jwt/v5"
+	"strconv"
 	"time"
 )
 
-type jwtClaim struct {
-	Name      string `json:"name"`
-	Phone     string `json:"phone"`
-	Role      string `json:"role"`
-	Timestamp int64  `json:"time"`
+type claimsCtxKey struct{}
+
+const AccessTokenTTL = time.Hour * 24
+
+var ErrInvalidClaims = errors.New(constant.MsgParseErr)
+
+type JWTClaim struct {
+	Name       string `json:"name"`
+	Phone      string `json:"phone"`
+	Role       string `json:"role"`
+	EmployeeID int    `json:"employee_id,omitempty"`
+	Timestamp  int64  `json:"time"`
 	jwt.RegisteredClaims
 }
 
@@ -21,13 +32,17 @@ type MyCustomClaims struct {
 func GenerateJWT(payload map[string]string, jwtKey string) (string, error) {
 	jwtKeyByte := []byte(jwtKey)
 
-	claims := jwtClaim{
+	employeeID, _ := strconv.Atoi(payload["employee_id"])
+
+	claims := JWTClaim{
 		payload["name"],
 		payload["phone"],
 		payload["role"],
+		employeeID,
 		time.Now().Unix(),
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+			Subject:   payload["sub"],
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 		},
 	}
 
@@ -35,3 +50,31 @@ func GenerateJWT(payload map[string]string, jwtKey string) (string, error) {
 	tokenString, err := token.SignedString(jwtKeyByte)
 	return tokenString, err
 }
+
+// ParseJWT verifies an HS256 token minted by GenerateJWT and returns its claims.
+func ParseJWT(tokenString string, jwtKey string) (*JWTClaim, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaim{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*JWTClaim)
+	if !ok {
+		return nil, ErrInvalidClaims
+	}
+
+	return claims, nil
+}
+
+func ContextWithClaims(ctx context.Context, claims *JWTClaim) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by the JWT middleware, or nil
+// when the request was not authenticated.
+func ClaimsFromContext(ctx context.Context) *JWTClaim {
+	claims, _ := ctx.Value(claimsCtxKey{}).(*JWTClaim)
+	return claims
+}
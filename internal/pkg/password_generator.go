@@ -1,12 +1,288 @@
 package pkg
 
-import "math/rand"
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
 
-func GeneratePassword() string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	password := make([]byte, 4)
-	for i := range password {
-		password[i] = charset[rand.Intn(len(charset))]
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!@#$%^&*()-_=+[]{};:,.?/"
+
+	// ambiguousChars are easily confused when a password is read aloud or copied by hand.
+	ambiguousChars = "Il1O0o|;:,."
+
+	MinPasswordLength  = 8
+	MinPassphraseWords = 3
+)
+
+var ErrInvalidPasswordPolicy = errors.New("invalid password policy")
+
+// PasswordPolicy describes the shape of a generated password.
+type PasswordPolicy struct {
+	Length           int
+	RequireLower     bool
+	RequireUpper     bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	ExcludeAmbiguous bool
+
+	// Passphrase generates Words dictionary words joined by Separator instead
+	// of random characters. RequireUpper and RequireDigit still apply.
+	Passphrase bool
+	Words      int
+	Separator  string
+}
+
+// DefaultPasswordPolicy is used for the initial credentials handed to new hires.
+var DefaultPasswordPolicy = PasswordPolicy{
+	Length:           16,
+	RequireLower:     true,
+	RequireUpper:     true,
+	RequireDigit:     true,
+	RequireSymbol:    true,
+	ExcludeAmbiguous: true,
+}
+
+type PasswordStrength struct {
+	Entropy float64 `json:"entropy_bits"`
+	Score   int     `json:"score"`
+	Label   string  `json:"label"`
+}
+
+// GeneratePassword builds a password from crypto/rand that satisfies the policy.
+func GeneratePassword(policy PasswordPolicy) (string, error) {
+	if policy.Passphrase {
+		return generatePassphrase(policy)
+	}
+
+	var classes []string
+	for _, class := range []struct {
+		required bool
+		chars    string
+	}{
+		{policy.RequireLower, lowerChars},
+		{policy.RequireUpper, upperChars},
+		{policy.RequireDigit, digitChars},
+		{policy.RequireSymbol, symbolChars},
+	} {
+		if class.required {
+			classes = append(classes, filterAmbiguous(class.chars, policy.ExcludeAmbiguous))
+		}
+	}
+
+	if len(classes) == 0 {
+		classes = []string{
+			filterAmbiguous(lowerChars, policy.ExcludeAmbiguous),
+			filterAmbiguous(upperChars, policy.ExcludeAmbiguous),
+			filterAmbiguous(digitChars, policy.ExcludeAmbiguous),
+		}
+	}
+
+	if policy.Length < MinPasswordLength || policy.Length < len(classes) {
+		return "", ErrInvalidPasswordPolicy
+	}
+
+	charset := strings.Join(classes, "")
+	password := make([]byte, 0, policy.Length)
+
+	// one character of every required class, the rest from the whole charset
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	for len(password) < policy.Length {
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	if err := shuffle(password); err != nil {
+		return "", err
+	}
+
+	return string(password), nil
+}
+
+// EstimatePasswordStrength approximates the entropy of a password from the
+// character classes it uses, or from the word list when it is a passphrase.
+func EstimatePasswordStrength(password string) PasswordStrength {
+	entropy := passphraseEntropy(password)
+	if entropy == 0 {
+		entropy = charsetEntropy(password)
+	}
+
+	strength := PasswordStrength{Entropy: math.Round(entropy*100) / 100}
+
+	switch {
+	case entropy < 28:
+		strength.Score, strength.Label = 0, "very weak"
+	case entropy < 36:
+		strength.Score, strength.Label = 1, "weak"
+	case entropy < 60:
+		strength.Score, strength.Label = 2, "fair"
+	case entropy < 100:
+		strength.Score, strength.Label = 3, "strong"
+	default:
+		strength.Score, strength.Label = 4, "very strong"
+	}
+
+	return strength
+}
+
+func generatePassphrase(policy PasswordPolicy) (string, error) {
+	words := policy.Words
+	if words == 0 {
+		words = 4
+	}
+
+	if words < MinPassphraseWords {
+		return "", ErrInvalidPasswordPolicy
+	}
+
+	separator := policy.Separator
+	if separator == "" {
+		separator = "-"
+	}
+
+	parts := make([]string, words)
+	for i := range parts {
+		n, err := randomInt(len(passphraseWords))
+		if err != nil {
+			return "", err
+		}
+		parts[i] = passphraseWords[n]
+	}
+
+	if policy.RequireUpper {
+		n, err := randomInt(words)
+		if err != nil {
+			return "", err
+		}
+		parts[n] = strings.ToUpper(parts[n][:1]) + parts[n][1:]
+	}
+
+	if policy.RequireDigit {
+		c, err := randomChar(filterAmbiguous(digitChars, policy.ExcludeAmbiguous))
+		if err != nil {
+			return "", err
+		}
+		parts[words-1] += string(c)
+	}
+
+	return strings.Join(parts, separator), nil
+}
+
+func charsetEntropy(password string) float64 {
+	if password == "" {
+		return 0
+	}
+
+	var hasLower, hasUpper, hasDigit, hasOther bool
+	distinct := map[rune]struct{}{}
+	for _, r := range password {
+		distinct[r] = struct{}{}
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasOther = true
+		}
+	}
+
+	pool := 0
+	if hasLower {
+		pool += len(lowerChars)
+	}
+	if hasUpper {
+		pool += len(upperChars)
+	}
+	if hasDigit {
+		pool += len(digitChars)
+	}
+	if hasOther {
+		pool += len(symbolChars)
+	}
+
+	// repeated characters add little, so only distinct ones are counted in full
+	length := float64(len(distinct)) + float64(len([]rune(password))-len(distinct))/4
+
+	return length * math.Log2(float64(pool))
+}
+
+func passphraseEntropy(password string) float64 {
+	fields := strings.FieldsFunc(password, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(fields) < MinPassphraseWords {
+		return 0
+	}
+
+	for _, field := range fields {
+		word := strings.ToLower(strings.TrimRightFunc(field, unicode.IsDigit))
+		if _, ok := passphraseIndex[word]; !ok {
+			return 0
+		}
+	}
+
+	return float64(len(fields)) * math.Log2(float64(len(passphraseWords)))
+}
+
+func filterAmbiguous(chars string, exclude bool) string {
+	if !exclude {
+		return chars
+	}
+
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(ambiguousChars, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
+
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n.Int64()), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
 	}
-	return string(password)
+
+	return chars[n], nil
+}
+
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return nil
 }
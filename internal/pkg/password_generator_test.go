@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      PasswordPolicy
+		checkReturn func(password string, err error)
+	}{
+		{
+			name:   "default policy contains every class",
+			policy: DefaultPasswordPolicy,
+			checkReturn: func(password string, err error) {
+				require.NoError(t, err)
+				assert.Len(t, password, 16)
+				assert.True(t, strings.ContainsAny(password, lowerChars))
+				assert.True(t, strings.ContainsAny(password, upperChars))
+				assert.True(t, strings.ContainsAny(password, digitChars))
+				assert.True(t, strings.ContainsAny(password, symbolChars))
+				assert.False(t, strings.ContainsAny(password, ambiguousChars))
+			},
+		},
+		{
+			name:   "digits only",
+			policy: PasswordPolicy{Length: 10, RequireDigit: true},
+			checkReturn: func(password string, err error) {
+				require.NoError(t, err)
+				assert.Len(t, password, 10)
+				assert.Empty(t, strings.Trim(password, digitChars))
+			},
+		},
+		{
+			name:   "too short",
+			policy: PasswordPolicy{Length: 4},
+			checkReturn: func(password string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPasswordPolicy)
+				assert.Empty(t, password)
+			},
+		},
+		{
+			name:   "passphrase",
+			policy: PasswordPolicy{Passphrase: true, Words: 5, Separator: ".", RequireDigit: true, RequireUpper: true},
+			checkReturn: func(password string, err error) {
+				require.NoError(t, err)
+				assert.Len(t, strings.Split(password, "."), 5)
+				assert.True(t, strings.ContainsAny(password, digitChars))
+				assert.True(t, strings.ContainsAny(password, upperChars))
+			},
+		},
+		{
+			name:   "passphrase with too few words",
+			policy: PasswordPolicy{Passphrase: true, Words: 2},
+			checkReturn: func(password string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPasswordPolicy)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkReturn(GeneratePassword(tc.policy))
+		})
+	}
+}
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	testCases := []struct {
+		name          string
+		password      string
+		expectedScore int
+	}{
+		{name: "empty", password: "", expectedScore: 0},
+		{name: "short lowercase", password: "abcd", expectedScore: 0},
+		{name: "repeated characters", password: "aaaaaaaaaaaa", expectedScore: 0},
+		{name: "mixed eight characters", password: "aB3$xY7!", expectedScore: 2},
+		{name: "four word passphrase", password: "bank-cake-fish-gold", expectedScore: 1},
+		{name: "generated default", password: "Kp7#vR2!mQ9@wZ4$", expectedScore: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedScore, EstimatePasswordStrength(tc.password).Score)
+		})
+	}
+}
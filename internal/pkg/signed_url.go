@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignURL appends an expiry and an HMAC of the path and expiry to the path,
+// so the link grants access to that path only until it expires.
+func SignURL(secret string, path string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", urlSignature(secret, path, expires))
+
+	return path + "?" + query.Encode()
+}
+
+// VerifyURL reports whether the signature was made by SignURL for the path
+// and expiry, and the expiry is not past.
+func VerifyURL(secret string, path string, expires int64, signature string, now time.Time) bool {
+	if now.Unix() > expires {
+		return false
+	}
+
+	expected := urlSignature(secret, path, strconv.FormatInt(expires, 10))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func urlSignature(secret string, path string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s", path, expires)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignURL(t *testing.T) {
+	now := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	signed := SignURL("secret", "/documents/7/download", now.Add(15*time.Minute))
+
+	path, rawQuery, found := strings.Cut(signed, "?")
+	require.True(t, found)
+	assert.Equal(t, "/documents/7/download", path)
+
+	query, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := query.Get("signature")
+
+	assert.True(t, VerifyURL("secret", path, expires, signature, now))
+	assert.False(t, VerifyURL("secret", path, expires, signature, now.Add(16*time.Minute)), "expired")
+	assert.False(t, VerifyURL("secret", "/documents/8/download", expires, signature, now), "other path")
+	assert.False(t, VerifyURL("secret", path, expires+3600, signature, now), "extended expiry")
+	assert.False(t, VerifyURL("other", path, expires, signature, now), "other secret")
+}
@@ -0,0 +1,36 @@
+package pkg
+
+// passphraseWords is a fixed list of 256 short, common words so every word of
+// a generated passphrase carries exactly 8 bits of entropy.
+var passphraseWords = []string{
+	"able", "acid", "aged", "also", "area", "army", "away", "baby", "back", "ball", "band", "bank",
+	"base", "bath", "bear", "beat", "been", "beer", "bell", "belt", "best", "bird", "blow", "blue",
+	"boat", "body", "bone", "book", "boot", "born", "boss", "both", "bowl", "bulk", "burn", "bush",
+	"busy", "cake", "call", "calm", "came", "camp", "card", "care", "cart", "case", "cash", "cast",
+	"cell", "chef", "chip", "city", "clay", "club", "coal", "coat", "code", "cold", "come", "cook",
+	"cool", "cope", "copy", "core", "corn", "cost", "crew", "crop", "dark", "data", "date", "dawn",
+	"deal", "dear", "debt", "deck", "deep", "desk", "dial", "diet", "dish", "disk", "dock", "door",
+	"dose", "down", "draw", "drop", "drum", "dual", "duck", "dust", "duty", "each", "earn", "ease",
+	"east", "easy", "edge", "else", "even", "ever", "exit", "face", "fact", "fair", "fall", "farm",
+	"fast", "fate", "fear", "feed", "feel", "file", "fill", "film", "find", "fine", "fire", "firm",
+	"fish", "five", "flag", "flat", "flow", "folk", "food", "foot", "fork", "form", "fort", "four",
+	"free", "frog", "fuel", "full", "fund", "gain", "game", "gate", "gear", "gift", "girl", "give",
+	"glad", "glow", "goal", "gold", "golf", "good", "gray", "grid", "grow", "gulf", "hair", "half",
+	"hall", "hand", "hang", "hard", "harm", "head", "heat", "held", "help", "herb", "hero", "high",
+	"hill", "hint", "hold", "hole", "home", "hook", "hope", "horn", "host", "hour", "huge", "idea",
+	"inch", "iron", "item", "jazz", "join", "joke", "jump", "jury", "keen", "keep", "kick", "kind",
+	"king", "kite", "knee", "knot", "lake", "lamp", "land", "lane", "last", "late", "lawn", "lead",
+	"leaf", "lean", "left", "lend", "lens", "life", "lift", "like", "lime", "line", "link", "lion",
+	"list", "live", "load", "loan", "lock", "loft", "long", "look", "loop", "lord", "loud", "love",
+	"luck", "lung", "made", "mail", "main", "make", "mall", "many", "mark", "mass", "meal", "meat",
+	"mild", "milk", "mill", "mind", "mine", "mint", "mode", "moon", "more", "most", "move", "much",
+	"must", "nail", "name", "navy",
+}
+
+var passphraseIndex = func() map[string]struct{} {
+	index := make(map[string]struct{}, len(passphraseWords))
+	for _, word := range passphraseWords {
+		index[word] = struct{}{}
+	}
+	return index
+}()
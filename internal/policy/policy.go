@@ -0,0 +1,299 @@
+package policy
+
+import (
+	"context"
+	"employee/internal/constant"
+)
+
+type Scope int
+
+const (
+	// ScopeNone denies the route.
+	ScopeNone Scope = iota
+	// ScopeAll grants the route on every employee.
+	ScopeAll
+	// ScopeReports grants the route only on the caller's direct reports.
+	ScopeReports
+	// ScopeSelf grants the route only on the caller's own employee record.
+	ScopeSelf
+)
+
+type scopeCtxKey struct{}
+
+// Rule maps a role to the scope it is granted on a route.
+type Rule map[string]Scope
+
+// routes is keyed by "<METHOD> <echo route path>" as registered in server.ConfigureRoutes.
+var routes = map[string]Rule{
+	"GET /employees": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /employees/export": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /employees/:employee_id": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /employees": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/import": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"PUT /employees/:employee_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"PATCH /employees/:employee_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"DELETE /employees/:employee_id": {
+		constant.RoleAdmin: ScopeAll,
+	},
+	"POST /employees/:employee_id/restore": {
+		constant.RoleAdmin: ScopeAll,
+	},
+	"GET /employees/:employee_id/reports": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /employees/:employee_id/chain": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /employees/:employee_id/positions": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /employees/:employee_id/positions": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /employees/:employee_id/compensations": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/:employee_id/compensations": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/:employee_id/status": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/:employee_id/terminate": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/:employee_id/rehire": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /employees/:employee_id/leave-balances": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"GET /employees/:employee_id/leave-requests": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"POST /employees/:employee_id/leave-requests": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeSelf,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"POST /employees/:employee_id/clock-in": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeSelf,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"POST /employees/:employee_id/clock-out": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeSelf,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"GET /employees/:employee_id/attendance/daily": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"GET /employees/:employee_id/attendance/weekly": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeReports,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"GET /attendance/report": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"POST /employees/:employee_id/documents": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /employees/:employee_id/documents": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeSelf,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"GET /employees/:employee_id/documents/:document_id": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeSelf,
+		constant.RoleViewer:  ScopeSelf,
+	},
+	"DELETE /employees/:employee_id/documents/:document_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /org-chart": {
+		constant.RoleAdmin:  ScopeAll,
+		constant.RoleHR:     ScopeAll,
+		constant.RoleViewer: ScopeAll,
+	},
+	"GET /employees/:employee_id/audit": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /audit": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"GET /departments": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /departments/:department_id": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /departments": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"PUT /departments/:department_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"DELETE /departments/:department_id": {
+		constant.RoleAdmin: ScopeAll,
+	},
+	"GET /positions": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /positions/:position_id": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /positions": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"PUT /positions/:position_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"DELETE /positions/:position_id": {
+		constant.RoleAdmin: ScopeAll,
+	},
+	"GET /custom-fields": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"GET /custom-fields/:custom_field_id": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /custom-fields": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"PUT /custom-fields/:custom_field_id": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	"DELETE /custom-fields/:custom_field_id": {
+		constant.RoleAdmin: ScopeAll,
+	},
+	"GET /leave-types": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleHR:      ScopeAll,
+		constant.RoleManager: ScopeAll,
+		constant.RoleViewer:  ScopeAll,
+	},
+	"POST /leave-types": {
+		constant.RoleAdmin: ScopeAll,
+		constant.RoleHR:    ScopeAll,
+	},
+	// the use case checks the caller manages the employee of the request
+	"POST /leave-requests/:leave_request_id/approve": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleManager: ScopeReports,
+	},
+	"POST /leave-requests/:leave_request_id/reject": {
+		constant.RoleAdmin:   ScopeAll,
+		constant.RoleManager: ScopeReports,
+	},
+}
+
+// Authorize returns the scope a role is granted on a route. Routes missing
+// from the table are denied for everyone.
+func Authorize(role string, method string, path string) (Scope, bool) {
+	rule, ok := routes[method+" "+path]
+	if !ok {
+		return ScopeNone, false
+	}
+
+	scope := rule[role]
+	return scope, scope != ScopeNone
+}
+
+func ContextWithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeCtxKey{}, scope)
+}
+
+// ScopeFromContext returns the scope granted by the authorization middleware.
+func ScopeFromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeCtxKey{}).(Scope)
+	return scope
+}
@@ -0,0 +1,285 @@
+package policy
+
+import (
+	"employee/internal/constant"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	testCases := []struct {
+		name          string
+		role          string
+		method        string
+		path          string
+		expectedScope Scope
+		expectedOK    bool
+	}{
+		{
+			name:          "viewer can list employees",
+			role:          constant.RoleViewer,
+			method:        http.MethodGet,
+			path:          "/employees",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "viewer cannot create employee",
+			role:          constant.RoleViewer,
+			method:        http.MethodPost,
+			path:          "/employees",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "hr can update employee",
+			role:          constant.RoleHR,
+			method:        http.MethodPut,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "hr cannot delete employee",
+			role:          constant.RoleHR,
+			method:        http.MethodDelete,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "admin can delete employee",
+			role:          constant.RoleAdmin,
+			method:        http.MethodDelete,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager only sees reports",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeReports,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot update employee",
+			role:          constant.RoleManager,
+			method:        http.MethodPut,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "manager only walks the reports of their reports",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id/reports",
+			expectedScope: ScopeReports,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot read the org chart",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/org-chart",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "manager can read departments",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/departments/:department_id",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "hr cannot delete department",
+			role:          constant.RoleHR,
+			method:        http.MethodDelete,
+			path:          "/departments/:department_id",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "manager reads the position history of reports only",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id/positions",
+			expectedScope: ScopeReports,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot assign positions",
+			role:          constant.RoleManager,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/positions",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "hr can create positions",
+			role:          constant.RoleHR,
+			method:        http.MethodPost,
+			path:          "/positions",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot read compensations",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id/compensations",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "hr can add compensations",
+			role:          constant.RoleHR,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/compensations",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "hr can terminate employees",
+			role:          constant.RoleHR,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/terminate",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot rehire employees",
+			role:          constant.RoleManager,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/rehire",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "viewer files their own leave",
+			role:          constant.RoleViewer,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/leave-requests",
+			expectedScope: ScopeSelf,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager approves leave of reports",
+			role:          constant.RoleManager,
+			method:        http.MethodPost,
+			path:          "/leave-requests/:leave_request_id/approve",
+			expectedScope: ScopeReports,
+			expectedOK:    true,
+		},
+		{
+			name:          "hr cannot approve leave",
+			role:          constant.RoleHR,
+			method:        http.MethodPost,
+			path:          "/leave-requests/:leave_request_id/approve",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "viewer clocks in for themselves",
+			role:          constant.RoleViewer,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/clock-in",
+			expectedScope: ScopeSelf,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager reads daily attendance of reports",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id/attendance/daily",
+			expectedScope: ScopeReports,
+			expectedOK:    true,
+		},
+		{
+			name:          "manager cannot read the attendance report",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/attendance/report",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "manager reads only their own documents",
+			role:          constant.RoleManager,
+			method:        http.MethodGet,
+			path:          "/employees/:employee_id/documents",
+			expectedScope: ScopeSelf,
+			expectedOK:    true,
+		},
+		{
+			name:          "viewer cannot upload documents",
+			role:          constant.RoleViewer,
+			method:        http.MethodPost,
+			path:          "/employees/:employee_id/documents",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "viewer can read custom fields",
+			role:          constant.RoleViewer,
+			method:        http.MethodGet,
+			path:          "/custom-fields",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "hr cannot delete custom fields",
+			role:          constant.RoleHR,
+			method:        http.MethodDelete,
+			path:          "/custom-fields/:custom_field_id",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "unknown role is denied",
+			role:          "intern",
+			method:        http.MethodGet,
+			path:          "/employees",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "hr can patch employees",
+			role:          constant.RoleHR,
+			method:        http.MethodPatch,
+			path:          "/employees/:employee_id",
+			expectedScope: ScopeAll,
+			expectedOK:    true,
+		},
+		{
+			name:          "viewer cannot import employees",
+			role:          constant.RoleViewer,
+			method:        http.MethodPost,
+			path:          "/employees/import",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+		{
+			name:          "route missing from the table is denied",
+			role:          constant.RoleAdmin,
+			method:        http.MethodPut,
+			path:          "/employees",
+			expectedScope: ScopeNone,
+			expectedOK:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, ok := Authorize(tc.role, tc.method, tc.path)
+
+			assert.Equal(t, tc.expectedScope, scope)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
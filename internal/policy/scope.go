@@ -0,0 +1,50 @@
+package policy
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/constant"
+	"employee/internal/pkg"
+	"employee/internal/transport"
+)
+
+// EmployeeReader reads the employee a scope is checked against, the employee
+// use case implements it.
+type EmployeeReader interface {
+	GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*transport.EmployeeRes, error)
+}
+
+// CheckEmployee applies the scope granted on a route to the employee the
+// request is about. ScopeSelf only reaches the caller's own employee record,
+// ScopeReports also the records of their direct reports, read with employees.
+// Other scopes are not restricted.
+func CheckEmployee(ctx context.Context, employeeID int, employees EmployeeReader) error {
+	scope := ScopeFromContext(ctx)
+	if scope != ScopeSelf && scope != ScopeReports {
+		return nil
+	}
+
+	claims := pkg.ClaimsFromContext(ctx)
+	if claims == nil || claims.EmployeeID == 0 {
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	if claims.EmployeeID == employeeID {
+		return nil
+	}
+
+	if scope == ScopeSelf || employees == nil {
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	employee, err := employees.GetEmployeeByID(ctx, employeeID, false)
+	if err != nil {
+		return err
+	}
+
+	if employee.ManagerID == nil || *employee.ManagerID != claims.EmployeeID {
+		return apperror.Forbidden(constant.MsgForbidden)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/pkg"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type employeeReaderStub map[int]*transport.EmployeeRes
+
+func (s employeeReaderStub) GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*transport.EmployeeRes, error) {
+	employee, ok := s[employeeID]
+	if !ok {
+		return nil, apperror.NotFound("employee not found")
+	}
+
+	return employee, nil
+}
+
+func TestCheckEmployee(t *testing.T) {
+	managerID := 1
+	employees := employeeReaderStub{
+		2: {ID: 2, ManagerID: &managerID},
+		3: {ID: 3},
+	}
+
+	testCases := []struct {
+		name       string
+		scope      Scope
+		employeeID int
+		reader     EmployeeReader
+		expectedOK bool
+	}{
+		{
+			name:       "scope all is not restricted",
+			scope:      ScopeAll,
+			employeeID: 3,
+			reader:     employees,
+			expectedOK: true,
+		},
+		{
+			name:       "self reaches the own record",
+			scope:      ScopeSelf,
+			employeeID: 1,
+			reader:     employees,
+			expectedOK: true,
+		},
+		{
+			name:       "self cannot reach a direct report",
+			scope:      ScopeSelf,
+			employeeID: 2,
+			reader:     employees,
+			expectedOK: false,
+		},
+		{
+			name:       "reports reaches a direct report",
+			scope:      ScopeReports,
+			employeeID: 2,
+			reader:     employees,
+			expectedOK: true,
+		},
+		{
+			name:       "reports cannot reach another employee",
+			scope:      ScopeReports,
+			employeeID: 3,
+			reader:     employees,
+			expectedOK: false,
+		},
+		{
+			name:       "reports without a reader only reaches the own record",
+			scope:      ScopeReports,
+			employeeID: 2,
+			reader:     nil,
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := pkg.ContextWithClaims(context.Background(), &pkg.JWTClaim{EmployeeID: managerID})
+			ctx = ContextWithScope(ctx, tc.scope)
+
+			err := CheckEmployee(ctx, tc.employeeID, tc.reader)
+
+			if tc.expectedOK {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, apperror.IsCode(err, apperror.CodeForbidden))
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package attendance
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.attendance")
+)
+
+// ErrAlreadyClockedIn is returned when the employee has an open session.
+var ErrAlreadyClockedIn = apperror.Conflict("clock_in", "employee is already clocked in")
+
+const openSessionKey = "attendance_open_key"
+
+const attendanceColumns = `id, employee_id, to_char(work_date, 'YYYY-MM-DD'), clock_in, clock_out, created_at`
+
+// workedSeconds sums the closed sessions, an open session counts once it ends.
+const workedSeconds = `COALESCE(SUM(EXTRACT(EPOCH FROM (a.clock_out - a.clock_in))), 0)`
+
+type AttendanceRepo interface {
+	ClockIn(ctx context.Context, attendance *model.Attendance) error
+	ClockOut(ctx context.Context, employeeID int, at time.Time) (*model.Attendance, error)
+	GetDailyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceDay, error)
+	GetWeeklyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceWeek, error)
+	GetEmployees(ctx context.Context, filter model.AttendanceFilter) ([]*model.Employee, error)
+	GetApprovedLeave(ctx context.Context, filter model.AttendanceFilter) ([]*model.LeaveRequest, error)
+}
+
+type attendanceRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoAttendance(sqlConn *sql.DB) AttendanceRepo {
+	return &attendanceRepo{sqlConn: sqlConn}
+}
+
+// ClockIn opens a session and sets its id and created_at. The partial unique
+// index on the open session rejects a second one with ErrAlreadyClockedIn.
+func (a *attendanceRepo) ClockIn(ctx context.Context, attendance *model.Attendance) error {
+	rLog := logRepo.WithField("function", "ClockIn")
+
+	query := `INSERT INTO attendance (employee_id, work_date, clock_in) values ($1, $2, $3) returning id, created_at`
+
+	err := repository.Conn(ctx, a.sqlConn).QueryRowContext(ctx, query, attendance.EmployeeID, attendance.WorkDate, attendance.ClockIn).
+		Scan(&attendance.ID, &attendance.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when clock in got: %s", err.Error())
+		if repository.IsUniqueViolation(err, openSessionKey) {
+			return ErrAlreadyClockedIn
+		}
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// ClockOut closes the open session of the employee at the given time, it
+// returns nil when the employee is not clocked in.
+func (a *attendanceRepo) ClockOut(ctx context.Context, employeeID int, at time.Time) (*model.Attendance, error) {
+	rLog := logRepo.WithField("function", "ClockOut")
+
+	attendance := &model.Attendance{}
+
+	query := `UPDATE attendance SET clock_out = GREATEST($2, clock_in) WHERE employee_id = $1 AND clock_out IS NULL
+		returning ` + attendanceColumns
+
+	err := repository.Conn(ctx, a.sqlConn).QueryRowContext(ctx, query, employeeID, at).
+		Scan(&attendance.ID, &attendance.EmployeeID, &attendance.WorkDate, &attendance.ClockIn, &attendance.ClockOut, &attendance.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when clock out got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return attendance, nil
+}
+
+// GetDailyHours sums the sessions of every employee per work date within the
+// filter, by employee then date. Deleted employees are left out.
+func (a *attendanceRepo) GetDailyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceDay, error) {
+	rLog := logRepo.WithField("function", "GetDailyHours")
+
+	var days []*model.AttendanceDay
+
+	query := `select a.employee_id, to_char(a.work_date, 'YYYY-MM-DD'), MIN(a.clock_in), MAX(a.clock_out), ` + workedSeconds + `,
+		BOOL_OR(a.clock_out IS NULL)
+		from attendance a join employees e on e.id = a.employee_id
+		where a.work_date between $1 and $2 and ($3 = 0 or a.employee_id = $3) and ($4 = 0 or e.department_id = $4) and e.deleted_at IS NULL
+		group by a.employee_id, a.work_date order by a.employee_id ASC, a.work_date ASC`
+
+	rows, err := repository.Conn(ctx, a.sqlConn).QueryContext(ctx, query, filter.From, filter.To, filter.EmployeeID, filter.DepartmentID)
+	if err != nil {
+		rLog.Errorf("error when get daily hours got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.AttendanceDay{}
+		err := rows.Scan(&temp.EmployeeID, &temp.WorkDate, &temp.FirstClockIn, &temp.LastClockOut, &temp.WorkedSeconds, &temp.Open)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		days = append(days, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return days, nil
+}
+
+// GetWeeklyHours sums the sessions of every employee per ISO week within the
+// filter, by employee then week. Deleted employees are left out.
+func (a *attendanceRepo) GetWeeklyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceWeek, error) {
+	rLog := logRepo.WithField("function", "GetWeeklyHours")
+
+	var weeks []*model.AttendanceWeek
+
+	query := `select a.employee_id, to_char(date_trunc('week', a.work_date), 'YYYY-MM-DD') as week_start, ` + workedSeconds + `,
+		COUNT(DISTINCT a.work_date)
+		from attendance a join employees e on e.id = a.employee_id
+		where a.work_date between $1 and $2 and ($3 = 0 or a.employee_id = $3) and ($4 = 0 or e.department_id = $4) and e.deleted_at IS NULL
+		group by a.employee_id, week_start order by a.employee_id ASC, week_start ASC`
+
+	rows, err := repository.Conn(ctx, a.sqlConn).QueryContext(ctx, query, filter.From, filter.To, filter.EmployeeID, filter.DepartmentID)
+	if err != nil {
+		rLog.Errorf("error when get weekly hours got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.AttendanceWeek{}
+		err := rows.Scan(&temp.EmployeeID, &temp.WeekStart, &temp.WorkedSeconds, &temp.DaysWorked)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		weeks = append(weeks, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return weeks, nil
+}
+
+// GetEmployees returns the employees of the filter employed for at least a
+// day of its range, by id. Only the fields attendance needs are set.
+func (a *attendanceRepo) GetEmployees(ctx context.Context, filter model.AttendanceFilter) ([]*model.Employee, error) {
+	rLog := logRepo.WithField("function", "GetEmployees")
+
+	var employees []*model.Employee
+
+	query := `select id, first_name, last_name, department_id, to_char(hire_date, 'YYYY-MM-DD'), to_char(termination_date, 'YYYY-MM-DD'), status
+		from employees
+		where deleted_at IS NULL and hire_date <= $2 and (termination_date IS NULL or termination_date >= $1)
+		and ($3 = 0 or id = $3) and ($4 = 0 or department_id = $4)
+		order by id ASC`
+
+	rows, err := repository.Conn(ctx, a.sqlConn).QueryContext(ctx, query, filter.From, filter.To, filter.EmployeeID, filter.DepartmentID)
+	if err != nil {
+		rLog.Errorf("error when get employees got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Employee{}
+		err := rows.Scan(&temp.ID, &temp.FirstName, &temp.LastName, &temp.DepartmentID, &temp.HireDate, &temp.TerminationDate, &temp.Status)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		employees = append(employees, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return employees, nil
+}
+
+// GetApprovedLeave returns the approved leave of the filter sharing a day with
+// its range, only the employee and the dates are set.
+func (a *attendanceRepo) GetApprovedLeave(ctx context.Context, filter model.AttendanceFilter) ([]*model.LeaveRequest, error) {
+	rLog := logRepo.WithField("function", "GetApprovedLeave")
+
+	var requests []*model.LeaveRequest
+
+	query := `select l.employee_id, to_char(l.start_date, 'YYYY-MM-DD'), to_char(l.end_date, 'YYYY-MM-DD')
+		from leave_requests l join employees e on e.id = l.employee_id
+		where l.status = 'approved' and l.start_date <= $2 and l.end_date >= $1
+		and ($3 = 0 or l.employee_id = $3) and ($4 = 0 or e.department_id = $4)
+		order by l.employee_id ASC, l.start_date ASC`
+
+	rows, err := repository.Conn(ctx, a.sqlConn).QueryContext(ctx, query, filter.From, filter.To, filter.EmployeeID, filter.DepartmentID)
+	if err != nil {
+		rLog.Errorf("error when get approved leave got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.LeaveRequest{}
+		err := rows.Scan(&temp.EmployeeID, &temp.StartDate, &temp.EndDate)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		requests = append(requests, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return requests, nil
+}
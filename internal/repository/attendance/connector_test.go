@@ -0,0 +1,181 @@
+package attendance
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	clockIn  = time.Date(2024, 6, 3, 9, 5, 0, 0, time.UTC)
+	clockOut = time.Date(2024, 6, 3, 17, 35, 0, 0, time.UTC)
+	filter   = model.AttendanceFilter{DepartmentID: 2, From: "2024-06-03", To: "2024-06-09"}
+)
+
+func TestClockIn(t *testing.T) {
+	query := `INSERT INTO attendance (employee_id, work_date, clock_in) values ($1, $2, $3) returning id, created_at`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(attendance *model.Attendance, err error)
+	}{
+		{
+			name: "error when already clocked in",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "attendance_open_key"})
+			},
+			checkReturn: func(attendance *model.Attendance, err error) {
+				assert.Equal(t, ErrAlreadyClockedIn, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, "2024-06-03", clockIn).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, clockIn))
+			},
+			checkReturn: func(attendance *model.Attendance, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), attendance.ID)
+				assert.Equal(t, clockIn, attendance.CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoAttendance(db)
+
+			attendance := &model.Attendance{EmployeeID: 3, WorkDate: "2024-06-03", ClockIn: clockIn}
+			err = repo.ClockIn(context.TODO(), attendance)
+
+			tc.checkReturn(attendance, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestClockOut(t *testing.T) {
+	query := `UPDATE attendance SET clock_out = GREATEST($2, clock_in) WHERE employee_id = $1 AND clock_out IS NULL`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(attendance *model.Attendance, err error)
+	}{
+		{
+			name: "nil when not clocked in",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrNoRows)
+			},
+			checkReturn: func(attendance *model.Attendance, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, attendance)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, clockOut).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "employee_id", "work_date", "clock_in", "clock_out", "created_at"}).
+						AddRow(7, 3, "2024-06-03", clockIn, clockOut, clockIn))
+			},
+			checkReturn: func(attendance *model.Attendance, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), attendance.ID)
+				assert.Equal(t, "2024-06-03", attendance.WorkDate)
+				assert.Equal(t, clockOut, *attendance.ClockOut)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoAttendance(db)
+
+			attendance, err := repo.ClockOut(context.TODO(), 3, clockOut)
+
+			tc.checkReturn(attendance, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetDailyHours(t *testing.T) {
+	query := `from attendance a join employees e on e.id = a.employee_id`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2024-06-03", "2024-06-09", 0, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"employee_id", "work_date", "min", "max", "worked", "open"}).
+			AddRow(3, "2024-06-03", clockIn, clockOut, 30600.0, false).
+			AddRow(3, "2024-06-04", clockIn, nil, 0.0, true))
+
+	days, err := NewRepoAttendance(db).GetDailyHours(context.TODO(), filter)
+
+	assert.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, 30600.0, days[0].WorkedSeconds)
+	assert.Equal(t, clockOut, *days[0].LastClockOut)
+	assert.Nil(t, days[1].LastClockOut)
+	assert.True(t, days[1].Open)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetWeeklyHours(t *testing.T) {
+	query := `to_char(date_trunc('week', a.work_date), 'YYYY-MM-DD') as week_start`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2024-06-03", "2024-06-09", 0, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"employee_id", "week_start", "worked", "days"}).
+			AddRow(3, "2024-06-03", 144000.0, 5))
+
+	weeks, err := NewRepoAttendance(db).GetWeeklyHours(context.TODO(), filter)
+
+	assert.NoError(t, err)
+	require.Len(t, weeks, 1)
+	assert.Equal(t, "2024-06-03", weeks[0].WeekStart)
+	assert.Equal(t, 5, weeks[0].DaysWorked)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
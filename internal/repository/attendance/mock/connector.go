@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) ClockIn(ctx context.Context, attendance *model.Attendance) error {
+	ret := m.Called(ctx, attendance)
+	return ret.Error(0)
+}
+
+func (m *DBMock) ClockOut(ctx context.Context, employeeID int, at time.Time) (*model.Attendance, error) {
+	ret := m.Called(ctx, employeeID, at)
+	return ret.Get(0).(*model.Attendance), ret.Error(1)
+}
+
+func (m *DBMock) GetDailyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceDay, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.AttendanceDay), ret.Error(1)
+}
+
+func (m *DBMock) GetWeeklyHours(ctx context.Context, filter model.AttendanceFilter) ([]*model.AttendanceWeek, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.AttendanceWeek), ret.Error(1)
+}
+
+func (m *DBMock) GetEmployees(ctx context.Context, filter model.AttendanceFilter) ([]*model.Employee, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.Employee), ret.Error(1)
+}
+
+func (m *DBMock) GetApprovedLeave(ctx context.Context, filter model.AttendanceFilter) ([]*model.LeaveRequest, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.LeaveRequest), ret.Error(1)
+}
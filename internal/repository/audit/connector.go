@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.audit")
+)
+
+type AuditRepo interface {
+	CreateAudit(ctx context.Context, audit *model.Audit) error
+	GetAudits(ctx context.Context, filter *model.AuditFilter) ([]*model.Audit, error)
+	CountAudits(ctx context.Context, filter *model.AuditFilter) (int, error)
+}
+
+type auditRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoAudit(sqlConn *sql.DB) AuditRepo {
+	return &auditRepo{sqlConn: sqlConn}
+}
+
+func (a *auditRepo) CreateAudit(ctx context.Context, audit *model.Audit) error {
+	rLog := logRepo.WithField("function", "CreateAudit")
+
+	query := `INSERT INTO employee_audit
+		(employee_id, action, actor_id, actor_name, actor_role, request_id, before, after, diff)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	values := []interface{}{
+		audit.EmployeeID,
+		audit.Action,
+		audit.ActorID,
+		audit.ActorName,
+		audit.ActorRole,
+		audit.RequestID,
+		nullableJSON(audit.Before),
+		nullableJSON(audit.After),
+		nullableJSON(audit.Diff),
+	}
+
+	_, err := repository.Conn(ctx, a.sqlConn).ExecContext(ctx, query, values...)
+	if err != nil {
+		rLog.Errorf("error when create audit got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (a *auditRepo) GetAudits(ctx context.Context, filter *model.AuditFilter) ([]*model.Audit, error) {
+	rLog := logRepo.WithField("function", "GetAudits")
+
+	var audits []*model.Audit
+
+	where, args := buildFilter(filter)
+
+	query := `select id, employee_id, action, COALESCE(actor_id, ''), COALESCE(actor_name, ''), COALESCE(actor_role, ''),
+		COALESCE(request_id, ''), before, after, diff, created_at from employee_audit`
+	query += whereClause(where)
+	query += " order by created_at DESC, id DESC"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" offset $%d", len(args))
+	}
+
+	rows, err := repository.Conn(ctx, a.sqlConn).QueryContext(ctx, query, args...)
+	if err != nil {
+		rLog.Errorf("error when get audits got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Audit{}
+		var before, after, diff []byte
+		err := rows.Scan(&temp.ID, &temp.EmployeeID, &temp.Action, &temp.ActorID, &temp.ActorName, &temp.ActorRole,
+			&temp.RequestID, &before, &after, &diff, &temp.CreatedAt)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		temp.Before, temp.After, temp.Diff = before, after, diff
+		audits = append(audits, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return audits, nil
+}
+
+func (a *auditRepo) CountAudits(ctx context.Context, filter *model.AuditFilter) (int, error) {
+	rLog := logRepo.WithField("function", "CountAudits")
+
+	var total int
+
+	where, args := buildFilter(filter)
+	query := `select count(*) from employee_audit` + whereClause(where)
+
+	err := repository.Conn(ctx, a.sqlConn).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count audits got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+func buildFilter(filter *model.AuditFilter) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.EmployeeID > 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("employee_id = $%d", len(args)))
+	}
+
+	if filter.ActorID != "" {
+		args = append(args, filter.ActorID)
+		where = append(where, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		where = append(where, fmt.Sprintf("action = $%d", len(args)))
+	}
+
+	if filter.From != "" {
+		args = append(args, filter.From)
+		where = append(where, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	// the upper bound is a date, so the whole day is included
+	if filter.To != "" {
+		args = append(args, filter.To)
+		where = append(where, fmt.Sprintf("created_at < $%d::date + 1", len(args)))
+	}
+
+	return where, args
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+
+	return " where " + strings.Join(where, " and ")
+}
+
+func nullableJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return string(raw)
+}
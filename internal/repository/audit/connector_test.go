@@ -0,0 +1,196 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCreateAudit(t *testing.T) {
+	query := `INSERT INTO employee_audit
+		(employee_id, action, actor_id, actor_name, actor_role, request_id, before, after, diff)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	audit := &model.Audit{
+		EmployeeID: 1,
+		Action:     model.AuditActionCreate,
+		ActorID:    "2",
+		ActorName:  "admin@mail.com",
+		ActorRole:  "admin",
+		RequestID:  "req-1",
+		After:      []byte(`{"id":1}`),
+		Diff:       []byte(`{"id":{"from":null,"to":1}}`),
+	}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error connection when create audit",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(1, "create", "2", "admin@mail.com", "admin", "req-1", nil, `{"id":1}`, `{"id":{"from":null,"to":1}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoAudit(db)
+
+			err = repo.CreateAudit(context.TODO(), audit)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetAudits(t *testing.T) {
+	query := `select id, employee_id, action, COALESCE(actor_id, ''), COALESCE(actor_name, ''), COALESCE(actor_role, ''),
+		COALESCE(request_id, ''), before, after, diff, created_at from employee_audit`
+
+	createdAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "employee_id", "action", "actor_id", "actor_name", "actor_role", "request_id", "before", "after", "diff", "created_at"}
+
+	testCase := []struct {
+		name        string
+		filter      *model.AuditFilter
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.Audit, err error)
+	}{
+		{
+			name:   "error connection when get audits",
+			filter: &model.AuditFilter{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.Audit, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:   "success with filter",
+			filter: &model.AuditFilter{EmployeeID: 1, Action: "update", From: "2023-05-01", To: "2023-05-31", Limit: 20, Offset: 20},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(3, 1, "update", "2", "admin@mail.com", "admin", "req-1", []byte(`{"id":1}`), []byte(`{"id":1}`), []byte(`{}`), createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(query+` where employee_id = $1 and action = $2 and created_at >= $3 and created_at < $4::date + 1 order by created_at DESC, id DESC limit $5 offset $6`)).
+					WithArgs(1, "update", "2023-05-01", "2023-05-31", 20, 20).
+					WillReturnRows(rows)
+			},
+			checkReturn: func(result []*model.Audit, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 1)
+				assert.Equal(t, int64(3), result[0].ID)
+				assert.JSONEq(t, `{"id":1}`, string(result[0].Before))
+				assert.Equal(t, createdAt, result[0].CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoAudit(db)
+
+			result, err := repo.GetAudits(context.TODO(), tc.filter)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCountAudits(t *testing.T) {
+	query := `select count(*) from employee_audit where actor_id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(total int, err error)
+	}{
+		{
+			name: "error connection when count audits",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(total int, err error) {
+				assert.Error(t, err)
+				assert.Zero(t, total)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			checkReturn: func(total int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, total)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoAudit(db)
+
+			total, err := repo.CountAudits(context.TODO(), &model.AuditFilter{ActorID: "2"})
+
+			tc.checkReturn(total, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
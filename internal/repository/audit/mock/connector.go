@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateAudit(ctx context.Context, audit *model.Audit) error {
+	ret := m.Called(ctx, audit)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetAudits(ctx context.Context, filter *model.AuditFilter) ([]*model.Audit, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.Audit), ret.Error(1)
+}
+
+func (m *DBMock) CountAudits(ctx context.Context, filter *model.AuditFilter) (int, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).(int), ret.Error(1)
+}
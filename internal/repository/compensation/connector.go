@@ -0,0 +1,130 @@
+package compensation
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.compensation")
+)
+
+const compensationColumns = `id, employee_id, amount, currency, pay_frequency, to_char(effective_from, 'YYYY-MM-DD'),
+	to_char(effective_to, 'YYYY-MM-DD'), created_by, created_at`
+
+type CompensationRepo interface {
+	LockEmployee(ctx context.Context, employeeID int) (bool, error)
+	CreateCompensation(ctx context.Context, compensation *model.Compensation) error
+	GetCompensations(ctx context.Context, employeeID int) ([]*model.Compensation, error)
+	EndCompensation(ctx context.Context, compensationID int64, effectiveTo string) error
+}
+
+type compensationRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoCompensation(sqlConn *sql.DB) CompensationRepo {
+	return &compensationRepo{sqlConn: sqlConn}
+}
+
+// LockEmployee locks the employee row until the transaction ends so the
+// compensation changes of an employee are checked one at a time. It reports
+// false when the employee does not exist or is deleted.
+func (c *compensationRepo) LockEmployee(ctx context.Context, employeeID int) (bool, error) {
+	rLog := logRepo.WithField("function", "LockEmployee")
+
+	var id int
+
+	query := `select id from employees where id = $1 and deleted_at IS NULL for update`
+
+	err := repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, employeeID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		rLog.Errorf("error when lock employee got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	return true, nil
+}
+
+// CreateCompensation inserts the compensation and sets its id and created_at.
+func (c *compensationRepo) CreateCompensation(ctx context.Context, compensation *model.Compensation) error {
+	rLog := logRepo.WithField("function", "CreateCompensation")
+
+	query := `INSERT INTO compensations (employee_id, amount, currency, pay_frequency, effective_from, effective_to, created_by)
+		values ($1, $2, $3, $4, $5, $6, $7) returning id, created_at`
+
+	values := []interface{}{
+		compensation.EmployeeID,
+		compensation.Amount,
+		compensation.Currency,
+		compensation.PayFrequency,
+		compensation.EffectiveFrom,
+		compensation.EffectiveTo,
+		compensation.CreatedBy,
+	}
+
+	err := repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, values...).
+		Scan(&compensation.ID, &compensation.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when create compensation got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetCompensations returns the compensations of the employee, latest first.
+func (c *compensationRepo) GetCompensations(ctx context.Context, employeeID int) ([]*model.Compensation, error) {
+	rLog := logRepo.WithField("function", "GetCompensations")
+
+	var compensations []*model.Compensation
+
+	query := `select ` + compensationColumns + ` from compensations where employee_id = $1 order by effective_from DESC, id DESC`
+
+	rows, err := repository.Conn(ctx, c.sqlConn).QueryContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when get compensations got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Compensation{}
+		err := rows.Scan(&temp.ID, &temp.EmployeeID, &temp.Amount, &temp.Currency, &temp.PayFrequency, &temp.EffectiveFrom,
+			&temp.EffectiveTo, &temp.CreatedBy, &temp.CreatedAt)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		compensations = append(compensations, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return compensations, nil
+}
+
+// EndCompensation sets the last day of a compensation that is still current.
+func (c *compensationRepo) EndCompensation(ctx context.Context, compensationID int64, effectiveTo string) error {
+	rLog := logRepo.WithField("function", "EndCompensation")
+
+	query := `UPDATE compensations SET effective_to = $1 WHERE id = $2 AND effective_to IS NULL`
+
+	_, err := repository.Conn(ctx, c.sqlConn).ExecContext(ctx, query, effectiveTo, compensationID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
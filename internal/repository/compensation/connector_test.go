@@ -0,0 +1,217 @@
+package compensation
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns   = []string{"id", "employee_id", "amount", "currency", "pay_frequency", "effective_from", "effective_to", "created_by", "created_at"}
+)
+
+func TestLockEmployee(t *testing.T) {
+	query := `select id from employees where id = $1 and deleted_at IS NULL for update`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(found bool, err error)
+	}{
+		{
+			name: "error connection when lock employee",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(found bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, found)
+			},
+		},
+		{
+			name: "employee not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			checkReturn: func(found bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, found)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			},
+			checkReturn: func(found bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, found)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCompensation(db)
+
+			found, err := repo.LockEmployee(context.TODO(), 3)
+
+			tc.checkReturn(found, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCreateCompensation(t *testing.T) {
+	query := `INSERT INTO compensations (employee_id, amount, currency, pay_frequency, effective_from, effective_to, created_by)`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(compensation *model.Compensation, err error)
+	}{
+		{
+			name: "error connection when create compensation",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(compensation *model.Compensation, err error) {
+				assert.Error(t, err)
+				assert.Zero(t, compensation.ID)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(3, int64(8500000), "USD", model.PayFrequencyAnnual, "2024-01-01", nil, "1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, createdAt))
+			},
+			checkReturn: func(compensation *model.Compensation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(10), compensation.ID)
+				assert.Equal(t, createdAt, compensation.CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCompensation(db)
+
+			compensation := &model.Compensation{
+				EmployeeID:    3,
+				Amount:        8500000,
+				Currency:      "USD",
+				PayFrequency:  model.PayFrequencyAnnual,
+				EffectiveFrom: "2024-01-01",
+				CreatedBy:     "1",
+			}
+			err = repo.CreateCompensation(context.TODO(), compensation)
+
+			tc.checkReturn(compensation, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetCompensations(t *testing.T) {
+	query := `from compensations where employee_id = $1 order by effective_from DESC, id DESC`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.Compensation, err error)
+	}{
+		{
+			name: "error connection when get compensations",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.Compensation, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(11, 3, 9000000, "USD", model.PayFrequencyAnnual, "2024-01-01", nil, "1", createdAt).
+					AddRow(10, 3, 8500000, "USD", model.PayFrequencyAnnual, "2023-05-01", "2023-12-31", "1", createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(rows)
+			},
+			checkReturn: func(result []*model.Compensation, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 2)
+				assert.Nil(t, result[0].EffectiveTo)
+				assert.Equal(t, "2023-12-31", *result[1].EffectiveTo)
+				assert.Equal(t, int64(8500000), result[1].Amount)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCompensation(db)
+
+			result, err := repo.GetCompensations(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEndCompensation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE compensations SET effective_to = $1 WHERE id = $2 AND effective_to IS NULL`)).
+		WithArgs("2023-12-31", int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewRepoCompensation(db).EndCompensation(context.TODO(), 10, "2023-12-31")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) LockEmployee(ctx context.Context, employeeID int) (bool, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *DBMock) CreateCompensation(ctx context.Context, compensation *model.Compensation) error {
+	ret := m.Called(ctx, compensation)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetCompensations(ctx context.Context, employeeID int) ([]*model.Compensation, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).([]*model.Compensation), ret.Error(1)
+}
+
+func (m *DBMock) EndCompensation(ctx context.Context, compensationID int64, effectiveTo string) error {
+	ret := m.Called(ctx, compensationID, effectiveTo)
+	return ret.Error(0)
+}
@@ -0,0 +1,217 @@
+package customfield
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	"encoding/json"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.customfield")
+)
+
+const customFieldColumns = `id, name, type, required, options, created_at, updated_at`
+
+type CustomFieldRepo interface {
+	CreateCustomField(ctx context.Context, field *model.CustomField) error
+	GetCustomFields(ctx context.Context) ([]*model.CustomField, error)
+	GetCustomFieldByID(ctx context.Context, fieldID int) (*model.CustomField, error)
+	UpdateCustomField(ctx context.Context, field *model.CustomField) error
+	DeleteCustomField(ctx context.Context, fieldID int) error
+	CountValues(ctx context.Context, name string, values []string) (int, error)
+	RemoveValues(ctx context.Context, name string) ([]*model.Employee, error)
+}
+
+type customFieldRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoCustomField(sqlConn *sql.DB) CustomFieldRepo {
+	return &customFieldRepo{sqlConn: sqlConn}
+}
+
+// CreateCustomField inserts the definition and sets its id and timestamps.
+func (c *customFieldRepo) CreateCustomField(ctx context.Context, field *model.CustomField) error {
+	rLog := logRepo.WithField("function", "CreateCustomField")
+
+	query := `INSERT INTO custom_fields (name, type, required, options) values ($1, $2, $3, $4) returning id, created_at, updated_at`
+
+	err := repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, field.Name, field.Type, field.Required, pq.Array(options(field))).
+		Scan(&field.ID, &field.CreatedAt, &field.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when create custom field got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetCustomFields returns every definition by name, there are few enough to
+// not paginate them.
+func (c *customFieldRepo) GetCustomFields(ctx context.Context) ([]*model.CustomField, error) {
+	rLog := logRepo.WithField("function", "GetCustomFields")
+
+	var fields []*model.CustomField
+
+	query := `select ` + customFieldColumns + ` from custom_fields order by name ASC`
+
+	rows, err := repository.Conn(ctx, c.sqlConn).QueryContext(ctx, query)
+	if err != nil {
+		rLog.Errorf("error when get custom fields got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp, err := scanCustomField(rows)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		fields = append(fields, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return fields, nil
+}
+
+func (c *customFieldRepo) GetCustomFieldByID(ctx context.Context, fieldID int) (*model.CustomField, error) {
+	rLog := logRepo.WithField("function", "GetCustomFieldByID")
+
+	query := `select ` + customFieldColumns + ` from custom_fields where id = $1`
+
+	field, err := scanCustomField(repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, fieldID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return field, nil
+}
+
+// UpdateCustomField writes whether the field is required and its options and
+// sets the new updated_at, the name and the type never change.
+func (c *customFieldRepo) UpdateCustomField(ctx context.Context, field *model.CustomField) error {
+	rLog := logRepo.WithField("function", "UpdateCustomField")
+
+	query := `UPDATE custom_fields SET required = $1, options = $2, updated_at = NOW() WHERE id = $3 returning updated_at`
+
+	err := repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, field.Required, pq.Array(options(field)), field.ID).
+		Scan(&field.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when update custom field got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (c *customFieldRepo) DeleteCustomField(ctx context.Context, fieldID int) error {
+	rLog := logRepo.WithField("function", "DeleteCustomField")
+
+	query := `DELETE FROM custom_fields WHERE id = $1`
+
+	_, err := repository.Conn(ctx, c.sqlConn).ExecContext(ctx, query, fieldID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// CountValues counts the employees holding one of the values in the field,
+// deleted ones included.
+func (c *customFieldRepo) CountValues(ctx context.Context, name string, values []string) (int, error) {
+	rLog := logRepo.WithField("function", "CountValues")
+
+	var total int
+
+	query := `select count(*) from employees where custom_fields->>$1 = ANY($2)`
+
+	err := repository.Conn(ctx, c.sqlConn).QueryRowContext(ctx, query, name, pq.Array(values)).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count values got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+// RemoveValues drops the field from every employee holding a value in it and
+// bumps their version. It returns the employees as they were before, with
+// only their id, custom fields and version set.
+func (c *customFieldRepo) RemoveValues(ctx context.Context, name string) ([]*model.Employee, error) {
+	rLog := logRepo.WithField("function", "RemoveValues")
+
+	query := `WITH old AS (SELECT id, custom_fields, version FROM employees WHERE custom_fields ? $1 FOR UPDATE)
+		UPDATE employees SET custom_fields = employees.custom_fields - $1, version = employees.version + 1
+		FROM old WHERE employees.id = old.id RETURNING old.id, old.custom_fields, old.version`
+
+	rows, err := repository.Conn(ctx, c.sqlConn).QueryContext(ctx, query, name)
+	if err != nil {
+		rLog.Error(err)
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	var employees []*model.Employee
+	for rows.Next() {
+		employee := &model.Employee{}
+		var customFields []byte
+		if err := rows.Scan(&employee.ID, &customFields, &employee.Version); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		if err := json.Unmarshal(customFields, &employee.CustomFields); err != nil {
+			rLog.Errorf("error when decode custom fields got: %s", err.Error())
+			return nil, err
+		}
+
+		employees = append(employees, employee)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return employees, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomField(row rowScanner) (*model.CustomField, error) {
+	field := &model.CustomField{}
+
+	err := row.Scan(&field.ID, &field.Name, &field.Type, &field.Required, pq.Array(&field.Options), &field.CreatedAt, &field.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// options stores a field without options as an empty array.
+func options(field *model.CustomField) []string {
+	if field.Options == nil {
+		return []string{}
+	}
+
+	return field.Options
+}
@@ -0,0 +1,297 @@
+package customfield
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt     = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	resultColumns = []string{"id", "name", "type", "required", "options", "created_at", "updated_at"}
+)
+
+func TestCreateCustomField(t *testing.T) {
+	query := `INSERT INTO custom_fields (name, type, required, options) values ($1, $2, $3, $4) returning id, created_at, updated_at`
+
+	testCase := []struct {
+		name        string
+		field       *model.CustomField
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(field *model.CustomField, err error)
+	}{
+		{
+			name:  "error when name is taken",
+			field: &model.CustomField{Name: "cost_center", Type: model.CustomFieldTypeText},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "custom_fields_name_key"})
+			},
+			checkReturn: func(field *model.CustomField, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeConflict, appErr.Code)
+				assert.Equal(t, "name", appErr.Field)
+			},
+		},
+		{
+			name:  "success without options",
+			field: &model.CustomField{Name: "cost_center", Type: model.CustomFieldTypeText, Required: true},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("cost_center", "text", true, "{}").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, createdAt, createdAt))
+			},
+			checkReturn: func(field *model.CustomField, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, field.ID)
+				assert.Equal(t, createdAt, field.CreatedAt)
+			},
+		},
+		{
+			name:  "success with options",
+			field: &model.CustomField{Name: "shirt_size", Type: model.CustomFieldTypeEnum, Options: []string{"S", "M", "L"}},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("shirt_size", "enum", false, `{"S","M","L"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(2, createdAt, createdAt))
+			},
+			checkReturn: func(field *model.CustomField, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, field.ID)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCustomField(db)
+
+			err = repo.CreateCustomField(context.TODO(), tc.field)
+
+			tc.checkReturn(tc.field, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetCustomFields(t *testing.T) {
+	query := `select id, name, type, required, options, created_at, updated_at from custom_fields order by name ASC`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(fields []*model.CustomField, err error)
+	}{
+		{
+			name: "error connection when get custom fields",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(fields []*model.CustomField, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeUnavailable))
+				assert.Nil(t, fields)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(resultColumns).
+					AddRow(1, "cost_center", "text", true, "{}", createdAt, createdAt).
+					AddRow(2, "shirt_size", "enum", false, "{S,M,L}", createdAt, createdAt))
+			},
+			checkReturn: func(fields []*model.CustomField, err error) {
+				assert.NoError(t, err)
+				require.Len(t, fields, 2)
+				assert.Empty(t, fields[0].Options)
+				assert.True(t, fields[0].Required)
+				assert.Equal(t, []string{"S", "M", "L"}, fields[1].Options)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCustomField(db)
+
+			fields, err := repo.GetCustomFields(context.TODO())
+
+			tc.checkReturn(fields, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetCustomFieldByID(t *testing.T) {
+	query := `select id, name, type, required, options, created_at, updated_at from custom_fields where id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(field *model.CustomField, err error)
+	}{
+		{
+			name: "not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnError(sql.ErrNoRows)
+			},
+			checkReturn: func(field *model.CustomField, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(sqlmock.NewRows(resultColumns).
+					AddRow(1, "shirt_size", "enum", false, "{S,M,L}", createdAt, createdAt))
+			},
+			checkReturn: func(field *model.CustomField, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, field)
+				assert.Equal(t, "shirt_size", field.Name)
+				assert.Equal(t, []string{"S", "M", "L"}, field.Options)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoCustomField(db)
+
+			field, err := repo.GetCustomFieldByID(context.TODO(), 1)
+
+			tc.checkReturn(field, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateCustomField(t *testing.T) {
+	query := `UPDATE custom_fields SET required = $1, options = $2, updated_at = NOW() WHERE id = $3 returning updated_at`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	updatedAt := createdAt.Add(time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(true, `{"S","M"}`, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+
+	field := &model.CustomField{ID: 2, Name: "shirt_size", Type: model.CustomFieldTypeEnum, Required: true, Options: []string{"S", "M"}}
+	err = NewRepoCustomField(db).UpdateCustomField(context.TODO(), field)
+
+	assert.NoError(t, err)
+	assert.Equal(t, updatedAt, field.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountValues(t *testing.T) {
+	query := `select count(*) from employees where custom_fields->>$1 = ANY($2)`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("shirt_size", `{"XL"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := NewRepoCustomField(db).CountValues(context.TODO(), "shirt_size", []string{"XL"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveValues(t *testing.T) {
+	query := `WITH old AS (SELECT id, custom_fields, version FROM employees WHERE custom_fields ? $1 FOR UPDATE)
+		UPDATE employees SET custom_fields = employees.custom_fields - $1, version = employees.version + 1
+		FROM old WHERE employees.id = old.id RETURNING old.id, old.custom_fields, old.version`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(employees []*model.Employee, err error)
+	}{
+		{
+			name: "error connection when remove values",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(employees []*model.Employee, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeUnavailable))
+			},
+		},
+		{
+			name: "success returns the employees as they were",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "custom_fields", "version"}).
+					AddRow(3, []byte(`{"cost_center":"CC-12","remote":true}`), 2)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("cost_center").WillReturnRows(rows)
+			},
+			checkReturn: func(employees []*model.Employee, err error) {
+				assert.NoError(t, err)
+				require.Len(t, employees, 1)
+				assert.Equal(t, 3, employees[0].ID)
+				assert.Equal(t, 2, employees[0].Version)
+				assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "remote": true}, employees[0].CustomFields)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			employees, err := NewRepoCustomField(db).RemoveValues(context.TODO(), "cost_center")
+
+			tc.checkReturn(employees, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateCustomField(ctx context.Context, field *model.CustomField) error {
+	ret := m.Called(ctx, field)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetCustomFields(ctx context.Context) ([]*model.CustomField, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).([]*model.CustomField), ret.Error(1)
+}
+
+func (m *DBMock) GetCustomFieldByID(ctx context.Context, fieldID int) (*model.CustomField, error) {
+	ret := m.Called(ctx, fieldID)
+	return ret.Get(0).(*model.CustomField), ret.Error(1)
+}
+
+func (m *DBMock) UpdateCustomField(ctx context.Context, field *model.CustomField) error {
+	ret := m.Called(ctx, field)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeleteCustomField(ctx context.Context, fieldID int) error {
+	ret := m.Called(ctx, fieldID)
+	return ret.Error(0)
+}
+
+func (m *DBMock) CountValues(ctx context.Context, name string, values []string) (int, error) {
+	ret := m.Called(ctx, name, values)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) RemoveValues(ctx context.Context, name string) ([]*model.Employee, error) {
+	ret := m.Called(ctx, name)
+	return ret.Get(0).([]*model.Employee), ret.Error(1)
+}
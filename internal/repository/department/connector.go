@@ -0,0 +1,201 @@
+package department
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.department")
+)
+
+// ErrDepartmentHasMembers is returned when deleting a department that
+// employees, deleted ones included, still belong to.
+var ErrDepartmentHasMembers = apperror.Conflict("", "department still has members")
+
+// membersForeignKey is the foreign key from employees.department_id.
+const membersForeignKey = "employees_department_id_fkey"
+
+type DepartmentRepo interface {
+	CreateDepartment(ctx context.Context, department *model.Department) error
+	GetDepartments(ctx context.Context, filter *model.DepartmentFilter) ([]*model.Department, error)
+	CountDepartments(ctx context.Context, filter *model.DepartmentFilter) (int, error)
+	GetDepartmentByID(ctx context.Context, departmentID int) (*model.Department, error)
+	UpdateDepartment(ctx context.Context, department *model.Department) error
+	DeleteDepartment(ctx context.Context, departmentID int) error
+	CountMembers(ctx context.Context, departmentID int) (int, error)
+}
+
+type departmentRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoDepartment(sqlConn *sql.DB) DepartmentRepo {
+	return &departmentRepo{sqlConn: sqlConn}
+}
+
+// CreateDepartment inserts the department and sets its id and timestamps.
+func (d *departmentRepo) CreateDepartment(ctx context.Context, department *model.Department) error {
+	rLog := logRepo.WithField("function", "CreateDepartment")
+
+	query := `INSERT INTO departments (name, description) values ($1, $2) returning id, created_at, updated_at`
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, department.Name, department.Description).
+		Scan(&department.ID, &department.CreatedAt, &department.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when create department got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (d *departmentRepo) GetDepartments(ctx context.Context, filter *model.DepartmentFilter) ([]*model.Department, error) {
+	rLog := logRepo.WithField("function", "GetDepartments")
+
+	var departments []*model.Department
+
+	where, args := buildFilter(filter)
+
+	query := `select id, name, description, created_at, updated_at from departments` + where + ` order by name ASC, id ASC`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" offset $%d", len(args))
+	}
+
+	rows, err := repository.Conn(ctx, d.sqlConn).QueryContext(ctx, query, args...)
+	if err != nil {
+		rLog.Errorf("error when get departments got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Department{}
+		if err := rows.Scan(&temp.ID, &temp.Name, &temp.Description, &temp.CreatedAt, &temp.UpdatedAt); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		departments = append(departments, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return departments, nil
+}
+
+func (d *departmentRepo) CountDepartments(ctx context.Context, filter *model.DepartmentFilter) (int, error) {
+	rLog := logRepo.WithField("function", "CountDepartments")
+
+	var total int
+
+	where, args := buildFilter(filter)
+	query := `select count(*) from departments` + where
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count departments got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+func (d *departmentRepo) GetDepartmentByID(ctx context.Context, departmentID int) (*model.Department, error) {
+	rLog := logRepo.WithField("function", "GetDepartmentByID")
+
+	department := &model.Department{}
+
+	query := `select id, name, description, created_at, updated_at from departments where id = $1`
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, departmentID).
+		Scan(&department.ID, &department.Name, &department.Description, &department.CreatedAt, &department.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return department, nil
+}
+
+// UpdateDepartment writes the name and description and sets the new updated_at.
+func (d *departmentRepo) UpdateDepartment(ctx context.Context, department *model.Department) error {
+	rLog := logRepo.WithField("function", "UpdateDepartment")
+
+	query := `UPDATE departments SET name = $1, description = $2, updated_at = NOW() WHERE id = $3 returning updated_at`
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, department.Name, department.Description, department.ID).
+		Scan(&department.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when update department got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// DeleteDepartment deletes the department, the foreign key of the employees
+// rejects it while members remain.
+func (d *departmentRepo) DeleteDepartment(ctx context.Context, departmentID int) error {
+	rLog := logRepo.WithField("function", "DeleteDepartment")
+
+	query := `DELETE FROM departments WHERE id = $1`
+
+	_, err := repository.Conn(ctx, d.sqlConn).ExecContext(ctx, query, departmentID)
+	if err != nil {
+		rLog.Error(err)
+		if repository.IsForeignKeyViolation(err, membersForeignKey) {
+			return ErrDepartmentHasMembers
+		}
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// CountMembers counts the employees of the department, deleted ones included
+// since they still reference it until they are purged.
+func (d *departmentRepo) CountMembers(ctx context.Context, departmentID int) (int, error) {
+	rLog := logRepo.WithField("function", "CountMembers")
+
+	var total int
+
+	query := `select count(*) from employees where department_id = $1`
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, departmentID).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count members got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+func buildFilter(filter *model.DepartmentFilter) (string, []interface{}) {
+	if filter.Name == "" {
+		return "", nil
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	return " where name ILIKE $1", []interface{}{replacer.Replace(filter.Name) + "%"}
+}
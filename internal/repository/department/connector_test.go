@@ -0,0 +1,355 @@
+package department
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns   = []string{"id", "name", "description", "created_at", "updated_at"}
+)
+
+func TestCreateDepartment(t *testing.T) {
+	query := `INSERT INTO departments (name, description) values ($1, $2) returning id, created_at, updated_at`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(department *model.Department, err error)
+	}{
+		{
+			name: "error connection when create department",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(department *model.Department, err error) {
+				assert.Error(t, err)
+				assert.Zero(t, department.ID)
+			},
+		},
+		{
+			name: "error when name is taken",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "departments_name_key"})
+			},
+			checkReturn: func(department *model.Department, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeConflict, appErr.Code)
+				assert.Equal(t, "name", appErr.Field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Engineering", "builds things").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, createdAt, createdAt))
+			},
+			checkReturn: func(department *model.Department, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, department.ID)
+				assert.Equal(t, createdAt, department.CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoDepartment(db)
+
+			department := &model.Department{Name: "Engineering", Description: "builds things"}
+			err = repo.CreateDepartment(context.TODO(), department)
+
+			tc.checkReturn(department, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetDepartments(t *testing.T) {
+	query := `select id, name, description, created_at, updated_at from departments`
+
+	testCase := []struct {
+		name        string
+		filter      *model.DepartmentFilter
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.Department, err error)
+	}{
+		{
+			name:   "error connection when get departments",
+			filter: &model.DepartmentFilter{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.Department, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:   "success with filter",
+			filter: &model.DepartmentFilter{Name: "eng_", Limit: 20, Offset: 20},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow(1, "Engineering", "", createdAt, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(query+` where name ILIKE $1 order by name ASC, id ASC limit $2 offset $3`)).
+					WithArgs(`eng\_%`, 20, 20).
+					WillReturnRows(rows)
+			},
+			checkReturn: func(result []*model.Department, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 1)
+				assert.Equal(t, "Engineering", result[0].Name)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoDepartment(db)
+
+			result, err := repo.GetDepartments(context.TODO(), tc.filter)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCountDepartments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`select count(*) from departments where name ILIKE $1`)).
+		WithArgs("eng%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := NewRepoDepartment(db).CountDepartments(context.TODO(), &model.DepartmentFilter{Name: "eng"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDepartmentByID(t *testing.T) {
+	query := `select id, name, description, created_at, updated_at from departments where id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.Department, err error)
+	}{
+		{
+			name: "error connection when get department",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result *model.Department, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "department not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			checkReturn: func(result *model.Department, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Engineering", "builds things", createdAt, createdAt))
+			},
+			checkReturn: func(result *model.Department, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &model.Department{ID: 1, Name: "Engineering", Description: "builds things", CreatedAt: createdAt, UpdatedAt: createdAt}, result)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoDepartment(db)
+
+			result, err := repo.GetDepartmentByID(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateDepartment(t *testing.T) {
+	query := `UPDATE departments SET name = $1, description = $2, updated_at = NOW() WHERE id = $3 returning updated_at`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(department *model.Department, err error)
+	}{
+		{
+			name: "error when name is taken",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "departments_name_key"})
+			},
+			checkReturn: func(department *model.Department, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Platform", "", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(createdAt))
+			},
+			checkReturn: func(department *model.Department, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, createdAt, department.UpdatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoDepartment(db)
+
+			department := &model.Department{ID: 1, Name: "Platform"}
+			err = repo.UpdateDepartment(context.TODO(), department)
+
+			tc.checkReturn(department, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeleteDepartment(t *testing.T) {
+	query := `DELETE FROM departments WHERE id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error connection when delete department",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeUnavailable))
+			},
+		},
+		{
+			name: "error when an employee joined in the meantime",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23503", Constraint: "employees_department_id_fkey"})
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrDepartmentHasMembers)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoDepartment(db)
+
+			err = repo.DeleteDepartment(context.TODO(), 1)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCountMembers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`select count(*) from employees where department_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	total, err := NewRepoDepartment(db).CountMembers(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
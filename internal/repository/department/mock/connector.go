@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateDepartment(ctx context.Context, department *model.Department) error {
+	ret := m.Called(ctx, department)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetDepartments(ctx context.Context, filter *model.DepartmentFilter) ([]*model.Department, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.Department), ret.Error(1)
+}
+
+func (m *DBMock) CountDepartments(ctx context.Context, filter *model.DepartmentFilter) (int, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) GetDepartmentByID(ctx context.Context, departmentID int) (*model.Department, error) {
+	ret := m.Called(ctx, departmentID)
+	return ret.Get(0).(*model.Department), ret.Error(1)
+}
+
+func (m *DBMock) UpdateDepartment(ctx context.Context, department *model.Department) error {
+	ret := m.Called(ctx, department)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeleteDepartment(ctx context.Context, departmentID int) error {
+	ret := m.Called(ctx, departmentID)
+	return ret.Error(0)
+}
+
+func (m *DBMock) CountMembers(ctx context.Context, departmentID int) (int, error) {
+	ret := m.Called(ctx, departmentID)
+	return ret.Get(0).(int), ret.Error(1)
+}
@@ -0,0 +1,137 @@
+package document
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.document")
+)
+
+const documentColumns = `id, employee_id, category, file_name, content_type, size, storage_key, uploaded_by, created_at`
+
+type DocumentRepo interface {
+	CreateDocument(ctx context.Context, document *model.Document) error
+	GetDocumentByID(ctx context.Context, documentID int64) (*model.Document, error)
+	GetDocuments(ctx context.Context, employeeID int) ([]*model.Document, error)
+	DeleteDocument(ctx context.Context, documentID int64) error
+}
+
+type documentRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoDocument(sqlConn *sql.DB) DocumentRepo {
+	return &documentRepo{sqlConn: sqlConn}
+}
+
+// CreateDocument inserts the document and sets its id and created_at.
+func (d *documentRepo) CreateDocument(ctx context.Context, document *model.Document) error {
+	rLog := logRepo.WithField("function", "CreateDocument")
+
+	query := `INSERT INTO employee_documents (employee_id, category, file_name, content_type, size, storage_key, uploaded_by)
+		values ($1, $2, $3, $4, $5, $6, $7) returning id, created_at`
+
+	values := []interface{}{
+		document.EmployeeID,
+		document.Category,
+		document.FileName,
+		document.ContentType,
+		document.Size,
+		document.StorageKey,
+		document.UploadedBy,
+	}
+
+	err := repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, values...).Scan(&document.ID, &document.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when create document got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (d *documentRepo) GetDocumentByID(ctx context.Context, documentID int64) (*model.Document, error) {
+	rLog := logRepo.WithField("function", "GetDocumentByID")
+
+	query := `select ` + documentColumns + ` from employee_documents where id = $1`
+
+	document, err := scanDocument(repository.Conn(ctx, d.sqlConn).QueryRowContext(ctx, query, documentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return document, nil
+}
+
+// GetDocuments returns the documents of the employee, the latest first.
+func (d *documentRepo) GetDocuments(ctx context.Context, employeeID int) ([]*model.Document, error) {
+	rLog := logRepo.WithField("function", "GetDocuments")
+
+	var documents []*model.Document
+
+	query := `select ` + documentColumns + ` from employee_documents where employee_id = $1 order by created_at DESC, id DESC`
+
+	rows, err := repository.Conn(ctx, d.sqlConn).QueryContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when get documents got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp, err := scanDocument(rows)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		documents = append(documents, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return documents, nil
+}
+
+func (d *documentRepo) DeleteDocument(ctx context.Context, documentID int64) error {
+	rLog := logRepo.WithField("function", "DeleteDocument")
+
+	query := `DELETE FROM employee_documents WHERE id = $1`
+
+	_, err := repository.Conn(ctx, d.sqlConn).ExecContext(ctx, query, documentID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDocument(row rowScanner) (*model.Document, error) {
+	document := &model.Document{}
+
+	err := row.Scan(&document.ID, &document.EmployeeID, &document.Category, &document.FileName, &document.ContentType, &document.Size,
+		&document.StorageKey, &document.UploadedBy, &document.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
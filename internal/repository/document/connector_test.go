@@ -0,0 +1,120 @@
+package document
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt     = time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	resultColumns = []string{"id", "employee_id", "category", "file_name", "content_type", "size", "storage_key", "uploaded_by", "created_at"}
+)
+
+func TestCreateDocument(t *testing.T) {
+	query := `INSERT INTO employee_documents (employee_id, category, file_name, content_type, size, storage_key, uploaded_by)`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(3, "contract", "contract.pdf", "application/pdf", int64(2048), "employees/3/abc", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, createdAt))
+
+	document := &model.Document{EmployeeID: 3, Category: "contract", FileName: "contract.pdf", ContentType: "application/pdf", Size: 2048,
+		StorageKey: "employees/3/abc", UploadedBy: "user-1"}
+	err = NewRepoDocument(db).CreateDocument(context.TODO(), document)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), document.ID)
+	assert.Equal(t, createdAt, document.CreatedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetDocumentByID(t *testing.T) {
+	query := `from employee_documents where id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(document *model.Document, err error)
+	}{
+		{
+			name: "nil when not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(7)).WillReturnError(sql.ErrNoRows)
+			},
+			checkReturn: func(document *model.Document, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, document)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(int64(7)).
+					WillReturnRows(sqlmock.NewRows(resultColumns).
+						AddRow(7, 3, "contract", "contract.pdf", "application/pdf", 2048, "employees/3/abc", "user-1", createdAt))
+			},
+			checkReturn: func(document *model.Document, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "employees/3/abc", document.StorageKey)
+				assert.Equal(t, int64(2048), document.Size)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			document, err := NewRepoDocument(db).GetDocumentByID(context.TODO(), 7)
+
+			tc.checkReturn(document, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetDocuments(t *testing.T) {
+	query := `from employee_documents where employee_id = $1 order by created_at DESC, id DESC`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(resultColumns).
+			AddRow(8, 3, "id", "passport.png", "image/png", 512, "employees/3/def", "user-1", createdAt).
+			AddRow(7, 3, "contract", "contract.pdf", "application/pdf", 2048, "employees/3/abc", "user-1", createdAt))
+
+	documents, err := NewRepoDocument(db).GetDocuments(context.TODO(), 3)
+
+	assert.NoError(t, err)
+	require.Len(t, documents, 2)
+	assert.Equal(t, int64(8), documents[0].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
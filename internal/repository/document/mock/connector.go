@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateDocument(ctx context.Context, document *model.Document) error {
+	ret := m.Called(ctx, document)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetDocumentByID(ctx context.Context, documentID int64) (*model.Document, error) {
+	ret := m.Called(ctx, documentID)
+	return ret.Get(0).(*model.Document), ret.Error(1)
+}
+
+func (m *DBMock) GetDocuments(ctx context.Context, employeeID int) ([]*model.Document, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).([]*model.Document), ret.Error(1)
+}
+
+func (m *DBMock) DeleteDocument(ctx context.Context, documentID int64) error {
+	ret := m.Called(ctx, documentID)
+	return ret.Error(0)
+}
@@ -3,20 +3,65 @@ package employee
 import (
 	"context"
 	"database/sql"
+	"employee/internal/apperror"
 	"employee/internal/model"
+	"employee/internal/repository"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
 )
 
 var (
 	logRepo = log.WithField("package", "repository.employee")
 )
 
+// ErrStaleVersion is returned when the employee is at another version than the
+// one the request expects, either from If-Match or from the row it was read at.
+var ErrStaleVersion = apperror.PreconditionFailed("employee version does not match, it was modified by another request")
+
+// importBatchSize bounds the rows of one multi-row insert, well under the
+// 65535 bind parameters postgres accepts per statement.
+const importBatchSize = 500
+
+// importColumns is the number of columns CreateEmployees inserts per row.
+const importColumns = 15
+
+// managementTreeLock is the advisory lock key serializing manager changes.
+const managementTreeLock = 17001
+
+// sortColumns whitelists the columns the list endpoint may be ordered by.
+var sortColumns = map[string]string{
+	"id":         "id",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"email":      "email",
+	"hire_date":  "hire_date",
+}
+
 type UserRepo interface {
 	CreateEmployee(ctx context.Context, employee *model.Employee) (int, error)
-	GetEmployees(ctx context.Context) ([]*model.Employee, error)
-	GetEmployeeByID(ctx context.Context, employeeID int) (*model.Employee, error)
+	CreateEmployees(ctx context.Context, employees []*model.Employee) ([]*model.Employee, error)
+	GetExistingEmails(ctx context.Context, emails []string) (map[string]bool, error)
+	GetExistingDepartments(ctx context.Context, departmentIDs []int) (map[int]bool, error)
+	GetEmployees(ctx context.Context, filter *model.EmployeeFilter) ([]*model.Employee, error)
+	StreamEmployees(ctx context.Context, filter *model.EmployeeFilter, fn func(employee *model.Employee) error) error
+	CountEmployees(ctx context.Context, filter *model.EmployeeFilter) (int, error)
+	GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*model.Employee, error)
+	GetEmployeeForUpdate(ctx context.Context, employeeID int) (*model.Employee, error)
 	UpdateEmployee(ctx context.Context, employee *model.Employee) error
-	DeleteEmployee(ctx context.Context, employeeID int) error
+	PatchEmployee(ctx context.Context, employeeID int, patch *model.EmployeePatch) error
+	SetPosition(ctx context.Context, employeeID int, positionID int, departmentID *int, version int) error
+	UpdateStatus(ctx context.Context, employee *model.Employee) error
+	DeleteEmployee(ctx context.Context, employeeID int, version int) error
+	RestoreEmployee(ctx context.Context, employeeID int) error
+	GetPurgedDocumentKeys(ctx context.Context, deletedBefore time.Time) ([]string, error)
+	PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]int, error)
+	GetReports(ctx context.Context, managerID int, depth int) ([]*model.HierarchyEmployee, error)
+	LockManagementTree(ctx context.Context) error
+	GetManagementChain(ctx context.Context, employeeID int) ([]*model.HierarchyEmployee, error)
 }
 
 type userRepo struct {
@@ -32,44 +77,256 @@ func (u *userRepo) CreateEmployee(ctx context.Context, employee *model.Employee)
 
 	var currentInsertedID int
 
+	address, contacts, err := encodeProfile(employee)
+	if err != nil {
+		rLog.Errorf("error when encode profile got: %s", err.Error())
+		return 0, err
+	}
+
+	customFields, err := encodeCustomFields(employee.CustomFields)
+	if err != nil {
+		rLog.Errorf("error when encode custom fields got: %s", err.Error())
+		return 0, err
+	}
+
 	query := `INSERT INTO employees 
-		(first_name, last_name,email,hire_date )
-		values ($1, $2, $3, $4) returning id`
+		(first_name, last_name,email,hire_date, department_id, manager_id, position_id, status,
+		phone, date_of_birth, address, emergency_contacts, national_id, work_location, custom_fields)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) returning id`
 
 	values := []interface{}{
 		employee.FirstName,
 		employee.LastName,
 		employee.Email,
 		employee.HireDate,
+		employee.DepartmentID,
+		employee.ManagerID,
+		employee.PositionID,
+		employee.Status,
+		employee.Phone,
+		employee.DateOfBirth,
+		address,
+		contacts,
+		employee.NationalID,
+		employee.WorkLocation,
+		customFields,
 	}
 
-	err := u.sqlConn.QueryRowContext(ctx, query, values...).Scan(&currentInsertedID)
+	err = repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, values...).Scan(&currentInsertedID)
 	if err != nil {
 		rLog.Errorf("error when create employee got: %s", err.Error())
-		return 0, err
+		return 0, repository.TranslateError(err)
 	}
 
 	return currentInsertedID, nil
 }
 
-func (u *userRepo) GetEmployees(ctx context.Context) ([]*model.Employee, error) {
+// CreateEmployees inserts the employees with multi-row inserts of at most
+// importBatchSize rows. Employees whose email is already taken are skipped,
+// the inserted ones are returned with their id.
+func (u *userRepo) CreateEmployees(ctx context.Context, employees []*model.Employee) ([]*model.Employee, error) {
+	rLog := logRepo.WithField("function", "CreateEmployees")
+
+	byEmail := make(map[string]*model.Employee, len(employees))
+	for _, employee := range employees {
+		byEmail[strings.ToLower(employee.Email)] = employee
+	}
+
+	var created []*model.Employee
+	for start := 0; start < len(employees); start += importBatchSize {
+		batch := employees[start:min(start+importBatchSize, len(employees))]
+
+		placeholders := make([]string, 0, len(batch))
+		values := make([]interface{}, 0, len(batch)*importColumns)
+		for _, employee := range batch {
+			address, contacts, err := encodeProfile(employee)
+			if err != nil {
+				rLog.Errorf("error when encode profile got: %s", err.Error())
+				return nil, err
+			}
+
+			customFields, err := encodeCustomFields(employee.CustomFields)
+			if err != nil {
+				rLog.Errorf("error when encode custom fields got: %s", err.Error())
+				return nil, err
+			}
+
+			params := make([]string, importColumns)
+			for i := range params {
+				params[i] = fmt.Sprintf("$%d", len(values)+i+1)
+			}
+			placeholders = append(placeholders, "("+strings.Join(params, ", ")+")")
+			values = append(values, employee.FirstName, employee.LastName, employee.Email, employee.HireDate,
+				employee.DepartmentID, employee.ManagerID, employee.PositionID, employee.Status,
+				employee.Phone, employee.DateOfBirth, address, contacts, employee.NationalID, employee.WorkLocation, customFields)
+		}
+
+		query := `INSERT INTO employees (first_name, last_name, email, hire_date, department_id, manager_id, position_id, status,
+		phone, date_of_birth, address, emergency_contacts, national_id, work_location, custom_fields) values ` +
+			strings.Join(placeholders, ", ") + ` ON CONFLICT (LOWER(email)) DO NOTHING returning id, email`
+
+		rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, values...)
+		if err != nil {
+			rLog.Errorf("error when create employees got: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		for rows.Next() {
+			var id int
+			var email string
+			if err := rows.Scan(&id, &email); err != nil {
+				rows.Close()
+				rLog.Errorf("error when scan: %s", err.Error())
+				return nil, repository.TranslateError(err)
+			}
+
+			employee := byEmail[strings.ToLower(email)]
+			employee.ID = id
+			employee.Version = 1
+			created = append(created, employee)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			rLog.Errorf("error when iterate rows: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+	}
+
+	return created, nil
+}
+
+// GetExistingEmails returns which of the emails, lower cased, are already
+// taken, deleted employees included.
+func (u *userRepo) GetExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	rLog := logRepo.WithField("function", "GetExistingEmails")
+
+	lowered := make([]string, 0, len(emails))
+	for _, email := range emails {
+		lowered = append(lowered, strings.ToLower(email))
+	}
+
+	query := `select LOWER(email) from employees where LOWER(email) = ANY($1)`
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, pq.Array(lowered))
+	if err != nil {
+		rLog.Errorf("error when get existing emails got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	existing := map[string]bool{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+		existing[email] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return existing, nil
+}
+
+// GetExistingDepartments returns which of the department ids exist.
+func (u *userRepo) GetExistingDepartments(ctx context.Context, departmentIDs []int) (map[int]bool, error) {
+	rLog := logRepo.WithField("function", "GetExistingDepartments")
+
+	query := `select id from departments where id = ANY($1)`
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, pq.Array(departmentIDs))
+	if err != nil {
+		rLog.Errorf("error when get existing departments got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	existing := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+		existing[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return existing, nil
+}
+
+func (u *userRepo) GetEmployees(ctx context.Context, filter *model.EmployeeFilter) ([]*model.Employee, error) {
 	rLog := logRepo.WithField("function", "GetEmployee")
 
 	var employees []*model.Employee
 
-	query := `select id, first_name, last_name, email, hire_date from employees order by id DESC`
+	where, args, err := buildFilter(filter)
+	if err != nil {
+		rLog.Errorf("error when build filter got: %s", err.Error())
+		return nil, err
+	}
+	column, direction := ParseSort(filter.Sort)
+
+	if filter.Cursor != nil {
+		operator := ">"
+		if direction == "DESC" {
+			operator = "<"
+		}
+
+		if column == "id" {
+			args = append(args, filter.Cursor.ID)
+			where = append(where, fmt.Sprintf("id %s $%d", operator, len(args)))
+		} else {
+			args = append(args, filter.Cursor.Value, filter.Cursor.ID)
+			where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)-1, len(args)))
+		}
+	}
+
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees`
+	query += whereClause(where)
+	query += fmt.Sprintf(" order by %s %s", column, direction)
+	if column != "id" {
+		query += fmt.Sprintf(", id %s", direction)
+	}
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" offset $%d", len(args))
+	}
 
-	rows, err := u.sqlConn.QueryContext(ctx, query)
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, args...)
 	if err != nil {
 		rLog.Errorf("error when get employees got: %s", err.Error())
-		return nil, err
+		return nil, repository.TranslateError(err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		temp := &model.Employee{}
-		err := rows.Scan(&temp.ID, &temp.FirstName, &temp.LastName, &temp.Email, &temp.HireDate)
+		var customFields []byte
+		err := rows.Scan(&temp.ID, &temp.FirstName, &temp.LastName, &temp.Email, &temp.HireDate, &temp.ManagerID, &temp.DepartmentID, &temp.PositionID, &temp.Status, &temp.TerminationDate, &temp.TerminationReason, &customFields, &temp.DeletedAt, &temp.Version)
 		if err != nil {
 			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		if temp.CustomFields, err = decodeCustomFields(customFields); err != nil {
+			rLog.Errorf("error when decode custom fields got: %s", err.Error())
 			return nil, err
 		}
 
@@ -77,56 +334,630 @@ func (u *userRepo) GetEmployees(ctx context.Context) ([]*model.Employee, error)
 
 	}
 
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
 	return employees, nil
 }
 
-func (u *userRepo) GetEmployeeByID(ctx context.Context, employeeID int) (*model.Employee, error) {
-	rLog := logRepo.WithField("function", "GetEmployeeByID")
+// StreamEmployees calls fn for every employee matching the filter, in the
+// filter's sort order, without holding the result set in memory. Cursor,
+// limit and offset are ignored. An error from fn stops the iteration and is
+// returned as is.
+func (u *userRepo) StreamEmployees(ctx context.Context, filter *model.EmployeeFilter, fn func(employee *model.Employee) error) error {
+	rLog := logRepo.WithField("function", "StreamEmployees")
+
+	where, args, err := buildFilter(filter)
+	if err != nil {
+		rLog.Errorf("error when build filter got: %s", err.Error())
+		return err
+	}
+	column, direction := ParseSort(filter.Sort)
+
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees`
+	query += whereClause(where)
+	query += fmt.Sprintf(" order by %s %s", column, direction)
+	if column != "id" {
+		query += fmt.Sprintf(", id %s", direction)
+	}
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, args...)
+	if err != nil {
+		rLog.Errorf("error when stream employees got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Employee{}
+		var customFields []byte
+		err := rows.Scan(&temp.ID, &temp.FirstName, &temp.LastName, &temp.Email, &temp.HireDate, &temp.ManagerID, &temp.DepartmentID, &temp.PositionID, &temp.Status, &temp.TerminationDate, &temp.TerminationReason, &customFields, &temp.DeletedAt, &temp.Version)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return repository.TranslateError(err)
+		}
+
+		if temp.CustomFields, err = decodeCustomFields(customFields); err != nil {
+			rLog.Errorf("error when decode custom fields got: %s", err.Error())
+			return err
+		}
+
+		if err := fn(temp); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (u *userRepo) CountEmployees(ctx context.Context, filter *model.EmployeeFilter) (int, error) {
+	rLog := logRepo.WithField("function", "CountEmployees")
+
+	var total int
+
+	where, args, err := buildFilter(filter)
+	if err != nil {
+		rLog.Errorf("error when build filter got: %s", err.Error())
+		return 0, err
+	}
+	query := `select count(*) from employees` + whereClause(where)
+
+	err = repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count employees got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+func (u *userRepo) GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*model.Employee, error) {
+	condition := ``
+	if !includeDeleted {
+		condition = ` and deleted_at IS NULL`
+	}
+
+	return u.getEmployee(ctx, "GetEmployeeByID", employeeID, condition)
+}
+
+// GetEmployeeForUpdate reads the employee and locks its row until the
+// transaction ends, so a read-modify-write sees no concurrent change.
+func (u *userRepo) GetEmployeeForUpdate(ctx context.Context, employeeID int) (*model.Employee, error) {
+	return u.getEmployee(ctx, "GetEmployeeForUpdate", employeeID, ` and deleted_at IS NULL FOR UPDATE`)
+}
+
+func (u *userRepo) getEmployee(ctx context.Context, function string, employeeID int, condition string) (*model.Employee, error) {
+	rLog := logRepo.WithField("function", function)
 
 	employees := &model.Employee{}
 
-	query := `select id, first_name, last_name, email, hire_date from employees where id = $1`
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason,
+		phone, to_char(date_of_birth, 'YYYY-MM-DD'), address, emergency_contacts, national_id, work_location, custom_fields, deleted_at, version from employees where id = $1` + condition
+
+	row := repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, employeeID)
 
-	row := u.sqlConn.QueryRowContext(ctx, query, employeeID)
+	var address, contacts, customFields []byte
 
-	err := row.Scan(&employees.ID, &employees.FirstName, &employees.LastName, &employees.Email, &employees.HireDate)
+	err := row.Scan(&employees.ID, &employees.FirstName, &employees.LastName, &employees.Email, &employees.HireDate, &employees.ManagerID, &employees.DepartmentID, &employees.PositionID, &employees.Status, &employees.TerminationDate, &employees.TerminationReason,
+		&employees.Phone, &employees.DateOfBirth, &address, &contacts, &employees.NationalID, &employees.WorkLocation, &customFields, &employees.DeletedAt, &employees.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	if err := decodeProfile(employees, address, contacts); err != nil {
+		rLog.Errorf("error when decode profile got: %s", err.Error())
+		return nil, err
+	}
+
+	if employees.CustomFields, err = decodeCustomFields(customFields); err != nil {
+		rLog.Errorf("error when decode custom fields got: %s", err.Error())
 		return nil, err
 	}
 
 	return employees, nil
 }
 
+// UpdateEmployee writes the employee, its profile and its custom fields only if
+// it is still at employee.Version.
 func (u *userRepo) UpdateEmployee(ctx context.Context, employee *model.Employee) error {
 	rLog := logRepo.WithField("function", "GetEmployeeByID")
 
-	values := []interface{}{employee.FirstName, employee.LastName, employee.Email, employee.HireDate, employee.ID}
+	address, contacts, err := encodeProfile(employee)
+	if err != nil {
+		rLog.Errorf("error when encode profile got: %s", err.Error())
+		return err
+	}
 
-	query := `UPDATE employees  SET first_name=$1, last_name=$2, email=$3, hire_date=$4 where id = $5`
+	customFields, err := encodeCustomFields(employee.CustomFields)
+	if err != nil {
+		rLog.Errorf("error when encode custom fields got: %s", err.Error())
+		return err
+	}
+
+	values := []interface{}{employee.FirstName, employee.LastName, employee.Email, employee.HireDate, employee.DepartmentID, employee.ManagerID,
+		employee.Phone, employee.DateOfBirth, address, contacts, employee.NationalID, employee.WorkLocation, customFields, employee.ID, employee.Version}
 
-	_, err := u.sqlConn.ExecContext(ctx, query, values...)
+	query := `UPDATE employees  SET first_name=$1, last_name=$2, email=$3, hire_date=$4, department_id=$5, manager_id=$6,
+		phone=$7, date_of_birth=$8, address=$9, emergency_contacts=$10, national_id=$11, work_location=$12, custom_fields=$13,
+		version = version + 1 where id = $14 and version = $15 and deleted_at IS NULL`
+
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, values...)
 	if err != nil {
 		rLog.Error(err)
-		return err
+		return repository.TranslateError(err)
 	}
 
-	return nil
+	return checkVersion(result)
 }
 
-func (u *userRepo) DeleteEmployee(ctx context.Context, employeeID int) error {
+// PatchEmployee writes only the columns set in the patch, if the employee is
+// still at patch.Version.
+func (u *userRepo) PatchEmployee(ctx context.Context, employeeID int, patch *model.EmployeePatch) error {
+	rLog := logRepo.WithField("function", "PatchEmployee")
+
+	var sets []string
+	var values []interface{}
+
+	columns := []struct {
+		name  string
+		value *string
+	}{
+		{"first_name", patch.FirstName},
+		{"last_name", patch.LastName},
+		{"email", patch.Email},
+		{"hire_date", patch.HireDate},
+	}
+
+	for _, column := range columns {
+		if column.value == nil {
+			continue
+		}
+		values = append(values, *column.value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column.name, len(values)))
+	}
+
+	if patch.Profile != nil {
+		address, contacts, err := encodeProfile(patch.Profile)
+		if err != nil {
+			rLog.Errorf("error when encode profile got: %s", err.Error())
+			return err
+		}
+		values = append(values, patch.Profile.Phone, patch.Profile.DateOfBirth, address, contacts, patch.Profile.NationalID, patch.Profile.WorkLocation)
+		sets = append(sets, fmt.Sprintf("phone = $%d, date_of_birth = $%d, address = $%d, emergency_contacts = $%d, national_id = $%d, work_location = $%d",
+			len(values)-5, len(values)-4, len(values)-3, len(values)-2, len(values)-1, len(values)))
+	}
+
+	if patch.CustomFields != nil {
+		customFields, err := encodeCustomFields(patch.CustomFields)
+		if err != nil {
+			rLog.Errorf("error when encode custom fields got: %s", err.Error())
+			return err
+		}
+		values = append(values, customFields)
+		sets = append(sets, fmt.Sprintf("custom_fields = $%d", len(values)))
+	}
+
+	if len(sets) == 0 {
+		return nil
+	}
+
+	sets = append(sets, "version = version + 1")
+	values = append(values, employeeID, patch.Version)
+	query := fmt.Sprintf(`UPDATE employees SET %s WHERE id = $%d AND version = $%d AND deleted_at IS NULL`,
+		strings.Join(sets, ", "), len(values)-1, len(values))
+
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, values...)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return checkVersion(result)
+}
+
+// SetPosition moves the employee to the position and department if it is
+// still at the given version.
+func (u *userRepo) SetPosition(ctx context.Context, employeeID int, positionID int, departmentID *int, version int) error {
+	rLog := logRepo.WithField("function", "SetPosition")
+
+	query := `UPDATE employees SET position_id = $1, department_id = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL`
+
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, positionID, departmentID, employeeID, version)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return checkVersion(result)
+}
+
+// UpdateStatus writes the employment status, the hire date and the termination
+// of the employee if it is still at employee.Version.
+func (u *userRepo) UpdateStatus(ctx context.Context, employee *model.Employee) error {
+	rLog := logRepo.WithField("function", "UpdateStatus")
+
+	values := []interface{}{employee.Status, employee.HireDate, employee.TerminationDate, employee.TerminationReason, employee.ID, employee.Version}
+
+	query := `UPDATE employees SET status = $1, hire_date = $2, termination_date = $3, termination_reason = $4, version = version + 1 WHERE id = $5 AND version = $6 AND deleted_at IS NULL`
+
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, values...)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return checkVersion(result)
+}
+
+// DeleteEmployee soft deletes the employee if it is still at the given version.
+func (u *userRepo) DeleteEmployee(ctx context.Context, employeeID int, version int) error {
 	rLog := logRepo.WithField("function", "DeleteEmployee")
 
-	query := `DELETE FROM employees WHERE id = $1`
+	query := `UPDATE employees SET deleted_at = NOW(), version = version + 1 WHERE id = $1 AND version = $2 AND deleted_at IS NULL`
 
-	_, err := u.sqlConn.ExecContext(ctx, query, employeeID)
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, employeeID, version)
 	if err != nil {
 		rLog.Error(err)
-		return err
+		return repository.TranslateError(err)
+	}
+
+	return checkVersion(result)
+}
+
+func (u *userRepo) RestoreEmployee(ctx context.Context, employeeID int) error {
+	rLog := logRepo.WithField("function", "RestoreEmployee")
+
+	query := `UPDATE employees SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetPurgedDocumentKeys returns the storage keys of the documents of the
+// employees PurgeEmployees removes for the same time. It locks those employees,
+// so called in the purge transaction no restore slips in between.
+func (u *userRepo) GetPurgedDocumentKeys(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	rLog := logRepo.WithField("function", "GetPurgedDocumentKeys")
+
+	var keys []string
+
+	query := `SELECT d.storage_key FROM employee_documents d JOIN employees e ON e.id = d.employee_id
+		WHERE e.deleted_at IS NOT NULL AND e.deleted_at < $1 FOR UPDATE OF e`
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		rLog.Errorf("error when query document keys got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return keys, nil
+}
+
+// PurgeEmployees hard deletes the employees soft deleted before the given time
+// and returns their ids.
+func (u *userRepo) PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	rLog := logRepo.WithField("function", "PurgeEmployees")
+
+	var purged []int
+
+	query := `DELETE FROM employees WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id`
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		rLog.Error(err)
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var employeeID int
+		if err := rows.Scan(&employeeID); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+		purged = append(purged, employeeID)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return purged, nil
+}
+
+// GetReports walks down the management tree from the manager and returns the
+// employees reporting to them up to depth levels below, deleted employees and
+// their reports excluded. The path guards the walk against cycles left in the
+// data before cycles were rejected.
+func (u *userRepo) GetReports(ctx context.Context, managerID int, depth int) ([]*model.HierarchyEmployee, error) {
+	rLog := logRepo.WithField("function", "GetReports")
+
+	query := `WITH RECURSIVE reports AS (
+		select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, deleted_at, version, 1 AS depth, ARRAY[id] AS path
+		from employees where manager_id = $1 and deleted_at IS NULL
+		UNION ALL
+		select e.id, e.first_name, e.last_name, e.email, e.hire_date, e.manager_id, e.department_id, e.position_id, e.status, to_char(e.termination_date, 'YYYY-MM-DD'), e.termination_reason, e.deleted_at, e.version, r.depth + 1, r.path || e.id
+		from employees e join reports r on e.manager_id = r.id
+		where r.depth < $2 and e.deleted_at IS NULL and NOT e.id = ANY(r.path)
+	)
+	select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, deleted_at, version, depth from reports order by depth, id`
+
+	return u.queryHierarchy(ctx, rLog, query, managerID, depth)
+}
+
+// LockManagementTree takes a transaction scoped advisory lock so that only one
+// transaction at a time checks and changes the managers of employees, two
+// concurrent changes could otherwise each pass the cycle check and form a cycle.
+func (u *userRepo) LockManagementTree(ctx context.Context) error {
+	rLog := logRepo.WithField("function", "LockManagementTree")
+
+	query := `select pg_advisory_xact_lock($1)`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, managementTreeLock)
+	if err != nil {
+		rLog.Errorf("error when lock management tree got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetManagementChain walks up the management tree and returns the managers of
+// the employee, from the direct manager to the root.
+func (u *userRepo) GetManagementChain(ctx context.Context, employeeID int) ([]*model.HierarchyEmployee, error) {
+	rLog := logRepo.WithField("function", "GetManagementChain")
+
+	query := `WITH RECURSIVE chain AS (
+		select m.id, m.first_name, m.last_name, m.email, m.hire_date, m.manager_id, m.department_id, m.position_id, m.status, to_char(m.termination_date, 'YYYY-MM-DD'), m.termination_reason, m.deleted_at, m.version, 1 AS depth, ARRAY[e.id, m.id] AS path
+		from employees e join employees m on m.id = e.manager_id where e.id = $1
+		UNION ALL
+		select m.id, m.first_name, m.last_name, m.email, m.hire_date, m.manager_id, m.department_id, m.position_id, m.status, to_char(m.termination_date, 'YYYY-MM-DD'), m.termination_reason, m.deleted_at, m.version, c.depth + 1, c.path || m.id
+		from employees m join chain c on m.id = c.manager_id
+		where NOT m.id = ANY(c.path)
+	)
+	select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, deleted_at, version, depth from chain order by depth`
+
+	return u.queryHierarchy(ctx, rLog, query, employeeID)
+}
+
+func (u *userRepo) queryHierarchy(ctx context.Context, rLog *log.Entry, query string, args ...interface{}) ([]*model.HierarchyEmployee, error) {
+	var employees []*model.HierarchyEmployee
+
+	rows, err := repository.Conn(ctx, u.sqlConn).QueryContext(ctx, query, args...)
+	if err != nil {
+		rLog.Errorf("error when walk hierarchy got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.HierarchyEmployee{}
+		err := rows.Scan(&temp.ID, &temp.FirstName, &temp.LastName, &temp.Email, &temp.HireDate, &temp.ManagerID, &temp.DepartmentID, &temp.PositionID, &temp.Status, &temp.TerminationDate, &temp.TerminationReason, &temp.DeletedAt, &temp.Version, &temp.Depth)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		employees = append(employees, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return employees, nil
+}
+
+// encodeProfile encodes the json columns of the profile, a missing address is
+// stored as NULL and missing emergency contacts as an empty array.
+func encodeProfile(employee *model.Employee) (interface{}, string, error) {
+	var address interface{}
+	if employee.Address != nil {
+		encoded, err := json.Marshal(employee.Address)
+		if err != nil {
+			return nil, "", err
+		}
+		address = string(encoded)
+	}
+
+	contacts := employee.EmergencyContacts
+	if contacts == nil {
+		contacts = []model.EmergencyContact{}
+	}
+
+	encoded, err := json.Marshal(contacts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return address, string(encoded), nil
+}
+
+func decodeProfile(employee *model.Employee, address []byte, contacts []byte) error {
+	if len(address) > 0 {
+		employee.Address = &model.Address{}
+		if err := json.Unmarshal(address, employee.Address); err != nil {
+			return err
+		}
+	}
+
+	if len(contacts) > 0 {
+		if err := json.Unmarshal(contacts, &employee.EmergencyContacts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeCustomFields stores an employee without custom fields as an empty object.
+func encodeCustomFields(customFields map[string]interface{}) (string, error) {
+	if customFields == nil {
+		return "{}", nil
+	}
+
+	encoded, err := json.Marshal(customFields)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// decodeCustomFields returns nil for an employee without custom fields.
+func decodeCustomFields(raw []byte) (map[string]interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var customFields map[string]interface{}
+	if err := json.Unmarshal(raw, &customFields); err != nil {
+		return nil, err
+	}
+
+	if len(customFields) == 0 {
+		return nil, nil
+	}
+
+	return customFields, nil
+}
+
+// checkVersion turns a conditional write that matched no row into ErrStaleVersion.
+func checkVersion(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return repository.TranslateError(err)
+	}
+
+	if affected == 0 {
+		return ErrStaleVersion
 	}
 
 	return nil
 }
+
+// ParseSort resolves a sort parameter such as "-hire_date" into a whitelisted
+// column and direction, falling back to the newest employees first.
+func ParseSort(sort string) (string, string) {
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	column, ok := sortColumns[sort]
+	if !ok {
+		return "id", "DESC"
+	}
+
+	return column, direction
+}
+
+func buildFilter(filter *model.EmployeeFilter) ([]string, []interface{}, error) {
+	var where []string
+	var args []interface{}
+
+	if !filter.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
+
+	prefixes := []struct {
+		column string
+		value  string
+	}{
+		{"first_name", filter.FirstName},
+		{"last_name", filter.LastName},
+		{"email", filter.Email},
+	}
+
+	for _, prefix := range prefixes {
+		if prefix.value == "" {
+			continue
+		}
+		args = append(args, escapeLike(prefix.value)+"%")
+		where = append(where, fmt.Sprintf("%s ILIKE $%d", prefix.column, len(args)))
+	}
+
+	if filter.HireDateFrom != "" {
+		args = append(args, filter.HireDateFrom)
+		where = append(where, fmt.Sprintf("hire_date >= $%d", len(args)))
+	}
+
+	if filter.HireDateTo != "" {
+		args = append(args, filter.HireDateTo)
+		where = append(where, fmt.Sprintf("hire_date <= $%d", len(args)))
+	}
+
+	if filter.ManagerID > 0 {
+		args = append(args, filter.ManagerID)
+		where = append(where, fmt.Sprintf("manager_id = $%d", len(args)))
+	}
+
+	if filter.DepartmentID > 0 {
+		args = append(args, filter.DepartmentID)
+		where = append(where, fmt.Sprintf("department_id = $%d", len(args)))
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	// one containment covers every custom field and is served by the gin index
+	if len(filter.CustomFields) > 0 {
+		customFields, err := json.Marshal(filter.CustomFields)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, string(customFields))
+		where = append(where, fmt.Sprintf("custom_fields @> $%d", len(args)))
+	}
+
+	return where, args, nil
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+
+	return " where " + strings.Join(where, " and ")
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
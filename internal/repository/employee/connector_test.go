@@ -3,18 +3,23 @@ package employee
 import (
 	"context"
 	"database/sql"
+	"employee/internal/apperror"
 	"employee/internal/model"
+	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestCreateEmployee(t *testing.T) {
 	query := `INSERT INTO employees 
-		(first_name, last_name,email,hire_date )
-		values ($1, $2, $3, $4) returning id`
+		(first_name, last_name,email,hire_date, department_id, manager_id, position_id, status,
+		phone, date_of_birth, address, emergency_contacts, national_id, work_location, custom_fields)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) returning id`
 
 	employee := &model.Employee{
 		FirstName: "test",
@@ -22,6 +27,7 @@ func TestCreateEmployee(t *testing.T) {
 		Email:     "test@test",
 		HireDate:  "2023-05-02",
 	}
+	phone := "+14155550123"
 
 	testCase := []struct {
 		name        string
@@ -42,6 +48,51 @@ func TestCreateEmployee(t *testing.T) {
 				assert.Zero(t, resultID)
 			},
 		},
+		{
+			name:    "error when email is taken",
+			payload: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).WillReturnError(&pq.Error{Code: "23505", Constraint: "employees_email_key"})
+			},
+			checkReturn: func(resultID int, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeConflict, appErr.Code)
+				assert.Equal(t, "email", appErr.Field)
+				assert.Zero(t, resultID)
+			},
+		},
+		{
+			name:    "error when department does not exist",
+			payload: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).WillReturnError(&pq.Error{Code: "23503", Constraint: "employees_department_id_fkey"})
+			},
+			checkReturn: func(resultID int, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeValidation, appErr.Code)
+				assert.Equal(t, "department_id", appErr.Field)
+				assert.Zero(t, resultID)
+			},
+		},
+		{
+			name:    "error when national id is taken",
+			payload: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).WillReturnError(&pq.Error{Code: "23505", Constraint: "employees_national_id_key"})
+			},
+			checkReturn: func(resultID int, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeConflict, appErr.Code)
+				assert.Equal(t, "national_id", appErr.Field)
+				assert.Zero(t, resultID)
+			},
+		},
 		{
 			name:    "success",
 			payload: employee,
@@ -54,6 +105,31 @@ func TestCreateEmployee(t *testing.T) {
 				assert.NotZero(t, resultID)
 			},
 		},
+		{
+			name: "success with profile",
+			payload: &model.Employee{
+				FirstName:         "test",
+				Email:             "test@test.com",
+				HireDate:          "2023-05-02",
+				Status:            "active",
+				Phone:             &phone,
+				Address:           &model.Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+				EmergencyContacts: []model.EmergencyContact{{Name: "Jane", Relationship: "spouse", Phone: "+14155550100"}},
+				CustomFields:      map[string]interface{}{"cost_center": "CC-12"},
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).
+					WithArgs("test", "", "test@test.com", "2023-05-02", nil, nil, nil, "active", &phone, nil,
+						`{"line1":"1 Main St","city":"Springfield","country":"US"}`,
+						`[{"name":"Jane","relationship":"spouse","phone":"+14155550100"}]`, nil, nil, `{"cost_center":"CC-12"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+			},
+			checkReturn: func(resultID int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, resultID)
+			},
+		},
 	}
 
 	for _, tc := range testCase {
@@ -78,20 +154,34 @@ func TestCreateEmployee(t *testing.T) {
 	}
 }
 
-func TestGetEmployees(t *testing.T) {
-	query := `select id, first_name, last_name, email, hire_date from employees order by id DESC`
+func TestCreateEmployees(t *testing.T) {
+	query := `INSERT INTO employees (first_name, last_name, email, hire_date, department_id, manager_id, position_id, status,
+		phone, date_of_birth, address, emergency_contacts, national_id, work_location, custom_fields) values ` +
+		`($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15), ` +
+		`($16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30) ON CONFLICT (LOWER(email)) DO NOTHING returning id, email`
+
+	positionID := 2
+	phone := "+14155550123"
+	newEmployees := func() []*model.Employee {
+		return []*model.Employee{
+			{FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01", Status: model.EmploymentStatusActive,
+				CustomFields: map[string]interface{}{"remote": true}},
+			{FirstName: "jane", Email: "Jane@mail.com", HireDate: "2023-05-02", Status: model.EmploymentStatusProbation,
+				PositionID: &positionID, Phone: &phone, Address: &model.Address{Line1: "1 Main St", City: "Springfield", Country: "US"}},
+		}
+	}
 
 	testCase := []struct {
 		name        string
+		employees   []*model.Employee
 		buildStub   func(mock sqlmock.Sqlmock)
 		checkReturn func(result []*model.Employee, err error)
 	}{
-
 		{
-			name: "error connection when get employee",
+			name:      "error connection when create employees",
+			employees: newEmployees(),
 			buildStub: func(mock sqlmock.Sqlmock) {
-				runQueryCount := regexp.QuoteMeta(query)
-				mock.ExpectQuery(runQueryCount).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
 			},
 			checkReturn: func(result []*model.Employee, err error) {
 				assert.Error(t, err)
@@ -99,15 +189,22 @@ func TestGetEmployees(t *testing.T) {
 			},
 		},
 		{
-			name: "success",
+			name:      "success skips taken emails",
+			employees: newEmployees(),
 			buildStub: func(mock sqlmock.Sqlmock) {
-				runQueryCount := regexp.QuoteMeta(query)
-				mock.ExpectQuery(runQueryCount).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "email", "hire_date"}).
-					AddRow("1", "test", "test", "test@mail.com", "2023-05-03"))
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("john", "", "john@mail.com", "2023-05-01", nil, nil, nil, "active",
+						nil, nil, nil, "[]", nil, nil, `{"remote":true}`,
+						"jane", "", "Jane@mail.com", "2023-05-02", nil, nil, positionID, "probation",
+						phone, nil, `{"line1":"1 Main St","city":"Springfield","country":"US"}`, "[]", nil, nil, "{}").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(8, "Jane@mail.com"))
 			},
 			checkReturn: func(result []*model.Employee, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
+				require.Len(t, result, 1)
+				assert.Equal(t, 8, result[0].ID)
+				assert.Equal(t, "jane", result[0].FirstName)
+				assert.Equal(t, &positionID, result[0].PositionID)
 			},
 		},
 	}
@@ -123,7 +220,7 @@ func TestGetEmployees(t *testing.T) {
 
 			repo := NewRepoUser(db)
 
-			result, err := repo.GetEmployees(context.TODO())
+			result, err := repo.CreateEmployees(context.TODO(), tc.employees)
 
 			tc.checkReturn(result, err)
 
@@ -134,41 +231,109 @@ func TestGetEmployees(t *testing.T) {
 	}
 }
 
-func TestGetEmployeeByID(t *testing.T) {
-	query := `select id, first_name, last_name, email, hire_date from employees where id = $1`
+func TestCreateEmployeesBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	employees := make([]*model.Employee, importBatchSize+1)
+	for i := range employees {
+		employees[i] = &model.Employee{FirstName: "test", Email: fmt.Sprintf("test%d@mail.com", i), HireDate: "2023-05-01"}
+	}
+
+	mock.ExpectQuery(`INSERT INTO employees .* \(\$7486, .*, \$7500\) ON CONFLICT`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "test0@mail.com"))
+	mock.ExpectQuery(regexp.QuoteMeta(`values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) ON CONFLICT`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(2, fmt.Sprintf("test%d@mail.com", importBatchSize)))
+
+	result, err := NewRepoUser(db).CreateEmployees(context.TODO(), employees)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExistingEmails(t *testing.T) {
+	query := `select LOWER(email) from employees where LOWER(email) = ANY($1)`
 
 	testCase := []struct {
 		name        string
-		employeeID  int
 		buildStub   func(mock sqlmock.Sqlmock)
-		checkReturn func(result *model.Employee, err error)
+		checkReturn func(result map[string]bool, err error)
 	}{
-
 		{
-			name: "error connection when get employee by id",
+			name: "error connection when get existing emails",
 			buildStub: func(mock sqlmock.Sqlmock) {
-				runQueryCount := regexp.QuoteMeta(query)
-				mock.ExpectQuery(runQueryCount).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
 			},
-			checkReturn: func(result *model.Employee, err error) {
+			checkReturn: func(result map[string]bool, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, result)
 			},
 		},
 		{
-			name:       "success",
-			employeeID: 1,
+			name: "success",
 			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(pq.Array([]string{"john@mail.com", "jane@mail.com"})).
+					WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("jane@mail.com"))
+			},
+			checkReturn: func(result map[string]bool, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]bool{"jane@mail.com": true}, result)
+			},
+		},
+	}
 
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "email", "hire_date"}).
-					AddRow(1, "test", "test", "test@mail.com", "2023-05-03")
-				runQuery := regexp.QuoteMeta(query)
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
 
-				mock.ExpectQuery(runQuery).WillReturnRows(rows)
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetExistingEmails(context.TODO(), []string{"john@mail.com", "Jane@mail.com"})
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetExistingDepartments(t *testing.T) {
+	query := `select id from departments where id = ANY($1)`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result map[int]bool, err error)
+	}{
+		{
+			name: "error connection when get existing departments",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
 			},
-			checkReturn: func(result *model.Employee, err error) {
+			checkReturn: func(result map[int]bool, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			checkReturn: func(result map[int]bool, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
+				assert.Equal(t, map[int]bool{2: true}, result)
 			},
 		},
 	}
@@ -184,7 +349,7 @@ func TestGetEmployeeByID(t *testing.T) {
 
 			repo := NewRepoUser(db)
 
-			result, err := repo.GetEmployeeByID(context.TODO(), tc.employeeID)
+			result, err := repo.GetExistingDepartments(context.TODO(), []int{1, 2})
 
 			tc.checkReturn(result, err)
 
@@ -195,45 +360,147 @@ func TestGetEmployeeByID(t *testing.T) {
 	}
 }
 
-func TestUpdateEmployee(t *testing.T) {
-	query := `UPDATE employees  SET first_name=$1, last_name=$2, email=$3, hire_date=$4 where id = $5`
+func TestGetEmployees(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees where deleted_at IS NULL order by id DESC limit $1`
 
-	employee := &model.Employee{
-		ID:        1,
-		FirstName: "test",
-		LastName:  "test",
-		Email:     "test@test",
-		HireDate:  "2023-05-02",
-	}
+	filteredQuery := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees where first_name ILIKE $1 and hire_date >= $2 and (hire_date, id) > ($3, $4) order by hire_date ASC, id ASC limit $5`
 
 	testCase := []struct {
 		name        string
-		model       *model.Employee
+		filter      *model.EmployeeFilter
 		buildStub   func(mock sqlmock.Sqlmock)
-		checkReturn func(err error)
+		checkReturn func(result []*model.Employee, err error)
 	}{
 
 		{
-			name:  "error connection when update employee",
-			model: employee,
+			name:   "error connection when get employee",
+			filter: &model.EmployeeFilter{Limit: 20},
 			buildStub: func(mock sqlmock.Sqlmock) {
 				runQueryCount := regexp.QuoteMeta(query)
-				mock.ExpectExec(runQueryCount).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(runQueryCount).WillReturnError(sql.ErrConnDone)
 			},
-			checkReturn: func(err error) {
+			checkReturn: func(result []*model.Employee, err error) {
 				assert.Error(t, err)
+				assert.Nil(t, result)
 			},
 		},
 		{
-			name:  "success",
-			model: employee,
+			name:   "success",
+			filter: &model.EmployeeFilter{Limit: 20},
 			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).WithArgs(20).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "custom_fields", "deleted_at", "version"}).
+					AddRow("1", "test", "test", "test@mail.com", "2023-05-03", nil, nil, nil, "active", nil, nil, []byte("{}"), nil, 1))
+			},
+			checkReturn: func(result []*model.Employee, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			},
+		},
+		{
+			name:   "success with custom fields",
+			filter: &model.EmployeeFilter{Limit: 20, CustomFields: map[string]interface{}{"remote": true, "cost_center": "CC-12"}},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQuery := regexp.QuoteMeta(`select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees where deleted_at IS NULL and custom_fields @> $1 order by id DESC limit $2`)
+				mock.ExpectQuery(runQuery).WithArgs(`{"cost_center":"CC-12","remote":true}`, 20).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "custom_fields", "deleted_at", "version"}))
+			},
+			checkReturn: func(result []*model.Employee, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, result)
+			},
+		},
+		{
+			name: "success with filter, sort and cursor",
+			filter: &model.EmployeeFilter{
+				FirstName:      "jo_",
+				HireDateFrom:   "2023-01-01",
+				IncludeDeleted: true,
+				Sort:           "hire_date",
+				Limit:          10,
+				Cursor:         &model.EmployeeCursor{Value: "2023-05-03", ID: 4},
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQuery := regexp.QuoteMeta(filteredQuery)
+				mock.ExpectQuery(runQuery).WithArgs(`jo\_%`, "2023-01-01", "2023-05-03", 4, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "custom_fields", "deleted_at", "version"}).
+						AddRow("5", "jo_e", "test", "test@mail.com", "2023-05-04", 1, nil, nil, "active", nil, nil, []byte(`{"cost_center":"CC-12"}`), time.Now(), 2))
+			},
+			checkReturn: func(result []*model.Employee, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 1)
+				assert.Equal(t, "CC-12", result[0].CustomFields["cost_center"])
+			},
+		},
+	}
 
-				runQuery := regexp.QuoteMeta(query)
-				mock.ExpectExec(runQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetEmployees(context.TODO(), tc.filter)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestStreamEmployees(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, custom_fields, deleted_at, version from employees where deleted_at IS NULL and last_name ILIKE $1 order by hire_date DESC, id DESC`
+
+	filter := &model.EmployeeFilter{LastName: "may", Sort: "-hire_date", Limit: 10}
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "custom_fields", "deleted_at", "version"}
+
+	testCase := []struct {
+		name        string
+		fnErr       error
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(streamed []int, err error)
+	}{
+		{
+			name: "error connection when stream employees",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
 			},
-			checkReturn: func(err error) {
+			checkReturn: func(streamed []int, err error) {
+				assert.Error(t, err)
+				assert.Empty(t, streamed)
+			},
+		},
+		{
+			name:  "error from fn stops the stream",
+			fnErr: sql.ErrTxDone,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, "john", "mayer", "john@mail.com", "2023-05-03", nil, nil, nil, "active", nil, nil, []byte("{}"), nil, 1).
+					AddRow(2, "jane", "mayer", "jane@mail.com", "2023-05-02", nil, nil, nil, "active", nil, nil, []byte("{}"), nil, 1))
+			},
+			checkReturn: func(streamed []int, err error) {
+				assert.ErrorIs(t, err, sql.ErrTxDone)
+				assert.Equal(t, []int{1}, streamed)
+			},
+		},
+		{
+			name: "success ignores limit",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("may%").WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, "john", "mayer", "john@mail.com", "2023-05-03", nil, nil, nil, "active", nil, nil, []byte("{}"), nil, 1).
+					AddRow(2, "jane", "mayer", "jane@mail.com", "2023-05-02", 1, nil, nil, "active", nil, nil, []byte("{}"), nil, 3))
+			},
+			checkReturn: func(streamed []int, err error) {
 				assert.NoError(t, err)
+				assert.Equal(t, []int{1, 2}, streamed)
 			},
 		},
 	}
@@ -249,9 +516,13 @@ func TestUpdateEmployee(t *testing.T) {
 
 			repo := NewRepoUser(db)
 
-			err = repo.UpdateEmployee(context.TODO(), tc.model)
+			var streamed []int
+			err = repo.StreamEmployees(context.TODO(), filter, func(employee *model.Employee) error {
+				streamed = append(streamed, employee.ID)
+				return tc.fnErr
+			})
 
-			tc.checkReturn(err)
+			tc.checkReturn(streamed, err)
 
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %s", err)
@@ -260,37 +531,38 @@ func TestUpdateEmployee(t *testing.T) {
 	}
 }
 
-func TestDeleteEmployee(t *testing.T) {
-	query := `DELETE FROM employees WHERE id = $1`
+func TestCountEmployees(t *testing.T) {
+	query := `select count(*) from employees where deleted_at IS NULL and email ILIKE $1 and department_id = $2`
 
 	testCase := []struct {
 		name        string
-		employeeID  int
+		filter      *model.EmployeeFilter
 		buildStub   func(mock sqlmock.Sqlmock)
-		checkReturn func(err error)
+		checkReturn func(total int, err error)
 	}{
 
 		{
-			name:       "error connection when delete employee",
-			employeeID: 1,
+			name:   "error connection when count employee",
+			filter: &model.EmployeeFilter{Email: "john", DepartmentID: 3},
 			buildStub: func(mock sqlmock.Sqlmock) {
-				runQueryCount := regexp.QuoteMeta(query)
-				mock.ExpectExec(runQueryCount).WillReturnError(sql.ErrConnDone)
+				runQuery := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQuery).WillReturnError(sql.ErrConnDone)
 			},
-			checkReturn: func(err error) {
+			checkReturn: func(total int, err error) {
 				assert.Error(t, err)
+				assert.Zero(t, total)
 			},
 		},
 		{
-			name:       "success",
-			employeeID: 1,
+			name:   "success",
+			filter: &model.EmployeeFilter{Email: "john", DepartmentID: 3},
 			buildStub: func(mock sqlmock.Sqlmock) {
-
 				runQuery := regexp.QuoteMeta(query)
-				mock.ExpectExec(runQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(runQuery).WithArgs("john%", 3).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 			},
-			checkReturn: func(err error) {
+			checkReturn: func(total int, err error) {
 				assert.NoError(t, err)
+				assert.Equal(t, 3, total)
 			},
 		},
 	}
@@ -306,9 +578,843 @@ func TestDeleteEmployee(t *testing.T) {
 
 			repo := NewRepoUser(db)
 
-			err = repo.DeleteEmployee(context.TODO(), tc.employeeID)
+			result, err := repo.CountEmployees(context.TODO(), tc.filter)
 
-			tc.checkReturn(err)
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetEmployeeByID(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason,
+		phone, to_char(date_of_birth, 'YYYY-MM-DD'), address, emergency_contacts, national_id, work_location, custom_fields, deleted_at, version from employees where id = $1 and deleted_at IS NULL`
+
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason",
+		"phone", "date_of_birth", "address", "emergency_contacts", "national_id", "work_location", "custom_fields", "deleted_at", "version"}
+
+	testCase := []struct {
+		name        string
+		employeeID  int
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.Employee, err error)
+	}{
+
+		{
+			name: "error connection when get employee by id",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectQuery(runQueryCount).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result *model.Employee, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "test", "test", "test@mail.com", "2023-05-03", 2, 4, nil, "active", nil, nil, nil, nil, nil, []byte("[]"), nil, nil, []byte("{}"), nil, 3)
+				runQuery := regexp.QuoteMeta(query)
+
+				mock.ExpectQuery(runQuery).WillReturnRows(rows)
+			},
+			checkReturn: func(result *model.Employee, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, 4, *result.DepartmentID)
+				assert.Nil(t, result.Address)
+				assert.Empty(t, result.EmergencyContacts)
+			},
+		},
+		{
+			name:       "success with profile",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "test", "test", "test@mail.com", "2023-05-03", nil, nil, nil, "active", nil, nil,
+						"+14155550123", "1990-04-12", []byte(`{"line1":"1 Main St","city":"Springfield","country":"US"}`),
+						[]byte(`[{"name":"Jane","relationship":"spouse","phone":"+14155550100"}]`), "AB123456", "Berlin", []byte(`{"cost_center":"CC-12","remote":true}`), nil, 3)
+
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(rows)
+			},
+			checkReturn: func(result *model.Employee, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, "1990-04-12", *result.DateOfBirth)
+				assert.Equal(t, &model.Address{Line1: "1 Main St", City: "Springfield", Country: "US"}, result.Address)
+				assert.Equal(t, []model.EmergencyContact{{Name: "Jane", Relationship: "spouse", Phone: "+14155550100"}}, result.EmergencyContacts)
+				assert.Equal(t, "AB123456", *result.NationalID)
+				assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "remote": true}, result.CustomFields)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetEmployeeByID(context.TODO(), tc.employeeID, false)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetEmployeeForUpdate(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason,
+		phone, to_char(date_of_birth, 'YYYY-MM-DD'), address, emergency_contacts, national_id, work_location, custom_fields, deleted_at, version from employees where id = $1 and deleted_at IS NULL FOR UPDATE`
+
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason",
+		"phone", "date_of_birth", "address", "emergency_contacts", "national_id", "work_location", "custom_fields", "deleted_at", "version"}
+
+	testCase := []struct {
+		name        string
+		employeeID  int
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.Employee, err error)
+	}{
+		{
+			name:       "not found",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnError(sql.ErrNoRows)
+			},
+			checkReturn: func(result *model.Employee, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:       "success locks the row",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "test", "test", "test@mail.com", "2023-05-03", nil, nil, nil, "active", nil, nil, nil, nil, nil, []byte("[]"), nil, nil, []byte("{}"), nil, 3)
+
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnRows(rows)
+			},
+			checkReturn: func(result *model.Employee, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, 3, result.Version)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetEmployeeForUpdate(context.TODO(), tc.employeeID)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateEmployee(t *testing.T) {
+	query := `UPDATE employees  SET first_name=$1, last_name=$2, email=$3, hire_date=$4, department_id=$5, manager_id=$6,
+		phone=$7, date_of_birth=$8, address=$9, emergency_contacts=$10, national_id=$11, work_location=$12, custom_fields=$13,
+		version = version + 1 where id = $14 and version = $15 and deleted_at IS NULL`
+
+	departmentID := 3
+	managerID := 5
+	employee := &model.Employee{
+		ID:           1,
+		FirstName:    "test",
+		LastName:     "test",
+		Email:        "test@test",
+		HireDate:     "2023-05-02",
+		DepartmentID: &departmentID,
+		ManagerID:    &managerID,
+		Version:      2,
+	}
+
+	testCase := []struct {
+		name        string
+		model       *model.Employee
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+
+		{
+			name:  "error connection when update employee",
+			model: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQueryCount).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:  "error when email is taken",
+			model: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQueryCount).WillReturnError(&pq.Error{Code: "23505", Constraint: "employees_email_key"})
+			},
+			checkReturn: func(err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			},
+		},
+		{
+			name:  "success",
+			model: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+
+				runQuery := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQuery).WithArgs("test", "test", "test@test", "2023-05-02", 3, 5, nil, nil, nil, "[]", nil, nil, "{}", 1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "error when version is stale",
+			model: employee,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQuery := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrStaleVersion)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.UpdateEmployee(context.TODO(), tc.model)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestPatchEmployee(t *testing.T) {
+	email := "new@test"
+	phone := "+14155550123"
+	hireDate := "2023-05-03"
+
+	testCase := []struct {
+		name        string
+		patch       *model.EmployeePatch
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name:      "nothing to write",
+			patch:     &model.EmployeePatch{},
+			buildStub: func(mock sqlmock.Sqlmock) {},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "error when email is taken",
+			patch: &model.EmployeePatch{Email: &email, Version: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				query := `UPDATE employees SET email = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL`
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "employees_email_key"})
+			},
+			checkReturn: func(err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			},
+		},
+		{
+			name:  "success writes only the changed columns",
+			patch: &model.EmployeePatch{Email: &email, HireDate: &hireDate, Version: 4},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				query := `UPDATE employees SET email = $1, hire_date = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL`
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(email, hireDate, 1, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "success writes the whole profile",
+			patch: &model.EmployeePatch{Profile: &model.Employee{Phone: &phone, Address: &model.Address{Line1: "1 Main St", City: "Berlin", Country: "DE"}}, Version: 4},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				query := `UPDATE employees SET phone = $1, date_of_birth = $2, address = $3, emergency_contacts = $4, national_id = $5, work_location = $6, version = version + 1 WHERE id = $7 AND version = $8 AND deleted_at IS NULL`
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(&phone, (*string)(nil), `{"line1":"1 Main St","city":"Berlin","country":"DE"}`, `[]`, (*string)(nil), (*string)(nil), 1, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "error when version is stale",
+			patch: &model.EmployeePatch{Email: &email, Version: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				query := `UPDATE employees SET email = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL`
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(email, 1, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrStaleVersion)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.PatchEmployee(context.TODO(), 1, tc.patch)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestSetPosition(t *testing.T) {
+	query := `UPDATE employees SET position_id = $1, department_id = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL`
+
+	departmentID := 3
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error when position does not exist",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23503", Constraint: "employees_position_id_fkey"})
+			},
+			checkReturn: func(err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, "position_id", appErr.Field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2, &departmentID, 1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "error when version is stale",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(2, &departmentID, 1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrStaleVersion)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.SetPosition(context.TODO(), 1, 2, &departmentID, 3)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	query := `UPDATE employees SET status = $1, hire_date = $2, termination_date = $3, termination_reason = $4, version = version + 1 WHERE id = $5 AND version = $6 AND deleted_at IS NULL`
+
+	terminationDate := "2024-03-01"
+	reason := "resigned"
+
+	employee := &model.Employee{
+		ID:                1,
+		HireDate:          "2023-05-03",
+		Status:            model.EmploymentStatusTerminated,
+		TerminationDate:   &terminationDate,
+		TerminationReason: &reason,
+		Version:           2,
+	}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error when status is not allowed",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23514", Constraint: "employees_status_check"})
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("terminated", "2023-05-03", &terminationDate, &reason, 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "error when version is stale",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("terminated", "2023-05-03", &terminationDate, &reason, 1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrStaleVersion)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.UpdateStatus(context.TODO(), employee)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeleteEmployee(t *testing.T) {
+	query := `UPDATE employees SET deleted_at = NOW(), version = version + 1 WHERE id = $1 AND version = $2 AND deleted_at IS NULL`
+
+	testCase := []struct {
+		name        string
+		employeeID  int
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+
+		{
+			name:       "error connection when delete employee",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQueryCount := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQueryCount).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+
+				runQuery := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQuery).WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "error when version is stale",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				runQuery := regexp.QuoteMeta(query)
+				mock.ExpectExec(runQuery).WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrStaleVersion)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.DeleteEmployee(context.TODO(), tc.employeeID, 3)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestRestoreEmployee(t *testing.T) {
+	query := `UPDATE employees SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	testCase := []struct {
+		name        string
+		employeeID  int
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+
+		{
+			name:       "error connection when restore employee",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:       "success",
+			employeeID: 1,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.RestoreEmployee(context.TODO(), tc.employeeID)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetPurgedDocumentKeys(t *testing.T) {
+	query := `SELECT d.storage_key FROM employee_documents d JOIN employees e ON e.id = d.employee_id
+		WHERE e.deleted_at IS NOT NULL AND e.deleted_at < $1 FOR UPDATE OF e`
+
+	deletedBefore := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(keys []string, err error)
+	}{
+		{
+			name: "error connection when get document keys",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(keys []string, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, keys)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"storage_key"}).AddRow("employees/3/a1").AddRow("employees/7/b2")
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(deletedBefore).WillReturnRows(rows)
+			},
+			checkReturn: func(keys []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"employees/3/a1", "employees/7/b2"}, keys)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			keys, err := repo.GetPurgedDocumentKeys(context.TODO(), deletedBefore)
+
+			tc.checkReturn(keys, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestPurgeEmployees(t *testing.T) {
+	query := `DELETE FROM employees WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id`
+
+	deletedBefore := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(purged []int, err error)
+	}{
+
+		{
+			name: "error connection when purge employees",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(purged []int, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, purged)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(7)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(deletedBefore).WillReturnRows(rows)
+			},
+			checkReturn: func(purged []int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []int{3, 7}, purged)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			purged, err := repo.PurgeEmployees(context.TODO(), deletedBefore)
+
+			tc.checkReturn(purged, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetReports(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, deleted_at, version, depth from reports order by depth, id`
+
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "deleted_at", "version", "depth"}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.HierarchyEmployee, err error)
+	}{
+		{
+			name: "error connection when get reports",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.HierarchyEmployee, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH RECURSIVE reports AS .*where r\.depth < \$2.*`+regexp.QuoteMeta(query)).WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(2, "jane", "mayer", "jane@mail.com", "2023-05-02", 1, nil, nil, "active", nil, nil, nil, 1, 1).
+						AddRow(3, "john", "mayer", "john@mail.com", "2023-05-03", 2, nil, nil, "active", nil, nil, nil, 1, 2))
+			},
+			checkReturn: func(result []*model.HierarchyEmployee, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 2)
+				assert.Equal(t, 2, result[1].Depth)
+				assert.Equal(t, 2, *result[1].ManagerID)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetReports(context.TODO(), 1, 2)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestLockManagementTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`select pg_advisory_xact_lock($1)`)).WithArgs(managementTreeLock).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewRepoUser(db).LockManagementTree(context.TODO()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetManagementChain(t *testing.T) {
+	query := `select id, first_name, last_name, email, hire_date, manager_id, department_id, position_id, status, to_char(termination_date, 'YYYY-MM-DD'), termination_reason, deleted_at, version, depth from chain order by depth`
+
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "termination_reason", "deleted_at", "version", "depth"}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.HierarchyEmployee, err error)
+	}{
+		{
+			name: "error connection when get chain",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.HierarchyEmployee, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success up to the root",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH RECURSIVE chain AS .*` + regexp.QuoteMeta(query)).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(2, "jane", "mayer", "jane@mail.com", "2023-05-02", 1, nil, nil, "active", nil, nil, nil, 1, 1).
+						AddRow(1, "root", "mayer", "root@mail.com", "2023-05-01", nil, nil, nil, "active", nil, nil, nil, 1, 2))
+			},
+			checkReturn: func(result []*model.HierarchyEmployee, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 2)
+				assert.Equal(t, 1, result[1].ID)
+				assert.Nil(t, result[1].ManagerID)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetManagementChain(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
 
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %s", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"employee/internal/model"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type DBMock struct {
@@ -15,12 +16,33 @@ func (m *DBMock) CreateEmployee(ctx context.Context, employee *model.Employee) (
 	return ret.Get(0).(int), ret.Error(1)
 }
 
-func (m *DBMock) GetEmployees(ctx context.Context) ([]*model.Employee, error) {
-	ret := m.Called(ctx)
+func (m *DBMock) GetEmployees(ctx context.Context, filter *model.EmployeeFilter) ([]*model.Employee, error) {
+	ret := m.Called(ctx, filter)
 	return ret.Get(0).([]*model.Employee), ret.Error(1)
 }
 
-func (m *DBMock) GetEmployeeByID(ctx context.Context, employeeID int) (*model.Employee, error) {
+// StreamEmployees calls fn with every employee given to Return.
+func (m *DBMock) StreamEmployees(ctx context.Context, filter *model.EmployeeFilter, fn func(employee *model.Employee) error) error {
+	ret := m.Called(ctx, filter)
+	for _, employee := range ret.Get(0).([]*model.Employee) {
+		if err := fn(employee); err != nil {
+			return err
+		}
+	}
+	return ret.Error(1)
+}
+
+func (m *DBMock) CountEmployees(ctx context.Context, filter *model.EmployeeFilter) (int, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*model.Employee, error) {
+	ret := m.Called(ctx, employeeID, includeDeleted)
+	return ret.Get(0).(*model.Employee), ret.Error(1)
+}
+
+func (m *DBMock) GetEmployeeForUpdate(ctx context.Context, employeeID int) (*model.Employee, error) {
 	ret := m.Called(ctx, employeeID)
 	return ret.Get(0).(*model.Employee), ret.Error(1)
 }
@@ -30,7 +52,67 @@ func (m *DBMock) UpdateEmployee(ctx context.Context, employee *model.Employee) e
 	return ret.Error(0)
 }
 
-func (m *DBMock) DeleteEmployee(ctx context.Context, employeeID int) error {
+func (m *DBMock) PatchEmployee(ctx context.Context, employeeID int, patch *model.EmployeePatch) error {
+	ret := m.Called(ctx, employeeID, patch)
+	return ret.Error(0)
+}
+
+func (m *DBMock) SetPosition(ctx context.Context, employeeID int, positionID int, departmentID *int, version int) error {
+	ret := m.Called(ctx, employeeID, positionID, departmentID, version)
+	return ret.Error(0)
+}
+
+func (m *DBMock) UpdateStatus(ctx context.Context, employee *model.Employee) error {
+	ret := m.Called(ctx, employee)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeleteEmployee(ctx context.Context, employeeID int, version int) error {
+	ret := m.Called(ctx, employeeID, version)
+	return ret.Error(0)
+}
+
+func (m *DBMock) RestoreEmployee(ctx context.Context, employeeID int) error {
 	ret := m.Called(ctx, employeeID)
 	return ret.Error(0)
 }
+
+func (m *DBMock) GetPurgedDocumentKeys(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	ret := m.Called(ctx, deletedBefore)
+	return ret.Get(0).([]string), ret.Error(1)
+}
+
+func (m *DBMock) PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	ret := m.Called(ctx, deletedBefore)
+	return ret.Get(0).([]int), ret.Error(1)
+}
+
+func (m *DBMock) CreateEmployees(ctx context.Context, employees []*model.Employee) ([]*model.Employee, error) {
+	ret := m.Called(ctx, employees)
+	return ret.Get(0).([]*model.Employee), ret.Error(1)
+}
+
+func (m *DBMock) GetExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	ret := m.Called(ctx, emails)
+	return ret.Get(0).(map[string]bool), ret.Error(1)
+}
+
+func (m *DBMock) GetExistingDepartments(ctx context.Context, departmentIDs []int) (map[int]bool, error) {
+	ret := m.Called(ctx, departmentIDs)
+	return ret.Get(0).(map[int]bool), ret.Error(1)
+}
+
+func (m *DBMock) GetReports(ctx context.Context, managerID int, depth int) ([]*model.HierarchyEmployee, error) {
+	ret := m.Called(ctx, managerID, depth)
+	return ret.Get(0).([]*model.HierarchyEmployee), ret.Error(1)
+}
+
+func (m *DBMock) LockManagementTree(ctx context.Context) error {
+	ret := m.Called(ctx)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetManagementChain(ctx context.Context, employeeID int) ([]*model.HierarchyEmployee, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).([]*model.HierarchyEmployee), ret.Error(1)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"employee/internal/apperror"
+	"errors"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	// pqConnectionClass covers every connection exception, 08000 to 08P01.
+	pqConnectionClass = "08"
+)
+
+// uniqueFields maps the unique constraints and indexes to the field they guard.
+var uniqueFields = map[string]string{
+	"employees_email_key":       "email",
+	"employees_national_id_key": "national_id",
+	"users_email_key":           "email",
+	"departments_name_key":      "name",
+	"positions_title_key":       "title",
+	"leave_types_name_key":      "name",
+	"custom_fields_name_key":    "name",
+}
+
+// foreignKeyFields maps the foreign keys to the field referencing the parent row.
+var foreignKeyFields = map[string]string{
+	"employees_manager_id_fkey":         "manager_id",
+	"employees_department_id_fkey":      "department_id",
+	"employees_position_id_fkey":        "position_id",
+	"leave_requests_leave_type_id_fkey": "leave_type_id",
+}
+
+// TranslateError turns the driver errors a caller can act on into domain
+// errors, any other error is returned unchanged.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrConnDone) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return apperror.Unavailable(err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch {
+	case pqErr.Code == pqUniqueViolation:
+		if field, ok := uniqueFields[pqErr.Constraint]; ok {
+			conflict := apperror.Conflict(field, field+" already exists")
+			conflict.Err = err
+			return conflict
+		}
+	case pqErr.Code == pqForeignKeyViolation:
+		if field, ok := foreignKeyFields[pqErr.Constraint]; ok {
+			invalid := apperror.Validation(field, field+" does not exist")
+			invalid.Err = err
+			return invalid
+		}
+	case strings.HasPrefix(string(pqErr.Code), pqConnectionClass):
+		return apperror.Unavailable(err)
+	}
+
+	return err
+}
+
+// IsForeignKeyViolation reports whether err was raised by the given foreign
+// key, e.g. when deleting a row that is still referenced.
+func IsForeignKeyViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation && pqErr.Constraint == constraint
+}
+
+// IsUniqueViolation reports whether err was raised by the given unique
+// constraint or index.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation && pqErr.Constraint == constraint
+}
@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.idempotency")
+)
+
+type IdempotencyRepo interface {
+	ReserveKey(ctx context.Context, key *model.IdempotencyKey) (bool, error)
+	ReclaimKey(ctx context.Context, key *model.IdempotencyKey, reservedBefore time.Time) (bool, error)
+	GetKey(ctx context.Context, actorID, key string) (*model.IdempotencyKey, error)
+	CompleteKey(ctx context.Context, key *model.IdempotencyKey) error
+	DeleteKey(ctx context.Context, actorID, key string) error
+	DeleteExpiredKeys(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoIdempotency(sqlConn *sql.DB) IdempotencyRepo {
+	return &idempotencyRepo{sqlConn: sqlConn}
+}
+
+// ReserveKey stores the key as in progress, it returns false when the caller
+// already used the key.
+func (i *idempotencyRepo) ReserveKey(ctx context.Context, key *model.IdempotencyKey) (bool, error) {
+	rLog := logRepo.WithField("function", "ReserveKey")
+
+	query := `INSERT INTO idempotency_keys (actor_id, key, method, path, request_hash)
+		values ($1, $2, $3, $4, $5) ON CONFLICT (actor_id, key) DO NOTHING`
+
+	result, err := repository.Conn(ctx, i.sqlConn).ExecContext(ctx, query, key.ActorID, key.Key, key.Method, key.Path, key.RequestHash)
+	if err != nil {
+		rLog.Errorf("error when reserve key got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		rLog.Errorf("error when get rows affected got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	return affected == 1, nil
+}
+
+// ReclaimKey takes over a key still in progress since before reservedBefore
+// for a repeat of the same request, e.g. when the request holding it crashed.
+// It returns false when the key completed, was reclaimed by another request
+// or holds another request.
+func (i *idempotencyRepo) ReclaimKey(ctx context.Context, key *model.IdempotencyKey, reservedBefore time.Time) (bool, error) {
+	rLog := logRepo.WithField("function", "ReclaimKey")
+
+	query := `UPDATE idempotency_keys SET created_at = NOW()
+		WHERE actor_id = $1 AND key = $2 AND request_hash = $3 AND status_code IS NULL AND created_at < $4`
+
+	result, err := repository.Conn(ctx, i.sqlConn).ExecContext(ctx, query, key.ActorID, key.Key, key.RequestHash, reservedBefore)
+	if err != nil {
+		rLog.Errorf("error when reclaim key got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		rLog.Errorf("error when get rows affected got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	return affected == 1, nil
+}
+
+func (i *idempotencyRepo) GetKey(ctx context.Context, actorID, key string) (*model.IdempotencyKey, error) {
+	rLog := logRepo.WithField("function", "GetKey")
+
+	query := `select actor_id, key, method, path, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''),
+		response_body, created_at from idempotency_keys where actor_id = $1 and key = $2`
+
+	stored := &model.IdempotencyKey{}
+	err := repository.Conn(ctx, i.sqlConn).QueryRowContext(ctx, query, actorID, key).Scan(&stored.ActorID, &stored.Key,
+		&stored.Method, &stored.Path, &stored.RequestHash, &stored.StatusCode, &stored.ContentType, &stored.ResponseBody, &stored.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when get key got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return stored, nil
+}
+
+// CompleteKey stores the response of a reserved key so repeats replay it.
+func (i *idempotencyRepo) CompleteKey(ctx context.Context, key *model.IdempotencyKey) error {
+	rLog := logRepo.WithField("function", "CompleteKey")
+
+	query := `UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3
+		WHERE actor_id = $4 AND key = $5`
+
+	_, err := repository.Conn(ctx, i.sqlConn).ExecContext(ctx, query, key.StatusCode, key.ContentType, key.ResponseBody, key.ActorID, key.Key)
+	if err != nil {
+		rLog.Errorf("error when complete key got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// DeleteKey releases a key so it can be retried, e.g. after a server error.
+func (i *idempotencyRepo) DeleteKey(ctx context.Context, actorID, key string) error {
+	rLog := logRepo.WithField("function", "DeleteKey")
+
+	query := `DELETE FROM idempotency_keys WHERE actor_id = $1 AND key = $2`
+
+	_, err := repository.Conn(ctx, i.sqlConn).ExecContext(ctx, query, actorID, key)
+	if err != nil {
+		rLog.Errorf("error when delete key got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (i *idempotencyRepo) DeleteExpiredKeys(ctx context.Context, createdBefore time.Time) (int64, error) {
+	rLog := logRepo.WithField("function", "DeleteExpiredKeys")
+
+	query := `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	result, err := repository.Conn(ctx, i.sqlConn).ExecContext(ctx, query, createdBefore)
+	if err != nil {
+		rLog.Errorf("error when delete expired keys got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		rLog.Errorf("error when get rows affected got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return deleted, nil
+}
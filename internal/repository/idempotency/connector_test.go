@@ -0,0 +1,338 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestReserveKey(t *testing.T) {
+	query := `INSERT INTO idempotency_keys (actor_id, key, method, path, request_hash)
+		values ($1, $2, $3, $4, $5) ON CONFLICT (actor_id, key) DO NOTHING`
+
+	key := &model.IdempotencyKey{
+		ActorID:     "1",
+		Key:         "key-1",
+		Method:      "POST",
+		Path:        "/employees",
+		RequestHash: "hash",
+	}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(reserved bool, err error)
+	}{
+		{
+			name: "error connection when reserve key",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(reserved bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, reserved)
+			},
+		},
+		{
+			name: "key already used",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, reserved)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("1", "key-1", "POST", "/employees", "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reserved)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoIdempotency(db)
+
+			reserved, err := repo.ReserveKey(context.TODO(), key)
+
+			tc.checkReturn(reserved, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestReclaimKey(t *testing.T) {
+	query := `UPDATE idempotency_keys SET created_at = NOW()
+		WHERE actor_id = $1 AND key = $2 AND request_hash = $3 AND status_code IS NULL AND created_at < $4`
+
+	key := &model.IdempotencyKey{
+		ActorID:     "1",
+		Key:         "key-1",
+		RequestHash: "hash",
+	}
+	reservedBefore := time.Now().Add(-time.Minute)
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(reclaimed bool, err error)
+	}{
+		{
+			name: "error connection when reclaim key",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(reclaimed bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, reclaimed)
+			},
+		},
+		{
+			name: "key completed or still leased",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(reclaimed bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, reclaimed)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs("1", "key-1", "hash", reservedBefore).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(reclaimed bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reclaimed)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoIdempotency(db)
+
+			reclaimed, err := repo.ReclaimKey(context.TODO(), key, reservedBefore)
+
+			tc.checkReturn(reclaimed, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetKey(t *testing.T) {
+	query := `select actor_id, key, method, path, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''),
+		response_body, created_at from idempotency_keys where actor_id = $1 and key = $2`
+
+	columns := []string{"actor_id", "key", "method", "path", "request_hash", "status_code", "content_type", "response_body", "created_at"}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(key *model.IdempotencyKey, err error)
+	}{
+		{
+			name: "error connection when get key",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(key *model.IdempotencyKey, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, key)
+			},
+		},
+		{
+			name: "key not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			checkReturn: func(key *model.IdempotencyKey, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, key)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow("1", "key-1", "POST", "/employees", "hash", 200, "application/json", []byte(`{"id":1}`), time.Now())
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("1", "key-1").WillReturnRows(rows)
+			},
+			checkReturn: func(key *model.IdempotencyKey, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, key)
+				assert.True(t, key.Completed())
+				assert.Equal(t, `{"id":1}`, string(key.ResponseBody))
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoIdempotency(db)
+
+			key, err := repo.GetKey(context.TODO(), "1", "key-1")
+
+			tc.checkReturn(key, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCompleteKey(t *testing.T) {
+	query := `UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3
+		WHERE actor_id = $4 AND key = $5`
+
+	key := &model.IdempotencyKey{
+		ActorID:      "1",
+		Key:          "key-1",
+		StatusCode:   200,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{"id":1}`),
+	}
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error connection when complete key",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(200, "application/json", []byte(`{"id":1}`), "1", "key-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoIdempotency(db)
+
+			err = repo.CompleteKey(context.TODO(), key)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeleteExpiredKeys(t *testing.T) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	createdBefore := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(deleted int64, err error)
+	}{
+		{
+			name: "error connection when delete expired keys",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(deleted int64, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(createdBefore).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			checkReturn: func(deleted int64, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), deleted)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoIdempotency(db)
+
+			deleted, err := repo.DeleteExpiredKeys(context.TODO(), createdBefore)
+
+			tc.checkReturn(deleted, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
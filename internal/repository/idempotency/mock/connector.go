@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) ReserveKey(ctx context.Context, key *model.IdempotencyKey) (bool, error) {
+	ret := m.Called(ctx, key)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *DBMock) ReclaimKey(ctx context.Context, key *model.IdempotencyKey, reservedBefore time.Time) (bool, error) {
+	ret := m.Called(ctx, key, reservedBefore)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *DBMock) GetKey(ctx context.Context, actorID, key string) (*model.IdempotencyKey, error) {
+	ret := m.Called(ctx, actorID, key)
+	return ret.Get(0).(*model.IdempotencyKey), ret.Error(1)
+}
+
+func (m *DBMock) CompleteKey(ctx context.Context, key *model.IdempotencyKey) error {
+	ret := m.Called(ctx, key)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeleteKey(ctx context.Context, actorID, key string) error {
+	ret := m.Called(ctx, actorID, key)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeleteExpiredKeys(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ret := m.Called(ctx, createdBefore)
+	return ret.Get(0).(int64), ret.Error(1)
+}
@@ -0,0 +1,344 @@
+package leave
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.leave")
+)
+
+// ErrLeaveRequestDecided is returned when deciding a leave request that was
+// approved or rejected by another request.
+var ErrLeaveRequestDecided = apperror.Conflict("status", "leave request is already decided")
+
+const leaveTypeColumns = `id, name, accrual_days, accrual_period, max_balance, created_at`
+
+const leaveRequestColumns = `id, employee_id, leave_type_id, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'), days,
+	reason, status, decided_by, decided_at, decision_note, created_at`
+
+type LeaveRepo interface {
+	CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) error
+	GetLeaveTypes(ctx context.Context) ([]*model.LeaveType, error)
+	GetLeaveTypeByID(ctx context.Context, leaveTypeID int) (*model.LeaveType, error)
+	LockEmployee(ctx context.Context, employeeID int) (bool, error)
+	GetBalances(ctx context.Context, employeeID int) ([]*model.LeaveBalance, error)
+	GetBalance(ctx context.Context, employeeID int, leaveTypeID int) (*model.LeaveBalance, error)
+	SaveBalance(ctx context.Context, balance *model.LeaveBalance) error
+	CreateLeaveRequest(ctx context.Context, request *model.LeaveRequest) error
+	GetLeaveRequestByID(ctx context.Context, requestID int64) (*model.LeaveRequest, error)
+	GetLeaveRequests(ctx context.Context, employeeID int, status string) ([]*model.LeaveRequest, error)
+	CountApprovedOverlaps(ctx context.Context, employeeID int, startDate string, endDate string) (int, error)
+	DecideLeaveRequest(ctx context.Context, request *model.LeaveRequest) error
+}
+
+type leaveRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoLeave(sqlConn *sql.DB) LeaveRepo {
+	return &leaveRepo{sqlConn: sqlConn}
+}
+
+// CreateLeaveType inserts the leave type and sets its id and created_at.
+func (l *leaveRepo) CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) error {
+	rLog := logRepo.WithField("function", "CreateLeaveType")
+
+	query := `INSERT INTO leave_types (name, accrual_days, accrual_period, max_balance) values ($1, $2, $3, $4) returning id, created_at`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, leaveType.Name, leaveType.AccrualDays, leaveType.AccrualPeriod, leaveType.MaxBalance).
+		Scan(&leaveType.ID, &leaveType.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when create leave type got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetLeaveTypes returns every leave type by name.
+func (l *leaveRepo) GetLeaveTypes(ctx context.Context) ([]*model.LeaveType, error) {
+	rLog := logRepo.WithField("function", "GetLeaveTypes")
+
+	var leaveTypes []*model.LeaveType
+
+	query := `select ` + leaveTypeColumns + ` from leave_types order by name ASC, id ASC`
+
+	rows, err := repository.Conn(ctx, l.sqlConn).QueryContext(ctx, query)
+	if err != nil {
+		rLog.Errorf("error when get leave types got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.LeaveType{}
+		err := rows.Scan(&temp.ID, &temp.Name, &temp.AccrualDays, &temp.AccrualPeriod, &temp.MaxBalance, &temp.CreatedAt)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		leaveTypes = append(leaveTypes, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return leaveTypes, nil
+}
+
+func (l *leaveRepo) GetLeaveTypeByID(ctx context.Context, leaveTypeID int) (*model.LeaveType, error) {
+	rLog := logRepo.WithField("function", "GetLeaveTypeByID")
+
+	leaveType := &model.LeaveType{}
+
+	query := `select ` + leaveTypeColumns + ` from leave_types where id = $1`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, leaveTypeID).
+		Scan(&leaveType.ID, &leaveType.Name, &leaveType.AccrualDays, &leaveType.AccrualPeriod, &leaveType.MaxBalance, &leaveType.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return leaveType, nil
+}
+
+// LockEmployee locks the employee row until the transaction ends so the leave
+// of an employee is requested and decided one at a time. It reports false when
+// the employee does not exist or is deleted.
+func (l *leaveRepo) LockEmployee(ctx context.Context, employeeID int) (bool, error) {
+	rLog := logRepo.WithField("function", "LockEmployee")
+
+	var id int
+
+	query := `select id from employees where id = $1 and deleted_at IS NULL for update`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, employeeID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		rLog.Errorf("error when lock employee got: %s", err.Error())
+		return false, repository.TranslateError(err)
+	}
+
+	return true, nil
+}
+
+// GetBalances returns the stored balances of the employee, leave types the
+// employee never used have none.
+func (l *leaveRepo) GetBalances(ctx context.Context, employeeID int) ([]*model.LeaveBalance, error) {
+	rLog := logRepo.WithField("function", "GetBalances")
+
+	var balances []*model.LeaveBalance
+
+	query := `select employee_id, leave_type_id, balance, to_char(accrued_through, 'YYYY-MM-DD') from leave_balances where employee_id = $1`
+
+	rows, err := repository.Conn(ctx, l.sqlConn).QueryContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when get balances got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.LeaveBalance{}
+		err := rows.Scan(&temp.EmployeeID, &temp.LeaveTypeID, &temp.Balance, &temp.AccruedThrough)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		balances = append(balances, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return balances, nil
+}
+
+func (l *leaveRepo) GetBalance(ctx context.Context, employeeID int, leaveTypeID int) (*model.LeaveBalance, error) {
+	rLog := logRepo.WithField("function", "GetBalance")
+
+	balance := &model.LeaveBalance{}
+
+	query := `select employee_id, leave_type_id, balance, to_char(accrued_through, 'YYYY-MM-DD') from leave_balances
+		where employee_id = $1 and leave_type_id = $2`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, employeeID, leaveTypeID).
+		Scan(&balance.EmployeeID, &balance.LeaveTypeID, &balance.Balance, &balance.AccruedThrough)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return balance, nil
+}
+
+// SaveBalance inserts the balance or overwrites the stored one.
+func (l *leaveRepo) SaveBalance(ctx context.Context, balance *model.LeaveBalance) error {
+	rLog := logRepo.WithField("function", "SaveBalance")
+
+	query := `INSERT INTO leave_balances (employee_id, leave_type_id, balance, accrued_through) values ($1, $2, $3, $4)
+		ON CONFLICT (employee_id, leave_type_id) DO UPDATE SET balance = EXCLUDED.balance, accrued_through = EXCLUDED.accrued_through, updated_at = NOW()`
+
+	_, err := repository.Conn(ctx, l.sqlConn).ExecContext(ctx, query, balance.EmployeeID, balance.LeaveTypeID, balance.Balance, balance.AccruedThrough)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// CreateLeaveRequest inserts the leave request and sets its id, status and created_at.
+func (l *leaveRepo) CreateLeaveRequest(ctx context.Context, request *model.LeaveRequest) error {
+	rLog := logRepo.WithField("function", "CreateLeaveRequest")
+
+	query := `INSERT INTO leave_requests (employee_id, leave_type_id, start_date, end_date, days, reason)
+		values ($1, $2, $3, $4, $5, $6) returning id, status, created_at`
+
+	values := []interface{}{
+		request.EmployeeID,
+		request.LeaveTypeID,
+		request.StartDate,
+		request.EndDate,
+		request.Days,
+		request.Reason,
+	}
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, values...).
+		Scan(&request.ID, &request.Status, &request.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when create leave request got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (l *leaveRepo) GetLeaveRequestByID(ctx context.Context, requestID int64) (*model.LeaveRequest, error) {
+	rLog := logRepo.WithField("function", "GetLeaveRequestByID")
+
+	query := `select ` + leaveRequestColumns + ` from leave_requests where id = $1`
+
+	request, err := scanLeaveRequest(repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, requestID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return request, nil
+}
+
+// GetLeaveRequests returns the leave requests of the employee, the latest
+// start first. An empty status returns every request.
+func (l *leaveRepo) GetLeaveRequests(ctx context.Context, employeeID int, status string) ([]*model.LeaveRequest, error) {
+	rLog := logRepo.WithField("function", "GetLeaveRequests")
+
+	var requests []*model.LeaveRequest
+
+	query := `select ` + leaveRequestColumns + ` from leave_requests where employee_id = $1 and ($2 = '' or status = $2)
+		order by start_date DESC, id DESC`
+
+	rows, err := repository.Conn(ctx, l.sqlConn).QueryContext(ctx, query, employeeID, status)
+	if err != nil {
+		rLog.Errorf("error when get leave requests got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp, err := scanLeaveRequest(rows)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		requests = append(requests, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return requests, nil
+}
+
+// CountApprovedOverlaps counts the approved leave of the employee sharing a
+// day with the inclusive range.
+func (l *leaveRepo) CountApprovedOverlaps(ctx context.Context, employeeID int, startDate string, endDate string) (int, error) {
+	rLog := logRepo.WithField("function", "CountApprovedOverlaps")
+
+	var count int
+
+	query := `select count(*) from leave_requests where employee_id = $1 and status = 'approved' and start_date <= $3 and end_date >= $2`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, employeeID, startDate, endDate).Scan(&count)
+	if err != nil {
+		rLog.Errorf("error when count overlaps got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return count, nil
+}
+
+// DecideLeaveRequest writes the decision of a pending leave request and sets
+// its decided_at, ErrLeaveRequestDecided when it is no longer pending.
+func (l *leaveRepo) DecideLeaveRequest(ctx context.Context, request *model.LeaveRequest) error {
+	rLog := logRepo.WithField("function", "DecideLeaveRequest")
+
+	query := `UPDATE leave_requests SET status = $1, decided_by = $2, decision_note = $3, decided_at = NOW()
+		WHERE id = $4 AND status = 'pending' returning decided_at`
+
+	err := repository.Conn(ctx, l.sqlConn).QueryRowContext(ctx, query, request.Status, request.DecidedBy, request.DecisionNote, request.ID).
+		Scan(&request.DecidedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrLeaveRequestDecided
+		}
+		rLog.Errorf("error when decide leave request got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLeaveRequest(row rowScanner) (*model.LeaveRequest, error) {
+	request := &model.LeaveRequest{}
+
+	err := row.Scan(&request.ID, &request.EmployeeID, &request.LeaveTypeID, &request.StartDate, &request.EndDate, &request.Days,
+		&request.Reason, &request.Status, &request.DecidedBy, &request.DecidedAt, &request.DecisionNote, &request.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
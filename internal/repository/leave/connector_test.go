@@ -0,0 +1,362 @@
+package leave
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt      = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	requestColumns = []string{"id", "employee_id", "leave_type_id", "start_date", "end_date", "days", "reason", "status", "decided_by",
+		"decided_at", "decision_note", "created_at"}
+)
+
+func TestCreateLeaveType(t *testing.T) {
+	query := `INSERT INTO leave_types (name, accrual_days, accrual_period, max_balance) values ($1, $2, $3, $4) returning id, created_at`
+
+	maxBalance := 30.0
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(leaveType *model.LeaveType, err error)
+	}{
+		{
+			name: "error when name is taken",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "leave_types_name_key"})
+			},
+			checkReturn: func(leaveType *model.LeaveType, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, "name", appErr.Field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Annual", 1.5, "monthly", &maxBalance).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+			},
+			checkReturn: func(leaveType *model.LeaveType, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, leaveType.ID)
+				assert.Equal(t, createdAt, leaveType.CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoLeave(db)
+
+			leaveType := &model.LeaveType{Name: "Annual", AccrualDays: 1.5, AccrualPeriod: "monthly", MaxBalance: &maxBalance}
+			err = repo.CreateLeaveType(context.TODO(), leaveType)
+
+			tc.checkReturn(leaveType, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	query := `select employee_id, leave_type_id, balance, to_char(accrued_through, 'YYYY-MM-DD') from leave_balances
+		where employee_id = $1 and leave_type_id = $2`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(balance *model.LeaveBalance, err error)
+	}{
+		{
+			name: "error connection when get balance",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(balance *model.LeaveBalance, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, balance)
+			},
+		},
+		{
+			name: "balance not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"employee_id", "leave_type_id", "balance", "accrued_through"}))
+			},
+			checkReturn: func(balance *model.LeaveBalance, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, balance)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"employee_id", "leave_type_id", "balance", "accrued_through"}).AddRow(3, 1, "12.50", "2024-01-03"))
+			},
+			checkReturn: func(balance *model.LeaveBalance, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 12.5, balance.Balance)
+				assert.Equal(t, "2024-01-03", balance.AccruedThrough)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoLeave(db)
+
+			balance, err := repo.GetBalance(context.TODO(), 3, 1)
+
+			tc.checkReturn(balance, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestSaveBalance(t *testing.T) {
+	query := `INSERT INTO leave_balances (employee_id, leave_type_id, balance, accrued_through) values ($1, $2, $3, $4)
+		ON CONFLICT (employee_id, leave_type_id) DO UPDATE SET balance = EXCLUDED.balance, accrued_through = EXCLUDED.accrued_through, updated_at = NOW()`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3, 1, 7.5, "2024-01-03").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewRepoLeave(db)
+
+	err = repo.SaveBalance(context.TODO(), &model.LeaveBalance{EmployeeID: 3, LeaveTypeID: 1, Balance: 7.5, AccruedThrough: "2024-01-03"})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateLeaveRequest(t *testing.T) {
+	query := `INSERT INTO leave_requests (employee_id, leave_type_id, start_date, end_date, days, reason)
+		values ($1, $2, $3, $4, $5, $6) returning id, status, created_at`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(request *model.LeaveRequest, err error)
+	}{
+		{
+			name: "error when leave type does not exist",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23503", Constraint: "leave_requests_leave_type_id_fkey"})
+			},
+			checkReturn: func(request *model.LeaveRequest, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, "leave_type_id", appErr.Field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, 1, "2024-07-01", "2024-07-05", 5.0, "holiday").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(10, "pending", createdAt))
+			},
+			checkReturn: func(request *model.LeaveRequest, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(10), request.ID)
+				assert.Equal(t, model.LeaveStatusPending, request.Status)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoLeave(db)
+
+			request := &model.LeaveRequest{EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-01", EndDate: "2024-07-05", Days: 5, Reason: "holiday"}
+			err = repo.CreateLeaveRequest(context.TODO(), request)
+
+			tc.checkReturn(request, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetLeaveRequests(t *testing.T) {
+	query := `select id, employee_id, leave_type_id, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'), days,
+	reason, status, decided_by, decided_at, decision_note, created_at from leave_requests where employee_id = $1 and ($2 = '' or status = $2)
+		order by start_date DESC, id DESC`
+
+	decidedBy := "2"
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(requests []*model.LeaveRequest, err error)
+	}{
+		{
+			name: "error connection when get leave requests",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(requests []*model.LeaveRequest, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, requests)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, "approved").
+					WillReturnRows(sqlmock.NewRows(requestColumns).
+						AddRow(10, 3, 1, "2024-07-01", "2024-07-05", "5.00", "holiday", "approved", decidedBy, createdAt, nil, createdAt))
+			},
+			checkReturn: func(requests []*model.LeaveRequest, err error) {
+				assert.NoError(t, err)
+				require.Len(t, requests, 1)
+				assert.Equal(t, 5.0, requests[0].Days)
+				assert.Equal(t, "2", *requests[0].DecidedBy)
+				assert.Nil(t, requests[0].DecisionNote)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoLeave(db)
+
+			requests, err := repo.GetLeaveRequests(context.TODO(), 3, "approved")
+
+			tc.checkReturn(requests, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCountApprovedOverlaps(t *testing.T) {
+	query := `select count(*) from leave_requests where employee_id = $1 and status = 'approved' and start_date <= $3 and end_date >= $2`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, "2024-07-01", "2024-07-05").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := NewRepoLeave(db)
+
+	count, err := repo.CountApprovedOverlaps(context.TODO(), 3, "2024-07-01", "2024-07-05")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDecideLeaveRequest(t *testing.T) {
+	query := `UPDATE leave_requests SET status = $1, decided_by = $2, decision_note = $3, decided_at = NOW()
+		WHERE id = $4 AND status = 'pending' returning decided_at`
+
+	decidedBy := "2"
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(request *model.LeaveRequest, err error)
+	}{
+		{
+			name: "error when request is already decided",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows([]string{"decided_at"}))
+			},
+			checkReturn: func(request *model.LeaveRequest, err error) {
+				assert.ErrorIs(t, err, ErrLeaveRequestDecided)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("approved", &decidedBy, nil, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"decided_at"}).AddRow(createdAt))
+			},
+			checkReturn: func(request *model.LeaveRequest, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, createdAt, *request.DecidedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoLeave(db)
+
+			request := &model.LeaveRequest{ID: 10, Status: model.LeaveStatusApproved, DecidedBy: &decidedBy}
+			err = repo.DecideLeaveRequest(context.TODO(), request)
+
+			tc.checkReturn(request, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
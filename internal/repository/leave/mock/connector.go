@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) error {
+	ret := m.Called(ctx, leaveType)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetLeaveTypes(ctx context.Context) ([]*model.LeaveType, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).([]*model.LeaveType), ret.Error(1)
+}
+
+func (m *DBMock) GetLeaveTypeByID(ctx context.Context, leaveTypeID int) (*model.LeaveType, error) {
+	ret := m.Called(ctx, leaveTypeID)
+	return ret.Get(0).(*model.LeaveType), ret.Error(1)
+}
+
+func (m *DBMock) LockEmployee(ctx context.Context, employeeID int) (bool, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *DBMock) GetBalances(ctx context.Context, employeeID int) ([]*model.LeaveBalance, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).([]*model.LeaveBalance), ret.Error(1)
+}
+
+func (m *DBMock) GetBalance(ctx context.Context, employeeID int, leaveTypeID int) (*model.LeaveBalance, error) {
+	ret := m.Called(ctx, employeeID, leaveTypeID)
+	return ret.Get(0).(*model.LeaveBalance), ret.Error(1)
+}
+
+func (m *DBMock) SaveBalance(ctx context.Context, balance *model.LeaveBalance) error {
+	ret := m.Called(ctx, balance)
+	return ret.Error(0)
+}
+
+func (m *DBMock) CreateLeaveRequest(ctx context.Context, request *model.LeaveRequest) error {
+	ret := m.Called(ctx, request)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetLeaveRequestByID(ctx context.Context, requestID int64) (*model.LeaveRequest, error) {
+	ret := m.Called(ctx, requestID)
+	return ret.Get(0).(*model.LeaveRequest), ret.Error(1)
+}
+
+func (m *DBMock) GetLeaveRequests(ctx context.Context, employeeID int, status string) ([]*model.LeaveRequest, error) {
+	ret := m.Called(ctx, employeeID, status)
+	return ret.Get(0).([]*model.LeaveRequest), ret.Error(1)
+}
+
+func (m *DBMock) CountApprovedOverlaps(ctx context.Context, employeeID int, startDate string, endDate string) (int, error) {
+	ret := m.Called(ctx, employeeID, startDate, endDate)
+	return ret.Int(0), ret.Error(1)
+}
+
+func (m *DBMock) DecideLeaveRequest(ctx context.Context, request *model.LeaveRequest) error {
+	ret := m.Called(ctx, request)
+	return ret.Error(0)
+}
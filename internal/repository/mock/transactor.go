@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type TransactorMock struct {
+	mock.Mock
+}
+
+func (m *TransactorMock) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ret := m.Called(ctx)
+	if err := ret.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
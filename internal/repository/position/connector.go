@@ -0,0 +1,341 @@
+package position
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.position")
+)
+
+// ErrPositionInUse is returned when deleting a position that an employee
+// holds or held.
+var ErrPositionInUse = apperror.Conflict("", "position is still referenced by employees")
+
+// positionForeignKeys are the foreign keys referencing a position.
+var positionForeignKeys = []string{"employees_position_id_fkey", "employee_positions_position_id_fkey"}
+
+const assignmentSelect = `ep.id, ep.employee_id, ep.position_id, p.title, p.grade, p.job_family, ep.department_id, ep.change,
+	to_char(ep.effective_date, 'YYYY-MM-DD'), to_char(ep.end_date, 'YYYY-MM-DD'), ep.created_at`
+
+type PositionRepo interface {
+	CreatePosition(ctx context.Context, position *model.Position) error
+	GetPositions(ctx context.Context, filter *model.PositionFilter) ([]*model.Position, error)
+	CountPositions(ctx context.Context, filter *model.PositionFilter) (int, error)
+	GetPositionByID(ctx context.Context, positionID int) (*model.Position, error)
+	UpdatePosition(ctx context.Context, position *model.Position) error
+	DeletePosition(ctx context.Context, positionID int) error
+	CountHolders(ctx context.Context, positionID int) (int, error)
+	CreateAssignment(ctx context.Context, assignment *model.PositionAssignment) error
+	GetCurrentAssignment(ctx context.Context, employeeID int) (*model.PositionAssignment, error)
+	EndAssignment(ctx context.Context, assignmentID int64, endDate string) error
+	GetAssignments(ctx context.Context, employeeID int) ([]*model.PositionAssignment, error)
+}
+
+type positionRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoPosition(sqlConn *sql.DB) PositionRepo {
+	return &positionRepo{sqlConn: sqlConn}
+}
+
+// CreatePosition inserts the position and sets its id and timestamps.
+func (p *positionRepo) CreatePosition(ctx context.Context, position *model.Position) error {
+	rLog := logRepo.WithField("function", "CreatePosition")
+
+	query := `INSERT INTO positions (title, grade, job_family) values ($1, $2, $3) returning id, created_at, updated_at`
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, position.Title, position.Grade, position.JobFamily).
+		Scan(&position.ID, &position.CreatedAt, &position.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when create position got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetPositions returns the positions grouped by job family, from the lowest grade up.
+func (p *positionRepo) GetPositions(ctx context.Context, filter *model.PositionFilter) ([]*model.Position, error) {
+	rLog := logRepo.WithField("function", "GetPositions")
+
+	var positions []*model.Position
+
+	where, args := buildFilter(filter)
+
+	query := `select id, title, grade, job_family, created_at, updated_at from positions` + where +
+		` order by job_family ASC, grade ASC, id ASC`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" offset $%d", len(args))
+	}
+
+	rows, err := repository.Conn(ctx, p.sqlConn).QueryContext(ctx, query, args...)
+	if err != nil {
+		rLog.Errorf("error when get positions got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp := &model.Position{}
+		if err := rows.Scan(&temp.ID, &temp.Title, &temp.Grade, &temp.JobFamily, &temp.CreatedAt, &temp.UpdatedAt); err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		positions = append(positions, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return positions, nil
+}
+
+func (p *positionRepo) CountPositions(ctx context.Context, filter *model.PositionFilter) (int, error) {
+	rLog := logRepo.WithField("function", "CountPositions")
+
+	var total int
+
+	where, args := buildFilter(filter)
+	query := `select count(*) from positions` + where
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count positions got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+func (p *positionRepo) GetPositionByID(ctx context.Context, positionID int) (*model.Position, error) {
+	rLog := logRepo.WithField("function", "GetPositionByID")
+
+	position := &model.Position{}
+
+	query := `select id, title, grade, job_family, created_at, updated_at from positions where id = $1`
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, positionID).
+		Scan(&position.ID, &position.Title, &position.Grade, &position.JobFamily, &position.CreatedAt, &position.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return position, nil
+}
+
+// UpdatePosition writes the title, grade and job family and sets the new updated_at.
+func (p *positionRepo) UpdatePosition(ctx context.Context, position *model.Position) error {
+	rLog := logRepo.WithField("function", "UpdatePosition")
+
+	query := `UPDATE positions SET title = $1, grade = $2, job_family = $3, updated_at = NOW() WHERE id = $4 returning updated_at`
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, position.Title, position.Grade, position.JobFamily, position.ID).
+		Scan(&position.UpdatedAt)
+	if err != nil {
+		rLog.Errorf("error when update position got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// DeletePosition deletes the position, the foreign keys of the employees and
+// of the position history reject it while it is referenced.
+func (p *positionRepo) DeletePosition(ctx context.Context, positionID int) error {
+	rLog := logRepo.WithField("function", "DeletePosition")
+
+	query := `DELETE FROM positions WHERE id = $1`
+
+	_, err := repository.Conn(ctx, p.sqlConn).ExecContext(ctx, query, positionID)
+	if err != nil {
+		rLog.Error(err)
+		for _, constraint := range positionForeignKeys {
+			if repository.IsForeignKeyViolation(err, constraint) {
+				return ErrPositionInUse
+			}
+		}
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// CountHolders counts the employees holding the position now or in their
+// history, deleted ones included.
+func (p *positionRepo) CountHolders(ctx context.Context, positionID int) (int, error) {
+	rLog := logRepo.WithField("function", "CountHolders")
+
+	var total int
+
+	query := `select count(*) from (
+		select id from employees where position_id = $1
+		union
+		select employee_id from employee_positions where position_id = $1
+	) holders`
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, positionID).Scan(&total)
+	if err != nil {
+		rLog.Errorf("error when count holders got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return total, nil
+}
+
+// CreateAssignment inserts a position history entry and sets its id and created_at.
+func (p *positionRepo) CreateAssignment(ctx context.Context, assignment *model.PositionAssignment) error {
+	rLog := logRepo.WithField("function", "CreateAssignment")
+
+	query := `INSERT INTO employee_positions (employee_id, position_id, department_id, change, effective_date)
+		values ($1, $2, $3, $4, $5) returning id, created_at`
+
+	values := []interface{}{
+		assignment.EmployeeID,
+		assignment.PositionID,
+		assignment.DepartmentID,
+		assignment.Change,
+		assignment.EffectiveDate,
+	}
+
+	err := repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, values...).
+		Scan(&assignment.ID, &assignment.CreatedAt)
+	if err != nil {
+		rLog.Errorf("error when create assignment got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetCurrentAssignment returns the open position history entry of the
+// employee, nil when the employee never held a position.
+func (p *positionRepo) GetCurrentAssignment(ctx context.Context, employeeID int) (*model.PositionAssignment, error) {
+	rLog := logRepo.WithField("function", "GetCurrentAssignment")
+
+	query := `select ` + assignmentSelect + ` from employee_positions ep
+		join positions p on p.id = ep.position_id
+		where ep.employee_id = $1 and ep.end_date IS NULL`
+
+	assignment, err := scanAssignment(repository.Conn(ctx, p.sqlConn).QueryRowContext(ctx, query, employeeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return assignment, nil
+}
+
+// EndAssignment closes the position history entry at the given date.
+func (p *positionRepo) EndAssignment(ctx context.Context, assignmentID int64, endDate string) error {
+	rLog := logRepo.WithField("function", "EndAssignment")
+
+	query := `UPDATE employee_positions SET end_date = $1 WHERE id = $2 AND end_date IS NULL`
+
+	_, err := repository.Conn(ctx, p.sqlConn).ExecContext(ctx, query, endDate, assignmentID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetAssignments returns the position history of the employee, latest first.
+func (p *positionRepo) GetAssignments(ctx context.Context, employeeID int) ([]*model.PositionAssignment, error) {
+	rLog := logRepo.WithField("function", "GetAssignments")
+
+	var assignments []*model.PositionAssignment
+
+	query := `select ` + assignmentSelect + ` from employee_positions ep
+		join positions p on p.id = ep.position_id
+		where ep.employee_id = $1 order by ep.effective_date DESC, ep.id DESC`
+
+	rows, err := repository.Conn(ctx, p.sqlConn).QueryContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when get assignments got: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		temp, err := scanAssignment(rows)
+		if err != nil {
+			rLog.Errorf("error when scan: %s", err.Error())
+			return nil, repository.TranslateError(err)
+		}
+
+		assignments = append(assignments, temp)
+	}
+
+	if err := rows.Err(); err != nil {
+		rLog.Errorf("error when iterate rows: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return assignments, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAssignment(row rowScanner) (*model.PositionAssignment, error) {
+	a := &model.PositionAssignment{}
+
+	err := row.Scan(&a.ID, &a.EmployeeID, &a.PositionID, &a.Title, &a.Grade, &a.JobFamily, &a.DepartmentID, &a.Change,
+		&a.EffectiveDate, &a.EndDate, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func buildFilter(filter *model.PositionFilter) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.Title != "" {
+		replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+		args = append(args, replacer.Replace(filter.Title)+"%")
+		where = append(where, fmt.Sprintf("title ILIKE $%d", len(args)))
+	}
+
+	if filter.JobFamily != "" {
+		args = append(args, filter.JobFamily)
+		where = append(where, fmt.Sprintf("LOWER(job_family) = LOWER($%d)", len(args)))
+	}
+
+	if len(where) == 0 {
+		return "", nil
+	}
+
+	return " where " + strings.Join(where, " and "), args
+}
@@ -0,0 +1,375 @@
+package position
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	createdAt         = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns           = []string{"id", "title", "grade", "job_family", "created_at", "updated_at"}
+	assignmentColumns = []string{"id", "employee_id", "position_id", "title", "grade", "job_family", "department_id", "change",
+		"effective_date", "end_date", "created_at"}
+)
+
+func TestCreatePosition(t *testing.T) {
+	query := `INSERT INTO positions (title, grade, job_family) values ($1, $2, $3) returning id, created_at, updated_at`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(position *model.Position, err error)
+	}{
+		{
+			name: "error when title is taken",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23505", Constraint: "positions_title_key"})
+			},
+			checkReturn: func(position *model.Position, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, apperror.CodeConflict, appErr.Code)
+				assert.Equal(t, "title", appErr.Field)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Software Engineer II", 3, "Engineering").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, createdAt, createdAt))
+			},
+			checkReturn: func(position *model.Position, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, position.ID)
+				assert.Equal(t, createdAt, position.CreatedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoPosition(db)
+
+			position := &model.Position{Title: "Software Engineer II", Grade: 3, JobFamily: "Engineering"}
+			err = repo.CreatePosition(context.TODO(), position)
+
+			tc.checkReturn(position, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetPositions(t *testing.T) {
+	query := `select id, title, grade, job_family, created_at, updated_at from positions`
+
+	testCase := []struct {
+		name        string
+		filter      *model.PositionFilter
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result []*model.Position, err error)
+	}{
+		{
+			name:   "error connection when get positions",
+			filter: &model.PositionFilter{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result []*model.Position, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:   "success with filter",
+			filter: &model.PositionFilter{Title: "soft", JobFamily: "engineering", Limit: 20, Offset: 20},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow(1, "Software Engineer II", 3, "Engineering", createdAt, createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(query+` where title ILIKE $1 and LOWER(job_family) = LOWER($2)`+
+					` order by job_family ASC, grade ASC, id ASC limit $3 offset $4`)).
+					WithArgs("soft%", "engineering", 20, 20).
+					WillReturnRows(rows)
+			},
+			checkReturn: func(result []*model.Position, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result, 1)
+				assert.Equal(t, 3, result[0].Grade)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoPosition(db)
+
+			result, err := repo.GetPositions(context.TODO(), tc.filter)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetPositionByID(t *testing.T) {
+	query := `select id, title, grade, job_family, created_at, updated_at from positions where id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.Position, err error)
+	}{
+		{
+			name: "position not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			checkReturn: func(result *model.Position, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Software Engineer II", 3, "Engineering", createdAt, createdAt))
+			},
+			checkReturn: func(result *model.Position, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &model.Position{ID: 1, Title: "Software Engineer II", Grade: 3, JobFamily: "Engineering", CreatedAt: createdAt, UpdatedAt: createdAt}, result)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoPosition(db)
+
+			result, err := repo.GetPositionByID(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeletePosition(t *testing.T) {
+	query := `DELETE FROM positions WHERE id = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error when an employee holds it",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23503", Constraint: "employees_position_id_fkey"})
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrPositionInUse)
+			},
+		},
+		{
+			name: "error when the history references it",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(&pq.Error{Code: "23503", Constraint: "employee_positions_position_id_fkey"})
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrPositionInUse)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoPosition(db)
+
+			err = repo.DeletePosition(context.TODO(), 1)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCreateAssignment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	departmentID := 2
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO employee_positions (employee_id, position_id, department_id, change, effective_date)`)).
+		WithArgs(7, 1, &departmentID, model.PositionChangePromotion, "2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, createdAt))
+
+	assignment := &model.PositionAssignment{
+		EmployeeID:    7,
+		PositionID:    1,
+		DepartmentID:  &departmentID,
+		Change:        model.PositionChangePromotion,
+		EffectiveDate: "2024-01-01",
+	}
+	err = NewRepoPosition(db).CreateAssignment(context.TODO(), assignment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), assignment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCurrentAssignment(t *testing.T) {
+	query := `from employee_positions ep
+		join positions p on p.id = ep.position_id
+		where ep.employee_id = $1 and ep.end_date IS NULL`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.PositionAssignment, err error)
+	}{
+		{
+			name: "employee never held a position",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(assignmentColumns))
+			},
+			checkReturn: func(result *model.PositionAssignment, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(assignmentColumns).
+						AddRow(10, 7, 1, "Software Engineer II", 3, "Engineering", nil, model.PositionChangeInitial, "2023-05-01", nil, createdAt))
+			},
+			checkReturn: func(result *model.PositionAssignment, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, int64(10), result.ID)
+				assert.Equal(t, 3, result.Grade)
+				assert.Equal(t, "2023-05-01", result.EffectiveDate)
+				assert.Nil(t, result.EndDate)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoPosition(db)
+
+			result, err := repo.GetCurrentAssignment(context.TODO(), 7)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEndAssignment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE employee_positions SET end_date = $1 WHERE id = $2 AND end_date IS NULL`)).
+		WithArgs("2024-01-01", int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewRepoPosition(db).EndAssignment(context.TODO(), 10, "2024-01-01")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAssignments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows(assignmentColumns).
+		AddRow(11, 7, 2, "Senior Software Engineer", 4, "Engineering", 2, model.PositionChangePromotion, "2024-01-01", nil, createdAt).
+		AddRow(10, 7, 1, "Software Engineer II", 3, "Engineering", 2, model.PositionChangeInitial, "2023-05-01", "2024-01-01", createdAt)
+	mock.ExpectQuery(regexp.QuoteMeta(`where ep.employee_id = $1 order by ep.effective_date DESC, ep.id DESC`)).
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	result, err := NewRepoPosition(db).GetAssignments(context.TODO(), 7)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, model.PositionChangePromotion, result[0].Change)
+	require.NotNil(t, result[1].EndDate)
+	assert.Equal(t, "2024-01-01", *result[1].EndDate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,66 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreatePosition(ctx context.Context, position *model.Position) error {
+	ret := m.Called(ctx, position)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetPositions(ctx context.Context, filter *model.PositionFilter) ([]*model.Position, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).([]*model.Position), ret.Error(1)
+}
+
+func (m *DBMock) CountPositions(ctx context.Context, filter *model.PositionFilter) (int, error) {
+	ret := m.Called(ctx, filter)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) GetPositionByID(ctx context.Context, positionID int) (*model.Position, error) {
+	ret := m.Called(ctx, positionID)
+	return ret.Get(0).(*model.Position), ret.Error(1)
+}
+
+func (m *DBMock) UpdatePosition(ctx context.Context, position *model.Position) error {
+	ret := m.Called(ctx, position)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DeletePosition(ctx context.Context, positionID int) error {
+	ret := m.Called(ctx, positionID)
+	return ret.Error(0)
+}
+
+func (m *DBMock) CountHolders(ctx context.Context, positionID int) (int, error) {
+	ret := m.Called(ctx, positionID)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) CreateAssignment(ctx context.Context, assignment *model.PositionAssignment) error {
+	ret := m.Called(ctx, assignment)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetCurrentAssignment(ctx context.Context, employeeID int) (*model.PositionAssignment, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).(*model.PositionAssignment), ret.Error(1)
+}
+
+func (m *DBMock) EndAssignment(ctx context.Context, assignmentID int64, endDate string) error {
+	ret := m.Called(ctx, assignmentID, endDate)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetAssignments(ctx context.Context, employeeID int) ([]*model.PositionAssignment, error) {
+	ret := m.Called(ctx, employeeID)
+	return ret.Get(0).([]*model.PositionAssignment), ret.Error(1)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logTx = log.WithField("package", "repository")
+)
+
+type txCtxKey struct{}
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by the repositories.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Transactor runs a function inside a database transaction shared by every
+// repository that resolves its connection through Conn.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	sqlConn *sql.DB
+}
+
+func NewTransactor(sqlConn *sql.DB) Transactor {
+	return &transactor{sqlConn: sqlConn}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	rLog := logTx.WithField("function", "WithinTransaction")
+
+	// nested calls join the outer transaction
+	if _, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.sqlConn.BeginTx(ctx, nil)
+	if err != nil {
+		rLog.Errorf("error when begin transaction got: %s", err.Error())
+		return TranslateError(err)
+	}
+
+	if err := fn(context.WithValue(ctx, txCtxKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			rLog.Errorf("error when rollback transaction got: %s", rbErr.Error())
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		rLog.Errorf("error when commit transaction got: %s", err.Error())
+		return TranslateError(err)
+	}
+
+	return nil
+}
+
+// Conn returns the transaction bound to ctx, or the connection pool when the
+// call is not part of a transaction.
+func Conn(ctx context.Context, sqlConn *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return sqlConn
+}
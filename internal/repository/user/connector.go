@@ -0,0 +1,203 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logRepo = log.WithField("package", "repository.user")
+)
+
+type UserRepo interface {
+	CreateUser(ctx context.Context, user *model.User) (int, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserByID(ctx context.Context, userID int) (*model.User, error)
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenID int) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID int) error
+	DisableEmployeeUsers(ctx context.Context, employeeID int) error
+	EnableEmployeeUsers(ctx context.Context, employeeID int) error
+}
+
+type userRepo struct {
+	sqlConn *sql.DB
+}
+
+func NewRepoUser(sqlConn *sql.DB) UserRepo {
+	return &userRepo{sqlConn: sqlConn}
+}
+
+func (u *userRepo) CreateUser(ctx context.Context, user *model.User) (int, error) {
+	rLog := logRepo.WithField("function", "CreateUser")
+
+	var currentInsertedID int
+
+	query := `INSERT INTO users (email, password_hash, role, employee_id) values ($1, $2, $3, $4) returning id`
+
+	values := []interface{}{
+		user.Email,
+		user.PasswordHash,
+		user.Role,
+		user.EmployeeID,
+	}
+
+	err := repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, values...).Scan(&currentInsertedID)
+	if err != nil {
+		rLog.Errorf("error when create user got: %s", err.Error())
+		return 0, repository.TranslateError(err)
+	}
+
+	return currentInsertedID, nil
+}
+
+func (u *userRepo) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	rLog := logRepo.WithField("function", "GetUserByEmail")
+
+	user := &model.User{}
+
+	query := `select id, email, password_hash, role, employee_id, disabled_at from users where LOWER(email) = LOWER($1)`
+
+	row := repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, email)
+
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.EmployeeID, &user.DisabledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return user, nil
+}
+
+func (u *userRepo) GetUserByID(ctx context.Context, userID int) (*model.User, error) {
+	rLog := logRepo.WithField("function", "GetUserByID")
+
+	user := &model.User{}
+
+	query := `select id, email, password_hash, role, employee_id, disabled_at from users where id = $1`
+
+	row := repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, userID)
+
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.EmployeeID, &user.DisabledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return user, nil
+}
+
+func (u *userRepo) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	rLog := logRepo.WithField("function", "CreateRefreshToken")
+
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, expires_at) values ($1, $2, $3)`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		rLog.Errorf("error when create refresh token got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (u *userRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	rLog := logRepo.WithField("function", "GetRefreshToken")
+
+	token := &model.RefreshToken{}
+
+	query := `select id, user_id, token_hash, expires_at, revoked_at from refresh_tokens where token_hash = $1`
+
+	row := repository.Conn(ctx, u.sqlConn).QueryRowContext(ctx, query, tokenHash)
+
+	err := row.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		rLog.Errorf("error when scan: %s", err.Error())
+		return nil, repository.TranslateError(err)
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken marks a token as revoked and reports whether this call
+// was the one that revoked it, so concurrent rotations of the same token can
+// be told apart.
+func (u *userRepo) RevokeRefreshToken(ctx context.Context, tokenID int) (bool, error) {
+	rLog := logRepo.WithField("function", "RevokeRefreshToken")
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, tokenID)
+	if err != nil {
+		rLog.Error(err)
+		return false, repository.TranslateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		rLog.Error(err)
+		return false, repository.TranslateError(err)
+	}
+
+	return affected > 0, nil
+}
+
+func (u *userRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	rLog := logRepo.WithField("function", "RevokeUserRefreshTokens")
+
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, userID)
+	if err != nil {
+		rLog.Error(err)
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+// DisableEmployeeUsers disables the accounts of the employee and revokes
+// their refresh tokens, JWTMiddleware rejects their access tokens from then on.
+func (u *userRepo) DisableEmployeeUsers(ctx context.Context, employeeID int) error {
+	rLog := logRepo.WithField("function", "DisableEmployeeUsers")
+
+	query := `WITH disabled AS (
+			UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()) WHERE employee_id = $1 RETURNING id
+		)
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE revoked_at IS NULL AND user_id IN (SELECT id FROM disabled)`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when disable users got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
+
+func (u *userRepo) EnableEmployeeUsers(ctx context.Context, employeeID int) error {
+	rLog := logRepo.WithField("function", "EnableEmployeeUsers")
+
+	query := `UPDATE users SET disabled_at = NULL WHERE employee_id = $1`
+
+	_, err := repository.Conn(ctx, u.sqlConn).ExecContext(ctx, query, employeeID)
+	if err != nil {
+		rLog.Errorf("error when enable users got: %s", err.Error())
+		return repository.TranslateError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCreateUser(t *testing.T) {
+	query := `INSERT INTO users (email, password_hash, role, employee_id) values ($1, $2, $3, $4) returning id`
+
+	user := &model.User{
+		Email:        "test@mail.com",
+		PasswordHash: "hash",
+		Role:         "viewer",
+	}
+
+	testCase := []struct {
+		name        string
+		payload     *model.User
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(resultID int, err error)
+	}{
+		{
+			name:    "error connection when create user",
+			payload: user,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(resultID int, err error) {
+				assert.Error(t, err)
+				assert.Zero(t, resultID)
+			},
+		},
+		{
+			name:    "success",
+			payload: user,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("test@mail.com", "hash", "viewer", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			checkReturn: func(resultID int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, resultID)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.CreateUser(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	query := `select id, email, password_hash, role, employee_id, disabled_at from users where LOWER(email) = LOWER($1)`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.User, err error)
+	}{
+		{
+			name: "error connection when get user",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result *model.User, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "user not found",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrNoRows)
+			},
+			checkReturn: func(result *model.User, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "employee_id", "disabled_at"}).
+					AddRow(1, "test@mail.com", "hash", "hr", 3, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Test@Mail.com").WillReturnRows(rows)
+			},
+			checkReturn: func(result *model.User, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, 3, *result.EmployeeID)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetUserByEmail(context.TODO(), "Test@Mail.com")
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetRefreshToken(t *testing.T) {
+	query := `select id, user_id, token_hash, expires_at, revoked_at from refresh_tokens where token_hash = $1`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(result *model.RefreshToken, err error)
+	}{
+		{
+			name: "error connection when get refresh token",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(result *model.RefreshToken, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "revoked_at"}).
+					AddRow(1, 2, "hash", time.Now().Add(time.Hour), nil)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("hash").WillReturnRows(rows)
+			},
+			checkReturn: func(result *model.RefreshToken, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Nil(t, result.RevokedAt)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.GetRefreshToken(context.TODO(), "hash")
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(revoked bool, err error)
+	}{
+		{
+			name: "error connection when revoke refresh token",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(revoked bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, revoked)
+			},
+		},
+		{
+			name: "token was already revoked",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			checkReturn: func(revoked bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, revoked)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkReturn: func(revoked bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, revoked)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			result, err := repo.RevokeRefreshToken(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDisableEmployeeUsers(t *testing.T) {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE revoked_at IS NULL AND user_id IN (SELECT id FROM disabled)`
+
+	testCase := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error connection when disable users",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCase {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			tc.buildStub(mock)
+
+			repo := NewRepoUser(db)
+
+			err = repo.DisableEmployeeUsers(context.TODO(), 3)
+
+			tc.checkReturn(err)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type DBMock struct {
+	mock.Mock
+}
+
+func (m *DBMock) CreateUser(ctx context.Context, user *model.User) (int, error) {
+	ret := m.Called(ctx, user)
+	return ret.Get(0).(int), ret.Error(1)
+}
+
+func (m *DBMock) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	ret := m.Called(ctx, email)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *DBMock) GetUserByID(ctx context.Context, userID int) (*model.User, error) {
+	ret := m.Called(ctx, userID)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *DBMock) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	ret := m.Called(ctx, token)
+	return ret.Error(0)
+}
+
+func (m *DBMock) GetRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	ret := m.Called(ctx, tokenHash)
+	return ret.Get(0).(*model.RefreshToken), ret.Error(1)
+}
+
+func (m *DBMock) RevokeRefreshToken(ctx context.Context, tokenID int) (bool, error) {
+	ret := m.Called(ctx, tokenID)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *DBMock) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	ret := m.Called(ctx, userID)
+	return ret.Error(0)
+}
+
+func (m *DBMock) DisableEmployeeUsers(ctx context.Context, employeeID int) error {
+	ret := m.Called(ctx, employeeID)
+	return ret.Error(0)
+}
+
+func (m *DBMock) EnableEmployeeUsers(ctx context.Context, employeeID int) error {
+	ret := m.Called(ctx, employeeID)
+	return ret.Error(0)
+}
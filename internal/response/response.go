@@ -1,11 +1,22 @@
 package response
 
 import (
+	"employee/internal/apperror"
+	"fmt"
 	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+var (
+	logger = log.WithField("package", "response")
 )
 
 type Error struct {
-	Message string `json:"message,omitempty"`
+	Code    string                `json:"code,omitempty"`
+	Message string                `json:"message,omitempty"`
+	Field   string                `json:"field,omitempty"`
+	Details []apperror.FieldError `json:"details,omitempty"`
 }
 
 type Response struct {
@@ -15,6 +26,19 @@ type Response struct {
 	Error   *Error      `json:"error,omitempty"`
 }
 
+// codeByStatus names the errors raised by echo itself, e.g. unknown routes or malformed bodies.
+var codeByStatus = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          apperror.CodeUnauthorized,
+	http.StatusForbidden:             apperror.CodeForbidden,
+	http.StatusNotFound:              apperror.CodeNotFound,
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusConflict:              apperror.CodeConflict,
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusServiceUnavailable:    apperror.CodeUnavailable,
+}
+
 func SuccessResponse(c echo.Context, data interface{}) error {
 	resp := Response{}
 	resp.Message = "success"
@@ -25,14 +49,47 @@ func SuccessResponse(c echo.Context, data interface{}) error {
 	return c.JSON(200, resp)
 }
 
-func ErrorResponse(c echo.Context, message string, statusCode int) error {
+// HTTPErrorHandler renders every error returned by the handlers and
+// middlewares in the Response envelope. Errors that are neither domain nor
+// echo errors are hidden behind a generic internal error.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, body := toError(err)
+	if status >= http.StatusInternalServerError {
+		logger.WithField("path", c.Path()).Errorf("request failed got %s", err.Error())
+	}
 
 	resp := Response{}
 	resp.Message = "failed"
-	resp.Status = statusCode
-	resp.Error = &Error{}
-	resp.Error.Message = message
+	resp.Status = status
+	resp.Error = body
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, resp)
+	}
+
+	if err != nil {
+		logger.Errorf("error when write error response got %s", err.Error())
+	}
+}
+
+func toError(err error) (int, *Error) {
+	if appErr, ok := apperror.As(err); ok {
+		return appErr.Status(), &Error{Code: appErr.Code, Message: appErr.Message, Field: appErr.Field, Details: appErr.Details}
+	}
 
-	return c.JSON(statusCode, resp)
+	if he, ok := err.(*echo.HTTPError); ok {
+		code, found := codeByStatus[he.Code]
+		if !found {
+			code = apperror.CodeInternal
+		}
+		return he.Code, &Error{Code: code, Message: fmt.Sprint(he.Message)}
+	}
 
+	return http.StatusInternalServerError, &Error{Code: apperror.CodeInternal, Message: http.StatusText(http.StatusInternalServerError)}
 }
@@ -0,0 +1,84 @@
+package response
+
+import (
+	"database/sql"
+	"employee/internal/apperror"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		err         error
+		checkReturn func(resp *httptest.ResponseRecorder, body *Response)
+	}{
+		{
+			name: "domain error",
+			err:  apperror.Conflict("email", "email already exists"),
+			checkReturn: func(resp *httptest.ResponseRecorder, body *Response) {
+				assert.Equal(t, http.StatusConflict, resp.Code)
+				assert.Equal(t, http.StatusConflict, body.Status)
+				assert.Equal(t, &Error{Code: apperror.CodeConflict, Message: "email already exists", Field: "email"}, body.Error)
+			},
+		},
+		{
+			name: "wrapped domain error",
+			err:  fmt.Errorf("update employee: %w", apperror.NotFound("employee not found")),
+			checkReturn: func(resp *httptest.ResponseRecorder, body *Response) {
+				assert.Equal(t, http.StatusNotFound, resp.Code)
+				assert.Equal(t, apperror.CodeNotFound, body.Error.Code)
+			},
+		},
+		{
+			name: "unavailable error hides the cause",
+			err:  apperror.Unavailable(sql.ErrConnDone),
+			checkReturn: func(resp *httptest.ResponseRecorder, body *Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+				assert.NotContains(t, body.Error.Message, sql.ErrConnDone.Error())
+			},
+		},
+		{
+			name: "echo error",
+			err:  echo.ErrMethodNotAllowed,
+			checkReturn: func(resp *httptest.ResponseRecorder, body *Response) {
+				assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+				assert.Equal(t, "method_not_allowed", body.Error.Code)
+			},
+		},
+		{
+			name: "unknown error",
+			err:  sql.ErrConnDone,
+			checkReturn: func(resp *httptest.ResponseRecorder, body *Response) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Equal(t, apperror.CodeInternal, body.Error.Code)
+				assert.NotContains(t, body.Error.Message, sql.ErrConnDone.Error())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			HTTPErrorHandler(tc.err, c)
+
+			body := new(Response)
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), body))
+
+			tc.checkReturn(rec, body)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"employee/internal/config"
+	"employee/internal/pkg"
+)
+
+// newPasswordPolicy returns the policy of the initial passwords of provisioned
+// accounts, DefaultPasswordPolicy with the PASSWORD_* settings applied. It
+// generates one password so that an unusable policy fails at startup.
+func newPasswordPolicy(cfg config.Config) (pkg.PasswordPolicy, error) {
+	policy := pkg.DefaultPasswordPolicy
+	if cfg.PasswordLength > 0 {
+		policy.Length = cfg.PasswordLength
+	}
+	policy.Passphrase = cfg.PasswordPassphrase
+	policy.Words = cfg.PasswordWords
+	policy.Separator = cfg.PasswordSeparator
+
+	if _, err := pkg.GeneratePassword(policy); err != nil {
+		return pkg.PasswordPolicy{}, err
+	}
+
+	return policy, nil
+}
@@ -1,20 +1,30 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"employee/internal/config"
+	"employee/internal/response"
 	"github.com/labstack/echo/v4"
 )
 
+type Job interface {
+	Start(ctx context.Context)
+}
+
 type Rest struct {
 	Echo   *echo.Echo
 	Config *config.Config
 	SQL    *sql.DB
+	Jobs   []Job
 }
 
 func NewServer(cfg *config.Config, sql *sql.DB) *Rest {
+	e := echo.New()
+	e.HTTPErrorHandler = response.HTTPErrorHandler
+
 	return &Rest{
-		Echo:   echo.New(),
+		Echo:   e,
 		Config: cfg,
 		SQL:    sql,
 	}
@@ -23,3 +33,11 @@ func NewServer(cfg *config.Config, sql *sql.DB) *Rest {
 func (r *Rest) Start(addr string) error {
 	return r.Echo.Start(":" + addr)
 }
+
+// StartJobs starts the background jobs registered by ConfigureRoutes, they
+// stop when ctx is cancelled.
+func (r *Rest) StartJobs(ctx context.Context) {
+	for _, job := range r.Jobs {
+		job.Start(ctx)
+	}
+}
@@ -1,11 +1,44 @@
 package server
 
 import (
+	"context"
+	attendanceHandler "employee/internal/handler/attendance"
+	auditHandler "employee/internal/handler/audit"
+	authHandler "employee/internal/handler/auth"
+	compHandler "employee/internal/handler/compensation"
+	cfHandler "employee/internal/handler/customfield"
+	deptHandler "employee/internal/handler/department"
+	docHandler "employee/internal/handler/document"
 	empHandler "employee/internal/handler/employee"
+	leaveHandler "employee/internal/handler/leave"
+	posHandler "employee/internal/handler/position"
+	"employee/internal/job"
 	mdlwr "employee/internal/middleware"
+	"employee/internal/repository"
+	attendanceRepo "employee/internal/repository/attendance"
+	auditRepo "employee/internal/repository/audit"
+	compRepo "employee/internal/repository/compensation"
+	cfRepo "employee/internal/repository/customfield"
+	deptRepo "employee/internal/repository/department"
+	docRepo "employee/internal/repository/document"
 	empRepo "employee/internal/repository/employee"
+	idempotencyRepo "employee/internal/repository/idempotency"
+	leaveRepo "employee/internal/repository/leave"
+	posRepo "employee/internal/repository/position"
+	userRepo "employee/internal/repository/user"
+	"employee/internal/transport"
+	attendanceUsecase "employee/internal/usecase/attendance"
+	auditUsecase "employee/internal/usecase/audit"
+	authUsecase "employee/internal/usecase/auth"
+	compUsecase "employee/internal/usecase/compensation"
+	cfUsecase "employee/internal/usecase/customfield"
+	deptUsecase "employee/internal/usecase/department"
+	docUsecase "employee/internal/usecase/document"
 	empUsecase "employee/internal/usecase/employee"
+	leaveUsecase "employee/internal/usecase/leave"
+	posUsecase "employee/internal/usecase/position"
 	log "github.com/sirupsen/logrus"
+	"time"
 )
 
 type Router struct {
@@ -16,16 +49,161 @@ var rLog = log.WithField("module", "router")
 func (r *Rest) ConfigureRoutes() {
 	cfg := *r.Config
 
+	r.Echo.Use(mdlwr.RequestIDMiddleware)
 	r.Echo.Use(mdlwr.LoggingMiddleware)
 
+	transactor := repository.NewTransactor(r.SQL)
+
+	usersRepo := userRepo.NewRepoUser(r.SQL)
+	authUseCase := authUsecase.NewUseCaseAuth(usersRepo, transactor, cfg)
+	authsHandler := authHandler.NewAuthHandler(authUseCase, cfg)
+
+	if cfg.AdminEmail != "" {
+		if err := authUseCase.EnsureAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword); err != nil {
+			rLog.Errorf("error when bootstrap admin account got %s", err.Error())
+		}
+	}
+
+	auth := r.Echo.Group("/auth")
+	auth.POST("/login", authsHandler.Login)
+	auth.POST("/refresh", authsHandler.Refresh)
+	auth.POST("/logout", authsHandler.Logout)
+
+	auditsRepo := auditRepo.NewRepoAudit(r.SQL)
+	auditUseCase := auditUsecase.NewUseCaseAudit(auditsRepo)
+	auditsHandler := auditHandler.NewAuditHandler(auditUseCase)
+
+	positionRepo := posRepo.NewRepoPosition(r.SQL)
+	customFieldRepo := cfRepo.NewRepoCustomField(r.SQL)
+
+	documentStorage, err := newDocumentStorage(cfg)
+	if err != nil {
+		rLog.Fatalf("error when open document storage got %s", err.Error())
+	}
+
+	passwordPolicy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		rLog.Fatalf("error when read password policy got %s", err.Error())
+	}
+
 	employeeRepo := empRepo.NewRepoUser(r.SQL)
-	employeeUseCase := empUsecase.NewUseCaseEmployee(employeeRepo)
+	employeeUseCase := empUsecase.NewUseCaseEmployee(employeeRepo, usersRepo, auditsRepo, positionRepo, customFieldRepo, transactor, documentStorage, passwordPolicy)
 	employeeHandler := empHandler.NewEmployeeHandler(employeeUseCase, cfg)
 
-	r.Echo.POST("/employees", employeeHandler.CreateEmployee)
-	r.Echo.GET("/employees", employeeHandler.GetEmployee)
-	r.Echo.GET("/employees/:employee_id", employeeHandler.GetEmployeeByID)
-	r.Echo.PUT("/employees/:employee_id", employeeHandler.UpdateEmployee)
-	r.Echo.DELETE("/employees/:employee_id", employeeHandler.DeleteEmployee)
+	compensationRepo := compRepo.NewRepoCompensation(r.SQL)
+	compensationUseCase := compUsecase.NewUseCaseCompensation(compensationRepo, employeeRepo, transactor)
+	compensationHandler := compHandler.NewCompensationHandler(compensationUseCase)
+
+	leavesRepo := leaveRepo.NewRepoLeave(r.SQL)
+	leaveUseCase := leaveUsecase.NewUseCaseLeave(leavesRepo, employeeRepo, auditsRepo, transactor)
+	leavesHandler := leaveHandler.NewLeaveHandler(leaveUseCase, employeeUseCase)
+
+	schedule, err := attendanceUsecase.NewSchedule(cfg.WorkStart, cfg.WorkDays, cfg.WorkGraceMinutes, cfg.WorkTimezone)
+	if err != nil {
+		rLog.Fatalf("error when read work schedule got %s", err.Error())
+	}
+
+	attendancesRepo := attendanceRepo.NewRepoAttendance(r.SQL)
+	attendanceUseCase := attendanceUsecase.NewUseCaseAttendance(attendancesRepo, employeeRepo, schedule)
+	attendancesHandler := attendanceHandler.NewAttendanceHandler(attendanceUseCase, employeeUseCase)
+
+	documentURLTTL := time.Duration(cfg.DocumentURLTTLMinutes) * time.Minute
+	if documentURLTTL <= 0 {
+		documentURLTTL = 15 * time.Minute
+	}
+
+	documentRepo := docRepo.NewRepoDocument(r.SQL)
+	documentUseCase := docUsecase.NewUseCaseDocument(documentRepo, employeeRepo, documentStorage, cfg.DocumentURLSecret, documentURLTTL)
+	documentHandler := docHandler.NewDocumentHandler(documentUseCase)
+
+	if cfg.PurgeRetentionDays > 0 && cfg.PurgeIntervalHours > 0 {
+		retention := time.Duration(cfg.PurgeRetentionDays) * 24 * time.Hour
+		interval := time.Duration(cfg.PurgeIntervalHours) * time.Hour
+		r.Jobs = append(r.Jobs, job.NewPurgeEmployees(employeeUseCase, retention, interval))
+	}
+
+	keysRepo := idempotencyRepo.NewRepoIdempotency(r.SQL)
+	keysTTL := time.Duration(cfg.IdempotencyTTLHours) * time.Hour
+	if keysTTL <= 0 {
+		keysTTL = 24 * time.Hour
+	}
+	r.Jobs = append(r.Jobs, job.NewExpireIdempotencyKeys(keysRepo, keysTTL, time.Hour))
+
+	employees := r.Echo.Group("/employees", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	employees.POST("", employeeHandler.CreateEmployee, mdlwr.IdempotencyMiddleware(keysRepo, keysTTL, transport.MaxJSONBytes))
+	employees.POST("/import", employeeHandler.ImportEmployees)
+	employees.GET("", employeeHandler.GetEmployee)
+	employees.GET("/export", employeeHandler.ExportEmployees)
+	employees.GET("/:employee_id", employeeHandler.GetEmployeeByID)
+	employees.PUT("/:employee_id", employeeHandler.UpdateEmployee)
+	employees.PATCH("/:employee_id", employeeHandler.PatchEmployee)
+	employees.DELETE("/:employee_id", employeeHandler.DeleteEmployee)
+	employees.POST("/:employee_id/restore", employeeHandler.RestoreEmployee)
+	employees.GET("/:employee_id/reports", employeeHandler.GetReports)
+	employees.GET("/:employee_id/chain", employeeHandler.GetManagementChain)
+	employees.GET("/:employee_id/positions", employeeHandler.GetPositionHistory)
+	employees.POST("/:employee_id/positions", employeeHandler.AssignPosition)
+	employees.POST("/:employee_id/status", employeeHandler.ChangeStatus)
+	employees.POST("/:employee_id/terminate", employeeHandler.TerminateEmployee)
+	employees.POST("/:employee_id/rehire", employeeHandler.RehireEmployee)
+	employees.GET("/:employee_id/compensations", compensationHandler.GetCompensations)
+	employees.POST("/:employee_id/compensations", compensationHandler.CreateCompensation)
+	employees.GET("/:employee_id/leave-balances", leavesHandler.GetBalances)
+	employees.GET("/:employee_id/leave-requests", leavesHandler.GetLeaveRequests)
+	employees.POST("/:employee_id/leave-requests", leavesHandler.CreateLeaveRequest)
+	employees.POST("/:employee_id/clock-in", attendancesHandler.ClockIn)
+	employees.POST("/:employee_id/clock-out", attendancesHandler.ClockOut)
+	employees.GET("/:employee_id/attendance/daily", attendancesHandler.GetDailyAttendance)
+	employees.GET("/:employee_id/attendance/weekly", attendancesHandler.GetWeeklyAttendance)
+	employees.POST("/:employee_id/documents", documentHandler.UploadDocument)
+	employees.GET("/:employee_id/documents", documentHandler.GetDocuments)
+	employees.GET("/:employee_id/documents/:document_id", documentHandler.GetDocument)
+	employees.DELETE("/:employee_id/documents/:document_id", documentHandler.DeleteDocument)
+	employees.GET("/:employee_id/audit", auditsHandler.GetEmployeeAudits)
+
+	r.Echo.GET("/audit", auditsHandler.GetAudits, mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	r.Echo.GET("/org-chart", employeeHandler.GetOrgChart, mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	r.Echo.GET("/attendance/report", attendancesHandler.GetReport, mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	// the signature of the link authorizes the download
+	r.Echo.GET("/documents/:document_id/download", documentHandler.DownloadDocument)
+
+	departmentRepo := deptRepo.NewRepoDepartment(r.SQL)
+	departmentUseCase := deptUsecase.NewUseCaseDepartment(departmentRepo, transactor)
+	departmentHandler := deptHandler.NewDepartmentHandler(departmentUseCase)
+
+	departments := r.Echo.Group("/departments", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	departments.POST("", departmentHandler.CreateDepartment)
+	departments.GET("", departmentHandler.GetDepartments)
+	departments.GET("/:department_id", departmentHandler.GetDepartmentByID)
+	departments.PUT("/:department_id", departmentHandler.UpdateDepartment)
+	departments.DELETE("/:department_id", departmentHandler.DeleteDepartment)
+
+	positionUseCase := posUsecase.NewUseCasePosition(positionRepo, transactor)
+	positionHandler := posHandler.NewPositionHandler(positionUseCase)
+
+	positions := r.Echo.Group("/positions", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	positions.POST("", positionHandler.CreatePosition)
+	positions.GET("", positionHandler.GetPositions)
+	positions.GET("/:position_id", positionHandler.GetPositionByID)
+	positions.PUT("/:position_id", positionHandler.UpdatePosition)
+	positions.DELETE("/:position_id", positionHandler.DeletePosition)
+
+	customFieldUseCase := cfUsecase.NewUseCaseCustomField(customFieldRepo, auditsRepo, transactor)
+	customFieldHandler := cfHandler.NewCustomFieldHandler(customFieldUseCase)
+
+	customFields := r.Echo.Group("/custom-fields", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	customFields.POST("", customFieldHandler.CreateCustomField)
+	customFields.GET("", customFieldHandler.GetCustomFields)
+	customFields.GET("/:custom_field_id", customFieldHandler.GetCustomFieldByID)
+	customFields.PUT("/:custom_field_id", customFieldHandler.UpdateCustomField)
+	customFields.DELETE("/:custom_field_id", customFieldHandler.DeleteCustomField)
+
+	leaveTypes := r.Echo.Group("/leave-types", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	leaveTypes.POST("", leavesHandler.CreateLeaveType)
+	leaveTypes.GET("", leavesHandler.GetLeaveTypes)
+
+	leaveRequests := r.Echo.Group("/leave-requests", mdlwr.JWTMiddleware(cfg.JWTSecret, usersRepo), mdlwr.AuthorizeMiddleware)
+	leaveRequests.POST("/:leave_request_id/approve", leavesHandler.ApproveLeaveRequest)
+	leaveRequests.POST("/:leave_request_id/reject", leavesHandler.RejectLeaveRequest)
 
 }
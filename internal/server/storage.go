@@ -0,0 +1,31 @@
+package server
+
+import (
+	"employee/internal/config"
+	"employee/internal/storage"
+	"fmt"
+)
+
+// newDocumentStorage opens the storage selected by STORAGE_DRIVER, the local
+// filesystem unless it is s3.
+func newDocumentStorage(cfg config.Config) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		dir := cfg.StorageDir
+		if dir == "" {
+			dir = "./storage"
+		}
+		return storage.NewLocalStorage(dir)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, expected local or s3", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+
+	store, err := NewLocalStorage(filepath.Join(root, "documents"))
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+
+	require.NoError(t, store.Put(ctx, "employees/3/contract", strings.NewReader("%PDF-1.7"), 8, "application/pdf"))
+
+	body, err := store.Get(ctx, "employees/3/contract")
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "%PDF-1.7", string(content))
+
+	// a key cannot climb out of the root
+	require.NoError(t, store.Put(ctx, "../../escape", strings.NewReader("x"), 1, "text/plain"))
+	_, err = os.Stat(filepath.Join(root, "escape"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, "documents", "escape"))
+	assert.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, "employees/3/contract"))
+	require.NoError(t, store.Delete(ctx, "employees/3/contract"))
+
+	_, err = store.Get(ctx, "employees/3/contract")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+
+	assert.Error(t, store.Put(ctx, "/", strings.NewReader("x"), 1, "text/plain"))
+}
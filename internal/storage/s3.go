@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config reaches a bucket of an S3 compatible service. Endpoint is the base
+// URL of the service, e.g. https://s3.eu-west-1.amazonaws.com or a MinIO
+// server. PathStyle addresses the bucket in the path rather than the host.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+type s3Storage struct {
+	endpoint    *url.URL
+	bucket      string
+	pathStyle   bool
+	credentials credentials
+	client      *http.Client
+}
+
+// NewS3Storage keeps the objects in a bucket of an S3 compatible service, the
+// requests are signed with Signature Version 4.
+func NewS3Storage(cfg S3Config, client *http.Client) (Storage, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket must be set")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: time.Minute}
+	}
+
+	return &s3Storage{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		credentials: credentials{
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			region:    region,
+			service:   "s3",
+		},
+		client: client,
+	}, nil
+}
+
+// Put uploads the object in a single request, the body is buffered to sign
+// its hash so it is meant for files of a few megabytes.
+func (s *s3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(payload))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, hashHex(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	return nil
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return responseError(resp)
+	}
+}
+
+// newRequest addresses the key in the bucket. The path is escaped the way it
+// is signed so both agree on every character of the key.
+func (s *s3Storage) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" {
+		return nil, fmt.Errorf("storage: invalid key %q", key)
+	}
+
+	target := *s.endpoint
+	basePath := strings.TrimSuffix(target.Path, "/")
+	if s.pathStyle {
+		target.Path = basePath + "/" + s.bucket + "/" + key
+	} else {
+		target.Host = s.bucket + "." + target.Host
+		target.Path = basePath + "/" + key
+	}
+	target.RawPath = uriEncode(target.Path, false)
+
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+func (s *s3Storage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.credentials.sign(req, payloadHash, time.Now())
+
+	return s.client.Do(req)
+}
+
+// responseError keeps the start of the error document sent by the service.
+func responseError(resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	return fmt.Errorf("storage: s3 responded %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
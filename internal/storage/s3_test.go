@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 stands in for an S3 compatible service, it keeps the objects in
+// memory and rejects requests whose signature does not match.
+type fakeS3 struct {
+	t           *testing.T
+	credentials credentials
+	mu          sync.Mutex
+	objects     map[string][]byte
+	types       map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(f.t, err)
+
+	if !f.verify(r, body) {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(object)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verify signs a copy of the received request again and compares both.
+func (f *fakeS3) verify(r *http.Request, body []byte) bool {
+	if r.Header.Get("X-Amz-Content-Sha256") != hashHex(body) {
+		return false
+	}
+
+	signedAt, err := time.Parse(amzDateLayout, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	copied, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.EscapedPath(), nil)
+	require.NoError(f.t, err)
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		copied.Header.Set("Content-Type", contentType)
+	}
+
+	f.credentials.sign(copied, hashHex(body), signedAt)
+
+	return copied.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func newFakeS3(t *testing.T, secretKey string) (*httptest.Server, *fakeS3) {
+	fake := &fakeS3{
+		t:           t,
+		credentials: credentials{accessKey: "minio", secretKey: secretKey, region: "us-east-1", service: "s3"},
+		objects:     make(map[string][]byte),
+		types:       make(map[string]string),
+	}
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return server, fake
+}
+
+func TestS3Storage(t *testing.T) {
+	server, fake := newFakeS3(t, "minio-secret")
+
+	store, err := NewS3Storage(S3Config{Endpoint: server.URL, Bucket: "documents", AccessKey: "minio", SecretKey: "minio-secret", PathStyle: true},
+		server.Client())
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	key := "employees/3/id card.png"
+
+	require.NoError(t, store.Put(ctx, key, bytes.NewReader([]byte("\x89PNG")), 4, "image/png"))
+	assert.Equal(t, "image/png", fake.types["/documents/"+key])
+
+	body, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "\x89PNG", string(content))
+
+	require.NoError(t, store.Delete(ctx, key))
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
+
+func TestS3StorageRejectedSignature(t *testing.T) {
+	server, _ := newFakeS3(t, "minio-secret")
+
+	store, err := NewS3Storage(S3Config{Endpoint: server.URL, Bucket: "documents", AccessKey: "minio", SecretKey: "wrong", PathStyle: true},
+		server.Client())
+	require.NoError(t, err)
+
+	err = store.Put(context.TODO(), "employees/3/contract", strings.NewReader("%PDF"), 4, "application/pdf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestNewS3Storage(t *testing.T) {
+	_, err := NewS3Storage(S3Config{Endpoint: "localhost:9000", Bucket: "documents"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewS3Storage(S3Config{Endpoint: "http://localhost:9000"}, nil)
+	assert.Error(t, err)
+}
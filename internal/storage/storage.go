@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectNotFound is returned when reading a key that holds no object.
+var ErrObjectNotFound = errors.New("storage: object not found")
+
+// Storage keeps the uploaded files under slash separated keys, e.g.
+// employees/3/9f86d081. Deleting a missing key is not an error.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
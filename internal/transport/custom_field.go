@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CustomFieldFilterPrefix prefixes the query parameters filtering employees by
+// the value of a custom field, e.g. cf.cost_center=CC-12.
+const CustomFieldFilterPrefix = "cf."
+
+// CustomFieldFilters returns the custom field filters of a query by field
+// name, the first value wins when a filter is repeated. It is nil without
+// any custom field filter.
+func CustomFieldFilters(query url.Values) map[string]string {
+	var filters map[string]string
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, CustomFieldFilterPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if filters == nil {
+			filters = make(map[string]string)
+		}
+		filters[name] = values[0]
+	}
+
+	return filters
+}
@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxDocumentBytes bounds an uploaded document, MaxDocumentRequestBytes
+	// the whole multipart request carrying it.
+	MaxDocumentBytes        = 10 << 20
+	MaxDocumentRequestBytes = MaxDocumentBytes + 1<<20
+	// maxFileNameLength bounds the file name kept with a document.
+	maxFileNameLength = 255
+	// sniffLength is the most http.DetectContentType reads.
+	sniffLength = 512
+)
+
+// documentTypes are the media types a document may have, as sniffed from
+// its content.
+var documentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+}
+
+// UploadDocumentReq attaches a file to an employee, the handler fills the
+// file fields from the multipart upload.
+type UploadDocumentReq struct {
+	EmployeeID  int       `json:"-"`
+	Category    string    `form:"category" validate:"required,oneof=contract id certificate other"`
+	FileName    string    `json:"-"`
+	ContentType string    `json:"-"`
+	Size        int64     `json:"-"`
+	Content     io.Reader `json:"-"`
+}
+
+// SniffDocumentType detects the media type of a document from its first
+// bytes and rewinds it. The type declared by the client is not trusted, media
+// types other than PDF and common images are rejected with 415.
+func SniffDocumentType(content io.ReadSeeker) (string, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mediaType := http.DetectContentType(head[:n])
+	if !documentTypes[mediaType] {
+		allowed := make([]string, 0, len(documentTypes))
+		for documentType := range documentTypes {
+			allowed = append(allowed, documentType)
+		}
+		sort.Strings(allowed)
+
+		return "", echo.NewHTTPError(echo.ErrUnsupportedMediaType.Code,
+			fmt.Sprintf("document must be one of %s, got %s", strings.Join(allowed, ", "), mediaType))
+	}
+
+	return mediaType, nil
+}
+
+// DocumentFileName keeps the base name of an uploaded file without control
+// characters, so it is safe to echo in a Content-Disposition header.
+func DocumentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+
+	return name
+}
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSniffDocumentType(t *testing.T) {
+	testCases := []struct {
+		name         string
+		content      []byte
+		expectedType string
+		expectedCode int
+	}{
+		{name: "pdf", content: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), expectedType: "application/pdf"},
+		{name: "png", content: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), expectedType: "image/png"},
+		{name: "html is rejected", content: []byte("<html><script>alert(1)</script></html>"), expectedCode: http.StatusUnsupportedMediaType},
+		{name: "executable is rejected", content: []byte("MZ\x90\x00\x03\x00\x00\x00"), expectedCode: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := bytes.NewReader(tc.content)
+
+			mediaType, err := SniffDocumentType(content)
+			if tc.expectedCode != 0 {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tc.expectedCode, httpErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedType, mediaType)
+
+			// the content is rewound for the upload
+			rest, err := io.ReadAll(content)
+			require.NoError(t, err)
+			assert.Equal(t, tc.content, rest)
+		})
+	}
+}
+
+func TestDocumentFileName(t *testing.T) {
+	assert.Equal(t, "contract.pdf", DocumentFileName("contract.pdf"))
+	assert.Equal(t, "passwd", DocumentFileName("../../etc/passwd"))
+	assert.Equal(t, "id.png", DocumentFileName(`C:\Users\jane\id.png`))
+	assert.Equal(t, "ab.pdf", DocumentFileName("a\"\r\nb.pdf"))
+	assert.Equal(t, "document", DocumentFileName(""))
+	assert.Len(t, []rune(DocumentFileName(strings.Repeat("é", 300))), 255)
+}
@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// ETag returns the strong entity tag of a resource version.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ParseIfMatch returns the version a request expects to modify. The header is
+// required, "*" matches any version and is returned as 0.
+func ParseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, apperror.PreconditionRequired("If-Match header is required")
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil || strings.HasPrefix(header, "W/") {
+		return 0, apperror.Validation(HeaderIfMatch, "If-Match must be a strong entity tag, e.g. \"3\"")
+	}
+
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, apperror.Validation(HeaderIfMatch, "If-Match must be a strong entity tag, e.g. \"3\"")
+	}
+
+	return version, nil
+}
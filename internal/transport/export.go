@@ -0,0 +1,332 @@
+package transport
+
+import (
+	"archive/zip"
+	"employee/internal/apperror"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+
+	MIMEXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// exportColumns are the columns an export can select, in their default order.
+var exportColumns = []string{"id", "first_name", "last_name", "email", "hire_date", "manager_id", "department_id", "position_id", "status", "termination_date", "deleted_at"}
+
+// ExportWriter encodes employees one at a time, Close flushes what is buffered
+// and must be called once every employee was written.
+type ExportWriter interface {
+	Write(employee *EmployeeRes) error
+	Close() error
+}
+
+// ParseExportColumns returns the comma separated columns in the order they
+// were asked for, every column when none is given.
+func ParseExportColumns(columns string) ([]string, error) {
+	if strings.TrimSpace(columns) == "" {
+		return exportColumns, nil
+	}
+
+	known := map[string]bool{}
+	for _, column := range exportColumns {
+		known[column] = true
+	}
+
+	var selected []string
+	seen := map[string]bool{}
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if !known[column] {
+			invalid := apperror.Validation("columns", fmt.Sprintf("unknown column '%s'", column))
+			invalid.Details = []apperror.FieldError{{Field: "columns", Rule: "oneof"}}
+			return nil, invalid
+		}
+		if !seen[column] {
+			seen[column] = true
+			selected = append(selected, column)
+		}
+	}
+
+	return selected, nil
+}
+
+// ExportContentType returns the media type an export format is served with.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return MIMECSV + "; charset=utf-8"
+	case ExportFormatNDJSON:
+		return MIMENDJSON
+	default:
+		return MIMEXLSX
+	}
+}
+
+func NewExportWriter(format string, w io.Writer, columns []string) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExport(w, columns)
+	case ExportFormatNDJSON:
+		return &ndjsonExport{encoder: json.NewEncoder(w), columns: columns}, nil
+	case ExportFormatXLSX:
+		return newXLSXExport(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// exportValue returns the column of the employee as text, empty when unset.
+func exportValue(employee *EmployeeRes, column string) string {
+	switch column {
+	case "id":
+		return strconv.Itoa(employee.ID)
+	case "first_name":
+		return employee.FirstName
+	case "last_name":
+		return employee.LastName
+	case "email":
+		return employee.Email
+	case "hire_date":
+		return employee.HireDate
+	case "manager_id":
+		if employee.ManagerID == nil {
+			return ""
+		}
+		return strconv.Itoa(*employee.ManagerID)
+	case "department_id":
+		if employee.DepartmentID == nil {
+			return ""
+		}
+		return strconv.Itoa(*employee.DepartmentID)
+	case "position_id":
+		if employee.PositionID == nil {
+			return ""
+		}
+		return strconv.Itoa(*employee.PositionID)
+	case "status":
+		return employee.Status
+	case "termination_date":
+		if employee.TerminationDate == nil {
+			return ""
+		}
+		return *employee.TerminationDate
+	case "deleted_at":
+		if employee.DeletedAt == nil {
+			return ""
+		}
+		return employee.DeletedAt.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// csvValue is exportValue for CSV, which spreadsheets evaluate when opening
+// it. Text starting with a formula trigger gets a leading quote so that a cell
+// like =HYPERLINK(...) is shown as text. XLSX needs none, its inline string
+// cells are never evaluated.
+func csvValue(employee *EmployeeRes, column string) string {
+	value := exportValue(employee, column)
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+type csvExport struct {
+	writer  *csv.Writer
+	columns []string
+	record  []string
+}
+
+func newCSVExport(w io.Writer, columns []string) (*csvExport, error) {
+	export := &csvExport{writer: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	if err := export.writer.Write(columns); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (e *csvExport) Write(employee *EmployeeRes) error {
+	for i, column := range e.columns {
+		e.record[i] = csvValue(employee, column)
+	}
+
+	return e.writer.Write(e.record)
+}
+
+func (e *csvExport) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type ndjsonExport struct {
+	encoder *json.Encoder
+	columns []string
+}
+
+func (e *ndjsonExport) Write(employee *EmployeeRes) error {
+	line := make(map[string]interface{}, len(e.columns))
+	for _, column := range e.columns {
+		switch column {
+		case "id":
+			line[column] = employee.ID
+		case "manager_id":
+			line[column] = employee.ManagerID
+		case "department_id":
+			line[column] = employee.DepartmentID
+		case "position_id":
+			line[column] = employee.PositionID
+		case "termination_date":
+			line[column] = employee.TerminationDate
+		case "deleted_at":
+			line[column] = employee.DeletedAt
+		default:
+			line[column] = exportValue(employee, column)
+		}
+	}
+
+	return e.encoder.Encode(line)
+}
+
+func (e *ndjsonExport) Close() error {
+	return nil
+}
+
+// xlsxParts are the parts of a workbook with a single sheet, the sheet itself
+// is streamed by xlsxExport.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="employees" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxNumericColumns are written as numbers, every other column as text.
+var xlsxNumericColumns = map[string]bool{"id": true, "manager_id": true, "department_id": true, "position_id": true}
+
+// xlsxExport writes an Office Open XML workbook. Zip entries are written
+// sequentially, so the sheet is streamed row by row as the last entry.
+type xlsxExport struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	columns []string
+	row     int
+}
+
+func newXLSXExport(w io.Writer, columns []string) (*xlsxExport, error) {
+	archive := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &xlsxExport{archive: archive, sheet: sheet, columns: columns}
+	if err := export.writeRow(columns, nil); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (e *xlsxExport) Write(employee *EmployeeRes) error {
+	values := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		values[i] = exportValue(employee, column)
+	}
+
+	return e.writeRow(values, xlsxNumericColumns)
+}
+
+func (e *xlsxExport) writeRow(values []string, numeric map[string]bool) error {
+	e.row++
+
+	var row strings.Builder
+	fmt.Fprintf(&row, `<row r="%d">`, e.row)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+
+		ref := columnName(i) + strconv.Itoa(e.row)
+		if numeric[e.columns[i]] {
+			fmt.Fprintf(&row, `<c r="%s"><v>%s</v></c>`, ref, value)
+			continue
+		}
+
+		fmt.Fprintf(&row, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(&row, []byte(value)); err != nil {
+			return err
+		}
+		row.WriteString(`</t></is></c>`)
+	}
+	row.WriteString(`</row>`)
+
+	_, err := io.WriteString(e.sheet, row.String())
+	return err
+}
+
+func (e *xlsxExport) Close() error {
+	if _, err := io.WriteString(e.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+
+	return e.archive.Close()
+}
+
+// columnName returns the spreadsheet name of a zero based column, e.g. 27 is "AB".
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+
+	return name
+}
@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"archive/zip"
+	"bytes"
+	"employee/internal/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseExportColumns(t *testing.T) {
+	columns, err := ParseExportColumns("")
+	require.NoError(t, err)
+	assert.Equal(t, exportColumns, columns)
+
+	columns, err = ParseExportColumns("email, id,email")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "id"}, columns)
+
+	_, err = ParseExportColumns("id,salary")
+	assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+	assert.ErrorContains(t, err, "salary")
+}
+
+func TestExportWriter(t *testing.T) {
+	managerID := 2
+	deletedAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	employees := []*EmployeeRes{
+		{ID: 1, FirstName: "john", LastName: "mayer", Email: "john@mail.com", HireDate: "2023-01-15"},
+		{ID: 3, FirstName: "jane, \"jr\"", Email: "jane@mail.com", HireDate: "2023-02-01", ManagerID: &managerID, DeletedAt: &deletedAt},
+	}
+
+	testCases := []struct {
+		name        string
+		format      string
+		columns     []string
+		checkReturn func(output []byte)
+	}{
+		{
+			name:    "csv",
+			format:  ExportFormatCSV,
+			columns: []string{"id", "first_name", "manager_id", "deleted_at"},
+			checkReturn: func(output []byte) {
+				assert.Equal(t, "id,first_name,manager_id,deleted_at\n1,john,,\n3,\"jane, \"\"jr\"\"\",2,2023-06-01T10:00:00Z\n", string(output))
+			},
+		},
+		{
+			name:    "ndjson keeps json types",
+			format:  ExportFormatNDJSON,
+			columns: []string{"id", "email", "manager_id"},
+			checkReturn: func(output []byte) {
+				assert.Equal(t, "{\"email\":\"john@mail.com\",\"id\":1,\"manager_id\":null}\n{\"email\":\"jane@mail.com\",\"id\":3,\"manager_id\":2}\n", string(output))
+			},
+		},
+		{
+			name:    "xlsx",
+			format:  ExportFormatXLSX,
+			columns: []string{"id", "first_name", "email"},
+			checkReturn: func(output []byte) {
+				archive, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+				require.NoError(t, err)
+
+				var names []string
+				var sheet []byte
+				for _, file := range archive.File {
+					names = append(names, file.Name)
+					if file.Name == "xl/worksheets/sheet1.xml" {
+						reader, err := file.Open()
+						require.NoError(t, err)
+						sheet, err = io.ReadAll(reader)
+						require.NoError(t, err)
+					}
+				}
+
+				assert.Contains(t, names, "[Content_Types].xml")
+				assert.Contains(t, names, "xl/workbook.xml")
+				assert.Contains(t, string(sheet), `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+				assert.Contains(t, string(sheet), `<row r="2"><c r="A2"><v>1</v></c>`)
+				assert.Contains(t, string(sheet), `<t xml:space="preserve">jane, &#34;jr&#34;</t>`)
+				assert.Contains(t, string(sheet), `</sheetData></worksheet>`)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			writer, err := NewExportWriter(tc.format, &output, tc.columns)
+			require.NoError(t, err)
+
+			for _, employee := range employees {
+				require.NoError(t, writer.Write(employee))
+			}
+			require.NoError(t, writer.Close())
+
+			tc.checkReturn(output.Bytes())
+		})
+	}
+}
+
+func TestExportWriterFormulas(t *testing.T) {
+	employee := &EmployeeRes{ID: 1, FirstName: "=HYPERLINK(\"http://evil\")", LastName: "-2+3", Email: "@sum", HireDate: "2023-01-15"}
+	columns := []string{"id", "first_name", "last_name", "email", "hire_date"}
+
+	var output bytes.Buffer
+	writer, err := NewExportWriter(ExportFormatCSV, &output, columns)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(employee))
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "id,first_name,last_name,email,hire_date\n1,\"'=HYPERLINK(\"\"http://evil\"\")\",'-2+3,'@sum,2023-01-15\n", output.String())
+
+	output.Reset()
+	writer, err = NewExportWriter(ExportFormatXLSX, &output, columns)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(employee))
+	require.NoError(t, writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	require.NoError(t, err)
+
+	var sheet []byte
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			sheet, err = io.ReadAll(reader)
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Contains(t, string(sheet), `<c r="A2"><v>1</v></c>`)
+	assert.Contains(t, string(sheet), `<c r="B2" t="inlineStr"><is><t xml:space="preserve">=HYPERLINK(&#34;http://evil&#34;)</t></is></c>`)
+	assert.Contains(t, string(sheet), `<t xml:space="preserve">-2+3</t>`)
+	assert.Contains(t, string(sheet), `<t xml:space="preserve">@sum</t>`)
+	assert.NotContains(t, string(sheet), `&#39;`)
+	assert.Contains(t, string(sheet), `<t xml:space="preserve">2023-01-15</t>`)
+
+	output.Reset()
+	writer, err = NewExportWriter(ExportFormatNDJSON, &output, []string{"first_name"})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(employee))
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "{\"first_name\":\"=HYPERLINK(\\\"http://evil\\\")\"}\n", output.String())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AB", columnName(27))
+}
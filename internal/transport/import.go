@@ -0,0 +1,321 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"employee/internal/apperror"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+
+	ImportModeAllOrNothing = "all_or_nothing"
+	ImportModeBestEffort   = "best_effort"
+
+	// MaxImportRows and MaxImportBytes bound a single import, bigger cohorts
+	// are split by the client.
+	MaxImportRows  = 5000
+	MaxImportBytes = 10 << 20
+
+	// ImportCustomFieldPrefix starts the CSV columns that hold a custom field,
+	// e.g. custom_fields.cost_center.
+	ImportCustomFieldPrefix = "custom_fields."
+)
+
+// importColumns are the CSV columns an import accepts, in CreateEmployeeReq
+// json names. The address and emergency_contacts cells hold their JSON value.
+var importColumns = map[string]bool{
+	"first_name":         true,
+	"last_name":          true,
+	"email":              true,
+	"hire_date":          true,
+	"department_id":      true,
+	"manager_id":         true,
+	"position_id":        true,
+	"status":             true,
+	"phone":              true,
+	"date_of_birth":      true,
+	"address":            true,
+	"emergency_contacts": true,
+	"national_id":        true,
+	"work_location":      true,
+}
+
+// importRecord is a JSON line of an import, accounts are not provisioned by imports.
+type importRecord struct {
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	HireDate     string `json:"hire_date"`
+	DepartmentID *int   `json:"department_id"`
+	ManagerID    *int   `json:"manager_id"`
+	PositionID   *int   `json:"position_id"`
+	Status       string `json:"status"`
+	EmployeeProfile
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// ImportRow is one record of an import file. Line is the line it was read
+// from, Errors lists every reason the row cannot be imported. CustomFieldCells
+// holds the custom field columns of a CSV row as text, the use case converts
+// them to the type of their field.
+type ImportRow struct {
+	Line             int
+	Employee         CreateEmployeeReq
+	CustomFieldCells map[string]string
+	Errors           []ImportError
+}
+
+func (r *ImportRow) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Reject records why the row cannot be imported.
+func (r *ImportRow) Reject(field string, rule string, message string) {
+	r.Errors = append(r.Errors, ImportError{Line: r.Line, Field: field, Rule: rule, Message: message})
+}
+
+// ParseImportType returns the media type of an import, other media types are
+// rejected with 415.
+func ParseImportType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != MIMECSV && mediaType != MIMENDJSON) {
+		return "", echo.NewHTTPError(echo.ErrUnsupportedMediaType.Code,
+			fmt.Sprintf("Content-Type must be %s or %s", MIMECSV, MIMENDJSON))
+	}
+
+	return mediaType, nil
+}
+
+// ParseImport reads the rows of a CSV file with a header line or of a JSON
+// Lines file and validates each with the CreateEmployeeReq rules. Malformed
+// or invalid rows are returned with their errors, only a malformed file as a
+// whole fails the import.
+func ParseImport(mediaType string, body io.Reader) ([]*ImportRow, error) {
+	var rows []*ImportRow
+	var err error
+
+	switch mediaType {
+	case MIMECSV:
+		rows, err = parseCSV(body)
+	case MIMENDJSON:
+		rows, err = parseNDJSON(body)
+	default:
+		return nil, fmt.Errorf("unsupported import type %q", mediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, apperror.Validation("", "import file has no rows")
+	}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		if !row.Valid() {
+			continue
+		}
+
+		if err := ValidateStruct(&row.Employee); err != nil {
+			appErr, ok := apperror.As(err)
+			if !ok {
+				return nil, err
+			}
+			for _, detail := range appErr.Details {
+				row.Reject(detail.Field, detail.Rule, fmt.Sprintf("Field '%s' %s", detail.Field, detail.Rule))
+			}
+			continue
+		}
+
+		email := strings.ToLower(row.Employee.Email)
+		if line, ok := seen[email]; ok {
+			row.Reject("email", "unique", fmt.Sprintf("email is already used on line %d", line))
+			continue
+		}
+		seen[email] = row.Line
+	}
+
+	return rows, nil
+}
+
+func parseCSV(body io.Reader) ([]*ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, apperror.Validation("", "import file has no rows")
+	}
+	if err != nil {
+		return nil, apperror.Validation("", fmt.Sprintf("invalid csv header: %s", err.Error()))
+	}
+
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if name, ok := strings.CutPrefix(column, ImportCustomFieldPrefix); !importColumns[column] && (!ok || name == "") {
+			invalid := apperror.Validation(column, fmt.Sprintf("unknown csv column '%s'", column))
+			invalid.Details = []apperror.FieldError{{Field: column, Rule: "column"}}
+			return nil, invalid
+		}
+		header[i] = column
+	}
+
+	var rows []*ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, err
+		}
+
+		// FieldPos is only valid for a record Read returned
+		row := &ImportRow{}
+		if parseErr != nil {
+			row.Line = parseErr.StartLine
+		} else {
+			row.Line, _ = reader.FieldPos(0)
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, apperror.Validation("", fmt.Sprintf("import is limited to %d rows", MaxImportRows))
+		}
+		rows = append(rows, row)
+
+		if parseErr != nil {
+			row.Reject("", "csv", parseErr.Err.Error())
+			continue
+		}
+		if len(record) != len(header) {
+			row.Reject("", "csv", fmt.Sprintf("expected %d columns, got %d", len(header), len(record)))
+			continue
+		}
+
+		values := map[string]string{}
+		for i, column := range header {
+			values[column] = strings.TrimSpace(record[i])
+		}
+		row.Employee = CreateEmployeeReq{
+			FirstName: values["first_name"],
+			LastName:  values["last_name"],
+			Email:     values["email"],
+			HireDate:  values["hire_date"],
+			Status:    values["status"],
+			EmployeeProfile: EmployeeProfile{
+				Phone:        values["phone"],
+				DateOfBirth:  values["date_of_birth"],
+				NationalID:   values["national_id"],
+				WorkLocation: values["work_location"],
+			},
+		}
+		row.Employee.DepartmentID = csvID(row, "department_id", values["department_id"])
+		row.Employee.ManagerID = csvID(row, "manager_id", values["manager_id"])
+		row.Employee.PositionID = csvID(row, "position_id", values["position_id"])
+		csvJSON(row, "address", values["address"], &row.Employee.Address)
+		csvJSON(row, "emergency_contacts", values["emergency_contacts"], &row.Employee.EmergencyContacts)
+
+		for column, value := range values {
+			if name, ok := strings.CutPrefix(column, ImportCustomFieldPrefix); ok {
+				if row.CustomFieldCells == nil {
+					row.CustomFieldCells = map[string]string{}
+				}
+				row.CustomFieldCells[name] = value
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// csvID reads an id cell, an empty cell is no id.
+func csvID(row *ImportRow, column string, value string) *int {
+	if value == "" {
+		return nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		row.Reject(column, "int", fmt.Sprintf("Field '%s' int", column))
+		return nil
+	}
+
+	return &id
+}
+
+// csvJSON decodes a cell holding a JSON value into target, an empty cell
+// leaves it unset.
+func csvJSON(row *ImportRow, column string, value string, target interface{}) {
+	if value == "" {
+		return
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		row.Reject(column, "json", err.Error())
+	}
+}
+
+func parseNDJSON(body io.Reader) ([]*ImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []*ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, apperror.Validation("", fmt.Sprintf("import is limited to %d rows", MaxImportRows))
+		}
+
+		row := &ImportRow{Line: line}
+		rows = append(rows, row)
+
+		record := importRecord{}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			row.Reject("", "json", err.Error())
+			continue
+		}
+
+		row.Employee = CreateEmployeeReq{
+			FirstName:       record.FirstName,
+			LastName:        record.LastName,
+			Email:           record.Email,
+			HireDate:        record.HireDate,
+			DepartmentID:    record.DepartmentID,
+			ManagerID:       record.ManagerID,
+			PositionID:      record.PositionID,
+			Status:          record.Status,
+			EmployeeProfile: record.EmployeeProfile,
+			CustomFields:    record.CustomFields,
+		}
+	}
+
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return nil, apperror.Validation("", "json lines must be at most 1MB long")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseImportType(t *testing.T) {
+	mediaType, err := ParseImportType("text/csv; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, MIMECSV, mediaType)
+
+	mediaType, err = ParseImportType(MIMENDJSON)
+	require.NoError(t, err)
+	assert.Equal(t, MIMENDJSON, mediaType)
+
+	_, err = ParseImportType("application/json")
+	assert.ErrorContains(t, err, "code=415")
+}
+
+func TestParseImport(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mediaType   string
+		body        string
+		checkReturn func(rows []*ImportRow, err error)
+	}{
+		{
+			name:      "csv rows are read by header name",
+			mediaType: MIMECSV,
+			body:      "email,first_name,hire_date\njohn@mail.com,john,2023-01-15\njane@mail.com, jane ,2023-02-01\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 2)
+				assert.True(t, rows[0].Valid())
+				assert.Equal(t, "john", rows[0].Employee.FirstName)
+				assert.Equal(t, "jane", rows[1].Employee.FirstName)
+				assert.Equal(t, 3, rows[1].Line)
+			},
+		},
+		{
+			name:      "csv rows failing validation are reported with their line",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date\njohn,john@mail.com,15-01-2023\n,jane@mail.com,2023-02-01\njohn,JOHN@mail.com,2023-01-15\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 3)
+				assert.Equal(t, []ImportError{{Line: 2, Field: "hire_date", Rule: "date", Message: "Field 'hire_date' date"}}, rows[0].Errors)
+				assert.Equal(t, "first_name", rows[1].Errors[0].Field)
+				assert.True(t, rows[2].Valid())
+			},
+		},
+		{
+			name:      "csv custom field columns are kept as text",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date,custom_fields.remote,custom_fields.cost_center\njohn,john@mail.com,2023-01-15,true,\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 1)
+				assert.True(t, rows[0].Valid())
+				assert.Equal(t, map[string]string{"remote": "true", "cost_center": ""}, rows[0].CustomFieldCells)
+			},
+		},
+		{
+			name:      "csv custom field column without a name is rejected",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date,custom_fields.\njohn,john@mail.com,2023-01-15,x\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+		{
+			name:      "json lines carry their custom fields",
+			mediaType: MIMENDJSON,
+			body:      `{"first_name":"john","email":"john@mail.com","hire_date":"2023-01-15","custom_fields":{"remote":true}}` + "\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 1)
+				assert.Equal(t, map[string]interface{}{"remote": true}, rows[0].Employee.CustomFields)
+			},
+		},
+		{
+			name:      "csv rows carry the references and the profile",
+			mediaType: MIMECSV,
+			body: "first_name,email,hire_date,department_id,manager_id,position_id,status,phone,date_of_birth,address,emergency_contacts,national_id,work_location\n" +
+				`john,john@mail.com,2023-01-15,1,2,3,probation,+14155550123,1990-04-01,"{""line1"":""1 Main St"",""city"":""Springfield"",""country"":""US""}","[{""name"":""jane"",""relationship"":""spouse"",""phone"":""+14155550124""}]",AB123,Remote` + "\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 1)
+				require.True(t, rows[0].Valid(), rows[0].Errors)
+				employee := rows[0].Employee
+				assert.Equal(t, 1, *employee.DepartmentID)
+				assert.Equal(t, 2, *employee.ManagerID)
+				assert.Equal(t, 3, *employee.PositionID)
+				assert.Equal(t, "probation", employee.Status)
+				assert.Equal(t, "+14155550123", employee.Phone)
+				assert.Equal(t, "1990-04-01", employee.DateOfBirth)
+				assert.Equal(t, &Address{Line1: "1 Main St", City: "Springfield", Country: "US"}, employee.Address)
+				assert.Equal(t, []EmergencyContact{{Name: "jane", Relationship: "spouse", Phone: "+14155550124"}}, employee.EmergencyContacts)
+				assert.Equal(t, "AB123", employee.NationalID)
+				assert.Equal(t, "Remote", employee.WorkLocation)
+			},
+		},
+		{
+			name:      "csv rows with malformed ids or json cells are rejected",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date,manager_id,address\njohn,john@mail.com,2023-01-15,x,\njane,jane@mail.com,2023-01-15,,{\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 2)
+				assert.Equal(t, []ImportError{{Line: 2, Field: "manager_id", Rule: "int", Message: "Field 'manager_id' int"}}, rows[0].Errors)
+				assert.Equal(t, "address", rows[1].Errors[0].Field)
+				assert.Equal(t, "json", rows[1].Errors[0].Rule)
+			},
+		},
+		{
+			name:      "profile fields are validated like create",
+			mediaType: MIMENDJSON,
+			body: `{"first_name":"john","email":"john@mail.com","hire_date":"2023-01-15","phone":"555-0123"}
+{"first_name":"jane","email":"jane@mail.com","hire_date":"2023-01-15","status":"terminated"}
+{"first_name":"joe","email":"joe@mail.com","hire_date":"2023-01-15","address":{"line1":"1 Main St"}}
+{"first_name":"ann","email":"ann@mail.com","hire_date":"2023-01-15","manager_id":0,"position_id":4,"work_location":"Remote"}`,
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 4)
+				assert.Equal(t, "phone", rows[0].Errors[0].Field)
+				assert.Equal(t, "status", rows[1].Errors[0].Field)
+				assert.Equal(t, "oneof", rows[1].Errors[0].Rule)
+				assert.Equal(t, "city", rows[2].Errors[0].Field)
+				assert.Equal(t, "manager_id", rows[3].Errors[0].Field)
+			},
+		},
+		{
+			name:      "csv row with a wrong column count is rejected",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date\njohn,john@mail.com\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 1)
+				assert.Equal(t, "csv", rows[0].Errors[0].Rule)
+			},
+		},
+		{
+			name:      "csv row with a malformed quote is rejected with its line",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date\na\"b,c,2023-01-15\njohn,john@mail.com,2023-01-15\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 2)
+				assert.Equal(t, 2, rows[0].Line)
+				assert.Equal(t, "csv", rows[0].Errors[0].Rule)
+				assert.Equal(t, 3, rows[1].Line)
+				assert.True(t, rows[1].Valid())
+			},
+		},
+		{
+			name:      "unknown csv column fails the file",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date,salary\njohn,john@mail.com,2023-01-15,10\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				assert.Nil(t, rows)
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+				assert.ErrorContains(t, err, "salary")
+			},
+		},
+		{
+			name:      "duplicate emails in the file are rejected",
+			mediaType: MIMENDJSON,
+			body: `{"first_name":"john","email":"john@mail.com","hire_date":"2023-01-15"}
+{"first_name":"john","email":"John@Mail.com","hire_date":"2023-01-15"}`,
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 2)
+				assert.True(t, rows[0].Valid())
+				assert.Equal(t, "unique", rows[1].Errors[0].Rule)
+				assert.Contains(t, rows[1].Errors[0].Message, "line 1")
+			},
+		},
+		{
+			name:      "malformed and unknown json lines are rejected, blank lines skipped",
+			mediaType: MIMENDJSON,
+			body: `{"first_name":"john","email":"john@mail.com","hire_date":"2023-01-15"}
+
+{"first_name":
+{"first_name":"jane","email":"jane@mail.com","hire_date":"2023-01-15","create_account":true}`,
+			checkReturn: func(rows []*ImportRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, rows, 3)
+				assert.True(t, rows[0].Valid())
+				assert.Equal(t, 3, rows[1].Line)
+				assert.Equal(t, "json", rows[1].Errors[0].Rule)
+				assert.Contains(t, rows[2].Errors[0].Message, "create_account")
+			},
+		},
+		{
+			name:      "empty file fails",
+			mediaType: MIMENDJSON,
+			body:      "\n",
+			checkReturn: func(rows []*ImportRow, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+		{
+			name:      "files over the row limit fail",
+			mediaType: MIMECSV,
+			body:      "first_name,email,hire_date\n" + strings.Repeat("john,john@mail.com,2023-01-15\n", MaxImportRows+1),
+			checkReturn: func(rows []*ImportRow, err error) {
+				assert.ErrorContains(t, err, "limited")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := ParseImport(tc.mediaType, strings.NewReader(tc.body))
+
+			tc.checkReturn(rows, err)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"reflect"
+	"strconv"
+)
+
+// BindPath fills the fields tagged `param` from the route path parameters and
+// validates them, so a malformed id is rejected before it reaches the use case.
+// Integer and string fields are supported, string ids are checked with the
+// usual rules, e.g. `validate:"uuid"`.
+func BindPath(c echo.Context, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindPath expects a pointer to a struct, got %T", dst)
+	}
+
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Tag.Get("param")
+		if name == "" {
+			continue
+		}
+
+		raw := c.Param(name)
+		field := value.Field(i)
+
+		switch field.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			number, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+			if err != nil {
+				invalid := apperror.Validation(name, fmt.Sprintf("Field '%s' integer", name))
+				invalid.Details = []apperror.FieldError{{Field: name, Rule: "integer"}}
+				return invalid
+			}
+			field.SetInt(number)
+		case reflect.String:
+			field.SetString(raw)
+		default:
+			return fmt.Errorf("unsupported path param type %s for %s", field.Kind(), name)
+		}
+	}
+
+	return ValidateStruct(dst)
+}
@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/labstack/echo/v4"
+	"mime"
+	"reflect"
+)
+
+const (
+	MIMEMergePatch = "application/merge-patch+json"
+	MIMEJSONPatch  = "application/json-patch+json"
+)
+
+// employeePatchDoc is the document patches are applied to, only these fields can be patched.
+type employeePatchDoc struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	HireDate  string `json:"hire_date"`
+	EmployeeProfile
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// profileFields are the fields of EmployeeProfile in the patch document.
+var profileFields = []string{"phone", "date_of_birth", "address", "emergency_contacts", "national_id", "work_location"}
+
+// ParsePatchType returns the patch media type of a Content-Type header, other
+// media types are rejected with 415.
+func ParsePatchType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != MIMEMergePatch && mediaType != MIMEJSONPatch) {
+		return "", echo.NewHTTPError(echo.ErrUnsupportedMediaType.Code,
+			fmt.Sprintf("Content-Type must be %s or %s", MIMEMergePatch, MIMEJSONPatch))
+	}
+
+	return mediaType, nil
+}
+
+// ApplyEmployeePatch applies a JSON Merge Patch (RFC 7386) or a JSON Patch
+// (RFC 6902) to the patchable fields of the employee and returns the fields
+// whose value changed. A removed or null field is returned as an empty string
+// so the validation of required fields rejects it. Changed custom fields are
+// returned as the whole resulting set, to be checked against the definitions,
+// and a changed profile field returns the whole resulting profile, validated
+// like the profile of a create or an update.
+func ApplyEmployeePatch(current *EmployeeRes, patchType string, patch []byte) (*EmployeeChanges, error) {
+	customFields := current.CustomFields
+	if customFields == nil {
+		customFields = map[string]interface{}{}
+	}
+
+	original, err := json.Marshal(employeePatchDoc{
+		FirstName: current.FirstName,
+		LastName:  current.LastName,
+		Email:     current.Email,
+		HireDate:  current.HireDate,
+		EmployeeProfile: EmployeeProfile{
+			Phone:             valueOf(current.Phone),
+			DateOfBirth:       valueOf(current.DateOfBirth),
+			Address:           current.Address,
+			EmergencyContacts: current.EmergencyContacts,
+			NationalID:        valueOf(current.NationalID),
+			WorkLocation:      valueOf(current.WorkLocation),
+		},
+		CustomFields: customFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patchType {
+	case MIMEMergePatch:
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, apperror.Validation("", fmt.Sprintf("invalid merge patch: %s", err.Error()))
+		}
+	case MIMEJSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, apperror.Validation("", fmt.Sprintf("invalid json patch: %s", err.Error()))
+		}
+
+		patched, err = operations.Apply(original)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, apperror.Conflict("", "json patch test operation failed")
+		}
+		if err != nil {
+			return nil, apperror.Validation("", fmt.Sprintf("json patch cannot be applied: %s", err.Error()))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return nil, apperror.Validation("", "patched document must be a JSON object")
+	}
+
+	payload := &EmployeeChanges{}
+	fields := map[string]**string{
+		"first_name": &payload.FirstName,
+		"last_name":  &payload.LastName,
+		"email":      &payload.Email,
+		"hire_date":  &payload.HireDate,
+	}
+
+	patchable := map[string]bool{"custom_fields": true}
+	for name := range fields {
+		patchable[name] = true
+	}
+	for _, name := range profileFields {
+		patchable[name] = true
+	}
+
+	for name := range after {
+		if !patchable[name] {
+			invalid := apperror.Validation(name, fmt.Sprintf("Field '%s' is not patchable", name))
+			invalid.Details = []apperror.FieldError{{Field: name, Rule: "patchable"}}
+			return nil, invalid
+		}
+	}
+
+	for name, target := range fields {
+		value, found := after[name]
+		if found && reflect.DeepEqual(value, before[name]) {
+			continue
+		}
+
+		var text string
+		switch value := value.(type) {
+		case nil:
+		case string:
+			text = value
+		default:
+			invalid := apperror.Validation(name, fmt.Sprintf("Field '%s' string", name))
+			invalid.Details = []apperror.FieldError{{Field: name, Rule: "string"}}
+			return nil, invalid
+		}
+
+		*target = &text
+	}
+
+	if value := after["custom_fields"]; !reflect.DeepEqual(value, before["custom_fields"]) {
+		switch value := value.(type) {
+		case nil:
+			payload.CustomFields = map[string]interface{}{}
+		case map[string]interface{}:
+			payload.CustomFields = value
+		default:
+			invalid := apperror.Validation("custom_fields", "Field 'custom_fields' object")
+			invalid.Details = []apperror.FieldError{{Field: "custom_fields", Rule: "object"}}
+			return nil, invalid
+		}
+	}
+
+	for _, name := range profileFields {
+		if reflect.DeepEqual(after[name], before[name]) {
+			continue
+		}
+
+		profile := &EmployeeProfile{}
+		if err := json.Unmarshal(patched, profile); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, err
+			}
+			invalid := apperror.Validation(typeErr.Field, fmt.Sprintf("Field '%s' %s", typeErr.Field, typeErr.Type.Kind()))
+			invalid.Details = []apperror.FieldError{{Field: typeErr.Field, Rule: typeErr.Type.Kind().String()}}
+			return nil, invalid
+		}
+		payload.Profile = profile
+		break
+	}
+
+	return payload, nil
+}
+
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,364 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePatchType(t *testing.T) {
+	patchType, err := ParsePatchType("application/merge-patch+json; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, MIMEMergePatch, patchType)
+
+	patchType, err = ParsePatchType(MIMEJSONPatch)
+	require.NoError(t, err)
+	assert.Equal(t, MIMEJSONPatch, patchType)
+
+	_, err = ParsePatchType("application/json")
+	assert.ErrorContains(t, err, "code=415")
+}
+
+func TestApplyEmployeePatch(t *testing.T) {
+	current := &EmployeeRes{
+		ID:        1,
+		FirstName: "john",
+		LastName:  "mayer",
+		Email:     "john@mail.com",
+		HireDate:  "2023-01-15",
+		CustomFields: map[string]interface{}{
+			"cost_center": "CC-12",
+			"level":       "senior",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		patchType   string
+		patch       string
+		checkReturn func(payload *EmployeeChanges, err error)
+	}{
+		{
+			name:      "merge patch changes only the supplied field",
+			patchType: MIMEMergePatch,
+			patch:     `{"email":"new@mail.com","first_name":"john"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.Email)
+				assert.Equal(t, "new@mail.com", *payload.Email)
+				assert.Nil(t, payload.FirstName)
+				assert.Nil(t, payload.LastName)
+				assert.Nil(t, payload.HireDate)
+			},
+		},
+		{
+			name:      "merge patch null clears the field",
+			patchType: MIMEMergePatch,
+			patch:     `{"last_name":null}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.LastName)
+				assert.Equal(t, "", *payload.LastName)
+			},
+		},
+		{
+			name:      "merge patch rejects fields that are not patchable",
+			patchType: MIMEMergePatch,
+			patch:     `{"id":5}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, http.StatusBadRequest, appErr.Status())
+				assert.Equal(t, "id", appErr.Field)
+			},
+		},
+		{
+			name:      "merge patch rejects values that are not strings",
+			patchType: MIMEMergePatch,
+			patch:     `{"hire_date":20230115}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+		{
+			name:      "json patch replaces a field",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"test","path":"/email","value":"john@mail.com"},{"op":"replace","path":"/hire_date","value":"2023-02-01"}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.HireDate)
+				assert.Equal(t, "2023-02-01", *payload.HireDate)
+				assert.Nil(t, payload.Email)
+			},
+		},
+		{
+			name:      "merge patch returns the whole set of custom fields",
+			patchType: MIMEMergePatch,
+			patch:     `{"custom_fields":{"remote":true,"cost_center":null}}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"level": "senior", "remote": true}, payload.CustomFields)
+				assert.Nil(t, payload.Email)
+			},
+		},
+		{
+			name:      "json patch adds a custom field",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"add","path":"/custom_fields/remote","value":false}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "level": "senior", "remote": false}, payload.CustomFields)
+			},
+		},
+		{
+			name:      "unchanged custom fields are not returned",
+			patchType: MIMEMergePatch,
+			patch:     `{"custom_fields":{"level":"senior"}}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Nil(t, payload.CustomFields)
+			},
+		},
+		{
+			name:      "merge patch rejects custom fields that are not an object",
+			patchType: MIMEMergePatch,
+			patch:     `{"custom_fields":"remote"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, "custom_fields", appErr.Field)
+			},
+		},
+		{
+			name:      "json patch failed test is a conflict",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"test","path":"/email","value":"other@mail.com"},{"op":"replace","path":"/email","value":"new@mail.com"}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			},
+		},
+		{
+			name:      "json patch remove clears a required field",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"remove","path":"/first_name"}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.FirstName)
+				assert.True(t, apperror.IsCode(ValidateStruct(payload), apperror.CodeValidation))
+			},
+		},
+		{
+			name:      "invalid json patch",
+			patchType: MIMEJSONPatch,
+			patch:     `{"op":"replace"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := ApplyEmployeePatch(current, tc.patchType, []byte(tc.patch))
+
+			tc.checkReturn(payload, err)
+		})
+	}
+}
+
+func TestApplyEmployeePatchProfile(t *testing.T) {
+	phone := "+14155550123"
+	nationalID := "AB123456"
+	current := &EmployeeRes{
+		ID:        1,
+		FirstName: "john",
+		Email:     "john@mail.com",
+		HireDate:  "2023-01-15",
+		Phone:     &phone,
+		Address:   &Address{Line1: "1 Main St", City: "Berlin", Country: "DE"},
+		EmergencyContacts: []EmergencyContact{
+			{Name: "jane", Relationship: "spouse", Phone: "+14155550124"},
+		},
+		NationalID: &nationalID,
+	}
+
+	testCases := []struct {
+		name        string
+		patchType   string
+		patch       string
+		checkReturn func(payload *EmployeeChanges, err error)
+	}{
+		{
+			name:      "phone keeps the rest of the profile",
+			patchType: MIMEMergePatch,
+			patch:     `{"phone":"+4930123456"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.Profile)
+				assert.Equal(t, "+4930123456", payload.Profile.Phone)
+				assert.Equal(t, "Berlin", payload.Profile.Address.City)
+				assert.Len(t, payload.Profile.EmergencyContacts, 1)
+				assert.Equal(t, nationalID, payload.Profile.NationalID)
+				assert.Nil(t, payload.Email)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "invalid phone",
+			patchType: MIMEMergePatch,
+			patch:     `{"phone":"12 34"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "phone")
+			},
+		},
+		{
+			name:      "date of birth",
+			patchType: MIMEMergePatch,
+			patch:     `{"date_of_birth":"1990-04-12"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "1990-04-12", payload.Profile.DateOfBirth)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "date of birth under the minimum age",
+			patchType: MIMEMergePatch,
+			patch:     `{"date_of_birth":"` + time.Now().AddDate(-10, 0, 0).Format("2006-01-02") + `"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "date_of_birth")
+			},
+		},
+		{
+			name:      "json patch replaces the city of the address",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"replace","path":"/address/city","value":"Munich"}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &Address{Line1: "1 Main St", City: "Munich", Country: "DE"}, payload.Profile.Address)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "address with an invalid country",
+			patchType: MIMEMergePatch,
+			patch:     `{"address":{"country":"Germany"}}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "country")
+			},
+		},
+		{
+			name:      "address null clears it",
+			patchType: MIMEMergePatch,
+			patch:     `{"address":null}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload.Profile)
+				assert.Nil(t, payload.Profile.Address)
+				assert.Equal(t, phone, payload.Profile.Phone)
+			},
+		},
+		{
+			name:      "json patch adds an emergency contact",
+			patchType: MIMEJSONPatch,
+			patch:     `[{"op":"add","path":"/emergency_contacts/-","value":{"name":"joe","relationship":"brother","phone":"+14155550125"}}]`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				require.Len(t, payload.Profile.EmergencyContacts, 2)
+				assert.Equal(t, "joe", payload.Profile.EmergencyContacts[1].Name)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "emergency contact without a phone",
+			patchType: MIMEMergePatch,
+			patch:     `{"emergency_contacts":[{"name":"joe","relationship":"brother"}]}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "phone")
+			},
+		},
+		{
+			name:      "national id null clears it",
+			patchType: MIMEMergePatch,
+			patch:     `{"national_id":null}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "", payload.Profile.NationalID)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "national id too long",
+			patchType: MIMEMergePatch,
+			patch:     `{"national_id":"` + strings.Repeat("A", 33) + `"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "national_id")
+			},
+		},
+		{
+			name:      "work location",
+			patchType: MIMEMergePatch,
+			patch:     `{"work_location":"Remote"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "Remote", payload.Profile.WorkLocation)
+				assert.NoError(t, ValidateStruct(payload))
+			},
+		},
+		{
+			name:      "work location too long",
+			patchType: MIMEMergePatch,
+			patch:     `{"work_location":"` + strings.Repeat("a", 101) + `"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.ErrorContains(t, ValidateStruct(payload), "work_location")
+			},
+		},
+		{
+			name:      "profile field of the wrong type",
+			patchType: MIMEMergePatch,
+			patch:     `{"emergency_contacts":"none"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, "emergency_contacts", appErr.Field)
+			},
+		},
+		{
+			name:      "unchanged profile is not returned",
+			patchType: MIMEMergePatch,
+			patch:     `{"phone":"+14155550123","first_name":"johnny"}`,
+			checkReturn: func(payload *EmployeeChanges, err error) {
+				require.NoError(t, err)
+				assert.Nil(t, payload.Profile)
+				require.NotNil(t, payload.FirstName)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := ApplyEmployeePatch(current, tc.patchType, []byte(tc.patch))
+
+			tc.checkReturn(payload, err)
+		})
+	}
+}
+
+func TestValidateEmployeeChanges(t *testing.T) {
+	invalidDate := "15-01-2023"
+	assert.Error(t, ValidateStruct(&EmployeeChanges{HireDate: &invalidDate}))
+
+	lastName := ""
+	assert.NoError(t, ValidateStruct(&EmployeeChanges{LastName: &lastName}))
+	assert.NoError(t, ValidateStruct(&EmployeeChanges{}))
+}
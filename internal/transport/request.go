@@ -1,15 +1,337 @@
 package transport
 
+// MaxJSONBytes bounds the JSON body of a request.
+const MaxJSONBytes = 1 << 20
+
 type CreateEmployeeReq struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name"`
-	Email     string `json:"email" validate:"required"`
-	HireDate  string `json:"hire_date" validate:"required,date"`
+	Email     string `json:"email" validate:"required,email"`
+	HireDate  string `json:"hire_date" validate:"required,date,notfuture"`
+	// DepartmentID assigns the employee to a department, it must exist.
+	DepartmentID *int `json:"department_id" validate:"omitempty,gt=0"`
+	// ManagerID must be an employee that is not deleted.
+	ManagerID *int `json:"manager_id" validate:"omitempty,gt=0"`
+	// PositionID starts the position history at the hire date, it must exist.
+	PositionID *int `json:"position_id" validate:"omitempty,gt=0"`
+	// Status starts the employment as active when it is omitted.
+	Status string `json:"status" validate:"omitempty,oneof=probation active"`
+
+	EmployeeProfile
+	// CustomFields holds values by custom field name, they are checked against
+	// the definitions by the use case.
+	CustomFields map[string]interface{} `json:"custom_fields"`
+
+	// CreateAccount provisions a login for the new employee with a generated password.
+	CreateAccount bool   `json:"create_account"`
+	AccountRole   string `json:"account_role" validate:"omitempty,oneof=hr manager viewer"`
+}
+
+// EmployeeProfile holds the optional profile of an employee, an update
+// replaces it as a whole so an omitted field is cleared.
+type EmployeeProfile struct {
+	// Phone is in E.164 format, e.g. +14155550123.
+	Phone string `json:"phone" validate:"omitempty,phone"`
+	// DateOfBirth puts the employee between 16 and 100 years old.
+	DateOfBirth       string             `json:"date_of_birth" validate:"omitempty,date,min_age=16,max_age=100"`
+	Address           *Address           `json:"address"`
+	EmergencyContacts []EmergencyContact `json:"emergency_contacts" validate:"omitempty,max=5,dive"`
+	NationalID        string             `json:"national_id" validate:"omitempty,max=32,printascii"`
+	WorkLocation      string             `json:"work_location" validate:"omitempty,max=100"`
+}
+
+type Address struct {
+	Line1      string `json:"line1" validate:"required,max=200"`
+	Line2      string `json:"line2,omitempty" validate:"max=200"`
+	City       string `json:"city" validate:"required,max=100"`
+	State      string `json:"state,omitempty" validate:"max=100"`
+	PostalCode string `json:"postal_code,omitempty" validate:"max=20"`
+	// Country is an ISO 3166-1 alpha-2 code, e.g. US.
+	Country string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+type EmergencyContact struct {
+	Name         string `json:"name" validate:"required,max=200"`
+	Relationship string `json:"relationship" validate:"required,max=50"`
+	Phone        string `json:"phone" validate:"required,phone"`
+}
+
+// ImportEmployeesReq holds the options of an import, the rows are parsed from
+// the body by ParseImport.
+type ImportEmployeesReq struct {
+	Mode   string       `query:"mode" validate:"omitempty,oneof=all_or_nothing best_effort"`
+	DryRun bool         `query:"dry_run"`
+	Rows   []*ImportRow `query:"-"`
 }
+
 type UpdateEmployeeReq struct {
-	ID        int    `json:"id"`
+	ID        int    `json:"-"`
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" `
-	Email     string `json:"email" validate:"required"`
-	HireDate  string `json:"hire_date" validate:"required,date"`
+	Email     string `json:"email" validate:"required,email"`
+	HireDate  string `json:"hire_date" validate:"required,date,notfuture"`
+	// DepartmentID replaces the department, null removes the employee from it.
+	DepartmentID *int `json:"department_id" validate:"omitempty,gt=0"`
+	// ManagerID replaces the manager, null makes the employee a root of the org chart.
+	ManagerID *int `json:"manager_id" validate:"omitempty,gt=0"`
+	EmployeeProfile
+	// CustomFields replaces the custom fields, omitted ones are cleared.
+	CustomFields map[string]interface{} `json:"custom_fields"`
+	Version      int                    `json:"-"`
+}
+
+// PatchEmployeeReq is a patch document as received, the use case applies it to
+// the employee it locked so the patch and the version check see the same row.
+type PatchEmployeeReq struct {
+	ID      int
+	Type    string
+	Patch   []byte
+	Version int
+}
+
+// EmployeeChanges holds only the fields a patch changed, nil fields are left untouched.
+type EmployeeChanges struct {
+	FirstName *string `json:"first_name" validate:"omitempty,min=1"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	HireDate  *string `json:"hire_date" validate:"omitempty,date,notfuture"`
+	// Profile is the whole profile when the patch changed any of its fields.
+	Profile *EmployeeProfile `json:"profile"`
+	// CustomFields is the whole set of custom fields when the patch changed any of them.
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+type ListEmployeesReq struct {
+	Limit        int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset       int    `query:"offset" validate:"omitempty,min=0"`
+	Cursor       string `query:"cursor"`
+	FirstName    string `query:"first_name"`
+	LastName     string `query:"last_name"`
+	Email        string `query:"email"`
+	HireDateFrom string `query:"hire_date_from" validate:"omitempty,date"`
+	HireDateTo   string `query:"hire_date_to" validate:"omitempty,date"`
+	ManagerID    int    `query:"manager_id" validate:"omitempty,min=1"`
+	DepartmentID int    `query:"department_id" validate:"omitempty,min=1"`
+	Status       string `query:"status" validate:"omitempty,oneof=probation active on_leave terminated rehired"`
+	// IncludeDeleted is restricted to admins by the handler.
+	IncludeDeleted bool   `query:"include_deleted"`
+	Sort           string `query:"sort" validate:"omitempty,oneof=id -id first_name -first_name last_name -last_name email -email hire_date -hire_date"`
+	// CustomFields holds the cf.<name> filters read by CustomFieldFilters.
+	CustomFields map[string]string `query:"-"`
+}
+
+// ExportEmployeesReq takes the filters and sort of ListEmployeesReq, an export
+// is never paginated.
+type ExportEmployeesReq struct {
+	Format       string `query:"format" validate:"required,oneof=csv ndjson xlsx"`
+	Columns      string `query:"columns"`
+	FirstName    string `query:"first_name"`
+	LastName     string `query:"last_name"`
+	Email        string `query:"email"`
+	HireDateFrom string `query:"hire_date_from" validate:"omitempty,date"`
+	HireDateTo   string `query:"hire_date_to" validate:"omitempty,date"`
+	ManagerID    int    `query:"manager_id" validate:"omitempty,min=1"`
+	DepartmentID int    `query:"department_id" validate:"omitempty,min=1"`
+	Status       string `query:"status" validate:"omitempty,oneof=probation active on_leave terminated rehired"`
+	// IncludeDeleted is restricted to admins by the handler.
+	IncludeDeleted bool   `query:"include_deleted"`
+	Sort           string `query:"sort" validate:"omitempty,oneof=id -id first_name -first_name last_name -last_name email -email hire_date -hire_date"`
+	// CustomFields holds the cf.<name> filters read by CustomFieldFilters.
+	CustomFields map[string]string `query:"-"`
+}
+
+type LoginReq struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ListAuditReq struct {
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset  int    `query:"offset" validate:"omitempty,min=0"`
+	ActorID string `query:"actor_id"`
+	Action  string `query:"action" validate:"omitempty,oneof=create update delete restore purge terminate rehire approve_leave reject_leave"`
+	From    string `query:"from" validate:"omitempty,date"`
+	To      string `query:"to" validate:"omitempty,date"`
+}
+
+// ListReportsReq walks down Depth levels of the management tree, 1 lists the direct reports.
+type ListReportsReq struct {
+	Depth int `query:"depth" validate:"omitempty,min=1,max=10"`
+}
+
+type EmployeeIDParam struct {
+	EmployeeID int `param:"employee_id" validate:"gt=0"`
+}
+
+type CreateDepartmentReq struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type UpdateDepartmentReq struct {
+	ID          int    `json:"-"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type ListDepartmentsReq struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+	Name   string `query:"name"`
+}
+
+type DepartmentIDParam struct {
+	DepartmentID int `param:"department_id" validate:"gt=0"`
+}
+
+type CreatePositionReq struct {
+	Title     string `json:"title" validate:"required,max=100"`
+	Grade     int    `json:"grade" validate:"required,min=1,max=99"`
+	JobFamily string `json:"job_family" validate:"required,max=100"`
+}
+
+type UpdatePositionReq struct {
+	ID        int    `json:"-"`
+	Title     string `json:"title" validate:"required,max=100"`
+	Grade     int    `json:"grade" validate:"required,min=1,max=99"`
+	JobFamily string `json:"job_family" validate:"required,max=100"`
+}
+
+type ListPositionsReq struct {
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `query:"offset" validate:"omitempty,min=0"`
+	Title     string `query:"title"`
+	JobFamily string `query:"job_family"`
+}
+
+type PositionIDParam struct {
+	PositionID int `param:"position_id" validate:"gt=0"`
+}
+
+// CreateCustomFieldReq defines a custom field, Options lists the values an
+// enum field allows and is rejected for the other types.
+type CreateCustomFieldReq struct {
+	Name     string   `json:"name" validate:"required,identifier"`
+	Type     string   `json:"type" validate:"required,oneof=text number boolean date enum"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" validate:"omitempty,max=50,unique,dive,required,max=100"`
+}
+
+// UpdateCustomFieldReq cannot change the name nor the type, the values the
+// employees hold depend on them.
+type UpdateCustomFieldReq struct {
+	ID       int      `json:"-"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" validate:"omitempty,max=50,unique,dive,required,max=100"`
+}
+
+type CustomFieldIDParam struct {
+	CustomFieldID int `param:"custom_field_id" validate:"gt=0"`
+}
+
+// AssignPositionReq moves an employee to a position from EffectiveDate on.
+// DepartmentID moves the employee to another department with it, the
+// department is kept when it is omitted.
+type AssignPositionReq struct {
+	EmployeeID    int    `json:"-"`
+	PositionID    int    `json:"position_id" validate:"required,gt=0"`
+	DepartmentID  *int   `json:"department_id" validate:"omitempty,gt=0"`
+	EffectiveDate string `json:"effective_date" validate:"required,date"`
+}
+
+// CreateCompensationReq adds a compensation from EffectiveFrom on. Amount is in
+// minor units of Currency, an omitted EffectiveTo keeps the compensation current.
+type CreateCompensationReq struct {
+	EmployeeID    int     `json:"-"`
+	Amount        int64   `json:"amount" validate:"required,gt=0"`
+	Currency      string  `json:"currency" validate:"required,iso4217"`
+	PayFrequency  string  `json:"pay_frequency" validate:"required,oneof=hourly weekly biweekly monthly annual"`
+	EffectiveFrom string  `json:"effective_from" validate:"required,date"`
+	EffectiveTo   *string `json:"effective_to" validate:"omitempty,date"`
+}
+
+// ChangeStatusReq moves an employee between the statuses of an ongoing
+// employment, terminate and rehire have their own requests.
+type ChangeStatusReq struct {
+	EmployeeID int    `json:"-"`
+	Status     string `json:"status" validate:"required,oneof=probation active on_leave"`
+}
+
+// TerminateEmployeeReq ends the employment on TerminationDate, which cannot be
+// before the hire date.
+type TerminateEmployeeReq struct {
+	EmployeeID      int    `json:"-"`
+	TerminationDate string `json:"termination_date" validate:"required,date"`
+	Reason          string `json:"reason" validate:"required,max=500"`
+}
+
+// RehireEmployeeReq starts a new employment of a terminated employee on
+// RehireDate, which becomes the hire date.
+type RehireEmployeeReq struct {
+	EmployeeID int    `json:"-"`
+	RehireDate string `json:"rehire_date" validate:"required,date"`
+}
+
+// CreateLeaveTypeReq adds a leave type credited AccrualDays every AccrualPeriod
+// since the hire date, an omitted MaxBalance lets the balance grow unbounded.
+type CreateLeaveTypeReq struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	AccrualDays   float64  `json:"accrual_days" validate:"min=0,max=366"`
+	AccrualPeriod string   `json:"accrual_period" validate:"required,oneof=monthly yearly"`
+	MaxBalance    *float64 `json:"max_balance" validate:"omitempty,gt=0,max=9999"`
+}
+
+// CreateLeaveRequestReq asks for the working days from StartDate to the
+// inclusive EndDate.
+type CreateLeaveRequestReq struct {
+	EmployeeID  int    `json:"-"`
+	LeaveTypeID int    `json:"leave_type_id" validate:"required,gt=0"`
+	StartDate   string `json:"start_date" validate:"required,date"`
+	EndDate     string `json:"end_date" validate:"required,date"`
+	Reason      string `json:"reason" validate:"max=500"`
+}
+
+type ListLeaveRequestsReq struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+}
+
+type LeaveRequestIDParam struct {
+	LeaveRequestID int64 `param:"leave_request_id" validate:"gt=0"`
+}
+
+// DecideLeaveRequestReq approves or rejects a pending leave request, Note is
+// kept with the decision.
+type DecideLeaveRequestReq struct {
+	LeaveRequestID int64  `json:"-"`
+	Note           string `json:"note" validate:"max=500"`
+}
+
+// AttendanceRangeReq selects the work dates from From to the inclusive To.
+type AttendanceRangeReq struct {
+	From string `query:"from" validate:"required,date"`
+	To   string `query:"to" validate:"required,date"`
+}
+
+// AttendanceReportReq selects the work dates from From to the inclusive To,
+// DepartmentID narrows the report to one department.
+type AttendanceReportReq struct {
+	From         string `query:"from" validate:"required,date"`
+	To           string `query:"to" validate:"required,date"`
+	DepartmentID int    `query:"department_id" validate:"omitempty,gt=0"`
+}
+
+type DocumentIDParam struct {
+	EmployeeID int   `param:"employee_id" validate:"gt=0"`
+	DocumentID int64 `param:"document_id" validate:"gt=0"`
+}
+
+// DownloadDocumentReq carries the expiry and signature of a download link.
+type DownloadDocumentReq struct {
+	DocumentID int64  `param:"document_id" validate:"gt=0"`
+	Expires    int64  `query:"expires" validate:"required,gt=0"`
+	Signature  string `query:"signature" validate:"required,hexadecimal"`
 }
@@ -1,13 +1,338 @@
 package transport
 
+import (
+	"employee/internal/pkg"
+	"encoding/json"
+	"time"
+)
+
 type EmployeeRes struct {
-	ID        int    `json:"id" swaggo:"example=1"`
-	FirstName string `json:"first_name" swaggo:"minLength=1,example=John"`
-	LastName  string `json:"last_name" swaggo:"minLength=1,example=Mayer"`
-	Email     string `json:"email" swaggo:"format=email,example=johndoe@example.com"`
-	HireDate  string `json:"hire_date" swaggo:"format=date,example=2023-01-15"`
+	ID                int     `json:"id" swaggo:"example=1"`
+	FirstName         string  `json:"first_name" swaggo:"minLength=1,example=John"`
+	LastName          string  `json:"last_name" swaggo:"minLength=1,example=Mayer"`
+	Email             string  `json:"email" swaggo:"format=email,example=johndoe@example.com"`
+	HireDate          string  `json:"hire_date" swaggo:"format=date,example=2023-01-15"`
+	ManagerID         *int    `json:"manager_id" swaggo:"example=2"`
+	DepartmentID      *int    `json:"department_id" swaggo:"example=3"`
+	PositionID        *int    `json:"position_id" swaggo:"example=4"`
+	Status            string  `json:"status" swaggo:"example=active"`
+	TerminationDate   *string `json:"termination_date,omitempty" swaggo:"format=date,example=2024-03-01"`
+	TerminationReason *string `json:"termination_reason,omitempty"`
+	// The profile is only returned for a single employee, the personal fields
+	// only to admins, HR and the employee.
+	Phone             *string            `json:"phone,omitempty" swaggo:"example=+14155550123"`
+	DateOfBirth       *string            `json:"date_of_birth,omitempty" swaggo:"format=date,example=1990-04-12"`
+	Address           *Address           `json:"address,omitempty"`
+	EmergencyContacts []EmergencyContact `json:"emergency_contacts,omitempty"`
+	NationalID        *string            `json:"national_id,omitempty"`
+	WorkLocation      *string            `json:"work_location,omitempty" swaggo:"example=Berlin"`
+	// CustomFields holds the values by custom field name.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	DeletedAt    *time.Time             `json:"deleted_at,omitempty"`
+	Version      int                    `json:"version" swaggo:"example=1"`
+
+	Account *AccountRes `json:"account,omitempty"`
+}
+
+// AccountRes is only returned when the account is provisioned, the initial
+// password is never stored in clear text and cannot be retrieved later.
+type AccountRes struct {
+	UserID           int                   `json:"user_id" swaggo:"example=1"`
+	Email            string                `json:"email" swaggo:"format=email,example=johndoe@example.com"`
+	Role             string                `json:"role" swaggo:"example=viewer"`
+	InitialPassword  string                `json:"initial_password"`
+	PasswordStrength *pkg.PasswordStrength `json:"password_strength"`
+}
+
+type Pagination struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ListEmployees struct {
+	Employees  []*EmployeeRes `json:"employees"`
+	Pagination *Pagination    `json:"pagination"`
+}
+
+type DepartmentRes struct {
+	ID          int       `json:"id" swaggo:"example=1"`
+	Name        string    `json:"name" swaggo:"example=Engineering"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListDepartments struct {
+	Departments []*DepartmentRes `json:"departments"`
+	Pagination  *Pagination      `json:"pagination"`
+}
+
+type PositionRes struct {
+	ID        int       `json:"id" swaggo:"example=1"`
+	Title     string    `json:"title" swaggo:"example=Software Engineer II"`
+	Grade     int       `json:"grade" swaggo:"example=3"`
+	JobFamily string    `json:"job_family" swaggo:"example=Engineering"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListPositions struct {
+	Positions  []*PositionRes `json:"positions"`
+	Pagination *Pagination    `json:"pagination"`
+}
+
+type CustomFieldRes struct {
+	ID        int       `json:"id" swaggo:"example=1"`
+	Name      string    `json:"name" swaggo:"example=cost_center"`
+	Type      string    `json:"type" swaggo:"example=text"`
+	Required  bool      `json:"required"`
+	Options   []string  `json:"options"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListCustomFields struct {
+	CustomFields []*CustomFieldRes `json:"custom_fields"`
+}
+
+// PositionAssignmentRes is one entry of the position history, EndDate is null
+// on the current position.
+type PositionAssignmentRes struct {
+	ID            int64   `json:"id" swaggo:"example=1"`
+	PositionID    int     `json:"position_id" swaggo:"example=4"`
+	Title         string  `json:"title" swaggo:"example=Software Engineer II"`
+	Grade         int     `json:"grade" swaggo:"example=3"`
+	JobFamily     string  `json:"job_family" swaggo:"example=Engineering"`
+	DepartmentID  *int    `json:"department_id" swaggo:"example=3"`
+	Change        string  `json:"change" swaggo:"example=promotion"`
+	EffectiveDate string  `json:"effective_date" swaggo:"format=date,example=2024-01-01"`
+	EndDate       *string `json:"end_date" swaggo:"format=date"`
+}
+
+// PositionHistory lists the positions of an employee, the current one first.
+type PositionHistory struct {
+	Positions []*PositionAssignmentRes `json:"positions"`
+}
+
+// CompensationRes is a compensation of an employee, Amount is in minor units
+// of Currency and EffectiveTo is null while the compensation is current.
+type CompensationRes struct {
+	ID            int64     `json:"id" swaggo:"example=1"`
+	Amount        int64     `json:"amount" swaggo:"example=8500000"`
+	Currency      string    `json:"currency" swaggo:"example=USD"`
+	PayFrequency  string    `json:"pay_frequency" swaggo:"example=annual"`
+	EffectiveFrom string    `json:"effective_from" swaggo:"format=date,example=2024-01-01"`
+	EffectiveTo   *string   `json:"effective_to" swaggo:"format=date"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ListCompensations lists the compensations of an employee, the latest first.
+type ListCompensations struct {
+	Compensations []*CompensationRes `json:"compensations"`
+}
+
+// LeaveTypeRes credits AccrualDays every AccrualPeriod, MaxBalance caps the
+// accrued balance when it is set.
+type LeaveTypeRes struct {
+	ID            int       `json:"id" swaggo:"example=1"`
+	Name          string    `json:"name" swaggo:"example=Annual leave"`
+	AccrualDays   float64   `json:"accrual_days" swaggo:"example=1.67"`
+	AccrualPeriod string    `json:"accrual_period" swaggo:"example=monthly"`
+	MaxBalance    *float64  `json:"max_balance" swaggo:"example=30"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ListLeaveTypes struct {
+	LeaveTypes []*LeaveTypeRes `json:"leave_types"`
+}
+
+// LeaveBalanceRes is the balance of a leave type accrued up to AccruedThrough.
+type LeaveBalanceRes struct {
+	LeaveTypeID    int     `json:"leave_type_id" swaggo:"example=1"`
+	LeaveType      string  `json:"leave_type" swaggo:"example=Annual leave"`
+	Balance        float64 `json:"balance" swaggo:"example=12.5"`
+	AccruedThrough string  `json:"accrued_through" swaggo:"format=date,example=2024-06-03"`
+}
+
+type ListLeaveBalances struct {
+	Balances []*LeaveBalanceRes `json:"balances"`
+}
+
+// LeaveRequestRes is a leave request, the decision fields are null while it is pending.
+type LeaveRequestRes struct {
+	ID           int64      `json:"id" swaggo:"example=1"`
+	EmployeeID   int        `json:"employee_id" swaggo:"example=3"`
+	LeaveTypeID  int        `json:"leave_type_id" swaggo:"example=1"`
+	StartDate    string     `json:"start_date" swaggo:"format=date,example=2024-07-01"`
+	EndDate      string     `json:"end_date" swaggo:"format=date,example=2024-07-05"`
+	Days         float64    `json:"days" swaggo:"example=5"`
+	Reason       string     `json:"reason"`
+	Status       string     `json:"status" swaggo:"example=pending"`
+	DecidedBy    *string    `json:"decided_by"`
+	DecidedAt    *time.Time `json:"decided_at"`
+	DecisionNote *string    `json:"decision_note"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ListLeaveRequests lists the leave requests of an employee, the latest start first.
+type ListLeaveRequests struct {
+	LeaveRequests []*LeaveRequestRes `json:"leave_requests"`
+}
+
+// AttendanceRes is a work session, ClockOut is null while the employee is clocked in.
+type AttendanceRes struct {
+	ID          int64      `json:"id" swaggo:"example=1"`
+	EmployeeID  int        `json:"employee_id" swaggo:"example=3"`
+	WorkDate    string     `json:"work_date" swaggo:"format=date,example=2024-06-03"`
+	ClockIn     time.Time  `json:"clock_in"`
+	ClockOut    *time.Time `json:"clock_out"`
+	WorkedHours float64    `json:"worked_hours" swaggo:"example=8.5"`
+}
+
+// AttendanceDayRes sums the sessions of a work date. A scheduled work day
+// without any session or approved leave is absent.
+type AttendanceDayRes struct {
+	Date         string     `json:"date" swaggo:"format=date,example=2024-06-03"`
+	FirstClockIn *time.Time `json:"first_clock_in"`
+	LastClockOut *time.Time `json:"last_clock_out"`
+	WorkedHours  float64    `json:"worked_hours" swaggo:"example=8.5"`
+	ClockedIn    bool       `json:"clocked_in"`
+	Late         bool       `json:"late"`
+	Absent       bool       `json:"absent"`
+	OnLeave      bool       `json:"on_leave"`
+}
+
+type ListAttendanceDays struct {
+	EmployeeID int                 `json:"employee_id" swaggo:"example=3"`
+	Days       []*AttendanceDayRes `json:"days"`
+}
+
+// AttendanceWeekRes sums the sessions of the week starting on the Monday WeekStart.
+type AttendanceWeekRes struct {
+	WeekStart   string  `json:"week_start" swaggo:"format=date,example=2024-06-03"`
+	WorkedHours float64 `json:"worked_hours" swaggo:"example=40"`
+	DaysWorked  int     `json:"days_worked" swaggo:"example=5"`
+}
+
+type ListAttendanceWeeks struct {
+	EmployeeID int                  `json:"employee_id" swaggo:"example=3"`
+	Weeks      []*AttendanceWeekRes `json:"weeks"`
+}
+
+// AttendanceSummaryRes counts the work days of an employee within a report.
+type AttendanceSummaryRes struct {
+	EmployeeID   int     `json:"employee_id" swaggo:"example=3"`
+	FirstName    string  `json:"first_name" swaggo:"example=Jane"`
+	LastName     string  `json:"last_name" swaggo:"example=Doe"`
+	DepartmentID *int    `json:"department_id" swaggo:"example=2"`
+	WorkedHours  float64 `json:"worked_hours" swaggo:"example=38.25"`
+	DaysPresent  int     `json:"days_present" swaggo:"example=4"`
+	LateDays     int     `json:"late_days" swaggo:"example=1"`
+	AbsentDays   int     `json:"absent_days" swaggo:"example=1"`
+	LeaveDays    int     `json:"leave_days" swaggo:"example=0"`
+}
+
+// DocumentRes is a file attached to an employee, DownloadURL serves it
+// without a token until DownloadExpiresAt.
+type DocumentRes struct {
+	ID                int64     `json:"id" swaggo:"example=1"`
+	EmployeeID        int       `json:"employee_id" swaggo:"example=3"`
+	Category          string    `json:"category" swaggo:"example=contract"`
+	FileName          string    `json:"file_name" swaggo:"example=contract.pdf"`
+	ContentType       string    `json:"content_type" swaggo:"example=application/pdf"`
+	Size              int64     `json:"size" swaggo:"example=48213"`
+	UploadedBy        string    `json:"uploaded_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	DownloadURL       string    `json:"download_url" swaggo:"example=/documents/1/download?expires=1717405200&signature=9f86d081"`
+	DownloadExpiresAt time.Time `json:"download_expires_at"`
+}
+
+// ListDocuments lists the documents of an employee, the latest first.
+type ListDocuments struct {
+	Documents []*DocumentRes `json:"documents"`
+}
+
+type AttendanceReport struct {
+	From      string                  `json:"from" swaggo:"format=date,example=2024-06-03"`
+	To        string                  `json:"to" swaggo:"format=date,example=2024-06-09"`
+	Employees []*AttendanceSummaryRes `json:"employees"`
+}
+
+// HierarchyEmployeeRes is an employee of a reporting line, Depth counts the
+// levels from the employee the line was read from.
+type HierarchyEmployeeRes struct {
+	EmployeeRes
+	Depth int `json:"depth" swaggo:"example=1"`
+}
+
+type ListReports struct {
+	Reports []*HierarchyEmployeeRes `json:"reports"`
+}
+
+// ManagementChain lists the managers of an employee from the direct manager to the root.
+type ManagementChain struct {
+	Chain []*HierarchyEmployeeRes `json:"chain"`
+}
+
+type OrgChartNode struct {
+	ID           int             `json:"id" swaggo:"example=1"`
+	FirstName    string          `json:"first_name"`
+	LastName     string          `json:"last_name"`
+	Email        string          `json:"email"`
+	DepartmentID *int            `json:"department_id"`
+	Reports      []*OrgChartNode `json:"reports"`
+}
+
+// OrgChart is the management forest, every employee without a manager is a root.
+type OrgChart struct {
+	Total int             `json:"total"`
+	Roots []*OrgChartNode `json:"roots"`
+}
+
+type TokenRes struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" swaggo:"example=Bearer"`
+	ExpiresIn    int    `json:"expires_in" swaggo:"example=86400"`
+}
+
+type AuditRes struct {
+	ID         int64           `json:"id"`
+	EmployeeID int             `json:"employee_id"`
+	Action     string          `json:"action" swaggo:"example=update"`
+	ActorID    string          `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	ActorRole  string          `json:"actor_role"`
+	RequestID  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Diff       json.RawMessage `json:"diff"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type ListAudits struct {
+	Audits     []*AuditRes `json:"audits"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+// ImportEmployeesRes reports an import. Errors lists every row that was not
+// imported, in all_or_nothing mode a single error means nothing was imported.
+type ImportEmployeesRes struct {
+	Mode      string         `json:"mode"`
+	DryRun    bool           `json:"dry_run"`
+	Total     int            `json:"total"`
+	Imported  int            `json:"imported"`
+	Failed    int            `json:"failed"`
+	Errors    []ImportError  `json:"errors"`
 	Employees []*EmployeeRes `json:"employees"`
 }
+
+type ImportError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
@@ -1,22 +1,63 @@
 package transport
 
 import (
+	"employee/internal/apperror"
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// phonePattern matches an E.164 number, a + and up to 15 digits without a
+// leading zero.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// identifierPattern matches a lower snake case name.
+var identifierPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// latestZone is the first timezone to reach a date, a date has not started
+// anywhere yet until it has started there.
+var latestZone = time.FixedZone("UTC+14", 14*60*60)
+
 func ValidateStruct(s interface{}) error {
 	validate := validator.New()
+	validate.RegisterTagNameFunc(fieldName)
+
+	validations := map[string]validator.Func{
+		"date": func(fl validator.FieldLevel) bool {
+			date := fl.Field().String()
+			_, err := time.Parse("2006-01-02", date)
+			return err == nil
+		},
+		"phone": func(fl validator.FieldLevel) bool {
+			return phonePattern.MatchString(fl.Field().String())
+		},
+		"identifier": func(fl validator.FieldLevel) bool {
+			return identifierPattern.MatchString(fl.Field().String())
+		},
+		// notfuture accepts today in any timezone.
+		"notfuture": func(fl validator.FieldLevel) bool {
+			date, err := time.Parse("2006-01-02", fl.Field().String())
+			return err == nil && !date.After(today())
+		},
+		"min_age": func(fl validator.FieldLevel) bool {
+			age, limit, ok := ageOf(fl)
+			return ok && age >= limit
+		},
+		"max_age": func(fl validator.FieldLevel) bool {
+			age, limit, ok := ageOf(fl)
+			return ok && age <= limit
+		},
+	}
 
-	if err := validate.RegisterValidation("date", func(fl validator.FieldLevel) bool {
-		date := fl.Field().String()
-		_, err := time.Parse("2006-01-02", date)
-		return err == nil
-	}); err != nil {
-		return errors.New(formatValidationErrors(err))
+	for tag, fn := range validations {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			return errors.New(formatValidationErrors(err))
+		}
 	}
 
 	err := validate.Struct(s)
@@ -25,12 +66,74 @@ func ValidateStruct(s interface{}) error {
 		for _, err := range err.(validator.ValidationErrors) {
 			errMessages = append(errMessages, err.Error())
 		}
-		return errors.New(formatValidationErrors(err))
+		return toValidationError(err)
 	}
 
 	return nil
 }
 
+func today() time.Time {
+	year, month, day := time.Now().In(latestZone).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ageOf returns the age in full years of a birth date field today and the
+// age the tag parameter asks for.
+func ageOf(fl validator.FieldLevel) (int, int, bool) {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return 0, 0, false
+	}
+
+	born, err := time.Parse("2006-01-02", fl.Field().String())
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return fullYears(born, today()), limit, true
+}
+
+// fullYears returns the age on the given day of someone born on born.
+func fullYears(born time.Time, on time.Time) int {
+	age := on.Year() - born.Year()
+	if on.Month() < born.Month() || (on.Month() == born.Month() && on.Day() < born.Day()) {
+		age--
+	}
+
+	return age
+}
+
+// toValidationError keeps every failed rule in the details, the first failing
+// field is reported as the error field.
+func toValidationError(err error) error {
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	appErr := apperror.Validation(errs[0].Field(), formatValidationErrors(err))
+	for _, e := range errs {
+		appErr.Details = append(appErr.Details, apperror.FieldError{Field: e.Field(), Rule: e.Tag()})
+	}
+
+	return appErr
+}
+
+// fieldName reports fields under the name the client sent them with.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "param", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
 func formatValidationErrors(err error) string {
 	if errs, ok := err.(validator.ValidationErrors); ok {
 		var errMessages []string
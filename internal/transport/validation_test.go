@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"employee/internal/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestValidateCreateEmployeeReq(t *testing.T) {
+	now := time.Now().UTC()
+	tomorrow := now.AddDate(0, 0, 2).Format("2006-01-02")
+	fifteenYearsAgo := now.AddDate(-15, 0, 0).Format("2006-01-02")
+
+	valid := func() *CreateEmployeeReq {
+		return &CreateEmployeeReq{
+			FirstName: "john",
+			Email:     "john@mail.com",
+			HireDate:  "2023-01-15",
+			EmployeeProfile: EmployeeProfile{
+				Phone:       "+14155550123",
+				DateOfBirth: "1990-04-12",
+				Address:     &Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+				EmergencyContacts: []EmergencyContact{
+					{Name: "Jane", Relationship: "spouse", Phone: "+14155550100"},
+				},
+				NationalID:   "AB123456",
+				WorkLocation: "Berlin",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(payload *CreateEmployeeReq)
+		field  string
+		rule   string
+	}{
+		{
+			name:   "success",
+			modify: func(payload *CreateEmployeeReq) {},
+		},
+		{
+			name:   "success without profile",
+			modify: func(payload *CreateEmployeeReq) { payload.EmployeeProfile = EmployeeProfile{} },
+		},
+		{
+			name:   "invalid email",
+			modify: func(payload *CreateEmployeeReq) { payload.Email = "john.mail.com" },
+			field:  "email",
+			rule:   "email",
+		},
+		{
+			name:   "hire date in the future",
+			modify: func(payload *CreateEmployeeReq) { payload.HireDate = tomorrow },
+			field:  "hire_date",
+			rule:   "notfuture",
+		},
+		{
+			name:   "phone without country code",
+			modify: func(payload *CreateEmployeeReq) { payload.Phone = "4155550123" },
+			field:  "phone",
+			rule:   "phone",
+		},
+		{
+			name:   "phone with a leading zero",
+			modify: func(payload *CreateEmployeeReq) { payload.Phone = "+04155550123" },
+			field:  "phone",
+			rule:   "phone",
+		},
+		{
+			name:   "too young",
+			modify: func(payload *CreateEmployeeReq) { payload.DateOfBirth = fifteenYearsAgo },
+			field:  "date_of_birth",
+			rule:   "min_age",
+		},
+		{
+			name:   "too old",
+			modify: func(payload *CreateEmployeeReq) { payload.DateOfBirth = "1900-01-01" },
+			field:  "date_of_birth",
+			rule:   "max_age",
+		},
+		{
+			name:   "address without city",
+			modify: func(payload *CreateEmployeeReq) { payload.Address.City = "" },
+			field:  "city",
+			rule:   "required",
+		},
+		{
+			name:   "address with an unknown country",
+			modify: func(payload *CreateEmployeeReq) { payload.Address.Country = "XX" },
+			field:  "country",
+			rule:   "iso3166_1_alpha2",
+		},
+		{
+			name:   "emergency contact with an invalid phone",
+			modify: func(payload *CreateEmployeeReq) { payload.EmergencyContacts[0].Phone = "555" },
+			field:  "phone",
+			rule:   "phone",
+		},
+		{
+			name: "too many emergency contacts",
+			modify: func(payload *CreateEmployeeReq) {
+				for i := 0; i < 5; i++ {
+					payload.EmergencyContacts = append(payload.EmergencyContacts, payload.EmergencyContacts[0])
+				}
+			},
+			field: "emergency_contacts",
+			rule:  "max",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := valid()
+			tc.modify(payload)
+
+			err := ValidateStruct(payload)
+			if tc.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			appErr, ok := apperror.As(err)
+			require.True(t, ok)
+			assert.Equal(t, apperror.CodeValidation, appErr.Code)
+			assert.Equal(t, tc.field, appErr.Field)
+			assert.Equal(t, []apperror.FieldError{{Field: tc.field, Rule: tc.rule}}, appErr.Details)
+		})
+	}
+}
+
+func TestFullYears(t *testing.T) {
+	born := time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 17, fullYears(born, time.Date(2018, time.February, 28, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, fullYears(born, time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24, fullYears(born, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,374 @@
+package attendance
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	aRepo "employee/internal/repository/attendance"
+	eRepo "employee/internal/repository/employee"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+	"math"
+	"time"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Attendance")
+)
+
+const dateLayout = "2006-01-02"
+
+// maxRangeDays bounds the dates a query lays out for every employee.
+const maxRangeDays = 366
+
+var (
+	ErrEmployeeNotFound   = apperror.NotFound("employee not found")
+	ErrEmployeeTerminated = apperror.Unprocessable("employee_id", "employee is terminated")
+	ErrNotClockedIn       = apperror.Conflict("clock_out", "employee is not clocked in")
+	ErrInvalidRange       = apperror.Validation("to", "to must not be before from")
+	ErrRangeTooLong       = apperror.Validation("to", "range must not exceed 366 days")
+)
+
+type UseCaseAttendance interface {
+	ClockIn(ctx context.Context, employeeID int) (*transport.AttendanceRes, error)
+	ClockOut(ctx context.Context, employeeID int) (*transport.AttendanceRes, error)
+	GetDailyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceDays, error)
+	GetWeeklyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceWeeks, error)
+	GetReport(ctx context.Context, payload *transport.AttendanceReportReq) (*transport.AttendanceReport, error)
+}
+
+type useCaseAttendance struct {
+	attendanceRepo aRepo.AttendanceRepo
+	employeeRepo   eRepo.UserRepo
+	schedule       *Schedule
+}
+
+func NewUseCaseAttendance(attendanceRepo aRepo.AttendanceRepo, employeeRepo eRepo.UserRepo, schedule *Schedule) UseCaseAttendance {
+	return &useCaseAttendance{attendanceRepo: attendanceRepo, employeeRepo: employeeRepo, schedule: schedule}
+}
+
+// ClockIn opens a session for the employee now, a second one fails while
+// the first is open.
+func (u *useCaseAttendance) ClockIn(ctx context.Context, employeeID int) (*transport.AttendanceRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "ClockIn")
+
+	employee, err := u.findEmployee(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	if employee.Status == model.EmploymentStatusTerminated {
+		uLog.Errorf("employee %d is terminated", employee.ID)
+		return nil, ErrEmployeeTerminated
+	}
+
+	now := time.Now()
+
+	attendance := &model.Attendance{
+		EmployeeID: employeeID,
+		WorkDate:   u.schedule.WorkDate(now),
+		ClockIn:    now,
+	}
+
+	if err := u.attendanceRepo.ClockIn(ctx, attendance); err != nil {
+		uLog.Errorf("error when call attendanceRepo.ClockIn got %s", err.Error())
+		return nil, err
+	}
+
+	return toAttendanceRes(attendance), nil
+}
+
+// ClockOut closes the open session of the employee now.
+func (u *useCaseAttendance) ClockOut(ctx context.Context, employeeID int) (*transport.AttendanceRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "ClockOut")
+
+	if _, err := u.findEmployee(ctx, employeeID); err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	attendance, err := u.attendanceRepo.ClockOut(ctx, employeeID, time.Now())
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.ClockOut got %s", err.Error())
+		return nil, err
+	}
+
+	if attendance == nil {
+		uLog.Errorf("employee %d is not clocked in", employeeID)
+		return nil, ErrNotClockedIn
+	}
+
+	return toAttendanceRes(attendance), nil
+}
+
+// GetDailyAttendance lays out the worked days of the employee within the
+// range with the scheduled work days they were late, absent or on leave.
+func (u *useCaseAttendance) GetDailyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceDays, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetDailyAttendance")
+
+	if err := checkRange(payload.From, payload.To); err != nil {
+		return nil, err
+	}
+
+	employee, err := u.findEmployee(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	filter := model.AttendanceFilter{EmployeeID: employeeID, From: payload.From, To: payload.To}
+
+	worked, err := u.attendanceRepo.GetDailyHours(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetDailyHours got %s", err.Error())
+		return nil, err
+	}
+
+	leave, err := u.attendanceRepo.GetApprovedLeave(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetApprovedLeave got %s", err.Error())
+		return nil, err
+	}
+
+	today := u.schedule.WorkDate(time.Now())
+	days := u.schedule.days(employee, worked, leave, payload.From, payload.To, today)
+
+	return &transport.ListAttendanceDays{EmployeeID: employeeID, Days: days}, nil
+}
+
+// GetWeeklyAttendance sums the worked hours of the employee per week within the range.
+func (u *useCaseAttendance) GetWeeklyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceWeeks, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetWeeklyAttendance")
+
+	if err := checkRange(payload.From, payload.To); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.findEmployee(ctx, employeeID); err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	filter := model.AttendanceFilter{EmployeeID: employeeID, From: payload.From, To: payload.To}
+
+	weeks, err := u.attendanceRepo.GetWeeklyHours(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetWeeklyHours got %s", err.Error())
+		return nil, err
+	}
+
+	res := make([]*transport.AttendanceWeekRes, 0, len(weeks))
+	for _, week := range weeks {
+		res = append(res, &transport.AttendanceWeekRes{
+			WeekStart:   week.WeekStart,
+			WorkedHours: hours(week.WorkedSeconds),
+			DaysWorked:  week.DaysWorked,
+		})
+	}
+
+	return &transport.ListAttendanceWeeks{EmployeeID: employeeID, Weeks: res}, nil
+}
+
+// GetReport sums the attendance of every employee employed within the range,
+// of one department when it is set.
+func (u *useCaseAttendance) GetReport(ctx context.Context, payload *transport.AttendanceReportReq) (*transport.AttendanceReport, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetReport")
+
+	if err := checkRange(payload.From, payload.To); err != nil {
+		return nil, err
+	}
+
+	filter := model.AttendanceFilter{DepartmentID: payload.DepartmentID, From: payload.From, To: payload.To}
+
+	employees, err := u.attendanceRepo.GetEmployees(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetEmployees got %s", err.Error())
+		return nil, err
+	}
+
+	worked, err := u.attendanceRepo.GetDailyHours(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetDailyHours got %s", err.Error())
+		return nil, err
+	}
+
+	leave, err := u.attendanceRepo.GetApprovedLeave(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call attendanceRepo.GetApprovedLeave got %s", err.Error())
+		return nil, err
+	}
+
+	workedByEmployee := make(map[int][]*model.AttendanceDay)
+	for _, day := range worked {
+		workedByEmployee[day.EmployeeID] = append(workedByEmployee[day.EmployeeID], day)
+	}
+
+	leaveByEmployee := make(map[int][]*model.LeaveRequest)
+	for _, request := range leave {
+		leaveByEmployee[request.EmployeeID] = append(leaveByEmployee[request.EmployeeID], request)
+	}
+
+	today := u.schedule.WorkDate(time.Now())
+
+	res := make([]*transport.AttendanceSummaryRes, 0, len(employees))
+	for _, employee := range employees {
+		days := u.schedule.days(employee, workedByEmployee[employee.ID], leaveByEmployee[employee.ID], payload.From, payload.To, today)
+		res = append(res, summarize(employee, days))
+	}
+
+	return &transport.AttendanceReport{From: payload.From, To: payload.To, Employees: res}, nil
+}
+
+func (u *useCaseAttendance) findEmployee(ctx context.Context, employeeID int) (*model.Employee, error) {
+	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	return employee, nil
+}
+
+// checkRange rejects a range ending before it starts or longer than
+// maxRangeDays, both dates are validated as YYYY-MM-DD.
+func checkRange(from string, to string) error {
+	start, _ := time.Parse(dateLayout, from)
+	end, _ := time.Parse(dateLayout, to)
+
+	if end.Before(start) {
+		return ErrInvalidRange
+	}
+
+	if end.Sub(start) >= maxRangeDays*24*time.Hour {
+		return ErrRangeTooLong
+	}
+
+	return nil
+}
+
+// days lays out every date of the range the employee worked or was expected
+// to. A scheduled work day is absent once it has passed without any session
+// or approved leave, days before the hire date or after the termination date
+// are not expected.
+func (s *Schedule) days(employee *model.Employee, worked []*model.AttendanceDay, leave []*model.LeaveRequest,
+	from string, to string, today string) []*transport.AttendanceDayRes {
+	byDate := make(map[string]*model.AttendanceDay, len(worked))
+	for _, day := range worked {
+		byDate[day.WorkDate] = day
+	}
+
+	hireDate := dateOf(employee.HireDate)
+
+	start, _ := time.Parse(dateLayout, from)
+	end, _ := time.Parse(dateLayout, to)
+
+	res := make([]*transport.AttendanceDayRes, 0)
+	for current := start; !current.After(end); current = current.AddDate(0, 0, 1) {
+		date := current.Format(dateLayout)
+		workday := s.IsWorkday(date)
+		onLeave := workday && onLeaveOn(leave, date)
+
+		if day, ok := byDate[date]; ok {
+			firstClockIn := day.FirstClockIn
+			res = append(res, &transport.AttendanceDayRes{
+				Date:         date,
+				FirstClockIn: &firstClockIn,
+				LastClockOut: day.LastClockOut,
+				WorkedHours:  hours(day.WorkedSeconds),
+				ClockedIn:    day.Open,
+				Late:         workday && !onLeave && s.IsLate(day.FirstClockIn),
+				OnLeave:      onLeave,
+			})
+			continue
+		}
+
+		employed := date >= hireDate && (employee.TerminationDate == nil || date <= dateOf(*employee.TerminationDate))
+		if !workday || !employed {
+			continue
+		}
+
+		if onLeave {
+			res = append(res, &transport.AttendanceDayRes{Date: date, OnLeave: true})
+			continue
+		}
+
+		if date < today {
+			res = append(res, &transport.AttendanceDayRes{Date: date, Absent: true})
+		}
+	}
+
+	return res
+}
+
+func onLeaveOn(leave []*model.LeaveRequest, date string) bool {
+	for _, request := range leave {
+		if request.StartDate <= date && date <= request.EndDate {
+			return true
+		}
+	}
+
+	return false
+}
+
+func summarize(employee *model.Employee, days []*transport.AttendanceDayRes) *transport.AttendanceSummaryRes {
+	summary := &transport.AttendanceSummaryRes{
+		EmployeeID:   employee.ID,
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		DepartmentID: employee.DepartmentID,
+	}
+
+	var worked float64
+	for _, day := range days {
+		worked += day.WorkedHours
+		if day.FirstClockIn != nil {
+			summary.DaysPresent++
+		}
+		if day.Late {
+			summary.LateDays++
+		}
+		if day.Absent {
+			summary.AbsentDays++
+		}
+		if day.OnLeave {
+			summary.LeaveDays++
+		}
+	}
+	summary.WorkedHours = math.Round(worked*100) / 100
+
+	return summary
+}
+
+// dateOf trims a date or RFC3339 timestamp to its YYYY-MM-DD date.
+func dateOf(value string) string {
+	if len(value) > len(dateLayout) {
+		return value[:len(dateLayout)]
+	}
+
+	return value
+}
+
+// hours rounds seconds to the hundredth of an hour.
+func hours(seconds float64) float64 {
+	return math.Round(seconds/36) / 100
+}
+
+func toAttendanceRes(attendance *model.Attendance) *transport.AttendanceRes {
+	res := &transport.AttendanceRes{
+		ID:         attendance.ID,
+		EmployeeID: attendance.EmployeeID,
+		WorkDate:   attendance.WorkDate,
+		ClockIn:    attendance.ClockIn,
+		ClockOut:   attendance.ClockOut,
+	}
+
+	if attendance.ClockOut != nil {
+		res.WorkedHours = hours(attendance.ClockOut.Sub(attendance.ClockIn).Seconds())
+	}
+
+	return res
+}
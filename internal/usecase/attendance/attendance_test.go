@@ -0,0 +1,282 @@
+package attendance
+
+import (
+	"context"
+	"employee/internal/model"
+	aRepo "employee/internal/repository/attendance"
+	attendanceRepoMock "employee/internal/repository/attendance/mock"
+	employeeRepoMock "employee/internal/repository/employee/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newSchedule(t *testing.T) *Schedule {
+	schedule, err := NewSchedule("09:00", "mon,tue,wed,thu,fri", 10, "UTC")
+	require.NoError(t, err)
+
+	return schedule
+}
+
+func TestDays(t *testing.T) {
+	schedule := newSchedule(t)
+
+	onTime := time.Date(2024, 6, 4, 8, 55, 0, 0, time.UTC)
+	late := time.Date(2024, 6, 5, 9, 30, 0, 0, time.UTC)
+	saturday := time.Date(2024, 6, 8, 11, 0, 0, 0, time.UTC)
+	clockOut := time.Date(2024, 6, 4, 17, 25, 0, 0, time.UTC)
+
+	// hired on Tuesday, Monday is not expected
+	employee := &model.Employee{ID: 3, HireDate: "2024-06-04T00:00:00Z"}
+	worked := []*model.AttendanceDay{
+		{EmployeeID: 3, WorkDate: "2024-06-04", FirstClockIn: onTime, LastClockOut: &clockOut, WorkedSeconds: 30600},
+		{EmployeeID: 3, WorkDate: "2024-06-05", FirstClockIn: late, Open: true},
+		{EmployeeID: 3, WorkDate: "2024-06-08", FirstClockIn: saturday, WorkedSeconds: 3600},
+	}
+	leave := []*model.LeaveRequest{{EmployeeID: 3, StartDate: "2024-06-07", EndDate: "2024-06-10"}}
+
+	days := schedule.days(employee, worked, leave, "2024-06-03", "2024-06-12", "2024-06-11")
+
+	dates := make([]string, 0, len(days))
+	for _, day := range days {
+		dates = append(dates, day.Date)
+	}
+	require.Equal(t, []string{"2024-06-04", "2024-06-05", "2024-06-06", "2024-06-07", "2024-06-08", "2024-06-10"}, dates)
+
+	assert.Equal(t, 8.5, days[0].WorkedHours)
+	assert.False(t, days[0].Late)
+	assert.True(t, days[1].Late)
+	assert.True(t, days[1].ClockedIn)
+	assert.True(t, days[2].Absent)
+	assert.True(t, days[3].OnLeave)
+	assert.False(t, days[3].Absent)
+	// weekend work is neither late nor leave
+	assert.False(t, days[4].Late)
+	assert.False(t, days[4].OnLeave)
+	assert.True(t, days[5].OnLeave)
+}
+
+func TestCheckRange(t *testing.T) {
+	assert.NoError(t, checkRange("2024-01-01", "2024-12-31"))
+	assert.ErrorIs(t, checkRange("2024-06-05", "2024-06-04"), ErrInvalidRange)
+	assert.ErrorIs(t, checkRange("2024-01-01", "2025-01-01"), ErrRangeTooLong)
+}
+
+func TestClockIn(t *testing.T) {
+	active := &model.Employee{ID: 3, Status: model.EmploymentStatusActive}
+	terminated := &model.Employee{ID: 3, Status: model.EmploymentStatusTerminated}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(attendanceRepo *attendanceRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.AttendanceRes, err error)
+	}{
+		{
+			name: "error when employee not found",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "error when employee is terminated",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(terminated, nil)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.ErrorIs(t, err, ErrEmployeeTerminated)
+			},
+		},
+		{
+			name: "error when already clocked in",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(active, nil)
+				attendanceRepo.On("ClockIn", mock.Anything, mock.Anything).Return(aRepo.ErrAlreadyClockedIn)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.ErrorIs(t, err, aRepo.ErrAlreadyClockedIn)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(active, nil)
+				attendanceRepo.On("ClockIn", mock.Anything, mock.MatchedBy(func(attendance *model.Attendance) bool {
+					return attendance.EmployeeID == 3 && attendance.WorkDate == attendance.ClockIn.UTC().Format(dateLayout)
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Attendance).ID = 7
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), result.ID)
+				assert.Nil(t, result.ClockOut)
+				assert.Zero(t, result.WorkedHours)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attendanceRepo := new(attendanceRepoMock.DBMock)
+			employeeRepo := new(employeeRepoMock.DBMock)
+
+			tc.buildStub(attendanceRepo, employeeRepo)
+
+			uc := NewUseCaseAttendance(attendanceRepo, employeeRepo, newSchedule(t))
+
+			result, err := uc.ClockIn(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestClockOut(t *testing.T) {
+	employee := &model.Employee{ID: 3, Status: model.EmploymentStatusActive}
+	clockIn := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	clockOut := time.Date(2024, 6, 3, 17, 15, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		buildStub   func(attendanceRepo *attendanceRepoMock.DBMock)
+		checkReturn func(result *transport.AttendanceRes, err error)
+	}{
+		{
+			name: "error when not clocked in",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock) {
+				attendanceRepo.On("ClockOut", mock.Anything, 3, mock.Anything).Return((*model.Attendance)(nil), nil)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.ErrorIs(t, err, ErrNotClockedIn)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock) {
+				attendanceRepo.On("ClockOut", mock.Anything, 3, mock.Anything).
+					Return(&model.Attendance{ID: 7, EmployeeID: 3, WorkDate: "2024-06-03", ClockIn: clockIn, ClockOut: &clockOut}, nil)
+			},
+			checkReturn: func(result *transport.AttendanceRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 8.25, result.WorkedHours)
+				assert.Equal(t, &clockOut, result.ClockOut)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attendanceRepo := new(attendanceRepoMock.DBMock)
+			employeeRepo := new(employeeRepoMock.DBMock)
+			employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+
+			tc.buildStub(attendanceRepo)
+
+			uc := NewUseCaseAttendance(attendanceRepo, employeeRepo, newSchedule(t))
+
+			result, err := uc.ClockOut(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetWeeklyAttendance(t *testing.T) {
+	attendanceRepo := new(attendanceRepoMock.DBMock)
+	employeeRepo := new(employeeRepoMock.DBMock)
+
+	filter := model.AttendanceFilter{EmployeeID: 3, From: "2024-06-03", To: "2024-06-16"}
+
+	employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+	attendanceRepo.On("GetWeeklyHours", mock.Anything, filter).Return([]*model.AttendanceWeek{
+		{EmployeeID: 3, WeekStart: "2024-06-03", WorkedSeconds: 144900, DaysWorked: 5},
+		{EmployeeID: 3, WeekStart: "2024-06-10", WorkedSeconds: 28800, DaysWorked: 1},
+	}, nil)
+
+	uc := NewUseCaseAttendance(attendanceRepo, employeeRepo, newSchedule(t))
+
+	result, err := uc.GetWeeklyAttendance(context.TODO(), 3, &transport.AttendanceRangeReq{From: "2024-06-03", To: "2024-06-16"})
+
+	assert.NoError(t, err)
+	require.Len(t, result.Weeks, 2)
+	assert.Equal(t, 40.25, result.Weeks[0].WorkedHours)
+	assert.Equal(t, 5, result.Weeks[0].DaysWorked)
+	assert.Equal(t, 8.0, result.Weeks[1].WorkedHours)
+}
+
+func TestGetReport(t *testing.T) {
+	departmentID := 2
+	filter := model.AttendanceFilter{DepartmentID: 2, From: "2024-06-03", To: "2024-06-07"}
+	payload := &transport.AttendanceReportReq{From: "2024-06-03", To: "2024-06-07", DepartmentID: 2}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.AttendanceReportReq
+		buildStub   func(attendanceRepo *attendanceRepoMock.DBMock)
+		checkReturn func(result *transport.AttendanceReport, err error)
+	}{
+		{
+			name:      "error when to is before from",
+			payload:   &transport.AttendanceReportReq{From: "2024-06-07", To: "2024-06-03"},
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock) {},
+			checkReturn: func(result *transport.AttendanceReport, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRange)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "success",
+			payload: payload,
+			buildStub: func(attendanceRepo *attendanceRepoMock.DBMock) {
+				attendanceRepo.On("GetEmployees", mock.Anything, filter).Return([]*model.Employee{
+					{ID: 3, FirstName: "Jane", LastName: "Doe", DepartmentID: &departmentID, HireDate: "2020-01-01"},
+					{ID: 4, FirstName: "John", LastName: "Roe", DepartmentID: &departmentID, HireDate: "2020-01-01"},
+				}, nil)
+				attendanceRepo.On("GetDailyHours", mock.Anything, filter).Return([]*model.AttendanceDay{
+					{EmployeeID: 3, WorkDate: "2024-06-03", FirstClockIn: time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC), WorkedSeconds: 28800},
+					{EmployeeID: 3, WorkDate: "2024-06-04", FirstClockIn: time.Date(2024, 6, 4, 9, 45, 0, 0, time.UTC), WorkedSeconds: 27000},
+				}, nil)
+				attendanceRepo.On("GetApprovedLeave", mock.Anything, filter).Return([]*model.LeaveRequest{
+					{EmployeeID: 4, StartDate: "2024-06-03", EndDate: "2024-06-05"},
+				}, nil)
+			},
+			checkReturn: func(result *transport.AttendanceReport, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Employees, 2)
+
+				jane := result.Employees[0]
+				assert.Equal(t, 15.5, jane.WorkedHours)
+				assert.Equal(t, 2, jane.DaysPresent)
+				assert.Equal(t, 1, jane.LateDays)
+				assert.Equal(t, 3, jane.AbsentDays)
+
+				john := result.Employees[1]
+				assert.Equal(t, 3, john.LeaveDays)
+				assert.Equal(t, 2, john.AbsentDays)
+				assert.Zero(t, john.DaysPresent)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attendanceRepo := new(attendanceRepoMock.DBMock)
+
+			tc.buildStub(attendanceRepo)
+
+			uc := NewUseCaseAttendance(attendanceRepo, new(employeeRepoMock.DBMock), newSchedule(t))
+
+			result, err := uc.GetReport(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
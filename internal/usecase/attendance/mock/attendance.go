@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type AttendanceUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *AttendanceUseCaseMock) ClockIn(ctx context.Context, employeeID int) (*transport.AttendanceRes, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.AttendanceRes), args.Error(1)
+}
+
+func (m *AttendanceUseCaseMock) ClockOut(ctx context.Context, employeeID int) (*transport.AttendanceRes, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.AttendanceRes), args.Error(1)
+}
+
+func (m *AttendanceUseCaseMock) GetDailyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceDays, error) {
+	args := m.Called(ctx, employeeID, payload)
+
+	return args.Get(0).(*transport.ListAttendanceDays), args.Error(1)
+}
+
+func (m *AttendanceUseCaseMock) GetWeeklyAttendance(ctx context.Context, employeeID int, payload *transport.AttendanceRangeReq) (*transport.ListAttendanceWeeks, error) {
+	args := m.Called(ctx, employeeID, payload)
+
+	return args.Get(0).(*transport.ListAttendanceWeeks), args.Error(1)
+}
+
+func (m *AttendanceUseCaseMock) GetReport(ctx context.Context, payload *transport.AttendanceReportReq) (*transport.AttendanceReport, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.AttendanceReport), args.Error(1)
+}
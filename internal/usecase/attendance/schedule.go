@@ -0,0 +1,104 @@
+package attendance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// the alpine image ships without a zoneinfo database
+	_ "time/tzdata"
+)
+
+const (
+	defaultWorkStart = "09:00"
+	defaultWorkDays  = "mon,tue,wed,thu,fri"
+	defaultTimezone  = "UTC"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is the work schedule lateness and absence are measured against.
+type Schedule struct {
+	start    time.Duration
+	grace    time.Duration
+	workdays map[time.Weekday]bool
+	location *time.Location
+}
+
+// NewSchedule reads a schedule starting at start, formatted HH:MM, on the
+// comma separated days, e.g. mon,tue,wed, in the IANA timezone. A first
+// clock-in later than graceMinutes after the start is late. Empty values fall
+// back to 09:00 from Monday to Friday in UTC.
+func NewSchedule(start string, days string, graceMinutes int, timezone string) (*Schedule, error) {
+	if start == "" {
+		start = defaultWorkStart
+	}
+	if days == "" {
+		days = defaultWorkDays
+	}
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	at, err := time.Parse("15:04", start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid work start %q, expected HH:MM", start)
+	}
+
+	if graceMinutes < 0 {
+		return nil, fmt.Errorf("invalid grace minutes %d, must not be negative", graceMinutes)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid work timezone %q: %w", timezone, err)
+	}
+
+	workdays := make(map[time.Weekday]bool)
+	for _, day := range strings.Split(days, ",") {
+		weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, fmt.Errorf("invalid work day %q, expected one of sun, mon, tue, wed, thu, fri, sat", day)
+		}
+		workdays[weekday] = true
+	}
+
+	return &Schedule{
+		start:    time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute,
+		grace:    time.Duration(graceMinutes) * time.Minute,
+		workdays: workdays,
+		location: location,
+	}, nil
+}
+
+// WorkDate is the date of t in the timezone of the schedule.
+func (s *Schedule) WorkDate(t time.Time) string {
+	return t.In(s.location).Format(dateLayout)
+}
+
+// IsWorkday reports whether the date, formatted YYYY-MM-DD, is a scheduled work day.
+func (s *Schedule) IsWorkday(date string) bool {
+	day, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return false
+	}
+
+	return s.workdays[day.Weekday()]
+}
+
+// IsLate reports whether a clock-in happened after the scheduled start of
+// its day and the grace period.
+func (s *Schedule) IsLate(clockIn time.Time) bool {
+	local := clockIn.In(s.location)
+	year, month, day := local.Date()
+	deadline := time.Date(year, month, day, 0, 0, 0, 0, s.location).Add(s.start + s.grace)
+
+	return local.After(deadline)
+}
@@ -0,0 +1,61 @@
+package attendance
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewSchedule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		start    string
+		days     string
+		grace    int
+		timezone string
+		wantErr  bool
+	}{
+		{name: "defaults"},
+		{name: "custom schedule", start: "08:30", days: "Mon, tue,sat", grace: 5, timezone: "Asia/Jakarta"},
+		{name: "error when start is not a time", start: "9am", wantErr: true},
+		{name: "error when a day is unknown", days: "mon,funday", wantErr: true},
+		{name: "error when grace is negative", grace: -1, wantErr: true},
+		{name: "error when timezone is unknown", timezone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := NewSchedule(tc.start, tc.days, tc.grace, tc.timezone)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, schedule)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, schedule)
+		})
+	}
+}
+
+func TestScheduleIsLate(t *testing.T) {
+	schedule, err := NewSchedule("09:00", "", 10, "Asia/Jakarta")
+	require.NoError(t, err)
+
+	// Asia/Jakarta is UTC+7, 09:10 there is 02:10 UTC
+	assert.False(t, schedule.IsLate(time.Date(2024, 6, 3, 2, 10, 0, 0, time.UTC)))
+	assert.True(t, schedule.IsLate(time.Date(2024, 6, 3, 2, 10, 1, 0, time.UTC)))
+	assert.False(t, schedule.IsLate(time.Date(2024, 6, 3, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-06-04", schedule.WorkDate(time.Date(2024, 6, 3, 18, 0, 0, 0, time.UTC)))
+}
+
+func TestScheduleIsWorkday(t *testing.T) {
+	schedule, err := NewSchedule("", "mon,wed", 0, "")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.IsWorkday("2024-06-03"))
+	assert.False(t, schedule.IsWorkday("2024-06-04"))
+	assert.True(t, schedule.IsWorkday("2024-06-05"))
+	assert.False(t, schedule.IsWorkday("not a date"))
+}
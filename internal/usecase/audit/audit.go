@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"employee/internal/model"
+	aRepo "employee/internal/repository/audit"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Audit")
+)
+
+const (
+	defaultPageLimit = 20
+)
+
+type UseCaseAudit interface {
+	GetAudits(ctx context.Context, employeeID int, payload *transport.ListAuditReq) (*transport.ListAudits, error)
+}
+
+type useCaseAudit struct {
+	auditRepo aRepo.AuditRepo
+}
+
+func NewUseCaseAudit(auditRepo aRepo.AuditRepo) UseCaseAudit {
+	return &useCaseAudit{auditRepo: auditRepo}
+}
+
+// GetAudits lists audit entries newest first, employeeID 0 lists every employee.
+func (u *useCaseAudit) GetAudits(ctx context.Context, employeeID int, payload *transport.ListAuditReq) (*transport.ListAudits, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetAudits")
+
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	filter := &model.AuditFilter{
+		EmployeeID: employeeID,
+		ActorID:    payload.ActorID,
+		Action:     payload.Action,
+		From:       payload.From,
+		To:         payload.To,
+	}
+
+	total, err := u.auditRepo.CountAudits(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call auditRepo.CountAudits got %s", err.Error())
+		return nil, err
+	}
+
+	filter.Limit = limit
+	filter.Offset = payload.Offset
+
+	audits, err := u.auditRepo.GetAudits(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call auditRepo.GetAudits got %s", err.Error())
+		return nil, err
+	}
+
+	auditsResData := make([]*transport.AuditRes, 0)
+	for _, audit := range audits {
+		auditsResData = append(auditsResData, &transport.AuditRes{
+			ID:         audit.ID,
+			EmployeeID: audit.EmployeeID,
+			Action:     audit.Action,
+			ActorID:    audit.ActorID,
+			ActorName:  audit.ActorName,
+			ActorRole:  audit.ActorRole,
+			RequestID:  audit.RequestID,
+			Before:     audit.Before,
+			After:      audit.After,
+			Diff:       audit.Diff,
+			CreatedAt:  audit.CreatedAt,
+		})
+	}
+
+	return &transport.ListAudits{
+		Audits: auditsResData,
+		Pagination: &transport.Pagination{
+			Total:  total,
+			Limit:  limit,
+			Offset: payload.Offset,
+		},
+	}, nil
+}
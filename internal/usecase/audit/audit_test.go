@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	auditRepoMock "employee/internal/repository/audit/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetAudits(t *testing.T) {
+
+	mockAudits := []*model.Audit{
+		{ID: 2, EmployeeID: 1, Action: model.AuditActionUpdate, ActorID: "3"},
+		{ID: 1, EmployeeID: 1, Action: model.AuditActionCreate, ActorID: "3"},
+	}
+
+	testCases := []struct {
+		name        string
+		employeeID  int
+		payload     *transport.ListAuditReq
+		buildStub   func(auditRepo *auditRepoMock.DBMock)
+		checkReturn func(result *transport.ListAudits, err error)
+	}{
+		{
+			name:    "error when count audits",
+			payload: &transport.ListAuditReq{},
+			buildStub: func(auditRepo *auditRepoMock.DBMock) {
+				auditRepo.On("CountAudits", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListAudits, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when get audits",
+			payload: &transport.ListAuditReq{},
+			buildStub: func(auditRepo *auditRepoMock.DBMock) {
+				auditRepo.On("CountAudits", mock.Anything, mock.Anything).Return(2, nil)
+				auditRepo.On("GetAudits", mock.Anything, mock.Anything).Return([]*model.Audit(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListAudits, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:       "success with default limit",
+			employeeID: 1,
+			payload:    &transport.ListAuditReq{Action: "update"},
+			buildStub: func(auditRepo *auditRepoMock.DBMock) {
+				auditRepo.On("CountAudits", mock.Anything, mock.Anything).Return(2, nil)
+				auditRepo.On("GetAudits", mock.Anything, mock.MatchedBy(func(filter *model.AuditFilter) bool {
+					return filter.EmployeeID == 1 && filter.Action == "update" && filter.Limit == defaultPageLimit
+				})).Return(mockAudits, nil)
+			},
+			checkReturn: func(result *transport.ListAudits, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Audits, 2)
+				assert.Equal(t, 2, result.Pagination.Total)
+				assert.Equal(t, defaultPageLimit, result.Pagination.Limit)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auditRepository := new(auditRepoMock.DBMock)
+			tc.buildStub(auditRepository)
+
+			u := NewUseCaseAudit(auditRepository)
+			result, err := u.GetAudits(context.TODO(), tc.employeeID, tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
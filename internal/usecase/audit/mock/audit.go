@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *AuditUseCaseMock) GetAudits(ctx context.Context, employeeID int, payload *transport.ListAuditReq) (*transport.ListAudits, error) {
+	args := m.Called(ctx, employeeID, payload)
+
+	return args.Get(0).(*transport.ListAudits), args.Error(1)
+}
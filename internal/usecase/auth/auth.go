@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/config"
+	"employee/internal/constant"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository"
+	uRepo "employee/internal/repository/user"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Auth")
+)
+
+const defaultRefreshTokenTTL = time.Hour * 24 * 30
+
+var (
+	ErrInvalidCredentials  = apperror.Unauthorized("invalid email or password")
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid refresh token")
+	ErrAccountDisabled     = apperror.Unauthorized("account is disabled")
+	ErrAdminPasswordUnset  = apperror.Validation("password", "admin password must be set and not the placeholder")
+)
+
+// dummyHash is compared against when the email is unknown so that a failed
+// login takes the same time whether or not the account exists.
+var dummyHash, _ = pkg.HashPassword("dummy-password")
+
+type UseCaseAuth interface {
+	Login(ctx context.Context, payload *transport.LoginReq) (*transport.TokenRes, error)
+	Refresh(ctx context.Context, payload *transport.RefreshTokenReq) (*transport.TokenRes, error)
+	Logout(ctx context.Context, payload *transport.RefreshTokenReq) error
+	EnsureAdmin(ctx context.Context, email string, password string) error
+}
+
+type useCaseAuth struct {
+	userRepo   uRepo.UserRepo
+	transactor repository.Transactor
+	cfg        config.Config
+}
+
+func NewUseCaseAuth(userRepo uRepo.UserRepo, transactor repository.Transactor, cfg config.Config) UseCaseAuth {
+	return &useCaseAuth{userRepo: userRepo, transactor: transactor, cfg: cfg}
+}
+
+func (u *useCaseAuth) Login(ctx context.Context, payload *transport.LoginReq) (*transport.TokenRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "Login")
+
+	user, err := u.userRepo.GetUserByEmail(ctx, payload.Email)
+	if err != nil {
+		uLog.Errorf("error when call userRepo.GetUserByEmail got %s", err.Error())
+		return nil, err
+	}
+
+	if user == nil {
+		_ = pkg.ComparePassword(dummyHash, payload.Password)
+		uLog.Errorf("login attempt for unknown email %s", payload.Email)
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := pkg.ComparePassword(user.PasswordHash, payload.Password); err != nil {
+		uLog.Errorf("wrong password for user %d", user.ID)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.DisabledAt != nil {
+		uLog.Errorf("login attempt for disabled user %d", user.ID)
+		return nil, ErrAccountDisabled
+	}
+
+	var result *transport.TokenRes
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		result, err = u.issueTokens(ctx, user)
+		return err
+	})
+	if err != nil {
+		uLog.Errorf("error when issue tokens got %s", err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *useCaseAuth) Refresh(ctx context.Context, payload *transport.RefreshTokenReq) (*transport.TokenRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "Refresh")
+
+	var result *transport.TokenRes
+	var reused bool
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		token, err := u.userRepo.GetRefreshToken(ctx, pkg.HashToken(payload.RefreshToken))
+		if err != nil {
+			uLog.Errorf("error when call userRepo.GetRefreshToken got %s", err.Error())
+			return err
+		}
+
+		if token == nil || token.ExpiresAt.Before(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+
+		revoked, err := u.userRepo.RevokeRefreshToken(ctx, token.ID)
+		if err != nil {
+			uLog.Errorf("error when call userRepo.RevokeRefreshToken got %s", err.Error())
+			return err
+		}
+
+		// a rotated token presented again means it leaked, so every session of
+		// the user is revoked and the transaction still commits
+		if !revoked {
+			reused = true
+			return u.userRepo.RevokeUserRefreshTokens(ctx, token.UserID)
+		}
+
+		user, err := u.userRepo.GetUserByID(ctx, token.UserID)
+		if err != nil {
+			uLog.Errorf("error when call userRepo.GetUserByID got %s", err.Error())
+			return err
+		}
+
+		if user == nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if user.DisabledAt != nil {
+			uLog.Errorf("refresh attempt for disabled user %d", user.ID)
+			return ErrAccountDisabled
+		}
+
+		result, err = u.issueTokens(ctx, user)
+		return err
+	})
+	if err != nil {
+		uLog.Errorf("error when refresh token got %s", err.Error())
+		return nil, err
+	}
+
+	if reused {
+		uLog.Error("refresh token reuse detected, revoked all sessions of the user")
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return result, nil
+}
+
+func (u *useCaseAuth) Logout(ctx context.Context, payload *transport.RefreshTokenReq) error {
+	uLog := logger.WithContext(ctx).WithField("function", "Logout")
+
+	token, err := u.userRepo.GetRefreshToken(ctx, pkg.HashToken(payload.RefreshToken))
+	if err != nil {
+		uLog.Errorf("error when call userRepo.GetRefreshToken got %s", err.Error())
+		return err
+	}
+
+	// logging out with an unknown or already revoked token is a no-op
+	if token == nil {
+		return nil
+	}
+
+	if _, err := u.userRepo.RevokeRefreshToken(ctx, token.ID); err != nil {
+		uLog.Errorf("error when call userRepo.RevokeRefreshToken got %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// EnsureAdmin creates the bootstrap admin account when it does not exist yet,
+// it refuses an empty or placeholder password.
+func (u *useCaseAuth) EnsureAdmin(ctx context.Context, email string, password string) error {
+	uLog := logger.WithContext(ctx).WithField("function", "EnsureAdmin")
+
+	if password == "" || password == config.PlaceholderPassword {
+		uLog.Error("admin password is empty or the placeholder")
+		return ErrAdminPasswordUnset
+	}
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		uLog.Errorf("error when call userRepo.GetUserByEmail got %s", err.Error())
+		return err
+	}
+
+	if user != nil {
+		return nil
+	}
+
+	hash, err := pkg.HashPassword(password)
+	if err != nil {
+		uLog.Errorf("error when hash password got %s", err.Error())
+		return err
+	}
+
+	_, err = u.userRepo.CreateUser(ctx, &model.User{Email: email, PasswordHash: hash, Role: constant.RoleAdmin})
+	if err != nil {
+		uLog.Errorf("error when call userRepo.CreateUser got %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *useCaseAuth) issueTokens(ctx context.Context, user *model.User) (*transport.TokenRes, error) {
+	claims := map[string]string{
+		"sub":  strconv.Itoa(user.ID),
+		"name": user.Email,
+		"role": user.Role,
+	}
+	if user.EmployeeID != nil {
+		claims["employee_id"] = strconv.Itoa(*user.EmployeeID)
+	}
+
+	accessToken, err := pkg.GenerateJWT(claims, u.cfg.JWTSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := pkg.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(u.cfg.RefreshTokenTTLHours) * time.Hour
+	if ttl <= 0 {
+		ttl = defaultRefreshTokenTTL
+	}
+
+	err = u.userRepo.CreateRefreshToken(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: pkg.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &transport.TokenRes{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(pkg.AccessTokenTTL.Seconds()),
+	}, nil
+}
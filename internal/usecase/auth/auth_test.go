@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/config"
+	"employee/internal/constant"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	txMock "employee/internal/repository/mock"
+	userRepoMock "employee/internal/repository/user/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+func TestLogin(t *testing.T) {
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	employeeID := 3
+	mockUser := &model.User{
+		ID:           1,
+		Email:        "test@mail.com",
+		PasswordHash: string(hash),
+		Role:         "hr",
+		EmployeeID:   &employeeID,
+	}
+	disabledAt := time.Now()
+	disabledUser := *mockUser
+	disabledUser.DisabledAt = &disabledAt
+
+	testCases := []struct {
+		name      string
+		payload   *transport.LoginReq
+		buildStub func(
+			userRepo *userRepoMock.DBMock,
+			transactor *txMock.TransactorMock,
+		)
+		checkReturn func(res *transport.TokenRes, err error)
+	}{
+		{
+			name:    "error when get user",
+			payload: &transport.LoginReq{Email: "test@mail.com", Password: "secret-password"},
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return((*model.User)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+		{
+			name:    "error when user does not exist",
+			payload: &transport.LoginReq{Email: "unknown@mail.com", Password: "secret-password"},
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return((*model.User)(nil), nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+			},
+		},
+		{
+			name:    "error when password is wrong",
+			payload: &transport.LoginReq{Email: "test@mail.com", Password: "wrong-password"},
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(mockUser, nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+			},
+		},
+		{
+			name:    "error when account is disabled",
+			payload: &transport.LoginReq{Email: "test@mail.com", Password: "secret-password"},
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(&disabledUser, nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrAccountDisabled)
+			},
+		},
+		{
+			name:    "success",
+			payload: &transport.LoginReq{Email: "test@mail.com", Password: "secret-password"},
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(mockUser, nil)
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *model.RefreshToken) bool {
+					return token.UserID == 1 && token.ExpiresAt.After(time.Now())
+				})).Return(nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, res)
+				assert.NotEmpty(t, res.RefreshToken)
+
+				claims, err := pkg.ParseJWT(res.AccessToken, "secret")
+				require.NoError(t, err)
+				assert.Equal(t, "hr", claims.Role)
+				assert.Equal(t, 3, claims.EmployeeID)
+				assert.Equal(t, "1", claims.Subject)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(userRepository, transactor)
+
+			u := NewUseCaseAuth(userRepository, transactor, config.Config{JWTSecret: "secret"})
+			result, err := u.Login(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+
+	payload := &transport.RefreshTokenReq{RefreshToken: "refresh-token"}
+	tokenHash := pkg.HashToken("refresh-token")
+
+	activeToken := &model.RefreshToken{ID: 10, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}
+	expiredToken := &model.RefreshToken{ID: 10, UserID: 1, TokenHash: tokenHash, ExpiresAt: time.Now().Add(-time.Hour)}
+
+	testCases := []struct {
+		name      string
+		buildStub func(
+			userRepo *userRepoMock.DBMock,
+			transactor *txMock.TransactorMock,
+		)
+		checkReturn func(res *transport.TokenRes, err error)
+	}{
+		{
+			name: "error when token is unknown",
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("GetRefreshToken", mock.Anything, tokenHash).Return((*model.RefreshToken)(nil), nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+			},
+		},
+		{
+			name: "error when token is expired",
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("GetRefreshToken", mock.Anything, tokenHash).Return(expiredToken, nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+			},
+		},
+		{
+			name: "revoke every session when a rotated token is reused",
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("GetRefreshToken", mock.Anything, tokenHash).Return(activeToken, nil)
+				userRepo.On("RevokeRefreshToken", mock.Anything, 10).Return(false, nil)
+				userRepo.On("RevokeUserRefreshTokens", mock.Anything, 1).Return(nil).Once()
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+			},
+		},
+		{
+			name: "error when account is disabled",
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				disabledAt := time.Now()
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("GetRefreshToken", mock.Anything, tokenHash).Return(activeToken, nil)
+				userRepo.On("RevokeRefreshToken", mock.Anything, 10).Return(true, nil)
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Role: "viewer", DisabledAt: &disabledAt}, nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, ErrAccountDisabled)
+			},
+		},
+		{
+			name: "success rotates the refresh token",
+			buildStub: func(userRepo *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				userRepo.On("GetRefreshToken", mock.Anything, tokenHash).Return(activeToken, nil)
+				userRepo.On("RevokeRefreshToken", mock.Anything, 10).Return(true, nil)
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Email: "test@mail.com", Role: "viewer"}, nil)
+				userRepo.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(res *transport.TokenRes, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, res)
+				assert.NotEqual(t, "refresh-token", res.RefreshToken)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(userRepository, transactor)
+
+			u := NewUseCaseAuth(userRepository, transactor, config.Config{JWTSecret: "secret"})
+			result, err := u.Refresh(context.TODO(), payload)
+
+			tc.checkReturn(result, err)
+			userRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+
+	payload := &transport.RefreshTokenReq{RefreshToken: "refresh-token"}
+	activeToken := &model.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+	testCases := []struct {
+		name      string
+		buildStub func(
+			userRepo *userRepoMock.DBMock,
+		)
+		checkReturn func(err error)
+	}{
+		{
+			name: "error when get refresh token",
+			buildStub: func(userRepo *userRepoMock.DBMock) {
+				userRepo.On("GetRefreshToken", mock.Anything, mock.Anything).Return((*model.RefreshToken)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "unknown token is ignored",
+			buildStub: func(userRepo *userRepoMock.DBMock) {
+				userRepo.On("GetRefreshToken", mock.Anything, mock.Anything).Return((*model.RefreshToken)(nil), nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(userRepo *userRepoMock.DBMock) {
+				userRepo.On("GetRefreshToken", mock.Anything, mock.Anything).Return(activeToken, nil)
+				userRepo.On("RevokeRefreshToken", mock.Anything, 10).Return(true, nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := new(userRepoMock.DBMock)
+			tc.buildStub(userRepository)
+
+			u := NewUseCaseAuth(userRepository, new(txMock.TransactorMock), config.Config{JWTSecret: "secret"})
+			err := u.Logout(context.TODO(), payload)
+
+			tc.checkReturn(err)
+		})
+	}
+}
+
+func TestEnsureAdmin(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		password    string
+		buildStub   func(userRepo *userRepoMock.DBMock)
+		checkReturn func(err error)
+	}{
+		{
+			name:      "error when password is empty",
+			password:  "",
+			buildStub: func(userRepo *userRepoMock.DBMock) {},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrAdminPasswordUnset)
+			},
+		},
+		{
+			name:      "error when password is the placeholder",
+			password:  config.PlaceholderPassword,
+			buildStub: func(userRepo *userRepoMock.DBMock) {},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrAdminPasswordUnset)
+			},
+		},
+		{
+			name:     "existing admin is kept",
+			password: "a-real-admin-password",
+			buildStub: func(userRepo *userRepoMock.DBMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, "admin@mail.com").Return(&model.User{ID: 1}, nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:     "success",
+			password: "a-real-admin-password",
+			buildStub: func(userRepo *userRepoMock.DBMock) {
+				userRepo.On("GetUserByEmail", mock.Anything, "admin@mail.com").Return((*model.User)(nil), nil)
+				userRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(user *model.User) bool {
+					return user.Role == constant.RoleAdmin && pkg.ComparePassword(user.PasswordHash, "a-real-admin-password") == nil
+				})).Return(1, nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := new(userRepoMock.DBMock)
+			tc.buildStub(userRepository)
+
+			u := NewUseCaseAuth(userRepository, new(txMock.TransactorMock), config.Config{JWTSecret: "secret"})
+			err := u.EnsureAdmin(context.TODO(), "admin@mail.com", tc.password)
+
+			tc.checkReturn(err)
+			userRepository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuthUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *AuthUseCaseMock) Login(ctx context.Context, payload *transport.LoginReq) (*transport.TokenRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.TokenRes), args.Error(1)
+}
+
+func (m *AuthUseCaseMock) Refresh(ctx context.Context, payload *transport.RefreshTokenReq) (*transport.TokenRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.TokenRes), args.Error(1)
+}
+
+func (m *AuthUseCaseMock) Logout(ctx context.Context, payload *transport.RefreshTokenReq) error {
+	args := m.Called(ctx, payload)
+
+	return args.Error(0)
+}
+
+func (m *AuthUseCaseMock) EnsureAdmin(ctx context.Context, email string, password string) error {
+	args := m.Called(ctx, email, password)
+
+	return args.Error(0)
+}
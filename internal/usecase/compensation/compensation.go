@@ -0,0 +1,180 @@
+package compensation
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository"
+	cRepo "employee/internal/repository/compensation"
+	eRepo "employee/internal/repository/employee"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Compensation")
+)
+
+// openEnded stands for the missing effective_to of a current compensation.
+const openEnded = "9999-12-31"
+
+var (
+	ErrEmployeeNotFound = apperror.NotFound("employee not found")
+	ErrInvalidRange     = apperror.Validation("effective_to", "effective_to must not be before effective_from")
+	ErrOverlap          = apperror.Conflict("effective_from", "compensation overlaps an existing compensation")
+)
+
+type UseCaseCompensation interface {
+	CreateCompensation(ctx context.Context, payload *transport.CreateCompensationReq) (*transport.CompensationRes, error)
+	GetCompensations(ctx context.Context, employeeID int) (*transport.ListCompensations, error)
+}
+
+type useCaseCompensation struct {
+	compensationRepo cRepo.CompensationRepo
+	employeeRepo     eRepo.UserRepo
+	transactor       repository.Transactor
+}
+
+func NewUseCaseCompensation(compensationRepo cRepo.CompensationRepo, employeeRepo eRepo.UserRepo, transactor repository.Transactor) UseCaseCompensation {
+	return &useCaseCompensation{compensationRepo: compensationRepo, employeeRepo: employeeRepo, transactor: transactor}
+}
+
+// CreateCompensation adds a compensation to the employee. A current
+// compensation that started earlier ends the day before the new one starts,
+// any other overlap with an existing range is rejected.
+func (u *useCaseCompensation) CreateCompensation(ctx context.Context, payload *transport.CreateCompensationReq) (*transport.CompensationRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreateCompensation")
+
+	if payload.EffectiveTo != nil && *payload.EffectiveTo < payload.EffectiveFrom {
+		return nil, ErrInvalidRange
+	}
+
+	compensation := &model.Compensation{
+		EmployeeID:    payload.EmployeeID,
+		Amount:        payload.Amount,
+		Currency:      payload.Currency,
+		PayFrequency:  payload.PayFrequency,
+		EffectiveFrom: payload.EffectiveFrom,
+		EffectiveTo:   payload.EffectiveTo,
+	}
+
+	if claims := pkg.ClaimsFromContext(ctx); claims != nil {
+		compensation.CreatedBy = claims.Subject
+	}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		found, err := u.compensationRepo.LockEmployee(ctx, payload.EmployeeID)
+		if err != nil {
+			uLog.Errorf("error when call compensationRepo.LockEmployee got %s", err.Error())
+			return err
+		}
+
+		if !found {
+			uLog.Errorf("error when call compensationRepo.LockEmployee got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		existing, err := u.compensationRepo.GetCompensations(ctx, payload.EmployeeID)
+		if err != nil {
+			uLog.Errorf("error when call compensationRepo.GetCompensations got %s", err.Error())
+			return err
+		}
+
+		var current *model.Compensation
+		for _, c := range existing {
+			if c.EffectiveTo == nil && c.EffectiveFrom < compensation.EffectiveFrom {
+				current = c
+				end := dayBefore(compensation.EffectiveFrom)
+				current.EffectiveTo = &end
+				break
+			}
+		}
+
+		for _, c := range existing {
+			if overlaps(c, compensation) {
+				uLog.Errorf("compensation overlaps compensation %d", c.ID)
+				return ErrOverlap
+			}
+		}
+
+		if current != nil {
+			if err := u.compensationRepo.EndCompensation(ctx, current.ID, *current.EffectiveTo); err != nil {
+				uLog.Errorf("error when call compensationRepo.EndCompensation got %s", err.Error())
+				return err
+			}
+		}
+
+		if err := u.compensationRepo.CreateCompensation(ctx, compensation); err != nil {
+			uLog.Errorf("error when call compensationRepo.CreateCompensation got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCompensationRes(compensation), nil
+}
+
+func (u *useCaseCompensation) GetCompensations(ctx context.Context, employeeID int) (*transport.ListCompensations, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetCompensations")
+
+	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+	if err != nil {
+		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+		return nil, err
+	}
+
+	if employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	compensations, err := u.compensationRepo.GetCompensations(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when call compensationRepo.GetCompensations got %s", err.Error())
+		return nil, err
+	}
+
+	res := make([]*transport.CompensationRes, 0, len(compensations))
+	for _, compensation := range compensations {
+		res = append(res, toCompensationRes(compensation))
+	}
+
+	return &transport.ListCompensations{Compensations: res}, nil
+}
+
+// overlaps reports whether two inclusive effective ranges share a day.
+func overlaps(a *model.Compensation, b *model.Compensation) bool {
+	return a.EffectiveFrom <= effectiveTo(b) && b.EffectiveFrom <= effectiveTo(a)
+}
+
+func effectiveTo(compensation *model.Compensation) string {
+	if compensation.EffectiveTo == nil {
+		return openEnded
+	}
+
+	return *compensation.EffectiveTo
+}
+
+// dayBefore returns the day before a date validated as YYYY-MM-DD.
+func dayBefore(date string) string {
+	day, _ := time.Parse("2006-01-02", date)
+	return day.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+func toCompensationRes(compensation *model.Compensation) *transport.CompensationRes {
+	return &transport.CompensationRes{
+		ID:            compensation.ID,
+		Amount:        compensation.Amount,
+		Currency:      compensation.Currency,
+		PayFrequency:  compensation.PayFrequency,
+		EffectiveFrom: compensation.EffectiveFrom,
+		EffectiveTo:   compensation.EffectiveTo,
+		CreatedBy:     compensation.CreatedBy,
+		CreatedAt:     compensation.CreatedAt,
+	}
+}
@@ -0,0 +1,198 @@
+package compensation
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	compensationRepoMock "employee/internal/repository/compensation/mock"
+	employeeRepoMock "employee/internal/repository/employee/mock"
+	txMock "employee/internal/repository/mock"
+	"employee/internal/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateCompensation(t *testing.T) {
+	endOf2023 := "2023-12-31"
+	endOfMarch := "2024-03-31"
+
+	current := func() *model.Compensation {
+		return &model.Compensation{ID: 10, EmployeeID: 3, EffectiveFrom: "2023-05-01"}
+	}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.CreateCompensationReq
+		buildStub   func(compensationRepo *compensationRepoMock.DBMock)
+		checkReturn func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock)
+	}{
+		{
+			name:      "error when effective_to is before effective_from",
+			payload:   &transport.CreateCompensationReq{EmployeeID: 3, EffectiveFrom: "2024-01-01", EffectiveTo: &endOf2023},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrInvalidRange)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when employee not found",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, EffectiveFrom: "2024-01-01"},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(false, nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+			},
+		},
+		{
+			name:    "error when it starts on the day the current one starts",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, EffectiveFrom: "2023-05-01"},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{current()}, nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrOverlap)
+				compensationRepo.AssertNotCalled(t, "EndCompensation", mock.Anything, mock.Anything, mock.Anything)
+				compensationRepo.AssertNotCalled(t, "CreateCompensation", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "error when it overlaps a closed range",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, EffectiveFrom: "2024-02-01", EffectiveTo: &endOfMarch},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{
+					{ID: 11, EffectiveFrom: "2024-03-01", EffectiveTo: &endOfMarch},
+				}, nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrOverlap)
+			},
+		},
+		{
+			name:    "error when an open range would cover a later one",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, EffectiveFrom: "2023-01-01"},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{current()}, nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrOverlap)
+			},
+		},
+		{
+			name:    "success ends the current compensation the day before",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, Amount: 9000000, Currency: "USD", PayFrequency: "annual", EffectiveFrom: "2024-01-01"},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{current()}, nil)
+				compensationRepo.On("EndCompensation", mock.Anything, int64(10), "2023-12-31").Return(nil)
+				compensationRepo.On("CreateCompensation", mock.Anything, mock.MatchedBy(func(compensation *model.Compensation) bool {
+					return compensation.Amount == 9000000 && compensation.EffectiveTo == nil && compensation.CreatedBy == "1"
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Compensation).ID = 11
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(11), result.ID)
+				assert.Nil(t, result.EffectiveTo)
+			},
+		},
+		{
+			name:    "success backfills a range before the current one",
+			payload: &transport.CreateCompensationReq{EmployeeID: 3, Amount: 8000000, Currency: "USD", PayFrequency: "annual", EffectiveFrom: "2022-05-01", EffectiveTo: &endOf2023},
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock) {
+				compensationRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{
+					{ID: 10, EmployeeID: 3, EffectiveFrom: "2024-01-01"},
+				}, nil)
+				compensationRepo.On("CreateCompensation", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.CompensationRes, err error, compensationRepo *compensationRepoMock.DBMock) {
+				assert.NoError(t, err)
+				compensationRepo.AssertNotCalled(t, "EndCompensation", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			compensationRepository := new(compensationRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(compensationRepository)
+
+			ctx := pkg.ContextWithClaims(context.TODO(), &pkg.JWTClaim{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+
+			u := NewUseCaseCompensation(compensationRepository, new(employeeRepoMock.DBMock), transactor)
+			result, err := u.CreateCompensation(ctx, tc.payload)
+
+			tc.checkReturn(result, err, compensationRepository)
+		})
+	}
+}
+
+func TestGetCompensations(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(compensationRepo *compensationRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.ListCompensations, err error)
+	}{
+		{
+			name: "error when employee not found",
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.ListCompensations, err error) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "error when get compensations",
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListCompensations, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(compensationRepo *compensationRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				compensationRepo.On("GetCompensations", mock.Anything, 3).Return([]*model.Compensation{
+					{ID: 11, Amount: 9000000, EffectiveFrom: "2024-01-01"},
+				}, nil)
+			},
+			checkReturn: func(result *transport.ListCompensations, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Compensations, 1)
+				assert.Equal(t, int64(9000000), result.Compensations[0].Amount)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			compensationRepository := new(compensationRepoMock.DBMock)
+			employeeRepository := new(employeeRepoMock.DBMock)
+			tc.buildStub(compensationRepository, employeeRepository)
+
+			u := NewUseCaseCompensation(compensationRepository, employeeRepository, new(txMock.TransactorMock))
+			result, err := u.GetCompensations(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type CompensationUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *CompensationUseCaseMock) CreateCompensation(ctx context.Context, payload *transport.CreateCompensationReq) (*transport.CompensationRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.CompensationRes), args.Error(1)
+}
+
+func (m *CompensationUseCaseMock) GetCompensations(ctx context.Context, employeeID int) (*transport.ListCompensations, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.ListCompensations), args.Error(1)
+}
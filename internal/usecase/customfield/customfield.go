@@ -0,0 +1,263 @@
+package customfield
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository"
+	aRepo "employee/internal/repository/audit"
+	cRepo "employee/internal/repository/customfield"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.CustomField")
+)
+
+var (
+	ErrCustomFieldNotFound = apperror.NotFound("custom field not found")
+	ErrOptionsRequired     = apperror.Validation("options", "options are required for an enum field")
+	ErrOptionsNotAllowed   = apperror.Validation("options", "options are only allowed for an enum field")
+	ErrOptionInUse         = apperror.Conflict("options", "a removed option is still held by employees")
+)
+
+type UseCaseCustomField interface {
+	CreateCustomField(ctx context.Context, payload *transport.CreateCustomFieldReq) (*transport.CustomFieldRes, error)
+	GetCustomFields(ctx context.Context) (*transport.ListCustomFields, error)
+	GetCustomFieldByID(ctx context.Context, fieldID int) (*transport.CustomFieldRes, error)
+	UpdateCustomField(ctx context.Context, payload *transport.UpdateCustomFieldReq) (*transport.CustomFieldRes, error)
+	DeleteCustomField(ctx context.Context, fieldID int) error
+}
+
+type useCaseCustomField struct {
+	customFieldRepo cRepo.CustomFieldRepo
+	auditRepo       aRepo.AuditRepo
+	transactor      repository.Transactor
+}
+
+func NewUseCaseCustomField(customFieldRepo cRepo.CustomFieldRepo, auditRepo aRepo.AuditRepo, transactor repository.Transactor) UseCaseCustomField {
+	return &useCaseCustomField{customFieldRepo: customFieldRepo, auditRepo: auditRepo, transactor: transactor}
+}
+
+// employeeValues is the part of an employee that removing a custom field
+// changes, as it is audited.
+type employeeValues struct {
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	Version      int                    `json:"version"`
+}
+
+func (u *useCaseCustomField) CreateCustomField(ctx context.Context, payload *transport.CreateCustomFieldReq) (*transport.CustomFieldRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreateCustomField")
+
+	field := &model.CustomField{
+		Name:     payload.Name,
+		Type:     payload.Type,
+		Required: payload.Required,
+		Options:  payload.Options,
+	}
+
+	if err := checkOptions(field); err != nil {
+		uLog.Errorf("error when check options got %s", err.Error())
+		return nil, err
+	}
+
+	if err := u.customFieldRepo.CreateCustomField(ctx, field); err != nil {
+		uLog.Errorf("error when call customFieldRepo.CreateCustomField got %s", err.Error())
+		return nil, err
+	}
+
+	return toCustomFieldRes(field), nil
+}
+
+func (u *useCaseCustomField) GetCustomFields(ctx context.Context) (*transport.ListCustomFields, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetCustomFields")
+
+	fields, err := u.customFieldRepo.GetCustomFields(ctx)
+	if err != nil {
+		uLog.Errorf("error when call customFieldRepo.GetCustomFields got %s", err.Error())
+		return nil, err
+	}
+
+	res := make([]*transport.CustomFieldRes, 0, len(fields))
+	for _, field := range fields {
+		res = append(res, toCustomFieldRes(field))
+	}
+
+	return &transport.ListCustomFields{CustomFields: res}, nil
+}
+
+func (u *useCaseCustomField) GetCustomFieldByID(ctx context.Context, fieldID int) (*transport.CustomFieldRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetCustomFieldByID")
+
+	field, err := u.customFieldRepo.GetCustomFieldByID(ctx, fieldID)
+	if err != nil {
+		uLog.Errorf("error when call customFieldRepo.GetCustomFieldByID got %s", err.Error())
+		return nil, err
+	}
+
+	if field == nil {
+		return nil, ErrCustomFieldNotFound
+	}
+
+	return toCustomFieldRes(field), nil
+}
+
+// UpdateCustomField changes whether the field is required and its options.
+// An option can only be removed once no employee holds it. Making a field
+// required does not touch the employees without a value, they get one on
+// their next update.
+func (u *useCaseCustomField) UpdateCustomField(ctx context.Context, payload *transport.UpdateCustomFieldReq) (*transport.CustomFieldRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "UpdateCustomField")
+
+	var res *transport.CustomFieldRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		field, err := u.customFieldRepo.GetCustomFieldByID(ctx, payload.ID)
+		if err != nil {
+			uLog.Errorf("error when call customFieldRepo.GetCustomFieldByID got %s", err.Error())
+			return err
+		}
+
+		if field == nil {
+			uLog.Errorf("error when call customFieldRepo.GetCustomFieldByID got %s", ErrCustomFieldNotFound.Error())
+			return ErrCustomFieldNotFound
+		}
+
+		removed := removedOptions(field.Options, payload.Options)
+
+		field.Required = payload.Required
+		field.Options = payload.Options
+
+		if err := checkOptions(field); err != nil {
+			uLog.Errorf("error when check options got %s", err.Error())
+			return err
+		}
+
+		if len(removed) > 0 {
+			holders, err := u.customFieldRepo.CountValues(ctx, field.Name, removed)
+			if err != nil {
+				uLog.Errorf("error when call customFieldRepo.CountValues got %s", err.Error())
+				return err
+			}
+
+			if holders > 0 {
+				uLog.Errorf("removed options of custom field %d are held by %d employees", field.ID, holders)
+				return ErrOptionInUse
+			}
+		}
+
+		if err := u.customFieldRepo.UpdateCustomField(ctx, field); err != nil {
+			uLog.Errorf("error when call customFieldRepo.UpdateCustomField got %s", err.Error())
+			return err
+		}
+
+		res = toCustomFieldRes(field)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteCustomField deletes the definition together with the values the
+// employees hold for it, each employee that held one is audited as updated.
+func (u *useCaseCustomField) DeleteCustomField(ctx context.Context, fieldID int) error {
+	uLog := logger.WithContext(ctx).WithField("function", "DeleteCustomField")
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		field, err := u.customFieldRepo.GetCustomFieldByID(ctx, fieldID)
+		if err != nil {
+			uLog.Errorf("error when call customFieldRepo.GetCustomFieldByID got %s", err.Error())
+			return err
+		}
+
+		if field == nil {
+			uLog.Errorf("error when call customFieldRepo.GetCustomFieldByID got %s", ErrCustomFieldNotFound.Error())
+			return ErrCustomFieldNotFound
+		}
+
+		if err := u.customFieldRepo.DeleteCustomField(ctx, fieldID); err != nil {
+			uLog.Errorf("error when call customFieldRepo.DeleteCustomField got %s", err.Error())
+			return err
+		}
+
+		employees, err := u.customFieldRepo.RemoveValues(ctx, field.Name)
+		if err != nil {
+			uLog.Errorf("error when call customFieldRepo.RemoveValues got %s", err.Error())
+			return err
+		}
+
+		for _, employee := range employees {
+			after := &employeeValues{CustomFields: map[string]interface{}{}, Version: employee.Version + 1}
+			for name, value := range employee.CustomFields {
+				if name != field.Name {
+					after.CustomFields[name] = value
+				}
+			}
+
+			audit, err := pkg.NewAudit(ctx, model.AuditActionUpdate, employee.ID,
+				&employeeValues{CustomFields: employee.CustomFields, Version: employee.Version}, after)
+			if err != nil {
+				return err
+			}
+
+			if err := u.auditRepo.CreateAudit(ctx, audit); err != nil {
+				uLog.Errorf("error when call auditRepo.CreateAudit got %s", err.Error())
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkOptions requires options on an enum field and rejects them on the
+// other types.
+func checkOptions(field *model.CustomField) error {
+	if field.Type == model.CustomFieldTypeEnum && len(field.Options) == 0 {
+		return ErrOptionsRequired
+	}
+
+	if field.Type != model.CustomFieldTypeEnum && len(field.Options) > 0 {
+		return ErrOptionsNotAllowed
+	}
+
+	return nil
+}
+
+func removedOptions(current []string, next []string) []string {
+	kept := make(map[string]bool, len(next))
+	for _, option := range next {
+		kept[option] = true
+	}
+
+	var removed []string
+	for _, option := range current {
+		if !kept[option] {
+			removed = append(removed, option)
+		}
+	}
+
+	return removed
+}
+
+func toCustomFieldRes(field *model.CustomField) *transport.CustomFieldRes {
+	options := field.Options
+	if options == nil {
+		options = []string{}
+	}
+
+	return &transport.CustomFieldRes{
+		ID:        field.ID,
+		Name:      field.Name,
+		Type:      field.Type,
+		Required:  field.Required,
+		Options:   options,
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}
+}
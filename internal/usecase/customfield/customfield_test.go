@@ -0,0 +1,266 @@
+package customfield
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	auditRepoMock "employee/internal/repository/audit/mock"
+	customFieldRepoMock "employee/internal/repository/customfield/mock"
+	txMock "employee/internal/repository/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestCreateCustomField(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		payload     *transport.CreateCustomFieldReq
+		buildStub   func(customFieldRepo *customFieldRepoMock.DBMock)
+		checkReturn func(result *transport.CustomFieldRes, err error)
+	}{
+		{
+			name:      "error when enum has no options",
+			payload:   &transport.CreateCustomFieldReq{Name: "shirt_size", Type: model.CustomFieldTypeEnum},
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.ErrorIs(t, err, ErrOptionsRequired)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:      "error when text has options",
+			payload:   &transport.CreateCustomFieldReq{Name: "cost_center", Type: model.CustomFieldTypeText, Options: []string{"CC-1"}},
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.ErrorIs(t, err, ErrOptionsNotAllowed)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when name is taken",
+			payload: &transport.CreateCustomFieldReq{Name: "cost_center", Type: model.CustomFieldTypeText},
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {
+				customFieldRepo.On("CreateCustomField", mock.Anything, mock.Anything).Return(apperror.Conflict("name", "name already exists"))
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "success",
+			payload: &transport.CreateCustomFieldReq{Name: "cost_center", Type: model.CustomFieldTypeText, Required: true},
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {
+				customFieldRepo.On("CreateCustomField", mock.Anything, mock.MatchedBy(func(field *model.CustomField) bool {
+					return field.Name == "cost_center" && field.Type == model.CustomFieldTypeText && field.Required
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.CustomField).ID = 1
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, result.ID)
+				assert.Equal(t, []string{}, result.Options)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			tc.buildStub(customFieldRepository)
+
+			u := NewUseCaseCustomField(customFieldRepository, new(auditRepoMock.DBMock), new(txMock.TransactorMock))
+			result, err := u.CreateCustomField(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetCustomFieldByID(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(customFieldRepo *customFieldRepoMock.DBMock)
+		checkReturn func(result *transport.CustomFieldRes, err error)
+	}{
+		{
+			name: "error when custom field not found",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return((*model.CustomField)(nil), nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.ErrorIs(t, err, ErrCustomFieldNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock) {
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).
+					Return(&model.CustomField{ID: 1, Name: "shirt_size", Type: model.CustomFieldTypeEnum, Options: []string{"S", "M"}}, nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"S", "M"}, result.Options)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			tc.buildStub(customFieldRepository)
+
+			u := NewUseCaseCustomField(customFieldRepository, new(auditRepoMock.DBMock), new(txMock.TransactorMock))
+			result, err := u.GetCustomFieldByID(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestUpdateCustomField(t *testing.T) {
+
+	payload := &transport.UpdateCustomFieldReq{ID: 1, Required: true, Options: []string{"S", "M"}}
+	stored := func() *model.CustomField {
+		return &model.CustomField{ID: 1, Name: "shirt_size", Type: model.CustomFieldTypeEnum, Options: []string{"S", "M", "L"}}
+	}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(customFieldRepo *customFieldRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(result *transport.CustomFieldRes, err error, customFieldRepo *customFieldRepoMock.DBMock)
+	}{
+		{
+			name: "error when custom field not found",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return((*model.CustomField)(nil), nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error, customFieldRepo *customFieldRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrCustomFieldNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "error when a removed option is held",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return(stored(), nil)
+				customFieldRepo.On("CountValues", mock.Anything, "shirt_size", []string{"L"}).Return(2, nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error, customFieldRepo *customFieldRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrOptionInUse)
+				assert.Nil(t, result)
+				customFieldRepo.AssertNotCalled(t, "UpdateCustomField", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return(stored(), nil)
+				customFieldRepo.On("CountValues", mock.Anything, "shirt_size", []string{"L"}).Return(0, nil)
+				customFieldRepo.On("UpdateCustomField", mock.Anything, mock.MatchedBy(func(field *model.CustomField) bool {
+					return field.Required && len(field.Options) == 2
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.CustomFieldRes, err error, customFieldRepo *customFieldRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.True(t, result.Required)
+				assert.Equal(t, []string{"S", "M"}, result.Options)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(customFieldRepository, transactor)
+
+			u := NewUseCaseCustomField(customFieldRepository, new(auditRepoMock.DBMock), transactor)
+			result, err := u.UpdateCustomField(context.TODO(), payload)
+
+			tc.checkReturn(result, err, customFieldRepository)
+		})
+	}
+}
+
+func TestDeleteCustomField(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(err error, customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock)
+	}{
+		{
+			name: "error when custom field not found",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return((*model.CustomField)(nil), nil)
+			},
+			checkReturn: func(err error, customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrCustomFieldNotFound)
+			},
+		},
+		{
+			name: "error when audit the removed values",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return(&model.CustomField{ID: 1, Name: "cost_center"}, nil)
+				customFieldRepo.On("DeleteCustomField", mock.Anything, 1).Return(nil)
+				customFieldRepo.On("RemoveValues", mock.Anything, "cost_center").
+					Return([]*model.Employee{{ID: 3, CustomFields: map[string]interface{}{"cost_center": "CC-12"}, Version: 2}}, nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(err error, customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success audits every employee that held a value",
+			buildStub: func(customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				customFieldRepo.On("GetCustomFieldByID", mock.Anything, 1).Return(&model.CustomField{ID: 1, Name: "cost_center"}, nil)
+				customFieldRepo.On("DeleteCustomField", mock.Anything, 1).Return(nil)
+				customFieldRepo.On("RemoveValues", mock.Anything, "cost_center").Return([]*model.Employee{
+					{ID: 3, CustomFields: map[string]interface{}{"cost_center": "CC-12", "remote": true}, Version: 2},
+					{ID: 4, CustomFields: map[string]interface{}{"cost_center": "CC-7"}, Version: 5},
+				}, nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.EmployeeID == 3 && audit.Action == model.AuditActionUpdate &&
+						string(audit.Diff) == `{"custom_fields":{"from":{"cost_center":"CC-12","remote":true},"to":{"remote":true}},"version":{"from":2,"to":3}}`
+				})).Return(nil).Once()
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.EmployeeID == 4 && string(audit.After) == `{"version":6}`
+				})).Return(nil).Once()
+			},
+			checkReturn: func(err error, customFieldRepo *customFieldRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				assert.NoError(t, err)
+				customFieldRepo.AssertCalled(t, "RemoveValues", mock.Anything, "cost_center")
+				auditRepo.AssertExpectations(t)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(customFieldRepository, auditRepository, transactor)
+
+			u := NewUseCaseCustomField(customFieldRepository, auditRepository, transactor)
+			err := u.DeleteCustomField(context.TODO(), 1)
+
+			tc.checkReturn(err, customFieldRepository, auditRepository)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type CustomFieldUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *CustomFieldUseCaseMock) CreateCustomField(ctx context.Context, payload *transport.CreateCustomFieldReq) (*transport.CustomFieldRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.CustomFieldRes), args.Error(1)
+}
+
+func (m *CustomFieldUseCaseMock) GetCustomFields(ctx context.Context) (*transport.ListCustomFields, error) {
+	args := m.Called(ctx)
+
+	return args.Get(0).(*transport.ListCustomFields), args.Error(1)
+}
+
+func (m *CustomFieldUseCaseMock) GetCustomFieldByID(ctx context.Context, fieldID int) (*transport.CustomFieldRes, error) {
+	args := m.Called(ctx, fieldID)
+
+	return args.Get(0).(*transport.CustomFieldRes), args.Error(1)
+}
+
+func (m *CustomFieldUseCaseMock) UpdateCustomField(ctx context.Context, payload *transport.UpdateCustomFieldReq) (*transport.CustomFieldRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.CustomFieldRes), args.Error(1)
+}
+
+func (m *CustomFieldUseCaseMock) DeleteCustomField(ctx context.Context, fieldID int) error {
+	args := m.Called(ctx, fieldID)
+
+	return args.Error(0)
+}
@@ -0,0 +1,194 @@
+package department
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	dRepo "employee/internal/repository/department"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Department")
+)
+
+const (
+	defaultPageLimit = 20
+)
+
+var (
+	ErrDepartmentNotFound = apperror.NotFound("department not found")
+)
+
+type UseCaseDepartment interface {
+	CreateDepartment(ctx context.Context, payload *transport.CreateDepartmentReq) (*transport.DepartmentRes, error)
+	GetDepartments(ctx context.Context, payload *transport.ListDepartmentsReq) (*transport.ListDepartments, error)
+	GetDepartmentByID(ctx context.Context, departmentID int) (*transport.DepartmentRes, error)
+	UpdateDepartment(ctx context.Context, payload *transport.UpdateDepartmentReq) (*transport.DepartmentRes, error)
+	DeleteDepartment(ctx context.Context, departmentID int) error
+}
+
+type useCaseDepartment struct {
+	departmentRepo dRepo.DepartmentRepo
+	transactor     repository.Transactor
+}
+
+func NewUseCaseDepartment(departmentRepo dRepo.DepartmentRepo, transactor repository.Transactor) UseCaseDepartment {
+	return &useCaseDepartment{departmentRepo: departmentRepo, transactor: transactor}
+}
+
+func (u *useCaseDepartment) CreateDepartment(ctx context.Context, payload *transport.CreateDepartmentReq) (*transport.DepartmentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreateDepartment")
+
+	department := &model.Department{
+		Name:        payload.Name,
+		Description: payload.Description,
+	}
+
+	if err := u.departmentRepo.CreateDepartment(ctx, department); err != nil {
+		uLog.Errorf("error when call departmentRepo.CreateDepartment got %s", err.Error())
+		return nil, err
+	}
+
+	return toDepartmentRes(department), nil
+}
+
+func (u *useCaseDepartment) GetDepartments(ctx context.Context, payload *transport.ListDepartmentsReq) (*transport.ListDepartments, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetDepartments")
+
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	filter := &model.DepartmentFilter{Name: payload.Name}
+
+	total, err := u.departmentRepo.CountDepartments(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call departmentRepo.CountDepartments got %s", err.Error())
+		return nil, err
+	}
+
+	filter.Limit = limit
+	filter.Offset = payload.Offset
+
+	departments, err := u.departmentRepo.GetDepartments(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call departmentRepo.GetDepartments got %s", err.Error())
+		return nil, err
+	}
+
+	departmentsResData := make([]*transport.DepartmentRes, 0)
+	for _, department := range departments {
+		departmentsResData = append(departmentsResData, toDepartmentRes(department))
+	}
+
+	return &transport.ListDepartments{
+		Departments: departmentsResData,
+		Pagination: &transport.Pagination{
+			Total:  total,
+			Limit:  limit,
+			Offset: payload.Offset,
+		},
+	}, nil
+}
+
+func (u *useCaseDepartment) GetDepartmentByID(ctx context.Context, departmentID int) (*transport.DepartmentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetDepartmentByID")
+
+	department, err := u.departmentRepo.GetDepartmentByID(ctx, departmentID)
+	if err != nil {
+		uLog.Errorf("error when call departmentRepo.GetDepartmentByID got %s", err.Error())
+		return nil, err
+	}
+
+	if department == nil {
+		return nil, ErrDepartmentNotFound
+	}
+
+	return toDepartmentRes(department), nil
+}
+
+func (u *useCaseDepartment) UpdateDepartment(ctx context.Context, payload *transport.UpdateDepartmentReq) (*transport.DepartmentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "UpdateDepartment")
+
+	var res *transport.DepartmentRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		department, err := u.departmentRepo.GetDepartmentByID(ctx, payload.ID)
+		if err != nil {
+			uLog.Errorf("error when call departmentRepo.GetDepartmentByID got %s", err.Error())
+			return err
+		}
+
+		if department == nil {
+			uLog.Errorf("error when call departmentRepo.GetDepartmentByID got %s", ErrDepartmentNotFound.Error())
+			return ErrDepartmentNotFound
+		}
+
+		department.Name = payload.Name
+		department.Description = payload.Description
+
+		if err := u.departmentRepo.UpdateDepartment(ctx, department); err != nil {
+			uLog.Errorf("error when call departmentRepo.UpdateDepartment got %s", err.Error())
+			return err
+		}
+
+		res = toDepartmentRes(department)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteDepartment deletes a department nobody belongs to anymore, employees
+// have to be moved out of it first.
+func (u *useCaseDepartment) DeleteDepartment(ctx context.Context, departmentID int) error {
+	uLog := logger.WithContext(ctx).WithField("function", "DeleteDepartment")
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		department, err := u.departmentRepo.GetDepartmentByID(ctx, departmentID)
+		if err != nil {
+			uLog.Errorf("error when call departmentRepo.GetDepartmentByID got %s", err.Error())
+			return err
+		}
+
+		if department == nil {
+			uLog.Errorf("error when call departmentRepo.GetDepartmentByID got %s", ErrDepartmentNotFound.Error())
+			return ErrDepartmentNotFound
+		}
+
+		members, err := u.departmentRepo.CountMembers(ctx, departmentID)
+		if err != nil {
+			uLog.Errorf("error when call departmentRepo.CountMembers got %s", err.Error())
+			return err
+		}
+
+		if members > 0 {
+			uLog.Errorf("department %d still has %d members", departmentID, members)
+			return dRepo.ErrDepartmentHasMembers
+		}
+
+		if err := u.departmentRepo.DeleteDepartment(ctx, departmentID); err != nil {
+			uLog.Errorf("error when call departmentRepo.DeleteDepartment got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+func toDepartmentRes(department *model.Department) *transport.DepartmentRes {
+	return &transport.DepartmentRes{
+		ID:          department.ID,
+		Name:        department.Name,
+		Description: department.Description,
+		CreatedAt:   department.CreatedAt,
+		UpdatedAt:   department.UpdatedAt,
+	}
+}
@@ -0,0 +1,315 @@
+package department
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	dRepo "employee/internal/repository/department"
+	departmentRepoMock "employee/internal/repository/department/mock"
+	txMock "employee/internal/repository/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateDepartment(t *testing.T) {
+
+	payload := &transport.CreateDepartmentReq{Name: "Engineering", Description: "builds things"}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(departmentRepo *departmentRepoMock.DBMock)
+		checkReturn func(result *transport.DepartmentRes, err error)
+	}{
+		{
+			name: "error when name is taken",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("CreateDepartment", mock.Anything, mock.Anything).Return(apperror.Conflict("name", "name already exists"))
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("CreateDepartment", mock.Anything, mock.MatchedBy(func(department *model.Department) bool {
+					return department.Name == "Engineering" && department.Description == "builds things"
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Department).ID = 1
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, result.ID)
+				assert.Equal(t, "Engineering", result.Name)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			departmentRepository := new(departmentRepoMock.DBMock)
+			tc.buildStub(departmentRepository)
+
+			u := NewUseCaseDepartment(departmentRepository, new(txMock.TransactorMock))
+			result, err := u.CreateDepartment(context.TODO(), payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetDepartments(t *testing.T) {
+
+	mockDepartments := []*model.Department{
+		{ID: 1, Name: "Engineering"},
+		{ID: 2, Name: "Finance"},
+	}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.ListDepartmentsReq
+		buildStub   func(departmentRepo *departmentRepoMock.DBMock)
+		checkReturn func(result *transport.ListDepartments, err error)
+	}{
+		{
+			name:    "error when count departments",
+			payload: &transport.ListDepartmentsReq{},
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("CountDepartments", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListDepartments, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when get departments",
+			payload: &transport.ListDepartmentsReq{},
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("CountDepartments", mock.Anything, mock.Anything).Return(2, nil)
+				departmentRepo.On("GetDepartments", mock.Anything, mock.Anything).Return([]*model.Department(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListDepartments, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "success with default limit",
+			payload: &transport.ListDepartmentsReq{Name: "e"},
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("CountDepartments", mock.Anything, mock.Anything).Return(2, nil)
+				departmentRepo.On("GetDepartments", mock.Anything, mock.MatchedBy(func(filter *model.DepartmentFilter) bool {
+					return filter.Name == "e" && filter.Limit == defaultPageLimit
+				})).Return(mockDepartments, nil)
+			},
+			checkReturn: func(result *transport.ListDepartments, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Departments, 2)
+				assert.Equal(t, 2, result.Pagination.Total)
+				assert.Equal(t, defaultPageLimit, result.Pagination.Limit)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			departmentRepository := new(departmentRepoMock.DBMock)
+			tc.buildStub(departmentRepository)
+
+			u := NewUseCaseDepartment(departmentRepository, new(txMock.TransactorMock))
+			result, err := u.GetDepartments(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetDepartmentByID(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(departmentRepo *departmentRepoMock.DBMock)
+		checkReturn func(result *transport.DepartmentRes, err error)
+	}{
+		{
+			name: "error when get department",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return((*model.Department)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "error when department not found",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return((*model.Department)(nil), nil)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.ErrorIs(t, err, ErrDepartmentNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock) {
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1, Name: "Engineering"}, nil)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Engineering", result.Name)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			departmentRepository := new(departmentRepoMock.DBMock)
+			tc.buildStub(departmentRepository)
+
+			u := NewUseCaseDepartment(departmentRepository, new(txMock.TransactorMock))
+			result, err := u.GetDepartmentByID(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestUpdateDepartment(t *testing.T) {
+
+	payload := &transport.UpdateDepartmentReq{ID: 1, Name: "Platform", Description: "runs things"}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(result *transport.DepartmentRes, err error)
+	}{
+		{
+			name: "error when department not found",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return((*model.Department)(nil), nil)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.ErrorIs(t, err, ErrDepartmentNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "error when update department",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1, Name: "Engineering"}, nil)
+				departmentRepo.On("UpdateDepartment", mock.Anything, mock.Anything).Return(apperror.Conflict("name", "name already exists"))
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1, Name: "Engineering"}, nil)
+				departmentRepo.On("UpdateDepartment", mock.Anything, mock.MatchedBy(func(department *model.Department) bool {
+					return department.ID == 1 && department.Name == "Platform" && department.Description == "runs things"
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.DepartmentRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Platform", result.Name)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			departmentRepository := new(departmentRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(departmentRepository, transactor)
+
+			u := NewUseCaseDepartment(departmentRepository, transactor)
+			result, err := u.UpdateDepartment(context.TODO(), payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestDeleteDepartment(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(err error, departmentRepo *departmentRepoMock.DBMock)
+	}{
+		{
+			name: "error when department not found",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return((*model.Department)(nil), nil)
+			},
+			checkReturn: func(err error, departmentRepo *departmentRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrDepartmentNotFound)
+			},
+		},
+		{
+			name: "error when department still has members",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1}, nil)
+				departmentRepo.On("CountMembers", mock.Anything, 1).Return(2, nil)
+			},
+			checkReturn: func(err error, departmentRepo *departmentRepoMock.DBMock) {
+				assert.ErrorIs(t, err, dRepo.ErrDepartmentHasMembers)
+				departmentRepo.AssertNotCalled(t, "DeleteDepartment", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "error when count members",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1}, nil)
+				departmentRepo.On("CountMembers", mock.Anything, 1).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(err error, departmentRepo *departmentRepoMock.DBMock) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(departmentRepo *departmentRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				departmentRepo.On("GetDepartmentByID", mock.Anything, 1).Return(&model.Department{ID: 1}, nil)
+				departmentRepo.On("CountMembers", mock.Anything, 1).Return(0, nil)
+				departmentRepo.On("DeleteDepartment", mock.Anything, 1).Return(nil)
+			},
+			checkReturn: func(err error, departmentRepo *departmentRepoMock.DBMock) {
+				assert.NoError(t, err)
+				departmentRepo.AssertCalled(t, "DeleteDepartment", mock.Anything, 1)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			departmentRepository := new(departmentRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(departmentRepository, transactor)
+
+			u := NewUseCaseDepartment(departmentRepository, transactor)
+			err := u.DeleteDepartment(context.TODO(), 1)
+
+			tc.checkReturn(err, departmentRepository)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type DepartmentUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *DepartmentUseCaseMock) CreateDepartment(ctx context.Context, payload *transport.CreateDepartmentReq) (*transport.DepartmentRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.DepartmentRes), args.Error(1)
+}
+
+func (m *DepartmentUseCaseMock) GetDepartments(ctx context.Context, payload *transport.ListDepartmentsReq) (*transport.ListDepartments, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.ListDepartments), args.Error(1)
+}
+
+func (m *DepartmentUseCaseMock) GetDepartmentByID(ctx context.Context, departmentID int) (*transport.DepartmentRes, error) {
+	args := m.Called(ctx, departmentID)
+
+	return args.Get(0).(*transport.DepartmentRes), args.Error(1)
+}
+
+func (m *DepartmentUseCaseMock) UpdateDepartment(ctx context.Context, payload *transport.UpdateDepartmentReq) (*transport.DepartmentRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.DepartmentRes), args.Error(1)
+}
+
+func (m *DepartmentUseCaseMock) DeleteDepartment(ctx context.Context, departmentID int) error {
+	args := m.Called(ctx, departmentID)
+
+	return args.Error(0)
+}
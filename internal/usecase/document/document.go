@@ -0,0 +1,247 @@
+package document
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	dRepo "employee/internal/repository/document"
+	eRepo "employee/internal/repository/employee"
+	"employee/internal/storage"
+	"employee/internal/transport"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"time"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Document")
+)
+
+var (
+	ErrEmployeeNotFound = apperror.NotFound("employee not found")
+	ErrDocumentNotFound = apperror.NotFound("document not found")
+	ErrInvalidLink      = apperror.Forbidden("download link is invalid or expired")
+)
+
+type UseCaseDocument interface {
+	UploadDocument(ctx context.Context, payload *transport.UploadDocumentReq) (*transport.DocumentRes, error)
+	GetDocuments(ctx context.Context, employeeID int) (*transport.ListDocuments, error)
+	GetDocument(ctx context.Context, employeeID int, documentID int64) (*transport.DocumentRes, error)
+	DeleteDocument(ctx context.Context, employeeID int, documentID int64) error
+	OpenDocument(ctx context.Context, payload *transport.DownloadDocumentReq) (*transport.DocumentRes, io.ReadCloser, error)
+}
+
+type useCaseDocument struct {
+	documentRepo dRepo.DocumentRepo
+	employeeRepo eRepo.UserRepo
+	storage      storage.Storage
+	urlSecret    string
+	urlTTL       time.Duration
+}
+
+// NewUseCaseDocument keeps the files in the storage and signs download links
+// valid for urlTTL with urlSecret.
+func NewUseCaseDocument(documentRepo dRepo.DocumentRepo, employeeRepo eRepo.UserRepo, store storage.Storage,
+	urlSecret string, urlTTL time.Duration) UseCaseDocument {
+	return &useCaseDocument{
+		documentRepo: documentRepo,
+		employeeRepo: employeeRepo,
+		storage:      store,
+		urlSecret:    urlSecret,
+		urlTTL:       urlTTL,
+	}
+}
+
+// UploadDocument stores the file under a random key, then its metadata. The
+// file is removed again when the metadata cannot be saved.
+func (u *useCaseDocument) UploadDocument(ctx context.Context, payload *transport.UploadDocumentReq) (*transport.DocumentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "UploadDocument")
+
+	if _, err := u.findEmployee(ctx, payload.EmployeeID); err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	token, err := pkg.GenerateOpaqueToken()
+	if err != nil {
+		uLog.Errorf("error when generate storage key got %s", err.Error())
+		return nil, err
+	}
+
+	document := &model.Document{
+		EmployeeID:  payload.EmployeeID,
+		Category:    payload.Category,
+		FileName:    payload.FileName,
+		ContentType: payload.ContentType,
+		Size:        payload.Size,
+		StorageKey:  fmt.Sprintf("employees/%d/%s", payload.EmployeeID, token),
+	}
+
+	if claims := pkg.ClaimsFromContext(ctx); claims != nil {
+		document.UploadedBy = claims.Subject
+	}
+
+	if err := u.storage.Put(ctx, document.StorageKey, payload.Content, payload.Size, payload.ContentType); err != nil {
+		uLog.Errorf("error when call storage.Put got %s", err.Error())
+		return nil, err
+	}
+
+	if err := u.documentRepo.CreateDocument(ctx, document); err != nil {
+		uLog.Errorf("error when call documentRepo.CreateDocument got %s", err.Error())
+		if err := u.storage.Delete(ctx, document.StorageKey); err != nil {
+			uLog.Errorf("error when remove %s got %s", document.StorageKey, err.Error())
+		}
+		return nil, err
+	}
+
+	return u.toDocumentRes(document, time.Now()), nil
+}
+
+func (u *useCaseDocument) GetDocuments(ctx context.Context, employeeID int) (*transport.ListDocuments, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetDocuments")
+
+	if _, err := u.findEmployee(ctx, employeeID); err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	documents, err := u.documentRepo.GetDocuments(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when call documentRepo.GetDocuments got %s", err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+
+	res := make([]*transport.DocumentRes, 0, len(documents))
+	for _, document := range documents {
+		res = append(res, u.toDocumentRes(document, now))
+	}
+
+	return &transport.ListDocuments{Documents: res}, nil
+}
+
+func (u *useCaseDocument) GetDocument(ctx context.Context, employeeID int, documentID int64) (*transport.DocumentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetDocument")
+
+	document, err := u.findDocument(ctx, employeeID, documentID)
+	if err != nil {
+		uLog.Errorf("error when find document got %s", err.Error())
+		return nil, err
+	}
+
+	return u.toDocumentRes(document, time.Now()), nil
+}
+
+// DeleteDocument removes the metadata, then the file. A file that cannot be
+// removed is only logged since the document is already gone.
+func (u *useCaseDocument) DeleteDocument(ctx context.Context, employeeID int, documentID int64) error {
+	uLog := logger.WithContext(ctx).WithField("function", "DeleteDocument")
+
+	document, err := u.findDocument(ctx, employeeID, documentID)
+	if err != nil {
+		uLog.Errorf("error when find document got %s", err.Error())
+		return err
+	}
+
+	if err := u.documentRepo.DeleteDocument(ctx, documentID); err != nil {
+		uLog.Errorf("error when call documentRepo.DeleteDocument got %s", err.Error())
+		return err
+	}
+
+	if err := u.storage.Delete(ctx, document.StorageKey); err != nil {
+		uLog.Errorf("error when remove %s got %s", document.StorageKey, err.Error())
+	}
+
+	return nil
+}
+
+// OpenDocument checks the download link and opens the file, the caller
+// closes it.
+func (u *useCaseDocument) OpenDocument(ctx context.Context, payload *transport.DownloadDocumentReq) (*transport.DocumentRes, io.ReadCloser, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "OpenDocument")
+
+	if !pkg.VerifyURL(u.urlSecret, downloadPath(payload.DocumentID), payload.Expires, payload.Signature, time.Now()) {
+		uLog.Errorf("download link of document %d is invalid or expired", payload.DocumentID)
+		return nil, nil, ErrInvalidLink
+	}
+
+	document, err := u.documentRepo.GetDocumentByID(ctx, payload.DocumentID)
+	if err != nil {
+		uLog.Errorf("error when call documentRepo.GetDocumentByID got %s", err.Error())
+		return nil, nil, err
+	}
+
+	if document == nil {
+		uLog.Errorf("error when call documentRepo.GetDocumentByID got %s", ErrDocumentNotFound.Error())
+		return nil, nil, ErrDocumentNotFound
+	}
+
+	content, err := u.storage.Get(ctx, document.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		uLog.Errorf("file %s of document %d is missing", document.StorageKey, document.ID)
+		return nil, nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		uLog.Errorf("error when call storage.Get got %s", err.Error())
+		return nil, nil, err
+	}
+
+	return toDocumentRes(document), content, nil
+}
+
+func (u *useCaseDocument) findEmployee(ctx context.Context, employeeID int) (*model.Employee, error) {
+	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	return employee, nil
+}
+
+// findDocument returns the document when it belongs to the employee.
+func (u *useCaseDocument) findDocument(ctx context.Context, employeeID int, documentID int64) (*model.Document, error) {
+	document, err := u.documentRepo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if document == nil || document.EmployeeID != employeeID {
+		return nil, ErrDocumentNotFound
+	}
+
+	return document, nil
+}
+
+// toDocumentRes adds a download link signed now to the document.
+func (u *useCaseDocument) toDocumentRes(document *model.Document, now time.Time) *transport.DocumentRes {
+	res := toDocumentRes(document)
+	res.DownloadExpiresAt = now.Add(u.urlTTL).UTC().Truncate(time.Second)
+	res.DownloadURL = pkg.SignURL(u.urlSecret, downloadPath(document.ID), res.DownloadExpiresAt)
+
+	return res
+}
+
+func downloadPath(documentID int64) string {
+	return fmt.Sprintf("/documents/%d/download", documentID)
+}
+
+func toDocumentRes(document *model.Document) *transport.DocumentRes {
+	return &transport.DocumentRes{
+		ID:          document.ID,
+		EmployeeID:  document.EmployeeID,
+		Category:    document.Category,
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Size:        document.Size,
+		UploadedBy:  document.UploadedBy,
+		CreatedAt:   document.CreatedAt,
+	}
+}
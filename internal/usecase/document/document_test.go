@@ -0,0 +1,209 @@
+package document
+
+import (
+	"context"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	documentRepoMock "employee/internal/repository/document/mock"
+	employeeRepoMock "employee/internal/repository/employee/mock"
+	"employee/internal/storage"
+	"employee/internal/transport"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const urlSecret = "document-secret"
+
+func newStorage(t *testing.T) storage.Storage {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestUploadDocument(t *testing.T) {
+	employee := &model.Employee{ID: 3}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(documentRepo *documentRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.DocumentRes, err error, store storage.Storage, documentRepo *documentRepoMock.DBMock)
+	}{
+		{
+			name: "error when employee not found",
+			buildStub: func(documentRepo *documentRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.DocumentRes, err error, store storage.Storage, documentRepo *documentRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "file is removed when metadata cannot be saved",
+			buildStub: func(documentRepo *documentRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				documentRepo.On("CreateDocument", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+			},
+			checkReturn: func(result *transport.DocumentRes, err error, store storage.Storage, documentRepo *documentRepoMock.DBMock) {
+				assert.Error(t, err)
+
+				document := documentRepo.Calls[0].Arguments.Get(1).(*model.Document)
+				_, err = store.Get(context.TODO(), document.StorageKey)
+				assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(documentRepo *documentRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				documentRepo.On("CreateDocument", mock.Anything, mock.MatchedBy(func(document *model.Document) bool {
+					return document.EmployeeID == 3 && document.UploadedBy == "user-1" && strings.HasPrefix(document.StorageKey, "employees/3/")
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Document).ID = 7
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.DocumentRes, err error, store storage.Storage, documentRepo *documentRepoMock.DBMock) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(7), result.ID)
+				assert.True(t, strings.HasPrefix(result.DownloadURL, "/documents/7/download?"))
+				assert.WithinDuration(t, time.Now().Add(15*time.Minute), result.DownloadExpiresAt, 2*time.Second)
+
+				document := documentRepo.Calls[0].Arguments.Get(1).(*model.Document)
+				content, err := store.Get(context.TODO(), document.StorageKey)
+				require.NoError(t, err)
+				defer content.Close()
+				body, _ := io.ReadAll(content)
+				assert.Equal(t, "%PDF-1.7", string(body))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			documentRepo := new(documentRepoMock.DBMock)
+			employeeRepo := new(employeeRepoMock.DBMock)
+			store := newStorage(t)
+
+			tc.buildStub(documentRepo, employeeRepo)
+
+			uc := NewUseCaseDocument(documentRepo, employeeRepo, store, urlSecret, 15*time.Minute)
+
+			ctx := pkg.ContextWithClaims(context.TODO(), &pkg.JWTClaim{RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"}})
+			payload := &transport.UploadDocumentReq{EmployeeID: 3, Category: model.DocumentCategoryContract, FileName: "contract.pdf",
+				ContentType: "application/pdf", Size: 8, Content: strings.NewReader("%PDF-1.7")}
+
+			result, err := uc.UploadDocument(ctx, payload)
+
+			tc.checkReturn(result, err, store, documentRepo)
+		})
+	}
+}
+
+func TestGetDocument(t *testing.T) {
+	documentRepo := new(documentRepoMock.DBMock)
+	documentRepo.On("GetDocumentByID", mock.Anything, int64(7)).Return(&model.Document{ID: 7, EmployeeID: 3}, nil)
+
+	uc := NewUseCaseDocument(documentRepo, new(employeeRepoMock.DBMock), newStorage(t), urlSecret, 15*time.Minute)
+
+	result, err := uc.GetDocument(context.TODO(), 3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+
+	// the document of another employee is not found under this one
+	_, err = uc.GetDocument(context.TODO(), 4, 7)
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+}
+
+func TestDeleteDocument(t *testing.T) {
+	store := newStorage(t)
+	require.NoError(t, store.Put(context.TODO(), "employees/3/abc", strings.NewReader("%PDF"), 4, "application/pdf"))
+
+	documentRepo := new(documentRepoMock.DBMock)
+	documentRepo.On("GetDocumentByID", mock.Anything, int64(7)).Return(&model.Document{ID: 7, EmployeeID: 3, StorageKey: "employees/3/abc"}, nil)
+	documentRepo.On("DeleteDocument", mock.Anything, int64(7)).Return(nil)
+
+	uc := NewUseCaseDocument(documentRepo, new(employeeRepoMock.DBMock), store, urlSecret, 15*time.Minute)
+
+	require.NoError(t, uc.DeleteDocument(context.TODO(), 3, 7))
+
+	_, err := store.Get(context.TODO(), "employees/3/abc")
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+}
+
+func TestOpenDocument(t *testing.T) {
+	store := newStorage(t)
+	require.NoError(t, store.Put(context.TODO(), "employees/3/abc", strings.NewReader("%PDF"), 4, "application/pdf"))
+
+	document := &model.Document{ID: 7, EmployeeID: 3, FileName: "contract.pdf", ContentType: "application/pdf", Size: 4, StorageKey: "employees/3/abc"}
+
+	signed := func(documentID int64, secret string, expiresAt time.Time) *transport.DownloadDocumentReq {
+		link, err := url.Parse(pkg.SignURL(secret, downloadPath(documentID), expiresAt))
+		require.NoError(t, err)
+		expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+		require.NoError(t, err)
+
+		return &transport.DownloadDocumentReq{DocumentID: 7, Expires: expires, Signature: link.Query().Get("signature")}
+	}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.DownloadDocumentReq
+		checkReturn func(result *transport.DocumentRes, content io.ReadCloser, err error)
+	}{
+		{
+			name:    "error when link is expired",
+			payload: signed(7, urlSecret, time.Now().Add(-time.Minute)),
+			checkReturn: func(result *transport.DocumentRes, content io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrInvalidLink)
+			},
+		},
+		{
+			name:    "error when link was signed for another document",
+			payload: signed(8, urlSecret, time.Now().Add(time.Minute)),
+			checkReturn: func(result *transport.DocumentRes, content io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrInvalidLink)
+			},
+		},
+		{
+			name:    "error when link was signed with another secret",
+			payload: signed(7, "other", time.Now().Add(time.Minute)),
+			checkReturn: func(result *transport.DocumentRes, content io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrInvalidLink)
+			},
+		},
+		{
+			name:    "success",
+			payload: signed(7, urlSecret, time.Now().Add(time.Minute)),
+			checkReturn: func(result *transport.DocumentRes, content io.ReadCloser, err error) {
+				require.NoError(t, err)
+				defer content.Close()
+				assert.Equal(t, "contract.pdf", result.FileName)
+				body, _ := io.ReadAll(content)
+				assert.Equal(t, "%PDF", string(body))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			documentRepo := new(documentRepoMock.DBMock)
+			documentRepo.On("GetDocumentByID", mock.Anything, int64(7)).Return(document, nil)
+
+			uc := NewUseCaseDocument(documentRepo, new(employeeRepoMock.DBMock), store, urlSecret, 15*time.Minute)
+
+			result, content, err := uc.OpenDocument(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, content, err)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+	"io"
+)
+
+type DocumentUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *DocumentUseCaseMock) UploadDocument(ctx context.Context, payload *transport.UploadDocumentReq) (*transport.DocumentRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.DocumentRes), args.Error(1)
+}
+
+func (m *DocumentUseCaseMock) GetDocuments(ctx context.Context, employeeID int) (*transport.ListDocuments, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.ListDocuments), args.Error(1)
+}
+
+func (m *DocumentUseCaseMock) GetDocument(ctx context.Context, employeeID int, documentID int64) (*transport.DocumentRes, error) {
+	args := m.Called(ctx, employeeID, documentID)
+
+	return args.Get(0).(*transport.DocumentRes), args.Error(1)
+}
+
+func (m *DocumentUseCaseMock) DeleteDocument(ctx context.Context, employeeID int, documentID int64) error {
+	args := m.Called(ctx, employeeID, documentID)
+
+	return args.Error(0)
+}
+
+func (m *DocumentUseCaseMock) OpenDocument(ctx context.Context, payload *transport.DownloadDocumentReq) (*transport.DocumentRes, io.ReadCloser, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.DocumentRes), args.Get(1).(io.ReadCloser), args.Error(2)
+}
@@ -0,0 +1,189 @@
+package employee
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/transport"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// loadCustomFields returns the custom field definitions by name.
+func (u *useCaseEmployee) loadCustomFields(ctx context.Context) (map[string]*model.CustomField, error) {
+	fields, err := u.customFieldRepo.GetCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make(map[string]*model.CustomField, len(fields))
+	for _, field := range fields {
+		definitions[field.Name] = field
+	}
+
+	return definitions, nil
+}
+
+// checkCustomFields validates the values of a request against the
+// definitions and returns the ones to store. Unknown names are rejected,
+// required fields must hold a value and null clears an optional field.
+func checkCustomFields(definitions map[string]*model.CustomField, values map[string]interface{}) (map[string]interface{}, error) {
+	for name := range values {
+		if _, ok := definitions[name]; !ok {
+			return nil, invalidCustomField("custom_fields."+name, "custom_field", fmt.Sprintf("custom field %s does not exist", name))
+		}
+	}
+
+	checked := make(map[string]interface{}, len(values))
+	for name, field := range definitions {
+		value := values[name]
+		if value == nil || value == "" {
+			if field.Required {
+				return nil, invalidCustomField("custom_fields."+name, "required", fmt.Sprintf("custom field %s is required", name))
+			}
+			continue
+		}
+
+		if !matchType(field, value) {
+			return nil, invalidCustomField("custom_fields."+name, field.Type, fmt.Sprintf("custom field %s must be a valid %s", name, field.Type))
+		}
+
+		checked[name] = value
+	}
+
+	return checked, nil
+}
+
+func matchType(field *model.CustomField, value interface{}) bool {
+	switch field.Type {
+	case model.CustomFieldTypeText:
+		_, ok := value.(string)
+		return ok
+	case model.CustomFieldTypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case model.CustomFieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case model.CustomFieldTypeDate:
+		date, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(dateLayout, date)
+		return err == nil
+	case model.CustomFieldTypeEnum:
+		option, ok := value.(string)
+		return ok && hasOption(field, option)
+	}
+
+	return false
+}
+
+// parseCustomFieldFilters converts the cf.<name> query filters to the type
+// of their field so they compare equal to the stored JSON values.
+func parseCustomFieldFilters(definitions map[string]*model.CustomField, filters map[string]string) (map[string]interface{}, error) {
+	parsed := make(map[string]interface{}, len(filters))
+	for name, raw := range filters {
+		param := transport.CustomFieldFilterPrefix + name
+
+		field, ok := definitions[name]
+		if !ok {
+			return nil, invalidCustomField(param, "custom_field", fmt.Sprintf("custom field %s does not exist", name))
+		}
+
+		value, err := parseCustomFieldValue(field, raw)
+		if err != nil {
+			return nil, invalidCustomField(param, field.Type, fmt.Sprintf("Field '%s' %s", param, field.Type))
+		}
+
+		parsed[name] = value
+	}
+
+	return parsed, nil
+}
+
+// parseCustomFieldValue converts the text of a custom field value, as read
+// from a query filter or a CSV cell, to the type of its field.
+func parseCustomFieldValue(field *model.CustomField, raw string) (interface{}, error) {
+	switch field.Type {
+	case model.CustomFieldTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err == nil && (math.IsNaN(number) || math.IsInf(number, 0)) {
+			err = strconv.ErrSyntax
+		}
+		return number, err
+	case model.CustomFieldTypeBoolean:
+		return strconv.ParseBool(raw)
+	}
+
+	if !matchType(field, raw) {
+		return nil, strconv.ErrSyntax
+	}
+
+	return raw, nil
+}
+
+// importCustomFields returns the custom field values of an import row, the
+// CSV cells are converted to the type of their field and empty cells are left
+// unset.
+func importCustomFields(definitions map[string]*model.CustomField, row *transport.ImportRow) (map[string]interface{}, error) {
+	if len(row.CustomFieldCells) == 0 {
+		return row.Employee.CustomFields, nil
+	}
+
+	values := make(map[string]interface{}, len(row.CustomFieldCells))
+	for name, raw := range row.CustomFieldCells {
+		field, ok := definitions[name]
+		if !ok {
+			return nil, invalidCustomField("custom_fields."+name, "custom_field", fmt.Sprintf("custom field %s does not exist", name))
+		}
+
+		if raw == "" {
+			continue
+		}
+
+		value, err := parseCustomFieldValue(field, raw)
+		if err != nil {
+			return nil, invalidCustomField("custom_fields."+name, field.Type, fmt.Sprintf("custom field %s must be a valid %s", name, field.Type))
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// customFieldFilter loads the definitions only when the request filters by
+// custom fields.
+func (u *useCaseEmployee) customFieldFilter(ctx context.Context, filters map[string]string) (map[string]interface{}, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := u.loadCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCustomFieldFilters(definitions, filters)
+}
+
+func hasOption(field *model.CustomField, option string) bool {
+	for _, allowed := range field.Options {
+		if allowed == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+func invalidCustomField(field string, rule string, message string) error {
+	invalid := apperror.Validation(field, message)
+	invalid.Details = []apperror.FieldError{{Field: field, Rule: rule}}
+
+	return invalid
+}
@@ -0,0 +1,336 @@
+package employee
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	auditRepoMock "employee/internal/repository/audit/mock"
+	customFieldRepoMock "employee/internal/repository/customfield/mock"
+	employeeRepoMock "employee/internal/repository/employee/mock"
+	txMock "employee/internal/repository/mock"
+	positionRepoMock "employee/internal/repository/position/mock"
+	userRepoMock "employee/internal/repository/user/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var customFieldDefinitions = []*model.CustomField{
+	{ID: 1, Name: "cost_center", Type: model.CustomFieldTypeText, Required: true},
+	{ID: 2, Name: "remote", Type: model.CustomFieldTypeBoolean},
+	{ID: 3, Name: "seniority", Type: model.CustomFieldTypeNumber},
+	{ID: 4, Name: "badge_expiry", Type: model.CustomFieldTypeDate},
+	{ID: 5, Name: "shirt_size", Type: model.CustomFieldTypeEnum, Options: []string{"S", "M", "L"}},
+}
+
+// noCustomFields returns a repository without any custom field definition.
+func noCustomFields() *customFieldRepoMock.DBMock {
+	customFieldRepo := new(customFieldRepoMock.DBMock)
+	customFieldRepo.On("GetCustomFields", mock.Anything).Return([]*model.CustomField{}, nil).Maybe()
+
+	return customFieldRepo
+}
+
+func definitionsByName() map[string]*model.CustomField {
+	definitions := make(map[string]*model.CustomField)
+	for _, field := range customFieldDefinitions {
+		definitions[field.Name] = field
+	}
+
+	return definitions
+}
+
+func TestCheckCustomFields(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		values map[string]interface{}
+		want   map[string]interface{}
+		field  string
+		rule   string
+	}{
+		{
+			name: "success",
+			values: map[string]interface{}{
+				"cost_center": "CC-12", "remote": true, "seniority": float64(3), "badge_expiry": "2027-01-31", "shirt_size": "M",
+			},
+			want: map[string]interface{}{
+				"cost_center": "CC-12", "remote": true, "seniority": float64(3), "badge_expiry": "2027-01-31", "shirt_size": "M",
+			},
+		},
+		{
+			name:   "success dropping null optional fields",
+			values: map[string]interface{}{"cost_center": "CC-12", "remote": nil},
+			want:   map[string]interface{}{"cost_center": "CC-12"},
+		},
+		{
+			name:   "unknown field",
+			values: map[string]interface{}{"cost_center": "CC-12", "favourite_color": "blue"},
+			field:  "custom_fields.favourite_color",
+			rule:   "custom_field",
+		},
+		{
+			name:   "missing required field",
+			values: map[string]interface{}{"remote": false},
+			field:  "custom_fields.cost_center",
+			rule:   "required",
+		},
+		{
+			name:   "empty required field",
+			values: map[string]interface{}{"cost_center": ""},
+			field:  "custom_fields.cost_center",
+			rule:   "required",
+		},
+		{
+			name:   "number given as text",
+			values: map[string]interface{}{"cost_center": "CC-12", "seniority": "3"},
+			field:  "custom_fields.seniority",
+			rule:   "number",
+		},
+		{
+			name:   "invalid date",
+			values: map[string]interface{}{"cost_center": "CC-12", "badge_expiry": "2027-02-30"},
+			field:  "custom_fields.badge_expiry",
+			rule:   "date",
+		},
+		{
+			name:   "unknown option",
+			values: map[string]interface{}{"cost_center": "CC-12", "shirt_size": "XL"},
+			field:  "custom_fields.shirt_size",
+			rule:   "enum",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checked, err := checkCustomFields(definitionsByName(), tc.values)
+			if tc.field == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, checked)
+				return
+			}
+
+			appErr, ok := apperror.As(err)
+			require.True(t, ok)
+			assert.Equal(t, apperror.CodeValidation, appErr.Code)
+			assert.Equal(t, []apperror.FieldError{{Field: tc.field, Rule: tc.rule}}, appErr.Details)
+		})
+	}
+}
+
+func TestParseCustomFieldFilters(t *testing.T) {
+	parsed, err := parseCustomFieldFilters(definitionsByName(), map[string]string{
+		"cost_center": "CC-12", "remote": "true", "seniority": "3.5", "shirt_size": "M",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "remote": true, "seniority": 3.5, "shirt_size": "M"}, parsed)
+
+	_, err = parseCustomFieldFilters(definitionsByName(), map[string]string{"seniority": "NaN"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+
+	_, err = parseCustomFieldFilters(definitionsByName(), map[string]string{"remote": "maybe"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+
+	_, err = parseCustomFieldFilters(definitionsByName(), map[string]string{"favourite_color": "blue"})
+	appErr, ok := apperror.As(err)
+	require.True(t, ok)
+	assert.Equal(t, "cf.favourite_color", appErr.Field)
+}
+
+func TestCreateEmployeeWithCustomFields(t *testing.T) {
+
+	payload := func(values map[string]interface{}) *transport.CreateEmployeeReq {
+		return &transport.CreateEmployeeReq{
+			FirstName:    "john",
+			Email:        "john@mail.com",
+			HireDate:     "2023-01-15",
+			CustomFields: values,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.CreateEmployeeReq
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock)
+	}{
+		{
+			name:      "error when a required field is missing",
+			payload:   payload(nil),
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+				assert.Nil(t, result)
+				employeeRepo.AssertNotCalled(t, "CreateEmployee", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "success",
+			payload: payload(map[string]interface{}{"cost_center": "CC-12", "shirt_size": "L"}),
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("CreateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.CustomFields["cost_center"] == "CC-12" && employee.CustomFields["shirt_size"] == "L"
+				})).Return(1, nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "shirt_size": "L"}, result.CustomFields)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			customFieldRepository.On("GetCustomFields", mock.Anything).Return(customFieldDefinitions, nil)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), customFieldRepository, transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.CreateEmployee(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err, employeeRepository)
+		})
+	}
+}
+
+func TestGetEmployeesByCustomFields(t *testing.T) {
+	employeeRepository := new(employeeRepoMock.DBMock)
+	customFieldRepository := new(customFieldRepoMock.DBMock)
+	customFieldRepository.On("GetCustomFields", mock.Anything).Return(customFieldDefinitions, nil)
+
+	matchFilter := mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+		return filter.CustomFields["remote"] == true && filter.CustomFields["seniority"] == float64(2)
+	})
+	employeeRepository.On("CountEmployees", mock.Anything, matchFilter).Return(1, nil)
+	employeeRepository.On("GetEmployees", mock.Anything, matchFilter).
+		Return([]*model.Employee{{ID: 1, CustomFields: map[string]interface{}{"remote": true, "seniority": float64(2)}}}, nil)
+
+	u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock),
+		customFieldRepository, new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+	result, err := u.GetEmployees(context.TODO(), &transport.ListEmployeesReq{CustomFields: map[string]string{"remote": "true", "seniority": "2"}})
+
+	require.NoError(t, err)
+	require.Len(t, result.Employees, 1)
+	assert.Equal(t, true, result.Employees[0].CustomFields["remote"])
+}
+
+func TestImportEmployeesWithCustomFields(t *testing.T) {
+	employeeRepository := new(employeeRepoMock.DBMock)
+	auditRepository := new(auditRepoMock.DBMock)
+	customFieldRepository := new(customFieldRepoMock.DBMock)
+	customFieldRepository.On("GetCustomFields", mock.Anything).Return(customFieldDefinitions, nil)
+	transactor := new(txMock.TransactorMock)
+	transactor.On("WithinTransaction", mock.Anything).Return(nil)
+
+	rows := []*transport.ImportRow{
+		{Line: 2, Employee: transport.CreateEmployeeReq{FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01"},
+			CustomFieldCells: map[string]string{"cost_center": "CC-12", "remote": "true", "seniority": ""}},
+		{Line: 3, Employee: transport.CreateEmployeeReq{FirstName: "jane", Email: "jane@mail.com", HireDate: "2023-05-02"}},
+		{Line: 4, Employee: transport.CreateEmployeeReq{FirstName: "jim", Email: "jim@mail.com", HireDate: "2023-05-03"},
+			CustomFieldCells: map[string]string{"cost_center": "CC-12", "remote": "maybe"}},
+		{Line: 5, Employee: transport.CreateEmployeeReq{FirstName: "joe", Email: "joe@mail.com", HireDate: "2023-05-04",
+			CustomFields: map[string]interface{}{"cost_center": "CC-7", "shirt_size": "M"}}},
+	}
+
+	employeeRepository.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+	employeeRepository.On("CreateEmployees", mock.Anything, mock.MatchedBy(func(employees []*model.Employee) bool {
+		return len(employees) == 2 &&
+			assert.ObjectsAreEqual(map[string]interface{}{"cost_center": "CC-12", "remote": true}, employees[0].CustomFields) &&
+			assert.ObjectsAreEqual(map[string]interface{}{"cost_center": "CC-7", "shirt_size": "M"}, employees[1].CustomFields)
+	})).Return([]*model.Employee{{ID: 1, Email: "john@mail.com"}, {ID: 2, Email: "joe@mail.com"}}, nil)
+	auditRepository.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+
+	u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), customFieldRepository, transactor, nil, pkg.DefaultPasswordPolicy)
+	result, err := u.ImportEmployees(context.TODO(), &transport.ImportEmployeesReq{Mode: transport.ImportModeBestEffort, Rows: rows})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, []transport.ImportError{
+		{Line: 3, Field: "custom_fields.cost_center", Rule: "required", Message: "custom field cost_center is required"},
+		{Line: 4, Field: "custom_fields.remote", Rule: model.CustomFieldTypeBoolean, Message: "custom field remote must be a valid boolean"},
+	}, result.Errors)
+	employeeRepository.AssertExpectations(t)
+}
+
+func TestPatchEmployeeCustomFields(t *testing.T) {
+
+	mockEmployeesResult := &model.Employee{
+		ID:           1,
+		FirstName:    "test",
+		Email:        "test@mail.com",
+		HireDate:     "2023-05-01",
+		CustomFields: map[string]interface{}{"cost_center": "CC-12", "remote": true},
+		Version:      2,
+	}
+
+	testCases := []struct {
+		name        string
+		patch       string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock)
+	}{
+		{
+			name:      "error when the patch clears a required field",
+			patch:     `{"custom_fields":{"cost_center":null}}`,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				appErr, ok := apperror.As(err)
+				require.True(t, ok)
+				assert.Equal(t, []apperror.FieldError{{Field: "custom_fields.cost_center", Rule: "required"}}, appErr.Details)
+				assert.Nil(t, result)
+				employeeRepo.AssertNotCalled(t, "PatchEmployee", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:      "error when the patch adds an unknown field",
+			patch:     `{"custom_fields":{"favourite_color":"blue"}}`,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+				employeeRepo.AssertNotCalled(t, "PatchEmployee", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:  "success merges into the stored custom fields",
+			patch: `{"custom_fields":{"remote":null,"shirt_size":"S"}}`,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("PatchEmployee", mock.Anything, 1, mock.MatchedBy(func(patch *model.EmployeePatch) bool {
+					return patch.Email == nil &&
+						assert.ObjectsAreEqual(map[string]interface{}{"cost_center": "CC-12", "shirt_size": "S"}, patch.CustomFields)
+				})).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"cost_center": "CC-12", "shirt_size": "S"}, result.CustomFields)
+				assert.Equal(t, 3, result.Version)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			employeeRepository.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			auditRepository := new(auditRepoMock.DBMock)
+			customFieldRepository := new(customFieldRepoMock.DBMock)
+			customFieldRepository.On("GetCustomFields", mock.Anything).Return(customFieldDefinitions, nil)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), customFieldRepository, transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.PatchEmployee(context.TODO(), &transport.PatchEmployeeReq{ID: 1, Type: transport.MIMEMergePatch, Patch: []byte(tc.patch), Version: 2})
+
+			tc.checkReturn(result, err, employeeRepository)
+		})
+	}
+}
@@ -2,163 +2,1537 @@ package employee
 
 import (
 	"context"
+	"employee/internal/apperror"
+	"employee/internal/constant"
 	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository"
+	aRepo "employee/internal/repository/audit"
+	cRepo "employee/internal/repository/customfield"
 	eRepo "employee/internal/repository/employee"
+	pRepo "employee/internal/repository/position"
+	uRepo "employee/internal/repository/user"
+	"employee/internal/storage"
 	"employee/internal/transport"
 	"errors"
 	log "github.com/sirupsen/logrus"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
 	logger = log.WithField("useCase", "useCase.Employee")
 )
 
+const (
+	defaultPageLimit    = 20
+	defaultReportsDepth = 1
+	dateLayout          = "2006-01-02"
+)
+
+var (
+	ErrEmployeeNotFound   = apperror.NotFound("employee not found")
+	ErrInvalidCursor      = apperror.Validation("cursor", "invalid cursor")
+	ErrEmployeeNotDeleted = apperror.Conflict("", "employee is not deleted")
+	ErrManagerNotFound    = apperror.Validation("manager_id", "manager_id does not exist")
+	ErrManagerCycle       = apperror.Unprocessable("manager_id", "manager_id would create a reporting cycle")
+	ErrPositionNotFound   = apperror.Validation("position_id", "position_id does not exist")
+	ErrDepartmentNotFound = apperror.Validation("department_id", "department_id does not exist")
+	ErrSamePosition       = apperror.Conflict("position_id", "employee already holds this position")
+	ErrEffectiveDate      = apperror.Unprocessable("effective_date", "effective_date must be after the effective_date of the current position")
+	ErrFutureEffective    = apperror.Unprocessable("effective_date", "effective_date must not be in the future")
+
+	ErrStatusTransition        = apperror.Unprocessable("status", "status transition is not allowed")
+	ErrTerminationBeforeHire   = apperror.Validation("termination_date", "termination_date must not be before hire_date")
+	ErrRehireBeforeTermination = apperror.Validation("rehire_date", "rehire_date must be after termination_date")
+)
+
+// statusTransitions lists the statuses an employee can move to from each
+// status. A terminated employee only comes back through a rehire.
+var statusTransitions = map[string][]string{
+	model.EmploymentStatusProbation:  {model.EmploymentStatusActive, model.EmploymentStatusOnLeave, model.EmploymentStatusTerminated},
+	model.EmploymentStatusActive:     {model.EmploymentStatusOnLeave, model.EmploymentStatusTerminated},
+	model.EmploymentStatusOnLeave:    {model.EmploymentStatusActive, model.EmploymentStatusTerminated},
+	model.EmploymentStatusTerminated: {model.EmploymentStatusRehired},
+	model.EmploymentStatusRehired:    {model.EmploymentStatusProbation, model.EmploymentStatusActive, model.EmploymentStatusOnLeave, model.EmploymentStatusTerminated},
+}
+
 type UseCaseEmployee interface {
 	CreateEmployee(ctx context.Context, payload *transport.CreateEmployeeReq) (*transport.EmployeeRes, error)
-	GetEmployees(ctx context.Context) (*transport.ListEmployees, error)
-	GetEmployeeByID(ctx context.Context, employeeID int) (*transport.EmployeeRes, error)
+	ImportEmployees(ctx context.Context, payload *transport.ImportEmployeesReq) (*transport.ImportEmployeesRes, error)
+	GetEmployees(ctx context.Context, payload *transport.ListEmployeesReq) (*transport.ListEmployees, error)
+	ExportEmployees(ctx context.Context, payload *transport.ExportEmployeesReq, fn func(employee *transport.EmployeeRes) error) error
+	GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*transport.EmployeeRes, error)
 	UpdateEmployee(ctx context.Context, payload *transport.UpdateEmployeeReq) error
-	DeleteEmployee(ctx context.Context, employeeID int) error
+	PatchEmployee(ctx context.Context, payload *transport.PatchEmployeeReq) (*transport.EmployeeRes, error)
+	DeleteEmployee(ctx context.Context, employeeID int, version int) error
+	RestoreEmployee(ctx context.Context, employeeID int) error
+	PurgeDeletedEmployees(ctx context.Context, retention time.Duration) (int64, error)
+	GetReports(ctx context.Context, employeeID int, depth int) (*transport.ListReports, error)
+	GetManagementChain(ctx context.Context, employeeID int) (*transport.ManagementChain, error)
+	GetOrgChart(ctx context.Context) (*transport.OrgChart, error)
+	AssignPosition(ctx context.Context, payload *transport.AssignPositionReq) (*transport.PositionAssignmentRes, error)
+	GetPositionHistory(ctx context.Context, employeeID int) (*transport.PositionHistory, error)
+	ChangeStatus(ctx context.Context, payload *transport.ChangeStatusReq) (*transport.EmployeeRes, error)
+	TerminateEmployee(ctx context.Context, payload *transport.TerminateEmployeeReq) (*transport.EmployeeRes, error)
+	RehireEmployee(ctx context.Context, payload *transport.RehireEmployeeReq) (*transport.EmployeeRes, error)
 }
 
 type useCaseEmployee struct {
-	employeeRepo eRepo.UserRepo
+	employeeRepo    eRepo.UserRepo
+	userRepo        uRepo.UserRepo
+	auditRepo       aRepo.AuditRepo
+	positionRepo    pRepo.PositionRepo
+	customFieldRepo cRepo.CustomFieldRepo
+	transactor      repository.Transactor
+	storage         storage.Storage
+	passwordPolicy  pkg.PasswordPolicy
 }
 
-func NewUseCaseEmployee(employeeRepo eRepo.UserRepo) UseCaseEmployee {
-	return &useCaseEmployee{employeeRepo: employeeRepo}
+func NewUseCaseEmployee(employeeRepo eRepo.UserRepo, userRepo uRepo.UserRepo, auditRepo aRepo.AuditRepo, positionRepo pRepo.PositionRepo,
+	customFieldRepo cRepo.CustomFieldRepo, transactor repository.Transactor, storage storage.Storage, passwordPolicy pkg.PasswordPolicy) UseCaseEmployee {
+	return &useCaseEmployee{employeeRepo: employeeRepo, userRepo: userRepo, auditRepo: auditRepo, positionRepo: positionRepo,
+		customFieldRepo: customFieldRepo, transactor: transactor, storage: storage, passwordPolicy: passwordPolicy}
 }
 
 func (u *useCaseEmployee) CreateEmployee(ctx context.Context, payload *transport.CreateEmployeeReq) (*transport.EmployeeRes, error) {
 	uLog := logger.WithContext(ctx).WithField("function", "Register")
 
-	employee := &model.Employee{
-		FirstName: payload.FirstName,
-		LastName:  payload.LastName,
-		Email:     payload.Email,
-		HireDate:  payload.HireDate,
+	employee := newEmployee(payload)
+
+	var currentID int
+	var account *transport.AccountRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if payload.ManagerID != nil {
+			if err := u.checkManager(ctx, 0, *payload.ManagerID); err != nil {
+				uLog.Errorf("error when check manager got %s", err.Error())
+				return err
+			}
+		}
+
+		var position *model.Position
+		if payload.PositionID != nil {
+			var err error
+			position, err = u.findPosition(ctx, *payload.PositionID)
+			if err != nil {
+				uLog.Errorf("error when find position got %s", err.Error())
+				return err
+			}
+		}
+
+		definitions, err := u.loadCustomFields(ctx)
+		if err != nil {
+			uLog.Errorf("error when load custom fields got %s", err.Error())
+			return err
+		}
+
+		employee.CustomFields, err = checkCustomFields(definitions, payload.CustomFields)
+		if err != nil {
+			uLog.Errorf("error when check custom fields got %s", err.Error())
+			return err
+		}
+
+		currentID, err = u.employeeRepo.CreateEmployee(ctx, employee)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.CreateEmployee got %s", err.Error())
+			return err
+		}
+
+		employee.ID = currentID
+
+		if position != nil {
+			assignment := &model.PositionAssignment{
+				EmployeeID:    currentID,
+				PositionID:    position.ID,
+				DepartmentID:  payload.DepartmentID,
+				Change:        model.PositionChangeInitial,
+				EffectiveDate: payload.HireDate,
+			}
+			if err := u.positionRepo.CreateAssignment(ctx, assignment); err != nil {
+				uLog.Errorf("error when call positionRepo.CreateAssignment got %s", err.Error())
+				return err
+			}
+		}
+
+		if err := u.recordAudit(ctx, model.AuditActionCreate, currentID, nil, employee); err != nil {
+			return err
+		}
+
+		if !payload.CreateAccount {
+			return nil
+		}
+
+		account, err = u.provisionAccount(ctx, currentID, payload)
+		if err != nil {
+			uLog.Errorf("error when provision account got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toEmployeeRes(employee)
+	result.Account = account
+
+	return result, nil
+}
+
+// ImportEmployees creates the valid rows of an import in one transaction.
+// Rows whose email is already taken, whose department, manager or position
+// does not exist or whose custom fields do not match their definitions are
+// rejected like invalid rows. In all_or_nothing mode any rejected row aborts
+// the whole import, in best_effort mode only the valid rows are created. A dry
+// run reports without writing.
+func (u *useCaseEmployee) ImportEmployees(ctx context.Context, payload *transport.ImportEmployeesReq) (*transport.ImportEmployeesRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "ImportEmployees")
+
+	mode := payload.Mode
+	if mode == "" {
+		mode = transport.ImportModeAllOrNothing
+	}
+
+	var emails []string
+	for _, row := range payload.Rows {
+		if row.Valid() {
+			emails = append(emails, row.Employee.Email)
+		}
+	}
+
+	if len(emails) > 0 {
+		existing, err := u.employeeRepo.GetExistingEmails(ctx, emails)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetExistingEmails got %s", err.Error())
+			return nil, err
+		}
+
+		for _, row := range payload.Rows {
+			if row.Valid() && existing[strings.ToLower(row.Employee.Email)] {
+				row.Reject("email", "unique", "email already exists")
+			}
+		}
 	}
 
-	currentID, err := u.employeeRepo.CreateEmployee(ctx, employee)
+	definitions, err := u.loadCustomFields(ctx)
 	if err != nil {
-		uLog.Errorf("error when call employeeRepo.CreateEmployee got %s", err.Error())
+		uLog.Errorf("error when load custom fields got %s", err.Error())
 		return nil, err
 	}
 
-	result := &transport.EmployeeRes{
-		ID:        currentID,
-		FirstName: payload.FirstName,
-		LastName:  payload.LastName,
-		Email:     payload.Email,
-		HireDate:  payload.HireDate,
+	references, err := u.loadImportReferences(ctx, payload.Rows)
+	if err != nil {
+		uLog.Errorf("error when load import references got %s", err.Error())
+		return nil, err
 	}
 
-	return result, nil
+	var valid []*model.Employee
+	for _, row := range payload.Rows {
+		if !row.Valid() {
+			continue
+		}
+
+		if err := u.checkImportReferences(ctx, references, &row.Employee); err != nil {
+			appErr, ok := apperror.As(err)
+			if !ok || appErr.Field == "" {
+				uLog.Errorf("error when check import references got %s", err.Error())
+				return nil, err
+			}
+			row.Reject(appErr.Field, "exists", appErr.Message)
+			continue
+		}
+
+		values, err := importCustomFields(definitions, row)
+		if err == nil {
+			values, err = checkCustomFields(definitions, values)
+		}
+		if err != nil {
+			appErr, ok := apperror.As(err)
+			if !ok || len(appErr.Details) == 0 {
+				return nil, err
+			}
+			row.Reject(appErr.Details[0].Field, appErr.Details[0].Rule, appErr.Message)
+			continue
+		}
+
+		employee := newEmployee(&row.Employee)
+		employee.CustomFields = values
+		valid = append(valid, employee)
+	}
+
+	rejected := len(payload.Rows) - len(valid)
+	if rejected > 0 && mode == transport.ImportModeAllOrNothing {
+		valid = nil
+	}
+
+	if payload.DryRun || len(valid) == 0 {
+		return toImportRes(payload, mode, valid), nil
+	}
+
+	var created []*model.Employee
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = u.employeeRepo.CreateEmployees(ctx, valid)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.CreateEmployees got %s", err.Error())
+			return err
+		}
+
+		// an email taken since GetExistingEmails was skipped by the insert
+		if len(created) != len(valid) && mode == transport.ImportModeAllOrNothing {
+			return apperror.Conflict("email", "email already exists")
+		}
+
+		for _, employee := range created {
+			if employee.PositionID != nil {
+				assignment := &model.PositionAssignment{
+					EmployeeID:    employee.ID,
+					PositionID:    *employee.PositionID,
+					DepartmentID:  employee.DepartmentID,
+					Change:        model.PositionChangeInitial,
+					EffectiveDate: employee.HireDate,
+				}
+				if err := u.positionRepo.CreateAssignment(ctx, assignment); err != nil {
+					uLog.Errorf("error when call positionRepo.CreateAssignment got %s", err.Error())
+					return err
+				}
+			}
+
+			if err := u.recordAudit(ctx, model.AuditActionCreate, employee.ID, nil, employee); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(created) != len(valid) {
+		inserted := map[string]bool{}
+		for _, employee := range created {
+			inserted[strings.ToLower(employee.Email)] = true
+		}
+
+		for _, row := range payload.Rows {
+			if row.Valid() && !inserted[strings.ToLower(row.Employee.Email)] {
+				row.Reject("email", "unique", "email already exists")
+			}
+		}
+	}
+
+	return toImportRes(payload, mode, created), nil
+}
+
+// importReferences caches the departments, managers and positions the rows
+// of an import refer to, each id is looked up once.
+type importReferences struct {
+	departments map[int]bool
+	managers    map[int]error
+	positions   map[int]*model.Position
 }
 
-func (u *useCaseEmployee) GetEmployees(ctx context.Context) (*transport.ListEmployees, error) {
+// loadImportReferences looks up the departments of the valid rows in one
+// query, managers and positions are looked up as rows refer to them.
+func (u *useCaseEmployee) loadImportReferences(ctx context.Context, rows []*transport.ImportRow) (*importReferences, error) {
+	references := &importReferences{
+		departments: map[int]bool{},
+		managers:    map[int]error{},
+		positions:   map[int]*model.Position{},
+	}
+
+	var departmentIDs []int
+	for _, row := range rows {
+		if row.Valid() && row.Employee.DepartmentID != nil {
+			departmentIDs = append(departmentIDs, *row.Employee.DepartmentID)
+		}
+	}
+
+	if len(departmentIDs) > 0 {
+		existing, err := u.employeeRepo.GetExistingDepartments(ctx, departmentIDs)
+		if err != nil {
+			return nil, err
+		}
+		references.departments = existing
+	}
+
+	return references, nil
+}
+
+// checkImportReferences checks the department, manager and position of an
+// import row like CreateEmployee does.
+func (u *useCaseEmployee) checkImportReferences(ctx context.Context, references *importReferences, employee *transport.CreateEmployeeReq) error {
+	if employee.DepartmentID != nil && !references.departments[*employee.DepartmentID] {
+		return ErrDepartmentNotFound
+	}
+
+	if employee.ManagerID != nil {
+		err, ok := references.managers[*employee.ManagerID]
+		if !ok {
+			err = u.checkManager(ctx, 0, *employee.ManagerID)
+			if err != nil && !errors.Is(err, ErrManagerNotFound) {
+				return err
+			}
+			references.managers[*employee.ManagerID] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if employee.PositionID != nil {
+		position, ok := references.positions[*employee.PositionID]
+		if !ok {
+			var err error
+			position, err = u.positionRepo.GetPositionByID(ctx, *employee.PositionID)
+			if err != nil {
+				return err
+			}
+			references.positions[*employee.PositionID] = position
+		}
+		if position == nil {
+			return ErrPositionNotFound
+		}
+	}
+
+	return nil
+}
+
+func toImportRes(payload *transport.ImportEmployeesReq, mode string, imported []*model.Employee) *transport.ImportEmployeesRes {
+	res := &transport.ImportEmployeesRes{
+		Mode:      mode,
+		DryRun:    payload.DryRun,
+		Total:     len(payload.Rows),
+		Imported:  len(imported),
+		Errors:    []transport.ImportError{},
+		Employees: []*transport.EmployeeRes{},
+	}
+
+	for _, row := range payload.Rows {
+		if !row.Valid() {
+			res.Failed++
+			res.Errors = append(res.Errors, row.Errors...)
+		}
+	}
+
+	for _, employee := range imported {
+		if employee.ID != 0 {
+			res.Employees = append(res.Employees, toEmployeeRes(employee))
+		}
+	}
+
+	return res
+}
+
+// provisionAccount creates the login of a new employee with an initial
+// password generated by the configured policy, which is returned once and
+// only stored hashed.
+func (u *useCaseEmployee) provisionAccount(ctx context.Context, employeeID int, payload *transport.CreateEmployeeReq) (*transport.AccountRes, error) {
+	role := payload.AccountRole
+	if role == "" {
+		role = constant.RoleViewer
+	}
+
+	password, err := pkg.GeneratePassword(u.passwordPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := pkg.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := u.userRepo.CreateUser(ctx, &model.User{
+		Email:        payload.Email,
+		PasswordHash: hash,
+		Role:         role,
+		EmployeeID:   &employeeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	strength := pkg.EstimatePasswordStrength(password)
+
+	return &transport.AccountRes{
+		UserID:           userID,
+		Email:            payload.Email,
+		Role:             role,
+		InitialPassword:  password,
+		PasswordStrength: &strength,
+	}, nil
+}
+
+func (u *useCaseEmployee) GetEmployees(ctx context.Context, payload *transport.ListEmployeesReq) (*transport.ListEmployees, error) {
 	uLog := logger.WithContext(ctx).WithField("function", "GetEmployees")
 
-	employees, err := u.employeeRepo.GetEmployees(ctx)
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	filter := &model.EmployeeFilter{
+		FirstName:      payload.FirstName,
+		LastName:       payload.LastName,
+		Email:          payload.Email,
+		HireDateFrom:   payload.HireDateFrom,
+		HireDateTo:     payload.HireDateTo,
+		ManagerID:      payload.ManagerID,
+		DepartmentID:   payload.DepartmentID,
+		Status:         payload.Status,
+		IncludeDeleted: payload.IncludeDeleted,
+		Sort:           payload.Sort,
+		Offset:         payload.Offset,
+	}
+
+	customFields, err := u.customFieldFilter(ctx, payload.CustomFields)
+	if err != nil {
+		uLog.Errorf("error when parse custom field filters got %s", err.Error())
+		return nil, err
+	}
+	filter.CustomFields = customFields
+
+	if payload.Cursor != "" {
+		if payload.Offset > 0 {
+			uLog.Error("offset and cursor are both set")
+			return nil, ErrInvalidCursor
+		}
+
+		cursor := &model.EmployeeCursor{}
+		if err := pkg.DecodeCursor(payload.Cursor, cursor); err != nil {
+			uLog.Errorf("error when decode cursor got %s", err.Error())
+			return nil, ErrInvalidCursor
+		}
+		filter.Cursor = cursor
+	}
+
+	total, err := u.employeeRepo.CountEmployees(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call employeeRepo.CountEmployees got %s", err.Error())
+		return nil, err
+	}
+
+	// fetch one extra row to know whether another page exists
+	filter.Limit = limit + 1
+
+	employees, err := u.employeeRepo.GetEmployees(ctx, filter)
 	if err != nil {
 		uLog.Errorf("error when call employeeRepo.GetEmployees got %s", err.Error())
 		return nil, err
 	}
 
+	pagination := &transport.Pagination{
+		Total:  total,
+		Limit:  limit,
+		Offset: payload.Offset,
+	}
+
+	if len(employees) > limit {
+		employees = employees[:limit]
+
+		last := employees[len(employees)-1]
+		column, _ := eRepo.ParseSort(payload.Sort)
+		nextCursor, err := pkg.EncodeCursor(&model.EmployeeCursor{Value: sortValue(last, column), ID: last.ID})
+		if err != nil {
+			uLog.Errorf("error when encode cursor got %s", err.Error())
+			return nil, err
+		}
+		pagination.NextCursor = nextCursor
+	}
+
 	employeesResData := make([]*transport.EmployeeRes, 0)
 	for _, employee := range employees {
-		emp := &transport.EmployeeRes{
-			ID:        employee.ID,
-			FirstName: employee.FirstName,
-			LastName:  employee.LastName,
-			Email:     employee.Email,
-			HireDate:  employee.HireDate,
-		}
-		employeesResData = append(employeesResData, emp)
+		employeesResData = append(employeesResData, toEmployeeRes(employee))
 	}
 
-	employeesRes := &transport.ListEmployees{Employees: employeesResData}
+	employeesRes := &transport.ListEmployees{Employees: employeesResData, Pagination: pagination}
 
 	return employeesRes, nil
 }
 
-func (u *useCaseEmployee) GetEmployeeByID(ctx context.Context, employeeID int) (*transport.EmployeeRes, error) {
+// ExportEmployees calls fn for every employee matching the filters, as they
+// are read from the repository.
+func (u *useCaseEmployee) ExportEmployees(ctx context.Context, payload *transport.ExportEmployeesReq, fn func(employee *transport.EmployeeRes) error) error {
+	uLog := logger.WithContext(ctx).WithField("function", "ExportEmployees")
+
+	filter := &model.EmployeeFilter{
+		FirstName:      payload.FirstName,
+		LastName:       payload.LastName,
+		Email:          payload.Email,
+		HireDateFrom:   payload.HireDateFrom,
+		HireDateTo:     payload.HireDateTo,
+		ManagerID:      payload.ManagerID,
+		DepartmentID:   payload.DepartmentID,
+		Status:         payload.Status,
+		IncludeDeleted: payload.IncludeDeleted,
+		Sort:           payload.Sort,
+	}
+
+	customFields, err := u.customFieldFilter(ctx, payload.CustomFields)
+	if err != nil {
+		uLog.Errorf("error when parse custom field filters got %s", err.Error())
+		return err
+	}
+	filter.CustomFields = customFields
+
+	err = u.employeeRepo.StreamEmployees(ctx, filter, func(employee *model.Employee) error {
+		return fn(toEmployeeRes(employee))
+	})
+	if err != nil {
+		uLog.Errorf("error when call employeeRepo.StreamEmployees got %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *useCaseEmployee) GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*transport.EmployeeRes, error) {
 	uLog := logger.WithContext(ctx).WithField("function", "GetEmployeeByID")
 
-	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID)
+	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, includeDeleted)
 	if err != nil {
 		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
 		return nil, err
 	}
 
-	if employee != nil {
-		employeeRes := &transport.EmployeeRes{
-			ID:        employee.ID,
-			FirstName: employee.FirstName,
-			LastName:  employee.LastName,
-			Email:     employee.Email,
-			HireDate:  employee.HireDate,
-		}
-		return employeeRes, nil
+	if employee == nil {
+		return nil, ErrEmployeeNotFound
 	}
 
-	return nil, nil
+	return toEmployeeRes(employee), nil
 
 }
 
 func (u *useCaseEmployee) UpdateEmployee(ctx context.Context, payload *transport.UpdateEmployeeReq) error {
 	uLog := logger.WithContext(ctx).WithField("function", "UpdateEmployee")
 
-	employee, err := u.employeeRepo.GetEmployeeByID(ctx, payload.ID)
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeByID(ctx, payload.ID, false)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		if !matchVersion(employee, payload.Version) {
+			uLog.Errorf("employee %d is at version %d, expected %d", employee.ID, employee.Version, payload.Version)
+			return eRepo.ErrStaleVersion
+		}
+
+		if payload.ManagerID != nil && !sameID(employee.ManagerID, payload.ManagerID) {
+			if err := u.checkManager(ctx, employee.ID, *payload.ManagerID); err != nil {
+				uLog.Errorf("error when check manager got %s", err.Error())
+				return err
+			}
+		}
+
+		definitions, err := u.loadCustomFields(ctx)
+		if err != nil {
+			uLog.Errorf("error when load custom fields got %s", err.Error())
+			return err
+		}
+
+		customFields, err := checkCustomFields(definitions, payload.CustomFields)
+		if err != nil {
+			uLog.Errorf("error when check custom fields got %s", err.Error())
+			return err
+		}
+
+		employeePayload := &model.Employee{
+			ID:           payload.ID,
+			FirstName:    payload.FirstName,
+			LastName:     payload.LastName,
+			Email:        payload.Email,
+			HireDate:     payload.HireDate,
+			DepartmentID: payload.DepartmentID,
+			ManagerID:    payload.ManagerID,
+			CustomFields: customFields,
+			Version:      employee.Version,
+		}
+		applyProfile(employeePayload, payload.EmployeeProfile)
+
+		err = u.employeeRepo.UpdateEmployee(ctx, employeePayload)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.UpdateEmployee got %s", err.Error())
+			return err
+		}
+
+		after := *employee
+		after.FirstName = payload.FirstName
+		after.LastName = payload.LastName
+		after.Email = payload.Email
+		after.HireDate = payload.HireDate
+		after.DepartmentID = payload.DepartmentID
+		after.ManagerID = payload.ManagerID
+		applyProfile(&after, payload.EmployeeProfile)
+		after.CustomFields = customFields
+		after.Version++
+
+		return u.recordAudit(ctx, model.AuditActionUpdate, employee.ID, employee, &after)
+	})
+
+}
+
+// PatchEmployee applies the patch document to the employee it locked and
+// writes the fields that differ from it, so the patch, the version check and
+// the write see the same row. A patch that changes nothing is not written nor audited.
+func (u *useCaseEmployee) PatchEmployee(ctx context.Context, payload *transport.PatchEmployeeReq) (*transport.EmployeeRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "PatchEmployee")
+
+	var res *transport.EmployeeRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeForUpdate(ctx, payload.ID)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeForUpdate got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeForUpdate got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		if !matchVersion(employee, payload.Version) {
+			uLog.Errorf("employee %d is at version %d, expected %d", employee.ID, employee.Version, payload.Version)
+			return eRepo.ErrStaleVersion
+		}
+
+		changes, err := transport.ApplyEmployeePatch(toEmployeeRes(employee), payload.Type, payload.Patch)
+		if err != nil {
+			uLog.Errorf("error when apply patch got %s", err.Error())
+			return err
+		}
+
+		if err := transport.ValidateStruct(changes); err != nil {
+			uLog.Errorf("error when validate patched employee got %s", err.Error())
+			return err
+		}
+
+		after := *employee
+		patch := &model.EmployeePatch{Version: employee.Version}
+		applyPatch(&patch.FirstName, changes.FirstName, &after.FirstName)
+		applyPatch(&patch.LastName, changes.LastName, &after.LastName)
+		applyPatch(&patch.Email, changes.Email, &after.Email)
+		applyPatch(&patch.HireDate, changes.HireDate, &after.HireDate)
+
+		if changes.Profile != nil {
+			applyProfile(&after, *changes.Profile)
+			if !sameProfile(&after, employee) {
+				patch.Profile = &after
+			}
+		}
+
+		if changes.CustomFields != nil {
+			definitions, err := u.loadCustomFields(ctx)
+			if err != nil {
+				uLog.Errorf("error when load custom fields got %s", err.Error())
+				return err
+			}
+
+			customFields, err := checkCustomFields(definitions, changes.CustomFields)
+			if err != nil {
+				uLog.Errorf("error when check custom fields got %s", err.Error())
+				return err
+			}
+
+			if !sameCustomFields(customFields, employee.CustomFields) {
+				patch.CustomFields = customFields
+				after.CustomFields = customFields
+			}
+		}
+
+		if patch.IsEmpty() {
+			res = toEmployeeRes(employee)
+			return nil
+		}
+
+		err = u.employeeRepo.PatchEmployee(ctx, employee.ID, patch)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.PatchEmployee got %s", err.Error())
+			return err
+		}
+
+		after.Version++
+		res = toEmployeeRes(&after)
+
+		return u.recordAudit(ctx, model.AuditActionUpdate, employee.ID, employee, &after)
+	})
 	if err != nil {
-		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
-		return err
+		return nil, err
 	}
 
-	if employee == nil {
-		err = errors.New("employee not found")
-		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+	return res, nil
+}
+
+// sameCustomFields reports whether two sets of custom fields hold the same
+// values, no custom field and an empty set are the same.
+func sameCustomFields(a map[string]interface{}, b map[string]interface{}) bool {
+	return (len(a) == 0 && len(b) == 0) || reflect.DeepEqual(a, b)
+}
+
+// sameProfile reports whether two employees hold the same profile, no
+// emergency contact and an empty list are the same.
+func sameProfile(a *model.Employee, b *model.Employee) bool {
+	return reflect.DeepEqual(a.Phone, b.Phone) && reflect.DeepEqual(a.DateOfBirth, b.DateOfBirth) &&
+		reflect.DeepEqual(a.Address, b.Address) && reflect.DeepEqual(a.NationalID, b.NationalID) &&
+		reflect.DeepEqual(a.WorkLocation, b.WorkLocation) &&
+		((len(a.EmergencyContacts) == 0 && len(b.EmergencyContacts) == 0) || reflect.DeepEqual(a.EmergencyContacts, b.EmergencyContacts))
+}
+
+// applyPatch sets the patch column and the stored value when the requested value differs from it.
+func applyPatch(column **string, requested *string, stored *string) {
+	if requested == nil || *requested == *stored {
+		return
+	}
+
+	*column = requested
+	*stored = *requested
+}
+
+func (u *useCaseEmployee) DeleteEmployee(ctx context.Context, employeeID int, version int) error {
+	uLog := logger.WithContext(ctx).WithField("function", "WithField")
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		if !matchVersion(employee, version) {
+			uLog.Errorf("employee %d is at version %d, expected %d", employee.ID, employee.Version, version)
+			return eRepo.ErrStaleVersion
+		}
+
+		err = u.employeeRepo.DeleteEmployee(ctx, employeeID, employee.Version)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.DeleteEmployee got %s", err.Error())
+			return err
+		}
+
+		if err := u.userRepo.DisableEmployeeUsers(ctx, employeeID); err != nil {
+			uLog.Errorf("error when call userRepo.DisableEmployeeUsers got %s", err.Error())
+			return err
+		}
+
+		after := *employee
+		deletedAt := time.Now()
+		after.DeletedAt = &deletedAt
+		after.Version++
+
+		return u.recordAudit(ctx, model.AuditActionDelete, employeeID, employee, &after)
+	})
+
+}
+
+func (u *useCaseEmployee) RestoreEmployee(ctx context.Context, employeeID int) error {
+	uLog := logger.WithContext(ctx).WithField("function", "RestoreEmployee")
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, true)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		if employee.DeletedAt == nil {
+			uLog.Errorf("employee %d is not deleted", employeeID)
+			return ErrEmployeeNotDeleted
+		}
+
+		err = u.employeeRepo.RestoreEmployee(ctx, employeeID)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.RestoreEmployee got %s", err.Error())
+			return err
+		}
+
+		// a terminated employee only gets the account back when rehired
+		if employee.Status != model.EmploymentStatusTerminated {
+			if err := u.userRepo.EnableEmployeeUsers(ctx, employeeID); err != nil {
+				uLog.Errorf("error when call userRepo.EnableEmployeeUsers got %s", err.Error())
+				return err
+			}
+		}
+
+		after := *employee
+		after.DeletedAt = nil
+		after.Version++
+
+		return u.recordAudit(ctx, model.AuditActionRestore, employeeID, employee, &after)
+	})
+}
+
+// PurgeDeletedEmployees hard deletes employees soft deleted longer than the
+// retention period. Their documents go with them, the files are removed from
+// the storage once the purge is committed.
+func (u *useCaseEmployee) PurgeDeletedEmployees(ctx context.Context, retention time.Duration) (int64, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "PurgeDeletedEmployees")
+
+	deletedBefore := time.Now().Add(-retention)
+
+	var purged []int
+	var keys []string
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		keys, err = u.employeeRepo.GetPurgedDocumentKeys(ctx, deletedBefore)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetPurgedDocumentKeys got %s", err.Error())
+			return err
+		}
+
+		purged, err = u.employeeRepo.PurgeEmployees(ctx, deletedBefore)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.PurgeEmployees got %s", err.Error())
+			return err
+		}
+
+		for _, employeeID := range purged {
+			if err := u.recordAudit(ctx, model.AuditActionPurge, employeeID, nil, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			uLog.Errorf("error when remove %s got %s", key, err.Error())
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+// checkManager rejects a manager that does not exist, is deleted, or already
+// reports to the employee directly or indirectly. employeeID is 0 for a new
+// employee, who cannot have reports yet.
+func (u *useCaseEmployee) checkManager(ctx context.Context, employeeID int, managerID int) error {
+	if managerID == employeeID {
+		return ErrManagerCycle
+	}
+
+	manager, err := u.employeeRepo.GetEmployeeByID(ctx, managerID, false)
+	if err != nil {
 		return err
 	}
 
-	employeePayload := &model.Employee{
-		ID:        payload.ID,
-		FirstName: payload.FirstName,
-		LastName:  payload.LastName,
-		Email:     payload.Email,
-		HireDate:  payload.HireDate,
+	if manager == nil {
+		return ErrManagerNotFound
 	}
 
-	err = u.employeeRepo.UpdateEmployee(ctx, employeePayload)
+	if employeeID == 0 {
+		return nil
+	}
+
+	if err := u.employeeRepo.LockManagementTree(ctx); err != nil {
+		return err
+	}
+
+	chain, err := u.employeeRepo.GetManagementChain(ctx, managerID)
 	if err != nil {
-		uLog.Errorf("error when call employeeRepo.UpdateEmployee got %s", err.Error())
 		return err
 	}
 
+	for _, above := range chain {
+		if above.ID == employeeID {
+			return ErrManagerCycle
+		}
+	}
+
 	return nil
+}
+
+// GetReports lists the employees below the employee in the management tree,
+// depth levels deep, direct reports only by default.
+func (u *useCaseEmployee) GetReports(ctx context.Context, employeeID int, depth int) (*transport.ListReports, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetReports")
+
+	if depth == 0 {
+		depth = defaultReportsDepth
+	}
+
+	if _, err := u.GetEmployeeByID(ctx, employeeID, false); err != nil {
+		uLog.Errorf("error when call GetEmployeeByID got %s", err.Error())
+		return nil, err
+	}
+
+	reports, err := u.employeeRepo.GetReports(ctx, employeeID, depth)
+	if err != nil {
+		uLog.Errorf("error when call employeeRepo.GetReports got %s", err.Error())
+		return nil, err
+	}
 
+	return &transport.ListReports{Reports: toHierarchyRes(reports)}, nil
 }
 
-func (u *useCaseEmployee) DeleteEmployee(ctx context.Context, employeeID int) error {
-	uLog := logger.WithContext(ctx).WithField("function", "WithField")
+// GetManagementChain lists the managers of the employee up to the root.
+func (u *useCaseEmployee) GetManagementChain(ctx context.Context, employeeID int) (*transport.ManagementChain, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetManagementChain")
+
+	if _, err := u.GetEmployeeByID(ctx, employeeID, false); err != nil {
+		uLog.Errorf("error when call GetEmployeeByID got %s", err.Error())
+		return nil, err
+	}
 
-	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID)
+	chain, err := u.employeeRepo.GetManagementChain(ctx, employeeID)
 	if err != nil {
-		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
-		return err
+		uLog.Errorf("error when call employeeRepo.GetManagementChain got %s", err.Error())
+		return nil, err
 	}
 
-	if employee == nil {
-		err = errors.New("employee not found")
-		uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
-		return err
+	return &transport.ManagementChain{Chain: toHierarchyRes(chain)}, nil
+}
+
+// GetOrgChart builds the management tree of every employee that is not
+// deleted. Employees whose manager is deleted become roots, and so does the
+// lowest id of a cycle left in the data before cycles were rejected.
+func (u *useCaseEmployee) GetOrgChart(ctx context.Context) (*transport.OrgChart, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetOrgChart")
+
+	var employees []*model.Employee
+	err := u.employeeRepo.StreamEmployees(ctx, &model.EmployeeFilter{Sort: "id"}, func(employee *model.Employee) error {
+		employees = append(employees, employee)
+		return nil
+	})
+	if err != nil {
+		uLog.Errorf("error when call employeeRepo.StreamEmployees got %s", err.Error())
+		return nil, err
+	}
+
+	nodes := make(map[int]*transport.OrgChartNode, len(employees))
+	for _, employee := range employees {
+		nodes[employee.ID] = &transport.OrgChartNode{
+			ID:           employee.ID,
+			FirstName:    employee.FirstName,
+			LastName:     employee.LastName,
+			Email:        employee.Email,
+			DepartmentID: employee.DepartmentID,
+			Reports:      []*transport.OrgChartNode{},
+		}
+	}
+
+	reports := map[int][]*transport.OrgChartNode{}
+	var roots []*transport.OrgChartNode
+	for _, employee := range employees {
+		if employee.ManagerID != nil && nodes[*employee.ManagerID] != nil {
+			reports[*employee.ManagerID] = append(reports[*employee.ManagerID], nodes[employee.ID])
+			continue
+		}
+		roots = append(roots, nodes[employee.ID])
+	}
+
+	attached := make(map[int]bool, len(employees))
+	var attach func(node *transport.OrgChartNode)
+	attach = func(node *transport.OrgChartNode) {
+		attached[node.ID] = true
+		for _, report := range reports[node.ID] {
+			if !attached[report.ID] {
+				node.Reports = append(node.Reports, report)
+				attach(report)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		attach(root)
+	}
+
+	// only employees in a cycle are left, cut the cycle at its lowest id
+	for _, employee := range employees {
+		if node := nodes[employee.ID]; !attached[node.ID] {
+			roots = append(roots, node)
+			attach(node)
+		}
+	}
+
+	if roots == nil {
+		roots = []*transport.OrgChartNode{}
+	}
+
+	return &transport.OrgChart{Total: len(employees), Roots: roots}, nil
+}
+
+// AssignPosition moves the employee to a position from the effective date on.
+// The current entry of the position history ends at that date and the change
+// is classified by comparing the grades of both positions. The employee row is
+// updated right away, so a change that only takes effect later is rejected.
+func (u *useCaseEmployee) AssignPosition(ctx context.Context, payload *transport.AssignPositionReq) (*transport.PositionAssignmentRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "AssignPosition")
+
+	if payload.EffectiveDate > time.Now().Format(dateLayout) {
+		uLog.Errorf("effective date %s is in the future", payload.EffectiveDate)
+		return nil, ErrFutureEffective
+	}
+
+	var res *transport.PositionAssignmentRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeByID(ctx, payload.EmployeeID, false)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		position, err := u.findPosition(ctx, payload.PositionID)
+		if err != nil {
+			uLog.Errorf("error when find position got %s", err.Error())
+			return err
+		}
+
+		current, err := u.positionRepo.GetCurrentAssignment(ctx, employee.ID)
+		if err != nil {
+			uLog.Errorf("error when call positionRepo.GetCurrentAssignment got %s", err.Error())
+			return err
+		}
+
+		departmentID := employee.DepartmentID
+		if payload.DepartmentID != nil {
+			departmentID = payload.DepartmentID
+		}
+
+		assignment := &model.PositionAssignment{
+			EmployeeID:    employee.ID,
+			PositionID:    position.ID,
+			Title:         position.Title,
+			Grade:         position.Grade,
+			JobFamily:     position.JobFamily,
+			DepartmentID:  departmentID,
+			Change:        model.PositionChangeInitial,
+			EffectiveDate: payload.EffectiveDate,
+		}
+
+		if current != nil {
+			if current.PositionID == position.ID && sameID(current.DepartmentID, departmentID) {
+				uLog.Errorf("employee %d already holds position %d", employee.ID, position.ID)
+				return ErrSamePosition
+			}
+
+			if payload.EffectiveDate <= current.EffectiveDate {
+				uLog.Errorf("effective date %s is not after %s", payload.EffectiveDate, current.EffectiveDate)
+				return ErrEffectiveDate
+			}
+
+			assignment.Change = positionChange(current, position)
+
+			if err := u.positionRepo.EndAssignment(ctx, current.ID, payload.EffectiveDate); err != nil {
+				uLog.Errorf("error when call positionRepo.EndAssignment got %s", err.Error())
+				return err
+			}
+		}
+
+		if err := u.positionRepo.CreateAssignment(ctx, assignment); err != nil {
+			uLog.Errorf("error when call positionRepo.CreateAssignment got %s", err.Error())
+			return err
+		}
+
+		if err := u.employeeRepo.SetPosition(ctx, employee.ID, position.ID, departmentID, employee.Version); err != nil {
+			uLog.Errorf("error when call employeeRepo.SetPosition got %s", err.Error())
+			return err
+		}
+
+		after := *employee
+		after.PositionID = &position.ID
+		after.DepartmentID = departmentID
+		after.Version++
+
+		res = toPositionAssignmentRes(assignment)
+
+		return u.recordAudit(ctx, model.AuditActionUpdate, employee.ID, employee, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetPositionHistory lists the positions the employee held, the current one first.
+func (u *useCaseEmployee) GetPositionHistory(ctx context.Context, employeeID int) (*transport.PositionHistory, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetPositionHistory")
+
+	if _, err := u.GetEmployeeByID(ctx, employeeID, false); err != nil {
+		uLog.Errorf("error when call GetEmployeeByID got %s", err.Error())
+		return nil, err
+	}
+
+	assignments, err := u.positionRepo.GetAssignments(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when call positionRepo.GetAssignments got %s", err.Error())
+		return nil, err
+	}
+
+	positions := make([]*transport.PositionAssignmentRes, 0, len(assignments))
+	for _, assignment := range assignments {
+		positions = append(positions, toPositionAssignmentRes(assignment))
+	}
+
+	return &transport.PositionHistory{Positions: positions}, nil
+}
+
+// ChangeStatus moves the employee between probation, active and on_leave.
+func (u *useCaseEmployee) ChangeStatus(ctx context.Context, payload *transport.ChangeStatusReq) (*transport.EmployeeRes, error) {
+	return u.transitionStatus(ctx, payload.EmployeeID, model.AuditActionUpdate, func(employee *model.Employee) error {
+		employee.Status = payload.Status
+		return nil
+	})
+}
+
+// TerminateEmployee ends the employment on the termination date, which cannot
+// be before the hire date.
+func (u *useCaseEmployee) TerminateEmployee(ctx context.Context, payload *transport.TerminateEmployeeReq) (*transport.EmployeeRes, error) {
+	return u.transitionStatus(ctx, payload.EmployeeID, model.AuditActionTerminate, func(employee *model.Employee) error {
+		if payload.TerminationDate < employee.HireDate {
+			return ErrTerminationBeforeHire
+		}
+
+		employee.Status = model.EmploymentStatusTerminated
+		employee.TerminationDate = &payload.TerminationDate
+		employee.TerminationReason = &payload.Reason
+		return nil
+	})
+}
+
+// RehireEmployee starts a new employment of a terminated employee, the rehire
+// date becomes the hire date and the termination is cleared.
+func (u *useCaseEmployee) RehireEmployee(ctx context.Context, payload *transport.RehireEmployeeReq) (*transport.EmployeeRes, error) {
+	return u.transitionStatus(ctx, payload.EmployeeID, model.AuditActionRehire, func(employee *model.Employee) error {
+		if employee.TerminationDate != nil && payload.RehireDate <= *employee.TerminationDate {
+			return ErrRehireBeforeTermination
+		}
+
+		employee.Status = model.EmploymentStatusRehired
+		employee.HireDate = payload.RehireDate
+		employee.TerminationDate = nil
+		employee.TerminationReason = nil
+		return nil
+	})
+}
+
+// transitionStatus applies fn to a copy of the employee and writes it when the
+// status transition is allowed by statusTransitions.
+func (u *useCaseEmployee) transitionStatus(ctx context.Context, employeeID int, action string, fn func(employee *model.Employee) error) (*transport.EmployeeRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "transitionStatus")
+
+	var res *transport.EmployeeRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+		if err != nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", err.Error())
+			return err
+		}
+
+		if employee == nil {
+			uLog.Errorf("error when call employeeRepo.GetEmployeeByID got %s", ErrEmployeeNotFound.Error())
+			return ErrEmployeeNotFound
+		}
+
+		// hire_date is scanned as a timestamp, the comparisons need the date
+		employee.HireDate = dateOf(employee.HireDate)
+
+		after := *employee
+		if err := fn(&after); err != nil {
+			uLog.Errorf("employee %d cannot change status got %s", employee.ID, err.Error())
+			return err
+		}
+
+		if !canTransition(employee.Status, after.Status) {
+			uLog.Errorf("employee %d cannot move from %s to %s", employee.ID, employee.Status, after.Status)
+			return ErrStatusTransition
+		}
+
+		if err := u.employeeRepo.UpdateStatus(ctx, &after); err != nil {
+			uLog.Errorf("error when call employeeRepo.UpdateStatus got %s", err.Error())
+			return err
+		}
+
+		switch {
+		case after.Status == model.EmploymentStatusTerminated:
+			if err := u.userRepo.DisableEmployeeUsers(ctx, employee.ID); err != nil {
+				uLog.Errorf("error when call userRepo.DisableEmployeeUsers got %s", err.Error())
+				return err
+			}
+		case employee.Status == model.EmploymentStatusTerminated:
+			if err := u.userRepo.EnableEmployeeUsers(ctx, employee.ID); err != nil {
+				uLog.Errorf("error when call userRepo.EnableEmployeeUsers got %s", err.Error())
+				return err
+			}
+		}
+
+		after.Version++
+		res = toEmployeeRes(&after)
+
+		return u.recordAudit(ctx, action, employee.ID, employee, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// canTransition reports whether statusTransitions allows moving from one status to the other.
+func canTransition(from string, to string) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dateOf returns the YYYY-MM-DD part of a date or timestamp.
+func dateOf(value string) string {
+	if len(value) > len(dateLayout) {
+		return value[:len(dateLayout)]
+	}
+
+	return value
+}
+
+func (u *useCaseEmployee) findPosition(ctx context.Context, positionID int) (*model.Position, error) {
+	position, err := u.positionRepo.GetPositionByID(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if position == nil {
+		return nil, ErrPositionNotFound
+	}
+
+	return position, nil
+}
+
+// positionChange classifies a move from the current position: a higher grade
+// is a promotion, a lower one a demotion, anything else a transfer.
+func positionChange(current *model.PositionAssignment, next *model.Position) string {
+	switch {
+	case next.Grade > current.Grade:
+		return model.PositionChangePromotion
+	case next.Grade < current.Grade:
+		return model.PositionChangeDemotion
+	default:
+		return model.PositionChangeTransfer
+	}
+}
+
+// recordAudit writes the audit entry of a mutation with the actor taken from
+// the JWT claims. It must run inside the transaction of the mutation.
+func (u *useCaseEmployee) recordAudit(ctx context.Context, action string, employeeID int, before *model.Employee, after *model.Employee) error {
+	uLog := logger.WithContext(ctx).WithField("function", "recordAudit")
+
+	var beforeRes, afterRes interface{}
+	if before != nil {
+		beforeRes = toEmployeeRes(before)
+	}
+
+	if after != nil {
+		afterRes = toEmployeeRes(after)
 	}
 
-	err = u.employeeRepo.DeleteEmployee(ctx, employeeID)
+	audit, err := pkg.NewAudit(ctx, action, employeeID, beforeRes, afterRes)
 	if err != nil {
-		uLog.Errorf("error when call employeeRepo.DeleteEmployee got %s", err.Error())
 		return err
 	}
+
+	if err := u.auditRepo.CreateAudit(ctx, audit); err != nil {
+		uLog.Errorf("error when call auditRepo.CreateAudit got %s", err.Error())
+		return err
+	}
+
 	return nil
+}
+
+// matchVersion reports whether the caller's expected version still matches,
+// a zero version means the caller did not ask for a check.
+func matchVersion(employee *model.Employee, version int) bool {
+	return version == 0 || employee.Version == version
+}
 
+func toEmployeeRes(employee *model.Employee) *transport.EmployeeRes {
+	return &transport.EmployeeRes{
+		ID:                employee.ID,
+		FirstName:         employee.FirstName,
+		LastName:          employee.LastName,
+		Email:             employee.Email,
+		HireDate:          employee.HireDate,
+		ManagerID:         employee.ManagerID,
+		DepartmentID:      employee.DepartmentID,
+		PositionID:        employee.PositionID,
+		Status:            employee.Status,
+		TerminationDate:   employee.TerminationDate,
+		TerminationReason: employee.TerminationReason,
+		Phone:             employee.Phone,
+		DateOfBirth:       employee.DateOfBirth,
+		Address:           toAddressRes(employee.Address),
+		EmergencyContacts: toEmergencyContactsRes(employee.EmergencyContacts),
+		NationalID:        employee.NationalID,
+		WorkLocation:      employee.WorkLocation,
+		CustomFields:      employee.CustomFields,
+		DeletedAt:         employee.DeletedAt,
+		Version:           employee.Version,
+	}
+}
+
+// applyProfile replaces the profile of the employee with the one of a
+// request, empty fields are stored as NULL.
+// newEmployee builds the employee a create request describes, the status
+// starts as active when it is omitted.
+func newEmployee(payload *transport.CreateEmployeeReq) *model.Employee {
+	status := payload.Status
+	if status == "" {
+		status = model.EmploymentStatusActive
+	}
+
+	employee := &model.Employee{
+		FirstName:    payload.FirstName,
+		LastName:     payload.LastName,
+		Email:        payload.Email,
+		HireDate:     payload.HireDate,
+		DepartmentID: payload.DepartmentID,
+		ManagerID:    payload.ManagerID,
+		PositionID:   payload.PositionID,
+		Status:       status,
+	}
+	applyProfile(employee, payload.EmployeeProfile)
+
+	return employee
+}
+
+func applyProfile(employee *model.Employee, profile transport.EmployeeProfile) {
+	employee.Phone = optional(profile.Phone)
+	employee.DateOfBirth = optional(profile.DateOfBirth)
+	employee.NationalID = optional(profile.NationalID)
+	employee.WorkLocation = optional(profile.WorkLocation)
+
+	employee.Address = nil
+	if profile.Address != nil {
+		employee.Address = &model.Address{
+			Line1:      profile.Address.Line1,
+			Line2:      profile.Address.Line2,
+			City:       profile.Address.City,
+			State:      profile.Address.State,
+			PostalCode: profile.Address.PostalCode,
+			Country:    profile.Address.Country,
+		}
+	}
+
+	employee.EmergencyContacts = nil
+	for _, contact := range profile.EmergencyContacts {
+		employee.EmergencyContacts = append(employee.EmergencyContacts, model.EmergencyContact(contact))
+	}
+}
+
+func toAddressRes(address *model.Address) *transport.Address {
+	if address == nil {
+		return nil
+	}
+
+	return &transport.Address{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func toEmergencyContactsRes(contacts []model.EmergencyContact) []transport.EmergencyContact {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	res := make([]transport.EmergencyContact, 0, len(contacts))
+	for _, contact := range contacts {
+		res = append(res, transport.EmergencyContact(contact))
+	}
+
+	return res
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func toHierarchyRes(employees []*model.HierarchyEmployee) []*transport.HierarchyEmployeeRes {
+	res := make([]*transport.HierarchyEmployeeRes, 0, len(employees))
+	for _, employee := range employees {
+		res = append(res, &transport.HierarchyEmployeeRes{EmployeeRes: *toEmployeeRes(&employee.Employee), Depth: employee.Depth})
+	}
+
+	return res
+}
+
+func toPositionAssignmentRes(assignment *model.PositionAssignment) *transport.PositionAssignmentRes {
+	return &transport.PositionAssignmentRes{
+		ID:            assignment.ID,
+		PositionID:    assignment.PositionID,
+		Title:         assignment.Title,
+		Grade:         assignment.Grade,
+		JobFamily:     assignment.JobFamily,
+		DepartmentID:  assignment.DepartmentID,
+		Change:        assignment.Change,
+		EffectiveDate: assignment.EffectiveDate,
+		EndDate:       assignment.EndDate,
+	}
+}
+
+// sameID reports whether two optional ids point to the same row.
+func sameID(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func sortValue(employee *model.Employee, column string) string {
+	switch column {
+	case "first_name":
+		return employee.FirstName
+	case "last_name":
+		return employee.LastName
+	case "email":
+		return employee.Email
+	case "hire_date":
+		return employee.HireDate
+	default:
+		return strconv.Itoa(employee.ID)
+	}
 }
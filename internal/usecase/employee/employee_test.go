@@ -3,15 +3,27 @@ package employee
 import (
 	"context"
 	"database/sql"
+	"employee/internal/apperror"
 	"employee/internal/model"
+	"employee/internal/pkg"
+	auditRepoMock "employee/internal/repository/audit/mock"
+	eRepo "employee/internal/repository/employee"
 	employeeRepoMock "employee/internal/repository/employee/mock"
+	txMock "employee/internal/repository/mock"
+	positionRepoMock "employee/internal/repository/position/mock"
+	userRepoMock "employee/internal/repository/user/mock"
+	"employee/internal/storage"
 	"employee/internal/transport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateEmployee(t *testing.T) {
+	managerID := 7
 
 	payload := &transport.CreateEmployeeReq{
 		FirstName: "test",
@@ -20,11 +32,21 @@ func TestCreateEmployee(t *testing.T) {
 		HireDate:  "2023-05-03",
 	}
 
+	payloadWithAccount := &transport.CreateEmployeeReq{
+		FirstName:     "test",
+		LastName:      "test",
+		Email:         "test@test.com",
+		HireDate:      "2023-05-03",
+		CreateAccount: true,
+	}
+
 	testCases := []struct {
 		name      string
 		payload   *transport.CreateEmployeeReq
 		buildStub func(
 			employeeRepo *employeeRepoMock.DBMock,
+			userRepo *userRepoMock.DBMock,
+			transactor *txMock.TransactorMock,
 		)
 		checkReturn func(user *transport.EmployeeRes, err error)
 	}{
@@ -32,7 +54,8 @@ func TestCreateEmployee(t *testing.T) {
 		{
 			name:    "error when create employee",
 			payload: payload,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
 				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
 			},
 			checkReturn: func(user *transport.EmployeeRes, err error) {
@@ -43,12 +66,95 @@ func TestCreateEmployee(t *testing.T) {
 		{
 			name:    "success when create employee",
 			payload: payload,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
 				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(1, nil)
 			},
 			checkReturn: func(user *transport.EmployeeRes, err error) {
 				assert.NotNil(t, user)
 				assert.NoError(t, err)
+				assert.Nil(t, user.Account)
+			},
+		},
+		{
+			name: "success when create employee with profile",
+			payload: &transport.CreateEmployeeReq{FirstName: "test", Email: "test@test.com", HireDate: "2023-05-03",
+				EmployeeProfile: transport.EmployeeProfile{
+					Phone:             "+14155550123",
+					Address:           &transport.Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+					EmergencyContacts: []transport.EmergencyContact{{Name: "Jane", Relationship: "spouse", Phone: "+14155550100"}},
+				}},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return *employee.Phone == "+14155550123" && employee.Address.City == "Springfield" &&
+						len(employee.EmergencyContacts) == 1 && employee.NationalID == nil && employee.DateOfBirth == nil
+				})).Return(1, nil)
+			},
+			checkReturn: func(user *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "+14155550123", *user.Phone)
+				assert.Equal(t, "US", user.Address.Country)
+				assert.Equal(t, "Jane", user.EmergencyContacts[0].Name)
+				assert.Nil(t, user.WorkLocation)
+			},
+		},
+		{
+			name:    "error when manager does not exist",
+			payload: &transport.CreateEmployeeReq{FirstName: "test", Email: "test@test.com", HireDate: "2023-05-03", ManagerID: &managerID},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(user *transport.EmployeeRes, err error) {
+				assert.Nil(t, user)
+				assert.ErrorIs(t, err, ErrManagerNotFound)
+			},
+		},
+		{
+			name:    "success when create employee with manager",
+			payload: &transport.CreateEmployeeReq{FirstName: "test", Email: "test@test.com", HireDate: "2023-05-03", ManagerID: &managerID},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return(&model.Employee{ID: managerID}, nil)
+				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.ManagerID != nil && *employee.ManagerID == managerID
+				})).Return(1, nil)
+			},
+			checkReturn: func(user *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, managerID, *user.ManagerID)
+			},
+		},
+		{
+			name:    "error when provision account",
+			payload: payloadWithAccount,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(1, nil)
+				userRepoMock.On("CreateUser", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(user *transport.EmployeeRes, err error) {
+				assert.Nil(t, user)
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "success when create employee with account",
+			payload: payloadWithAccount,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, userRepoMock *userRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				employeeRepoMock.On("CreateEmployee", mock.Anything, mock.Anything).Return(1, nil)
+				userRepoMock.On("CreateUser", mock.Anything, mock.MatchedBy(func(user *model.User) bool {
+					return user.Role == "viewer" && *user.EmployeeID == 1 && user.PasswordHash != ""
+				})).Return(5, nil)
+			},
+			checkReturn: func(user *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				require.NotNil(t, user.Account)
+				assert.Equal(t, 5, user.Account.UserID)
+				assert.Len(t, user.Account.InitialPassword, pkg.DefaultPasswordPolicy.Length)
+				assert.GreaterOrEqual(t, user.Account.PasswordStrength.Score, 3)
 			},
 		},
 	}
@@ -56,9 +162,13 @@ func TestCreateEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			employeeRepository := new(employeeRepoMock.DBMock)
-			tc.buildStub(employeeRepository)
+			userRepository := new(userRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			auditRepository.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(employeeRepository, userRepository, transactor)
 
-			u := NewUseCaseEmployee(employeeRepository)
+			u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
 			result, err := u.CreateEmployee(context.TODO(), tc.payload)
 
 			tc.checkReturn(result, err)
@@ -67,6 +177,253 @@ func TestCreateEmployee(t *testing.T) {
 	}
 }
 
+func TestCreateEmployeePasswordPolicy(t *testing.T) {
+	employeeRepository := new(employeeRepoMock.DBMock)
+	employeeRepository.On("CreateEmployee", mock.Anything, mock.Anything).Return(1, nil)
+	userRepository := new(userRepoMock.DBMock)
+	userRepository.On("CreateUser", mock.Anything, mock.Anything).Return(5, nil)
+	auditRepository := new(auditRepoMock.DBMock)
+	auditRepository.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+	transactor := new(txMock.TransactorMock)
+	transactor.On("WithinTransaction", mock.Anything).Return(nil)
+
+	policy := pkg.PasswordPolicy{Passphrase: true, Words: 5, Separator: "."}
+
+	u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, policy)
+	result, err := u.CreateEmployee(context.TODO(), &transport.CreateEmployeeReq{
+		FirstName:     "test",
+		LastName:      "test",
+		Email:         "test@test.com",
+		HireDate:      "2023-05-03",
+		CreateAccount: true,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Account)
+	assert.Len(t, strings.Split(result.Account.InitialPassword, "."), 5)
+}
+
+func TestImportEmployees(t *testing.T) {
+
+	newRows := func() []*transport.ImportRow {
+		invalid := &transport.ImportRow{Line: 4}
+		invalid.Reject("hire_date", "date", "Field 'hire_date' date")
+
+		return []*transport.ImportRow{
+			{Line: 2, Employee: transport.CreateEmployeeReq{FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01"}},
+			{Line: 3, Employee: transport.CreateEmployeeReq{FirstName: "jane", Email: "Jane@mail.com", HireDate: "2023-05-02"}},
+			invalid,
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		mode      string
+		dryRun    bool
+		buildStub func(
+			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
+		)
+		checkReturn func(res *transport.ImportEmployeesRes, err error)
+	}{
+		{
+			name: "error when get existing emails",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name: "all or nothing imports nothing when a row fails",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, []string{"john@mail.com", "Jane@mail.com"}).Return(map[string]bool{"jane@mail.com": true}, nil)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, transport.ImportModeAllOrNothing, res.Mode)
+				assert.Equal(t, 3, res.Total)
+				assert.Equal(t, 0, res.Imported)
+				assert.Equal(t, 2, res.Failed)
+				assert.Equal(t, []transport.ImportError{
+					{Line: 3, Field: "email", Rule: "unique", Message: "email already exists"},
+					{Line: 4, Field: "hire_date", Rule: "date", Message: "Field 'hire_date' date"},
+				}, res.Errors)
+			},
+		},
+		{
+			name:   "dry run reports without writing",
+			mode:   transport.ImportModeBestEffort,
+			dryRun: true,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				require.NoError(t, err)
+				assert.True(t, res.DryRun)
+				assert.Equal(t, 2, res.Imported)
+				assert.Equal(t, 1, res.Failed)
+				assert.Empty(t, res.Employees)
+			},
+		},
+		{
+			name: "error when create employees",
+			mode: transport.ImportModeBestEffort,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+				employeeRepoMock.On("CreateEmployees", mock.Anything, mock.Anything).Return([]*model.Employee(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name: "best effort imports the valid rows",
+			mode: transport.ImportModeBestEffort,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+				employeeRepoMock.On("CreateEmployees", mock.Anything, mock.MatchedBy(func(employees []*model.Employee) bool {
+					return len(employees) == 2
+				})).Return([]*model.Employee{{ID: 10, FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01"}}, nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionCreate && audit.EmployeeID == 10
+				})).Return(nil).Once()
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1, res.Imported)
+				assert.Equal(t, 2, res.Failed)
+				require.Len(t, res.Employees, 1)
+				assert.Equal(t, 10, res.Employees[0].ID)
+				assert.Equal(t, 3, res.Errors[0].Line)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			res, err := u.ImportEmployees(context.TODO(), &transport.ImportEmployeesReq{Mode: tc.mode, DryRun: tc.dryRun, Rows: newRows()})
+
+			tc.checkReturn(res, err)
+
+			employeeRepository.AssertExpectations(t)
+			auditRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportEmployeesReferences(t *testing.T) {
+	departmentID, managerID, positionID, unknownID := 1, 2, 3, 9
+
+	newRows := func() []*transport.ImportRow {
+		return []*transport.ImportRow{
+			{Line: 2, Employee: transport.CreateEmployeeReq{FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01",
+				DepartmentID: &departmentID, ManagerID: &managerID, PositionID: &positionID, Status: model.EmploymentStatusProbation,
+				EmployeeProfile: transport.EmployeeProfile{Phone: "+14155550123", WorkLocation: "Remote"}}},
+			{Line: 3, Employee: transport.CreateEmployeeReq{FirstName: "jane", Email: "jane@mail.com", HireDate: "2023-05-02", ManagerID: &managerID, PositionID: &positionID}},
+			{Line: 4, Employee: transport.CreateEmployeeReq{FirstName: "joe", Email: "joe@mail.com", HireDate: "2023-05-02", DepartmentID: &unknownID}},
+			{Line: 5, Employee: transport.CreateEmployeeReq{FirstName: "ann", Email: "ann@mail.com", HireDate: "2023-05-02", ManagerID: &unknownID}},
+			{Line: 6, Employee: transport.CreateEmployeeReq{FirstName: "bob", Email: "bob@mail.com", HireDate: "2023-05-02", PositionID: &unknownID}},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		buildStub func(
+			employeeRepo *employeeRepoMock.DBMock,
+			positionRepo *positionRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
+		)
+		checkReturn func(res *transport.ImportEmployeesRes, err error)
+	}{
+		{
+			name: "error when get existing departments",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, positionRepoMock *positionRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+				employeeRepoMock.On("GetExistingDepartments", mock.Anything, []int{departmentID, unknownID}).Return(map[int]bool(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name: "error when get manager",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, positionRepoMock *positionRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+				employeeRepoMock.On("GetExistingDepartments", mock.Anything, mock.Anything).Return(map[int]bool{departmentID: true}, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return((*model.Employee)(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name: "best effort rejects unknown references and starts the position history",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, positionRepoMock *positionRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetExistingEmails", mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+				employeeRepoMock.On("GetExistingDepartments", mock.Anything, mock.Anything).Return(map[int]bool{departmentID: true}, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, managerID, false).Return(&model.Employee{ID: managerID}, nil).Once()
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, unknownID, false).Return((*model.Employee)(nil), nil).Once()
+				positionRepoMock.On("GetPositionByID", mock.Anything, positionID).Return(&model.Position{ID: positionID}, nil).Once()
+				positionRepoMock.On("GetPositionByID", mock.Anything, unknownID).Return((*model.Position)(nil), nil).Once()
+				employeeRepoMock.On("CreateEmployees", mock.Anything, mock.MatchedBy(func(employees []*model.Employee) bool {
+					return len(employees) == 2 && *employees[0].DepartmentID == departmentID && *employees[0].ManagerID == managerID &&
+						employees[0].Status == model.EmploymentStatusProbation && *employees[0].Phone == "+14155550123" &&
+						*employees[0].WorkLocation == "Remote" && employees[1].Status == model.EmploymentStatusActive
+				})).Return([]*model.Employee{
+					{ID: 10, FirstName: "john", Email: "john@mail.com", HireDate: "2023-05-01", DepartmentID: &departmentID, PositionID: &positionID},
+				}, nil)
+				positionRepoMock.On("CreateAssignment", mock.Anything, &model.PositionAssignment{
+					EmployeeID: 10, PositionID: positionID, DepartmentID: &departmentID, Change: model.PositionChangeInitial, EffectiveDate: "2023-05-01",
+				}).Return(nil).Once()
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			checkReturn: func(res *transport.ImportEmployeesRes, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1, res.Imported)
+				assert.Equal(t, []transport.ImportError{
+					{Line: 3, Field: "email", Rule: "unique", Message: "email already exists"},
+					{Line: 4, Field: "department_id", Rule: "exists", Message: "department_id does not exist"},
+					{Line: 5, Field: "manager_id", Rule: "exists", Message: "manager_id does not exist"},
+					{Line: 6, Field: "position_id", Rule: "exists", Message: "position_id does not exist"},
+				}, res.Errors)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			positionRepository := new(positionRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, positionRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, positionRepository, noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			res, err := u.ImportEmployees(context.TODO(), &transport.ImportEmployeesReq{Mode: transport.ImportModeBestEffort, Rows: newRows()})
+
+			tc.checkReturn(res, err)
+
+			employeeRepository.AssertExpectations(t)
+			positionRepository.AssertExpectations(t)
+			auditRepository.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetEmployee(t *testing.T) {
 
 	mockEmployeesResult := []*model.Employee{
@@ -79,8 +436,16 @@ func TestGetEmployee(t *testing.T) {
 		},
 	}
 
+	mockEmployeesNextPage := []*model.Employee{
+		{ID: 3, FirstName: "test", HireDate: "2023-05-03"},
+		{ID: 2, FirstName: "test", HireDate: "2023-05-02"},
+	}
+
+	validCursor, _ := pkg.EncodeCursor(&model.EmployeeCursor{Value: "2", ID: 2})
+
 	testCases := []struct {
 		name      string
+		payload   *transport.ListEmployeesReq
 		buildStub func(
 			employeeRepo *employeeRepoMock.DBMock,
 		)
@@ -88,8 +453,21 @@ func TestGetEmployee(t *testing.T) {
 	}{
 
 		{
-			name: "error when get all employee",
+			name:    "error when count employee",
+			payload: &transport.ListEmployeesReq{},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("CountEmployees", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(employees *transport.ListEmployees, err error) {
+				assert.Nil(t, employees)
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "error when get all employee",
+			payload: &transport.ListEmployeesReq{},
 			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("CountEmployees", mock.Anything, mock.Anything).Return(1, nil)
 				employeeRepoMock.On("GetEmployees", mock.Anything, mock.Anything).Return([]*model.Employee{}, sql.ErrConnDone)
 			},
 			checkReturn: func(employees *transport.ListEmployees, err error) {
@@ -98,13 +476,72 @@ func TestGetEmployee(t *testing.T) {
 			},
 		},
 		{
-			name: "success when get employee",
+			name:    "error when cursor is malformed",
+			payload: &transport.ListEmployeesReq{Cursor: "not-a-cursor"},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+			},
+			checkReturn: func(employees *transport.ListEmployees, err error) {
+				assert.Nil(t, employees)
+				assert.ErrorIs(t, err, ErrInvalidCursor)
+			},
+		},
+		{
+			name:    "error when cursor is combined with offset",
+			payload: &transport.ListEmployeesReq{Cursor: validCursor, Offset: 10},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+			},
+			checkReturn: func(employees *transport.ListEmployees, err error) {
+				assert.Nil(t, employees)
+				assert.ErrorIs(t, err, ErrInvalidCursor)
+			},
+		},
+		{
+			name:    "success when get employee",
+			payload: &transport.ListEmployeesReq{},
 			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployees", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("CountEmployees", mock.Anything, mock.Anything).Return(1, nil)
+				employeeRepoMock.On("GetEmployees", mock.Anything, mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+					return filter.Limit == defaultPageLimit+1
+				})).Return(mockEmployeesResult, nil)
 			},
 			checkReturn: func(employees *transport.ListEmployees, err error) {
 				assert.NotNil(t, employees)
 				assert.NoError(t, err)
+				assert.Equal(t, 1, employees.Pagination.Total)
+				assert.Empty(t, employees.Pagination.NextCursor)
+			},
+		},
+		{
+			name:    "success passes the status filter",
+			payload: &transport.ListEmployeesReq{Status: model.EmploymentStatusTerminated},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				statusFilter := mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+					return filter.Status == model.EmploymentStatusTerminated
+				})
+				employeeRepoMock.On("CountEmployees", mock.Anything, statusFilter).Return(1, nil)
+				employeeRepoMock.On("GetEmployees", mock.Anything, statusFilter).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employees *transport.ListEmployees, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, employees.Employees, 1)
+			},
+		},
+		{
+			name:    "success when another page exists",
+			payload: &transport.ListEmployeesReq{Limit: 1, Cursor: validCursor, Sort: "-hire_date"},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("CountEmployees", mock.Anything, mock.Anything).Return(5, nil)
+				employeeRepoMock.On("GetEmployees", mock.Anything, mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+					return filter.Cursor != nil && filter.Cursor.ID == 2 && filter.Limit == 2
+				})).Return(mockEmployeesNextPage, nil)
+			},
+			checkReturn: func(employees *transport.ListEmployees, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, employees.Employees, 1)
+
+				cursor := &model.EmployeeCursor{}
+				assert.NoError(t, pkg.DecodeCursor(employees.Pagination.NextCursor, cursor))
+				assert.Equal(t, &model.EmployeeCursor{Value: "2023-05-03", ID: 3}, cursor)
 			},
 		},
 	}
@@ -114,8 +551,8 @@ func TestGetEmployee(t *testing.T) {
 			employeeRepository := new(employeeRepoMock.DBMock)
 			tc.buildStub(employeeRepository)
 
-			u := NewUseCaseEmployee(employeeRepository)
-			result, err := u.GetEmployees(context.TODO())
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetEmployees(context.TODO(), tc.payload)
 
 			tc.checkReturn(result, err)
 
@@ -123,6 +560,58 @@ func TestGetEmployee(t *testing.T) {
 	}
 }
 
+func TestExportEmployees(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		payload     *transport.ExportEmployeesReq
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(exported []*transport.EmployeeRes, err error)
+	}{
+		{
+			name:    "error when stream employees",
+			payload: &transport.ExportEmployeesReq{Format: transport.ExportFormatCSV},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("StreamEmployees", mock.Anything, mock.Anything).Return([]*model.Employee{}, sql.ErrConnDone)
+			},
+			checkReturn: func(exported []*transport.EmployeeRes, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "success passes the filters",
+			payload: &transport.ExportEmployeesReq{Format: transport.ExportFormatCSV, FirstName: "jo", ManagerID: 3, Status: model.EmploymentStatusActive, Sort: "-hire_date"},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("StreamEmployees", mock.Anything, mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+					return filter.FirstName == "jo" && filter.ManagerID == 3 && filter.Status == model.EmploymentStatusActive && filter.Sort == "-hire_date" && filter.Limit == 0
+				})).Return([]*model.Employee{{ID: 1, FirstName: "john"}, {ID: 2, FirstName: "joe"}}, nil)
+			},
+			checkReturn: func(exported []*transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				require.Len(t, exported, 2)
+				assert.Equal(t, "joe", exported[1].FirstName)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			tc.buildStub(employeeRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+
+			var exported []*transport.EmployeeRes
+			err := u.ExportEmployees(context.TODO(), tc.payload, func(employee *transport.EmployeeRes) error {
+				exported = append(exported, employee)
+				return nil
+			})
+
+			tc.checkReturn(exported, err)
+		})
+	}
+}
+
 func TestGetEmployeeByID(t *testing.T) {
 
 	mockEmployeesResult := &model.Employee{
@@ -145,17 +634,27 @@ func TestGetEmployeeByID(t *testing.T) {
 		{
 			name: "error when get all employee",
 			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(&model.Employee{}, sql.ErrConnDone)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(&model.Employee{}, sql.ErrConnDone)
 			},
 			checkReturn: func(employees *transport.EmployeeRes, err error) {
 				assert.Nil(t, employees)
 				assert.Error(t, err)
 			},
 		},
+		{
+			name: "error when employee not found",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(employees *transport.EmployeeRes, err error) {
+				assert.Nil(t, employees)
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+			},
+		},
 		{
 			name: "success when get employee",
 			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
 			},
 			checkReturn: func(employees *transport.EmployeeRes, err error) {
 				assert.NotNil(t, employees)
@@ -169,8 +668,8 @@ func TestGetEmployeeByID(t *testing.T) {
 			employeeRepository := new(employeeRepoMock.DBMock)
 			tc.buildStub(employeeRepository)
 
-			u := NewUseCaseEmployee(employeeRepository)
-			result, err := u.GetEmployeeByID(context.TODO(), tc.employeeID)
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetEmployeeByID(context.TODO(), tc.employeeID, false)
 
 			tc.checkReturn(result, err)
 
@@ -179,6 +678,9 @@ func TestGetEmployeeByID(t *testing.T) {
 }
 
 func TestUpdateEmployee(t *testing.T) {
+	departmentID := 3
+	selfID := 1
+	reportID := 4
 
 	payload := &transport.UpdateEmployeeReq{
 		ID:        1,
@@ -186,6 +688,7 @@ func TestUpdateEmployee(t *testing.T) {
 		LastName:  "test",
 		Email:     "test@test.com",
 		HireDate:  "2023-05-03",
+		Version:   2,
 	}
 
 	mockEmployeesResult := &model.Employee{
@@ -194,6 +697,7 @@ func TestUpdateEmployee(t *testing.T) {
 		LastName:  "test",
 		Email:     "test@mail.com",
 		HireDate:  "2023-05-01",
+		Version:   2,
 	}
 
 	testCases := []struct {
@@ -201,6 +705,7 @@ func TestUpdateEmployee(t *testing.T) {
 		payload   *transport.UpdateEmployeeReq
 		buildStub func(
 			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
 		)
 		checkReturn func(err error)
 	}{
@@ -208,18 +713,28 @@ func TestUpdateEmployee(t *testing.T) {
 		{
 			name:    "error when get employee detail",
 			payload: payload,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(&model.Employee{}, sql.ErrConnDone)
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(&model.Employee{}, sql.ErrConnDone)
 			},
 			checkReturn: func(err error) {
 				assert.Error(t, err)
 			},
 		},
+		{
+			name:    "error when version does not match",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", Email: "test@test.com", HireDate: "2023-05-03", Version: 1},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, eRepo.ErrStaleVersion)
+			},
+		},
 		{
 			name:    "error when update employee",
 			payload: payload,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
 				employeeRepoMock.On("UpdateEmployee", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
 			},
 			checkReturn: func(err error) {
@@ -227,11 +742,106 @@ func TestUpdateEmployee(t *testing.T) {
 			},
 		},
 		{
-			name:    "success when update employee",
+			name:    "error when record audit",
 			payload: payload,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
 				employeeRepoMock.On("UpdateEmployee", mock.Anything, mock.Anything).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "success when update employee",
+			payload: payload,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("UpdateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.Version == 2
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionUpdate &&
+						audit.ActorName == "system" &&
+						string(audit.Diff) == `{"email":{"from":"test@mail.com","to":"test@test.com"},"hire_date":{"from":"2023-05-01","to":"2023-05-03"},"version":{"from":2,"to":3}}`
+				})).Return(nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "error when employee would manage themselves",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", Email: "test@mail.com", HireDate: "2023-05-01", ManagerID: &selfID, Version: 2},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, false).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrManagerCycle)
+			},
+		},
+		{
+			name:    "error when lock the management tree",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", Email: "test@mail.com", HireDate: "2023-05-01", ManagerID: &reportID, Version: 2},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, reportID, false).Return(&model.Employee{ID: reportID}, nil)
+				employeeRepoMock.On("LockManagementTree", mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+		{
+			name:    "error when new manager reports to the employee",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", Email: "test@mail.com", HireDate: "2023-05-01", ManagerID: &reportID, Version: 2},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, reportID, false).Return(&model.Employee{ID: reportID}, nil)
+				employeeRepoMock.On("LockManagementTree", mock.Anything).Return(nil)
+				employeeRepoMock.On("GetManagementChain", mock.Anything, reportID).Return([]*model.HierarchyEmployee{
+					{Employee: model.Employee{ID: 2}, Depth: 1},
+					{Employee: model.Employee{ID: 1}, Depth: 2},
+				}, nil)
+			},
+			checkReturn: func(err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeUnprocessable))
+				assert.ErrorIs(t, err, ErrManagerCycle)
+			},
+		},
+		{
+			name:    "success when changing manager",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", LastName: "test", Email: "test@mail.com", HireDate: "2023-05-01", ManagerID: &reportID, Version: 2},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, reportID, false).Return(&model.Employee{ID: reportID}, nil)
+				employeeRepoMock.On("LockManagementTree", mock.Anything).Return(nil)
+				employeeRepoMock.On("GetManagementChain", mock.Anything, reportID).Return([]*model.HierarchyEmployee{
+					{Employee: model.Employee{ID: 9}, Depth: 1},
+				}, nil)
+				employeeRepoMock.On("UpdateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.ManagerID != nil && *employee.ManagerID == reportID
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return string(audit.Diff) == `{"manager_id":{"from":null,"to":4},"version":{"from":2,"to":3}}`
+				})).Return(nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "success when moving employee to a department",
+			payload: &transport.UpdateEmployeeReq{ID: 1, FirstName: "test", LastName: "test", Email: "test@mail.com", HireDate: "2023-05-01", DepartmentID: &departmentID, Version: 2},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("UpdateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.DepartmentID != nil && *employee.DepartmentID == departmentID
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return string(audit.Diff) == `{"department_id":{"from":null,"to":3},"version":{"from":2,"to":3}}`
+				})).Return(nil)
 			},
 			checkReturn: func(err error) {
 				assert.NoError(t, err)
@@ -242,9 +852,12 @@ func TestUpdateEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			employeeRepository := new(employeeRepoMock.DBMock)
-			tc.buildStub(employeeRepository)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
 
-			u := NewUseCaseEmployee(employeeRepository)
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
 			err := u.UpdateEmployee(context.TODO(), tc.payload)
 
 			tc.checkReturn(err)
@@ -253,29 +866,216 @@ func TestUpdateEmployee(t *testing.T) {
 	}
 }
 
-func TestDeleteEmployee(t *testing.T) {
+func TestPatchEmployee(t *testing.T) {
+
+	email := "new@mail.com"
+	mergePatch := func(id int, patch string, version int) *transport.PatchEmployeeReq {
+		return &transport.PatchEmployeeReq{ID: id, Type: transport.MIMEMergePatch, Patch: []byte(patch), Version: version}
+	}
+
 	mockEmployeesResult := &model.Employee{
 		ID:        1,
 		FirstName: "test",
 		LastName:  "test",
 		Email:     "test@mail.com",
 		HireDate:  "2023-05-01",
+		Version:   2,
 	}
 
 	testCases := []struct {
-		name       string
-		employeeID int
-		buildStub  func(
+		name      string
+		payload   *transport.PatchEmployeeReq
+		buildStub func(
 			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
 		)
-		checkReturn func(err error)
+		checkReturn func(employee *transport.EmployeeRes, err error)
 	}{
 
 		{
-			name:       "error when get employee detail",
-			employeeID: 1,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(&model.Employee{}, sql.ErrConnDone)
+			name:    "error when employee not found",
+			payload: mergePatch(1, `{"email":"new@mail.com"}`, 0),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+			},
+		},
+		{
+			name:    "unchanged fields are not written",
+			payload: mergePatch(1, `{"first_name":"test"}`, 0),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "test@mail.com", employee.Email)
+			},
+		},
+		{
+			name:    "error when version does not match",
+			payload: mergePatch(1, `{"email":"new@mail.com"}`, 1),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.ErrorIs(t, err, eRepo.ErrStaleVersion)
+			},
+		},
+		{
+			name:    "error when patch document is malformed",
+			payload: mergePatch(1, `{"email":`, 2),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "success patches the phone and keeps the profile",
+			payload: mergePatch(1, `{"phone":"+14155550123"}`, 2),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				workLocation := "Berlin"
+				withProfile := *mockEmployeesResult
+				withProfile.WorkLocation = &workLocation
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(&withProfile, nil)
+				employeeRepoMock.On("PatchEmployee", mock.Anything, 1, mock.MatchedBy(func(patch *model.EmployeePatch) bool {
+					return patch.Profile != nil && *patch.Profile.Phone == "+14155550123" && *patch.Profile.WorkLocation == "Berlin" &&
+						patch.Email == nil && patch.CustomFields == nil
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return strings.Contains(string(audit.Diff), "phone")
+				})).Return(nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "+14155550123", *employee.Phone)
+				assert.Equal(t, "Berlin", *employee.WorkLocation)
+			},
+		},
+		{
+			name:    "error when patched profile field is invalid",
+			payload: mergePatch(1, `{"phone":"12 34"}`, 2),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+		{
+			name:    "error when patched field is invalid",
+			payload: mergePatch(1, `{"hire_date":"01-05-2023"}`, 2),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.True(t, apperror.IsCode(err, apperror.CodeValidation))
+			},
+		},
+		{
+			name:    "error when patch employee",
+			payload: mergePatch(1, `{"email":"new@mail.com"}`, 0),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("PatchEmployee", mock.Anything, 1, mock.Anything).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.Nil(t, employee)
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:    "success when patch employee",
+			payload: mergePatch(1, `{"first_name":"test","email":"new@mail.com"}`, 2),
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("PatchEmployee", mock.Anything, 1, mock.MatchedBy(func(patch *model.EmployeePatch) bool {
+					return patch.FirstName == nil && patch.LastName == nil && patch.HireDate == nil && *patch.Email == email && patch.Version == 2
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionUpdate &&
+						string(audit.Diff) == `{"email":{"from":"test@mail.com","to":"new@mail.com"},"version":{"from":2,"to":3}}`
+				})).Return(nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, email, employee.Email)
+				assert.Equal(t, "test", employee.FirstName)
+				assert.Equal(t, 3, employee.Version)
+			},
+		},
+		{
+			name:    "success with json patch and wildcard version writes against the locked row",
+			payload: &transport.PatchEmployeeReq{ID: 1, Type: transport.MIMEJSONPatch, Patch: []byte(`[{"op":"replace","path":"/last_name","value":"mayer"}]`)},
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeForUpdate", mock.Anything, 1).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("PatchEmployee", mock.Anything, 1, mock.MatchedBy(func(patch *model.EmployeePatch) bool {
+					return *patch.LastName == "mayer" && patch.Email == nil && patch.Version == 2
+				})).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(employee *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "mayer", employee.LastName)
+				assert.Equal(t, 3, employee.Version)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.PatchEmployee(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+
+			employeeRepository.AssertExpectations(t)
+			auditRepository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteEmployee(t *testing.T) {
+	mockEmployeesResult := &model.Employee{
+		ID:        1,
+		FirstName: "test",
+		LastName:  "test",
+		Email:     "test@mail.com",
+		HireDate:  "2023-05-01",
+		Version:   2,
+	}
+
+	testCases := []struct {
+		name       string
+		employeeID int
+		version    int
+		buildStub  func(
+			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
+			userRepo *userRepoMock.DBMock,
+		)
+		checkReturn func(err error)
+	}{
+
+		{
+			name:       "error when get employee detail",
+			employeeID: 1,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(&model.Employee{}, sql.ErrConnDone)
 			},
 			checkReturn: func(err error) {
 				assert.Error(t, err)
@@ -284,20 +1084,49 @@ func TestDeleteEmployee(t *testing.T) {
 		{
 			name:       "error when update employee",
 			employeeID: 1,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
-				employeeRepoMock.On("DeleteEmployee", mock.Anything, mock.Anything).Return(sql.ErrConnDone)
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("DeleteEmployee", mock.Anything, 1, 2).Return(sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:       "error when disable the account",
+			employeeID: 1,
+			version:    2,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("DeleteEmployee", mock.Anything, 1, 2).Return(nil)
+				userRepoMock.On("DisableEmployeeUsers", mock.Anything, 1).Return(sql.ErrConnDone)
 			},
 			checkReturn: func(err error) {
 				assert.Error(t, err)
 			},
 		},
+		{
+			name:       "error when version does not match",
+			employeeID: 1,
+			version:    5,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, eRepo.ErrStaleVersion)
+			},
+		},
 		{
 			name:       "success when update employee",
 			employeeID: 1,
-			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock) {
-				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything).Return(mockEmployeesResult, nil)
-				employeeRepoMock.On("DeleteEmployee", mock.Anything, mock.Anything).Return(nil)
+			version:    2,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, mock.Anything, false).Return(mockEmployeesResult, nil)
+				employeeRepoMock.On("DeleteEmployee", mock.Anything, 1, 2).Return(nil)
+				userRepoMock.On("DisableEmployeeUsers", mock.Anything, 1).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionDelete && audit.EmployeeID == 1 && len(audit.Before) > 0
+				})).Return(nil)
 			},
 			checkReturn: func(err error) {
 				assert.NoError(t, err)
@@ -308,13 +1137,862 @@ func TestDeleteEmployee(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			employeeRepository := new(employeeRepoMock.DBMock)
-			tc.buildStub(employeeRepository)
+			auditRepository := new(auditRepoMock.DBMock)
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository, userRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			err := u.DeleteEmployee(context.TODO(), tc.employeeID, tc.version)
+
+			tc.checkReturn(err)
+
+		})
+	}
+}
+
+func TestRestoreEmployee(t *testing.T) {
+	deletedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		employeeID int
+		buildStub  func(
+			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
+			userRepo *userRepoMock.DBMock,
+		)
+		checkReturn func(err error)
+	}{
+
+		{
+			name:       "error when get employee detail",
+			employeeID: 1,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, true).Return(&model.Employee{}, sql.ErrConnDone)
+			},
+			checkReturn: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:       "error when employee is not deleted",
+			employeeID: 1,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, true).Return(&model.Employee{ID: 1}, nil)
+			},
+			checkReturn: func(err error) {
+				assert.ErrorIs(t, err, ErrEmployeeNotDeleted)
+			},
+		},
+		{
+			name:       "success when restore employee",
+			employeeID: 1,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, true).Return(&model.Employee{ID: 1, DeletedAt: &deletedAt}, nil)
+				employeeRepoMock.On("RestoreEmployee", mock.Anything, 1).Return(nil)
+				userRepoMock.On("EnableEmployeeUsers", mock.Anything, 1).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionRestore && audit.EmployeeID == 1
+				})).Return(nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "success keeps the account of a terminated employee disabled",
+			employeeID: 1,
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock, userRepoMock *userRepoMock.DBMock) {
+				employeeRepoMock.On("GetEmployeeByID", mock.Anything, 1, true).
+					Return(&model.Employee{ID: 1, Status: model.EmploymentStatusTerminated, DeletedAt: &deletedAt}, nil)
+				employeeRepoMock.On("RestoreEmployee", mock.Anything, 1).Return(nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository, userRepository)
 
-			u := NewUseCaseEmployee(employeeRepository)
-			err := u.DeleteEmployee(context.TODO(), tc.employeeID)
+			u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			err := u.RestoreEmployee(context.TODO(), tc.employeeID)
 
 			tc.checkReturn(err)
 
 		})
 	}
 }
+
+func TestPurgeDeletedEmployees(t *testing.T) {
+	retention := 30 * 24 * time.Hour
+
+	testCases := []struct {
+		name      string
+		buildStub func(
+			employeeRepo *employeeRepoMock.DBMock,
+			auditRepo *auditRepoMock.DBMock,
+		)
+		checkReturn func(purged int64, err error, store storage.Storage)
+	}{
+
+		{
+			name: "error when get document keys",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetPurgedDocumentKeys", mock.Anything, mock.Anything).Return([]string(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(purged int64, err error, store storage.Storage) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "error when purge employees keeps the files",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetPurgedDocumentKeys", mock.Anything, mock.Anything).Return([]string{"employees/3/contract"}, nil)
+				employeeRepoMock.On("PurgeEmployees", mock.Anything, mock.Anything).Return([]int(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(purged int64, err error, store storage.Storage) {
+				assert.Error(t, err)
+				_, err = store.Get(context.TODO(), "employees/3/contract")
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "success when purge employees",
+			buildStub: func(employeeRepoMock *employeeRepoMock.DBMock, auditRepoMock *auditRepoMock.DBMock) {
+				employeeRepoMock.On("GetPurgedDocumentKeys", mock.Anything, mock.Anything).Return([]string{"employees/3/contract", "employees/7/missing"}, nil)
+				employeeRepoMock.On("PurgeEmployees", mock.Anything, mock.MatchedBy(func(deletedBefore time.Time) bool {
+					return deletedBefore.Before(time.Now().Add(-retention + time.Minute))
+				})).Return([]int{3, 7}, nil)
+				auditRepoMock.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionPurge && audit.Before == nil && audit.After == nil
+				})).Return(nil).Times(2)
+			},
+			checkReturn: func(purged int64, err error, store storage.Storage) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), purged)
+				_, err = store.Get(context.TODO(), "employees/3/contract")
+				assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, store.Put(context.TODO(), "employees/3/contract", strings.NewReader("pdf"), 3, "application/pdf"))
+
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, store, pkg.DefaultPasswordPolicy)
+			purged, err := u.PurgeDeletedEmployees(context.TODO(), retention)
+
+			tc.checkReturn(purged, err, store)
+		})
+	}
+}
+
+func TestGetReports(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		depth       int
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.ListReports, err error)
+	}{
+		{
+			name: "error when employee not found",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 1, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.ListReports, err error) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success with default depth",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 1, false).Return(&model.Employee{ID: 1}, nil)
+				employeeRepo.On("GetReports", mock.Anything, 1, defaultReportsDepth).Return([]*model.HierarchyEmployee{
+					{Employee: model.Employee{ID: 2, FirstName: "jane"}, Depth: 1},
+				}, nil)
+			},
+			checkReturn: func(result *transport.ListReports, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Reports, 1)
+				assert.Equal(t, "jane", result.Reports[0].FirstName)
+				assert.Equal(t, 1, result.Reports[0].Depth)
+			},
+		},
+		{
+			name:  "success without reports",
+			depth: 3,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 1, false).Return(&model.Employee{ID: 1}, nil)
+				employeeRepo.On("GetReports", mock.Anything, 1, 3).Return([]*model.HierarchyEmployee(nil), nil)
+			},
+			checkReturn: func(result *transport.ListReports, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, result.Reports)
+				assert.Empty(t, result.Reports)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			tc.buildStub(employeeRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetReports(context.TODO(), 1, tc.depth)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetManagementChain(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.ManagementChain, err error)
+	}{
+		{
+			name: "error when get chain",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				employeeRepo.On("GetManagementChain", mock.Anything, 3).Return([]*model.HierarchyEmployee(nil), sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ManagementChain, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				employeeRepo.On("GetManagementChain", mock.Anything, 3).Return([]*model.HierarchyEmployee{
+					{Employee: model.Employee{ID: 2}, Depth: 1},
+					{Employee: model.Employee{ID: 1}, Depth: 2},
+				}, nil)
+			},
+			checkReturn: func(result *transport.ManagementChain, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Chain, 2)
+				assert.Equal(t, 1, result.Chain[1].ID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			tc.buildStub(employeeRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetManagementChain(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetOrgChart(t *testing.T) {
+	id := func(id int) *int { return &id }
+
+	testCases := []struct {
+		name        string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.OrgChart, err error)
+	}{
+		{
+			name: "error when stream employees",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("StreamEmployees", mock.Anything, mock.Anything).Return([]*model.Employee{}, sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.OrgChart, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success builds the tree",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("StreamEmployees", mock.Anything, mock.MatchedBy(func(filter *model.EmployeeFilter) bool {
+					return filter.Sort == "id" && !filter.IncludeDeleted
+				})).Return([]*model.Employee{
+					{ID: 1},
+					{ID: 2, ManagerID: id(1)},
+					{ID: 3, ManagerID: id(2)},
+					{ID: 4, ManagerID: id(1)},
+					// manager 9 is deleted, so 5 is a root
+					{ID: 5, ManagerID: id(9)},
+				}, nil)
+			},
+			checkReturn: func(result *transport.OrgChart, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 5, result.Total)
+				require.Len(t, result.Roots, 2)
+				assert.Equal(t, 1, result.Roots[0].ID)
+				require.Len(t, result.Roots[0].Reports, 2)
+				assert.Equal(t, 3, result.Roots[0].Reports[0].Reports[0].ID)
+				assert.Equal(t, 5, result.Roots[1].ID)
+			},
+		},
+		{
+			name: "success cuts a legacy cycle",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock) {
+				employeeRepo.On("StreamEmployees", mock.Anything, mock.Anything).Return([]*model.Employee{
+					{ID: 1, ManagerID: id(2)},
+					{ID: 2, ManagerID: id(1)},
+				}, nil)
+			},
+			checkReturn: func(result *transport.OrgChart, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Roots, 1)
+				assert.Equal(t, 1, result.Roots[0].ID)
+				require.Len(t, result.Roots[0].Reports, 1)
+				assert.Empty(t, result.Roots[0].Reports[0].Reports)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			tc.buildStub(employeeRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), new(positionRepoMock.DBMock), noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetOrgChart(context.TODO())
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestCreateEmployeeWithPosition(t *testing.T) {
+	positionID := 4
+	departmentID := 2
+
+	payload := &transport.CreateEmployeeReq{
+		FirstName:    "test",
+		Email:        "test@test.com",
+		HireDate:     "2023-05-03",
+		DepartmentID: &departmentID,
+		PositionID:   &positionID,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock)
+	}{
+		{
+			name: "error when position does not exist",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("GetPositionByID", mock.Anything, positionID).Return((*model.Position)(nil), nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrPositionNotFound)
+				assert.Nil(t, result)
+				employeeRepo.AssertNotCalled(t, "CreateEmployee", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "success records the initial position at the hire date",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("GetPositionByID", mock.Anything, positionID).Return(&model.Position{ID: positionID, Grade: 3}, nil)
+				employeeRepo.On("CreateEmployee", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.PositionID != nil && *employee.PositionID == positionID
+				})).Return(1, nil)
+				positionRepo.On("CreateAssignment", mock.Anything, mock.MatchedBy(func(assignment *model.PositionAssignment) bool {
+					return assignment.EmployeeID == 1 && assignment.PositionID == positionID &&
+						assignment.Change == model.PositionChangeInitial && assignment.EffectiveDate == "2023-05-03" &&
+						*assignment.DepartmentID == departmentID
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, positionID, *result.PositionID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			positionRepository := new(positionRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			auditRepository.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, positionRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, positionRepository, noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.CreateEmployee(context.TODO(), payload)
+
+			tc.checkReturn(result, err, employeeRepository)
+		})
+	}
+}
+
+func TestAssignPosition(t *testing.T) {
+	departmentID := 2
+	otherDepartmentID := 5
+
+	employee := &model.Employee{ID: 3, DepartmentID: &departmentID, Version: 4}
+	current := &model.PositionAssignment{ID: 10, EmployeeID: 3, PositionID: 1, Grade: 3, DepartmentID: &departmentID, EffectiveDate: "2023-05-03"}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.AssignPositionReq
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock)
+		checkReturn func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock)
+	}{
+		{
+			name:    "error when employee not found",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when position does not exist",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 2).Return((*model.Position)(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrPositionNotFound)
+			},
+		},
+		{
+			name:    "error when employee already holds the position",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 1, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1, Grade: 3}, nil)
+				positionRepo.On("GetCurrentAssignment", mock.Anything, 3).Return(current, nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrSamePosition)
+			},
+		},
+		{
+			name:    "error when effective date is not after the current one",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2023-05-03"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 2).Return(&model.Position{ID: 2, Grade: 4}, nil)
+				positionRepo.On("GetCurrentAssignment", mock.Anything, 3).Return(current, nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEffectiveDate)
+				positionRepo.AssertNotCalled(t, "EndAssignment", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "error when effective date is in the future",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: time.Now().AddDate(0, 0, 1).Format(dateLayout)},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrFutureEffective)
+				positionRepo.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "success promotion",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 2).Return(&model.Position{ID: 2, Title: "Senior Software Engineer", Grade: 4}, nil)
+				positionRepo.On("GetCurrentAssignment", mock.Anything, 3).Return(current, nil)
+				positionRepo.On("EndAssignment", mock.Anything, int64(10), "2024-01-01").Return(nil)
+				positionRepo.On("CreateAssignment", mock.Anything, mock.MatchedBy(func(assignment *model.PositionAssignment) bool {
+					return assignment.Change == model.PositionChangePromotion && *assignment.DepartmentID == departmentID
+				})).Return(nil)
+				employeeRepo.On("SetPosition", mock.Anything, 3, 2, &departmentID, 4).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionUpdate && strings.Contains(string(audit.Diff), "position_id")
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.PositionChangePromotion, result.Change)
+				assert.Equal(t, "Senior Software Engineer", result.Title)
+			},
+		},
+		{
+			name:    "success transfer to another department",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 1, DepartmentID: &otherDepartmentID, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1, Grade: 3}, nil)
+				positionRepo.On("GetCurrentAssignment", mock.Anything, 3).Return(current, nil)
+				positionRepo.On("EndAssignment", mock.Anything, int64(10), "2024-01-01").Return(nil)
+				positionRepo.On("CreateAssignment", mock.Anything, mock.Anything).Return(nil)
+				employeeRepo.On("SetPosition", mock.Anything, 3, 1, &otherDepartmentID, 4).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.PositionChangeTransfer, result.Change)
+				assert.Equal(t, otherDepartmentID, *result.DepartmentID)
+			},
+		},
+		{
+			name:    "success first position",
+			payload: &transport.AssignPositionReq{EmployeeID: 3, PositionID: 2, EffectiveDate: "2024-01-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 2).Return(&model.Position{ID: 2, Grade: 4}, nil)
+				positionRepo.On("GetCurrentAssignment", mock.Anything, 3).Return((*model.PositionAssignment)(nil), nil)
+				positionRepo.On("CreateAssignment", mock.Anything, mock.Anything).Return(nil)
+				employeeRepo.On("SetPosition", mock.Anything, 3, 2, &departmentID, 4).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.PositionAssignmentRes, err error, positionRepo *positionRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.PositionChangeInitial, result.Change)
+				positionRepo.AssertNotCalled(t, "EndAssignment", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			positionRepository := new(positionRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, positionRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, positionRepository, noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.AssignPosition(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err, positionRepository)
+		})
+	}
+}
+
+func TestGetPositionHistory(t *testing.T) {
+
+	endDate := "2024-01-01"
+
+	testCases := []struct {
+		name        string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock)
+		checkReturn func(result *transport.PositionHistory, err error)
+	}{
+		{
+			name: "error when employee not found",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionHistory, err error) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				positionRepo.On("GetAssignments", mock.Anything, 3).Return([]*model.PositionAssignment{
+					{ID: 11, PositionID: 2, Change: model.PositionChangePromotion, EffectiveDate: "2024-01-01"},
+					{ID: 10, PositionID: 1, Change: model.PositionChangeInitial, EffectiveDate: "2023-05-03", EndDate: &endDate},
+				}, nil)
+			},
+			checkReturn: func(result *transport.PositionHistory, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Positions, 2)
+				assert.Nil(t, result.Positions[0].EndDate)
+				assert.Equal(t, endDate, *result.Positions[1].EndDate)
+			},
+		},
+		{
+			name: "success without positions",
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, positionRepo *positionRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3}, nil)
+				positionRepo.On("GetAssignments", mock.Anything, 3).Return([]*model.PositionAssignment(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionHistory, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, result.Positions)
+				assert.Empty(t, result.Positions)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			positionRepository := new(positionRepoMock.DBMock)
+			tc.buildStub(employeeRepository, positionRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), new(auditRepoMock.DBMock), positionRepository, noCustomFields(), new(txMock.TransactorMock), nil, pkg.DefaultPasswordPolicy)
+			result, err := u.GetPositionHistory(context.TODO(), 3)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestTerminateEmployee(t *testing.T) {
+	payload := &transport.TerminateEmployeeReq{EmployeeID: 3, TerminationDate: "2024-03-01", Reason: "resigned"}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.TerminateEmployeeReq
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock)
+	}{
+		{
+			name:    "error when employee not found",
+			payload: payload,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return((*model.Employee)(nil), nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "error when termination is before the hire date",
+			payload: &transport.TerminateEmployeeReq{EmployeeID: 3, TerminationDate: "2023-05-02", Reason: "resigned"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusActive, Version: 2}, nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrTerminationBeforeHire)
+				employeeRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "error when employee is already terminated",
+			payload: payload,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusTerminated, Version: 2}, nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrStatusTransition)
+			},
+		},
+		{
+			name:    "success on the hire date",
+			payload: &transport.TerminateEmployeeReq{EmployeeID: 3, TerminationDate: "2023-05-03", Reason: "no show"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusProbation, Version: 2}, nil)
+				employeeRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+				userRepo.On("DisableEmployeeUsers", mock.Anything, 3).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionTerminate
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.EmploymentStatusTerminated, result.Status)
+				assert.Equal(t, "2023-05-03", *result.TerminationDate)
+				assert.Equal(t, 3, result.Version)
+			},
+		},
+		{
+			name:    "success",
+			payload: payload,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusOnLeave, Version: 2}, nil)
+				employeeRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.Status == model.EmploymentStatusTerminated && employee.HireDate == "2023-05-03" &&
+						*employee.TerminationReason == "resigned" && employee.Version == 2
+				})).Return(nil)
+				userRepo.On("DisableEmployeeUsers", mock.Anything, 3).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.Anything).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error, employeeRepo *employeeRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, "resigned", *result.TerminationReason)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository, userRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.TerminateEmployee(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err, employeeRepository)
+		})
+	}
+}
+
+func TestRehireEmployee(t *testing.T) {
+	terminationDate := "2024-03-01"
+	reason := "resigned"
+
+	testCases := []struct {
+		name        string
+		payload     *transport.RehireEmployeeReq
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error)
+	}{
+		{
+			name:    "error when employee is not terminated",
+			payload: &transport.RehireEmployeeReq{EmployeeID: 3, RehireDate: "2024-06-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusActive, Version: 2}, nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.ErrorIs(t, err, ErrStatusTransition)
+			},
+		},
+		{
+			name:    "error when rehire is not after the termination",
+			payload: &transport.RehireEmployeeReq{EmployeeID: 3, RehireDate: "2024-03-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusTerminated, TerminationDate: &terminationDate, TerminationReason: &reason, Version: 2}, nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.ErrorIs(t, err, ErrRehireBeforeTermination)
+			},
+		},
+		{
+			name:    "success",
+			payload: &transport.RehireEmployeeReq{EmployeeID: 3, RehireDate: "2024-06-01"},
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock, userRepo *userRepoMock.DBMock) {
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).
+					Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: model.EmploymentStatusTerminated, TerminationDate: &terminationDate, TerminationReason: &reason, Version: 2}, nil)
+				employeeRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(employee *model.Employee) bool {
+					return employee.Status == model.EmploymentStatusRehired && employee.HireDate == "2024-06-01" &&
+						employee.TerminationDate == nil && employee.TerminationReason == nil
+				})).Return(nil)
+				userRepo.On("EnableEmployeeUsers", mock.Anything, 3).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionRehire
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.EmploymentStatusRehired, result.Status)
+				assert.Equal(t, "2024-06-01", result.HireDate)
+				assert.Nil(t, result.TerminationDate)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			userRepository := new(userRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository, userRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, userRepository, auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.RehireEmployee(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      string
+		current     string
+		buildStub   func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock)
+		checkReturn func(result *transport.EmployeeRes, err error)
+	}{
+		{
+			name:      "error when active goes back to probation",
+			status:    model.EmploymentStatusProbation,
+			current:   model.EmploymentStatusActive,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.ErrorIs(t, err, ErrStatusTransition)
+			},
+		},
+		{
+			name:      "error when status does not change",
+			status:    model.EmploymentStatusOnLeave,
+			current:   model.EmploymentStatusOnLeave,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.ErrorIs(t, err, ErrStatusTransition)
+			},
+		},
+		{
+			name:    "success",
+			status:  model.EmploymentStatusActive,
+			current: model.EmploymentStatusOnLeave,
+			buildStub: func(employeeRepo *employeeRepoMock.DBMock, auditRepo *auditRepoMock.DBMock) {
+				employeeRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+				auditRepo.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+					return audit.Action == model.AuditActionUpdate
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.EmployeeRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.EmploymentStatusActive, result.Status)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employeeRepository := new(employeeRepoMock.DBMock)
+			employeeRepository.On("GetEmployeeByID", mock.Anything, 3, false).
+				Return(&model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", Status: tc.current, Version: 2}, nil)
+			auditRepository := new(auditRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(employeeRepository, auditRepository)
+
+			u := NewUseCaseEmployee(employeeRepository, new(userRepoMock.DBMock), auditRepository, new(positionRepoMock.DBMock), noCustomFields(), transactor, nil, pkg.DefaultPasswordPolicy)
+			result, err := u.ChangeStatus(context.TODO(), &transport.ChangeStatusReq{EmployeeID: 3, Status: tc.status})
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
@@ -4,26 +4,45 @@ import (
 	"context"
 	"employee/internal/transport"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type EmployeeUseCaseMock struct {
 	mock.Mock
 }
 
-func (m *EmployeeUseCaseMock) GetEmployees(ctx context.Context) (*transport.ListEmployees, error) {
-	args := m.Called(ctx)
+func (m *EmployeeUseCaseMock) GetEmployees(ctx context.Context, payload *transport.ListEmployeesReq) (*transport.ListEmployees, error) {
+	args := m.Called(ctx, payload)
 
 	return args.Get(0).(*transport.ListEmployees), args.Error(1)
 }
 
+// ExportEmployees calls fn with every employee given to Return.
+func (m *EmployeeUseCaseMock) ExportEmployees(ctx context.Context, payload *transport.ExportEmployeesReq, fn func(employee *transport.EmployeeRes) error) error {
+	args := m.Called(ctx, payload)
+	for _, employee := range args.Get(0).([]*transport.EmployeeRes) {
+		if err := fn(employee); err != nil {
+			return err
+		}
+	}
+
+	return args.Error(1)
+}
+
 func (m *EmployeeUseCaseMock) CreateEmployee(ctx context.Context, payload *transport.CreateEmployeeReq) (*transport.EmployeeRes, error) {
 	args := m.Called(ctx, payload)
 
 	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
 }
 
-func (m *EmployeeUseCaseMock) GetEmployeeByID(ctx context.Context, employeeID int) (*transport.EmployeeRes, error) {
-	args := m.Called(ctx, employeeID)
+func (m *EmployeeUseCaseMock) ImportEmployees(ctx context.Context, payload *transport.ImportEmployeesReq) (*transport.ImportEmployeesRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.ImportEmployeesRes), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) GetEmployeeByID(ctx context.Context, employeeID int, includeDeleted bool) (*transport.EmployeeRes, error) {
+	args := m.Called(ctx, employeeID, includeDeleted)
 
 	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
 }
@@ -34,8 +53,74 @@ func (m *EmployeeUseCaseMock) UpdateEmployee(ctx context.Context, payload *trans
 	return args.Error(0)
 }
 
-func (m *EmployeeUseCaseMock) DeleteEmployee(ctx context.Context, employeeID int) error {
+func (m *EmployeeUseCaseMock) PatchEmployee(ctx context.Context, payload *transport.PatchEmployeeReq) (*transport.EmployeeRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) DeleteEmployee(ctx context.Context, employeeID int, version int) error {
+	args := m.Called(ctx, employeeID, version)
+
+	return args.Error(0)
+}
+
+func (m *EmployeeUseCaseMock) RestoreEmployee(ctx context.Context, employeeID int) error {
 	args := m.Called(ctx, employeeID)
 
 	return args.Error(0)
 }
+
+func (m *EmployeeUseCaseMock) PurgeDeletedEmployees(ctx context.Context, retention time.Duration) (int64, error) {
+	args := m.Called(ctx, retention)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) GetReports(ctx context.Context, employeeID int, depth int) (*transport.ListReports, error) {
+	args := m.Called(ctx, employeeID, depth)
+
+	return args.Get(0).(*transport.ListReports), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) GetManagementChain(ctx context.Context, employeeID int) (*transport.ManagementChain, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.ManagementChain), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) GetOrgChart(ctx context.Context) (*transport.OrgChart, error) {
+	args := m.Called(ctx)
+
+	return args.Get(0).(*transport.OrgChart), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) AssignPosition(ctx context.Context, payload *transport.AssignPositionReq) (*transport.PositionAssignmentRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.PositionAssignmentRes), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) GetPositionHistory(ctx context.Context, employeeID int) (*transport.PositionHistory, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.PositionHistory), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) ChangeStatus(ctx context.Context, payload *transport.ChangeStatusReq) (*transport.EmployeeRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) TerminateEmployee(ctx context.Context, payload *transport.TerminateEmployeeReq) (*transport.EmployeeRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
+}
+
+func (m *EmployeeUseCaseMock) RehireEmployee(ctx context.Context, payload *transport.RehireEmployeeReq) (*transport.EmployeeRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.EmployeeRes), args.Error(1)
+}
@@ -0,0 +1,549 @@
+package leave
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/constant"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	"employee/internal/repository"
+	aRepo "employee/internal/repository/audit"
+	eRepo "employee/internal/repository/employee"
+	lRepo "employee/internal/repository/leave"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+	"math"
+	"time"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Leave")
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	ErrEmployeeNotFound     = apperror.NotFound("employee not found")
+	ErrEmployeeTerminated   = apperror.Unprocessable("employee_id", "employee is terminated")
+	ErrLeaveTypeNotFound    = apperror.Validation("leave_type_id", "leave_type_id does not exist")
+	ErrLeaveRequestNotFound = apperror.NotFound("leave request not found")
+	ErrInvalidRange         = apperror.Validation("end_date", "end_date must not be before start_date")
+	ErrNoWorkingDays        = apperror.Validation("end_date", "leave must cover at least one working day")
+	ErrLeaveOverlap         = apperror.Conflict("start_date", "leave overlaps approved leave")
+	ErrInsufficientBalance  = apperror.Unprocessable("leave_type_id", "leave balance is not sufficient")
+	ErrNotPending           = apperror.Conflict("status", "leave request is already decided")
+	ErrNotManager           = apperror.Forbidden("only the manager of the employee can decide on the leave request")
+)
+
+type UseCaseLeave interface {
+	CreateLeaveType(ctx context.Context, payload *transport.CreateLeaveTypeReq) (*transport.LeaveTypeRes, error)
+	GetLeaveTypes(ctx context.Context) (*transport.ListLeaveTypes, error)
+	GetBalances(ctx context.Context, employeeID int) (*transport.ListLeaveBalances, error)
+	CreateLeaveRequest(ctx context.Context, payload *transport.CreateLeaveRequestReq) (*transport.LeaveRequestRes, error)
+	GetLeaveRequests(ctx context.Context, employeeID int, payload *transport.ListLeaveRequestsReq) (*transport.ListLeaveRequests, error)
+	ApproveLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error)
+	RejectLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error)
+}
+
+type useCaseLeave struct {
+	leaveRepo    lRepo.LeaveRepo
+	employeeRepo eRepo.UserRepo
+	auditRepo    aRepo.AuditRepo
+	transactor   repository.Transactor
+}
+
+func NewUseCaseLeave(leaveRepo lRepo.LeaveRepo, employeeRepo eRepo.UserRepo, auditRepo aRepo.AuditRepo, transactor repository.Transactor) UseCaseLeave {
+	return &useCaseLeave{leaveRepo: leaveRepo, employeeRepo: employeeRepo, auditRepo: auditRepo, transactor: transactor}
+}
+
+func (u *useCaseLeave) CreateLeaveType(ctx context.Context, payload *transport.CreateLeaveTypeReq) (*transport.LeaveTypeRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreateLeaveType")
+
+	leaveType := &model.LeaveType{
+		Name:          payload.Name,
+		AccrualDays:   payload.AccrualDays,
+		AccrualPeriod: payload.AccrualPeriod,
+		MaxBalance:    payload.MaxBalance,
+	}
+
+	if err := u.leaveRepo.CreateLeaveType(ctx, leaveType); err != nil {
+		uLog.Errorf("error when call leaveRepo.CreateLeaveType got %s", err.Error())
+		return nil, err
+	}
+
+	return toLeaveTypeRes(leaveType), nil
+}
+
+func (u *useCaseLeave) GetLeaveTypes(ctx context.Context) (*transport.ListLeaveTypes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetLeaveTypes")
+
+	leaveTypes, err := u.leaveRepo.GetLeaveTypes(ctx)
+	if err != nil {
+		uLog.Errorf("error when call leaveRepo.GetLeaveTypes got %s", err.Error())
+		return nil, err
+	}
+
+	res := make([]*transport.LeaveTypeRes, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		res = append(res, toLeaveTypeRes(leaveType))
+	}
+
+	return &transport.ListLeaveTypes{LeaveTypes: res}, nil
+}
+
+// GetBalances returns the balance of every leave type accrued up to today.
+// The accrual is only stored when leave is deducted from the balance.
+func (u *useCaseLeave) GetBalances(ctx context.Context, employeeID int) (*transport.ListLeaveBalances, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetBalances")
+
+	employee, err := u.findEmployee(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	leaveTypes, err := u.leaveRepo.GetLeaveTypes(ctx)
+	if err != nil {
+		uLog.Errorf("error when call leaveRepo.GetLeaveTypes got %s", err.Error())
+		return nil, err
+	}
+
+	stored, err := u.leaveRepo.GetBalances(ctx, employeeID)
+	if err != nil {
+		uLog.Errorf("error when call leaveRepo.GetBalances got %s", err.Error())
+		return nil, err
+	}
+
+	byType := make(map[int]*model.LeaveBalance, len(stored))
+	for _, balance := range stored {
+		byType[balance.LeaveTypeID] = balance
+	}
+
+	today := time.Now()
+
+	res := make([]*transport.LeaveBalanceRes, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		balance := byType[leaveType.ID]
+		if balance == nil {
+			balance = openingBalance(employee, leaveType.ID)
+		}
+
+		accrue(balance, leaveType, accrualDay(employee), today)
+
+		res = append(res, &transport.LeaveBalanceRes{
+			LeaveTypeID:    leaveType.ID,
+			LeaveType:      leaveType.Name,
+			Balance:        balance.Balance,
+			AccruedThrough: balance.AccruedThrough,
+		})
+	}
+
+	return &transport.ListLeaveBalances{Balances: res}, nil
+}
+
+// CreateLeaveRequest files a pending leave request. It is rejected when it
+// overlaps approved leave or asks for more days than the accrued balance.
+func (u *useCaseLeave) CreateLeaveRequest(ctx context.Context, payload *transport.CreateLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreateLeaveRequest")
+
+	if payload.EndDate < payload.StartDate {
+		return nil, ErrInvalidRange
+	}
+
+	days := workingDays(payload.StartDate, payload.EndDate)
+	if days == 0 {
+		return nil, ErrNoWorkingDays
+	}
+
+	request := &model.LeaveRequest{
+		EmployeeID:  payload.EmployeeID,
+		LeaveTypeID: payload.LeaveTypeID,
+		StartDate:   payload.StartDate,
+		EndDate:     payload.EndDate,
+		Days:        days,
+		Reason:      payload.Reason,
+	}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		employee, err := u.lockEmployee(ctx, payload.EmployeeID)
+		if err != nil {
+			uLog.Errorf("error when lock employee got %s", err.Error())
+			return err
+		}
+
+		if employee.Status == model.EmploymentStatusTerminated {
+			uLog.Errorf("employee %d is terminated", employee.ID)
+			return ErrEmployeeTerminated
+		}
+
+		if err := u.checkOverlap(ctx, request); err != nil {
+			uLog.Errorf("error when check overlap got %s", err.Error())
+			return err
+		}
+
+		balance, err := u.currentBalance(ctx, employee, payload.LeaveTypeID)
+		if err != nil {
+			uLog.Errorf("error when get current balance got %s", err.Error())
+			return err
+		}
+
+		if balance.Balance < request.Days {
+			uLog.Errorf("employee %d asks for %.2f days with %.2f left", employee.ID, request.Days, balance.Balance)
+			return ErrInsufficientBalance
+		}
+
+		if err := u.leaveRepo.CreateLeaveRequest(ctx, request); err != nil {
+			uLog.Errorf("error when call leaveRepo.CreateLeaveRequest got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toLeaveRequestRes(request), nil
+}
+
+func (u *useCaseLeave) GetLeaveRequests(ctx context.Context, employeeID int, payload *transport.ListLeaveRequestsReq) (*transport.ListLeaveRequests, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetLeaveRequests")
+
+	if _, err := u.findEmployee(ctx, employeeID); err != nil {
+		uLog.Errorf("error when find employee got %s", err.Error())
+		return nil, err
+	}
+
+	requests, err := u.leaveRepo.GetLeaveRequests(ctx, employeeID, payload.Status)
+	if err != nil {
+		uLog.Errorf("error when call leaveRepo.GetLeaveRequests got %s", err.Error())
+		return nil, err
+	}
+
+	res := make([]*transport.LeaveRequestRes, 0, len(requests))
+	for _, request := range requests {
+		res = append(res, toLeaveRequestRes(request))
+	}
+
+	return &transport.ListLeaveRequests{LeaveRequests: res}, nil
+}
+
+// ApproveLeaveRequest approves a pending leave request and deducts its days
+// from the balance in the same transaction. Overlap and balance are checked
+// again since other leave may have been approved after the request was filed.
+func (u *useCaseLeave) ApproveLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "ApproveLeaveRequest")
+
+	return u.decide(ctx, payload, model.LeaveStatusApproved, func(ctx context.Context, employee *model.Employee, request *model.LeaveRequest) error {
+		if err := u.checkOverlap(ctx, request); err != nil {
+			uLog.Errorf("error when check overlap got %s", err.Error())
+			return err
+		}
+
+		balance, err := u.currentBalance(ctx, employee, request.LeaveTypeID)
+		if err != nil {
+			uLog.Errorf("error when get current balance got %s", err.Error())
+			return err
+		}
+
+		if balance.Balance < request.Days {
+			uLog.Errorf("leave request %d asks for %.2f days with %.2f left", request.ID, request.Days, balance.Balance)
+			return ErrInsufficientBalance
+		}
+
+		balance.Balance = roundDays(balance.Balance - request.Days)
+
+		if err := u.leaveRepo.SaveBalance(ctx, balance); err != nil {
+			uLog.Errorf("error when call leaveRepo.SaveBalance got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (u *useCaseLeave) RejectLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	return u.decide(ctx, payload, model.LeaveStatusRejected, nil)
+}
+
+// decide writes the decision of a pending leave request after fn ran in the
+// same transaction and records it in the audit of the employee. Only the
+// manager of the employee, or an admin, decides.
+func (u *useCaseLeave) decide(ctx context.Context, payload *transport.DecideLeaveRequestReq, status string,
+	fn func(ctx context.Context, employee *model.Employee, request *model.LeaveRequest) error) (*transport.LeaveRequestRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "decide")
+
+	var request *model.LeaveRequest
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = u.leaveRepo.GetLeaveRequestByID(ctx, payload.LeaveRequestID)
+		if err != nil {
+			uLog.Errorf("error when call leaveRepo.GetLeaveRequestByID got %s", err.Error())
+			return err
+		}
+
+		if request == nil {
+			uLog.Errorf("error when call leaveRepo.GetLeaveRequestByID got %s", ErrLeaveRequestNotFound.Error())
+			return ErrLeaveRequestNotFound
+		}
+
+		employee, err := u.lockEmployee(ctx, request.EmployeeID)
+		if err != nil {
+			uLog.Errorf("error when lock employee got %s", err.Error())
+			return err
+		}
+
+		claims := pkg.ClaimsFromContext(ctx)
+		if !canDecide(claims, employee) {
+			uLog.Errorf("caller is not the manager of employee %d", employee.ID)
+			return ErrNotManager
+		}
+
+		if request.Status != model.LeaveStatusPending {
+			uLog.Errorf("leave request %d is %s", request.ID, request.Status)
+			return ErrNotPending
+		}
+
+		if fn != nil {
+			if err := fn(ctx, employee, request); err != nil {
+				return err
+			}
+		}
+
+		before := toLeaveRequestRes(request)
+
+		request.Status = status
+		request.DecidedBy = &claims.Subject
+		if payload.Note != "" {
+			request.DecisionNote = &payload.Note
+		}
+
+		if err := u.leaveRepo.DecideLeaveRequest(ctx, request); err != nil {
+			uLog.Errorf("error when call leaveRepo.DecideLeaveRequest got %s", err.Error())
+			return err
+		}
+
+		action := model.AuditActionApproveLeave
+		if status == model.LeaveStatusRejected {
+			action = model.AuditActionRejectLeave
+		}
+
+		audit, err := pkg.NewAudit(ctx, action, employee.ID, before, toLeaveRequestRes(request))
+		if err != nil {
+			return err
+		}
+
+		if err := u.auditRepo.CreateAudit(ctx, audit); err != nil {
+			uLog.Errorf("error when call auditRepo.CreateAudit got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toLeaveRequestRes(request), nil
+}
+
+func (u *useCaseLeave) findEmployee(ctx context.Context, employeeID int) (*model.Employee, error) {
+	employee, err := u.employeeRepo.GetEmployeeByID(ctx, employeeID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	return employee, nil
+}
+
+// lockEmployee locks the employee row for the rest of the transaction and
+// returns the employee.
+func (u *useCaseLeave) lockEmployee(ctx context.Context, employeeID int) (*model.Employee, error) {
+	found, err := u.leaveRepo.LockEmployee(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ErrEmployeeNotFound
+	}
+
+	return u.findEmployee(ctx, employeeID)
+}
+
+func (u *useCaseLeave) checkOverlap(ctx context.Context, request *model.LeaveRequest) error {
+	count, err := u.leaveRepo.CountApprovedOverlaps(ctx, request.EmployeeID, request.StartDate, request.EndDate)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrLeaveOverlap
+	}
+
+	return nil
+}
+
+// currentBalance returns the balance of the leave type accrued up to today,
+// starting from the hire date when the employee has none yet.
+func (u *useCaseLeave) currentBalance(ctx context.Context, employee *model.Employee, leaveTypeID int) (*model.LeaveBalance, error) {
+	leaveType, err := u.leaveRepo.GetLeaveTypeByID(ctx, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if leaveType == nil {
+		return nil, ErrLeaveTypeNotFound
+	}
+
+	balance, err := u.leaveRepo.GetBalance(ctx, employee.ID, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if balance == nil {
+		balance = openingBalance(employee, leaveTypeID)
+	}
+
+	accrue(balance, leaveType, accrualDay(employee), time.Now())
+
+	return balance, nil
+}
+
+// canDecide reports whether the caller manages the employee directly or is an admin.
+func canDecide(claims *pkg.JWTClaim, employee *model.Employee) bool {
+	if claims == nil {
+		return false
+	}
+
+	if claims.Role == constant.RoleAdmin {
+		return true
+	}
+
+	return claims.EmployeeID != 0 && employee.ManagerID != nil && *employee.ManagerID == claims.EmployeeID
+}
+
+// openingBalance is the empty balance an employee starts with at the hire date.
+func openingBalance(employee *model.Employee, leaveTypeID int) *model.LeaveBalance {
+	return &model.LeaveBalance{EmployeeID: employee.ID, LeaveTypeID: leaveTypeID, AccruedThrough: hireDate(employee)}
+}
+
+// hireDate is the hire date of the employee as YYYY-MM-DD.
+func hireDate(employee *model.Employee) string {
+	if len(employee.HireDate) > len(dateLayout) {
+		return employee.HireDate[:len(dateLayout)]
+	}
+
+	return employee.HireDate
+}
+
+// accrue credits the balance for every accrual period completed between
+// AccruedThrough and today and moves AccruedThrough to the end of the last
+// one. Periods end on anchorDay, the day of the month the employee was hired,
+// or on the last day of the months shorter than that. A balance already above
+// MaxBalance is kept but does not grow.
+func accrue(balance *model.LeaveBalance, leaveType *model.LeaveType, anchorDay int, today time.Time) {
+	through, err := time.Parse(dateLayout, balance.AccruedThrough)
+	if err != nil {
+		return
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	months := 1
+	if leaveType.AccrualPeriod == model.AccrualPeriodYearly {
+		months = 12
+	}
+
+	elapsed := (today.Year()-through.Year())*12 + int(today.Month()) - int(through.Month())
+	if addMonths(through, elapsed, anchorDay).After(today) {
+		elapsed--
+	}
+
+	periods := elapsed / months
+	if periods <= 0 {
+		return
+	}
+
+	credited := balance.Balance + float64(periods)*leaveType.AccrualDays
+	if leaveType.MaxBalance != nil && credited > *leaveType.MaxBalance {
+		credited = math.Max(balance.Balance, *leaveType.MaxBalance)
+	}
+
+	balance.Balance = roundDays(credited)
+	balance.AccruedThrough = addMonths(through, periods*months, anchorDay).Format(dateLayout)
+}
+
+// addMonths moves the date months forward to the day of the target month,
+// clamped to its last day where time.AddDate would roll over into the next month.
+func addMonths(date time.Time, months int, day int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// accrualDay is the day of the month the accrual periods of the employee end
+// on, the day of the hire date.
+func accrualDay(employee *model.Employee) int {
+	hired, err := time.Parse(dateLayout, hireDate(employee))
+	if err != nil {
+		return 1
+	}
+
+	return hired.Day()
+}
+
+// workingDays counts the days from Monday to Friday between two dates
+// validated as YYYY-MM-DD, both included.
+func workingDays(startDate string, endDate string) float64 {
+	start, _ := time.Parse(dateLayout, startDate)
+	end, _ := time.Parse(dateLayout, endDate)
+
+	var days float64
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+
+	return days
+}
+
+// roundDays rounds to the hundredth of a day stored by the leave tables.
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
+
+func toLeaveTypeRes(leaveType *model.LeaveType) *transport.LeaveTypeRes {
+	return &transport.LeaveTypeRes{
+		ID:            leaveType.ID,
+		Name:          leaveType.Name,
+		AccrualDays:   leaveType.AccrualDays,
+		AccrualPeriod: leaveType.AccrualPeriod,
+		MaxBalance:    leaveType.MaxBalance,
+		CreatedAt:     leaveType.CreatedAt,
+	}
+}
+
+func toLeaveRequestRes(request *model.LeaveRequest) *transport.LeaveRequestRes {
+	return &transport.LeaveRequestRes{
+		ID:           request.ID,
+		EmployeeID:   request.EmployeeID,
+		LeaveTypeID:  request.LeaveTypeID,
+		StartDate:    request.StartDate,
+		EndDate:      request.EndDate,
+		Days:         request.Days,
+		Reason:       request.Reason,
+		Status:       request.Status,
+		DecidedBy:    request.DecidedBy,
+		DecidedAt:    request.DecidedAt,
+		DecisionNote: request.DecisionNote,
+		CreatedAt:    request.CreatedAt,
+	}
+}
@@ -0,0 +1,541 @@
+package leave
+
+import (
+	"context"
+	"employee/internal/constant"
+	"employee/internal/model"
+	"employee/internal/pkg"
+	auditRepoMock "employee/internal/repository/audit/mock"
+	employeeRepoMock "employee/internal/repository/employee/mock"
+	lRepo "employee/internal/repository/leave"
+	leaveRepoMock "employee/internal/repository/leave/mock"
+	txMock "employee/internal/repository/mock"
+	"employee/internal/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAccrue(t *testing.T) {
+	maxBalance := 20.0
+	today := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		balance         *model.LeaveBalance
+		leaveType       *model.LeaveType
+		anchorDay       int
+		expectedBalance float64
+		expectedThrough string
+	}{
+		{
+			name:            "monthly credits every completed month",
+			balance:         &model.LeaveBalance{Balance: 2, AccruedThrough: "2024-01-03"},
+			leaveType:       &model.LeaveType{AccrualDays: 1.67, AccrualPeriod: model.AccrualPeriodMonthly},
+			anchorDay:       3,
+			expectedBalance: 10.35,
+			expectedThrough: "2024-06-03",
+		},
+		{
+			name:            "monthly does not credit a month in progress",
+			balance:         &model.LeaveBalance{Balance: 2, AccruedThrough: "2024-05-15"},
+			leaveType:       &model.LeaveType{AccrualDays: 1.67, AccrualPeriod: model.AccrualPeriodMonthly},
+			anchorDay:       15,
+			expectedBalance: 2,
+			expectedThrough: "2024-05-15",
+		},
+		{
+			name:            "yearly credits every completed year",
+			balance:         &model.LeaveBalance{AccruedThrough: "2022-03-01"},
+			leaveType:       &model.LeaveType{AccrualDays: 5, AccrualPeriod: model.AccrualPeriodYearly},
+			anchorDay:       1,
+			expectedBalance: 10,
+			expectedThrough: "2024-03-01",
+		},
+		{
+			name:            "credit stops at the max balance",
+			balance:         &model.LeaveBalance{Balance: 18, AccruedThrough: "2024-01-03"},
+			leaveType:       &model.LeaveType{AccrualDays: 1.5, AccrualPeriod: model.AccrualPeriodMonthly, MaxBalance: &maxBalance},
+			anchorDay:       3,
+			expectedBalance: 20,
+			expectedThrough: "2024-06-03",
+		},
+		{
+			name:            "balance above the max balance is kept",
+			balance:         &model.LeaveBalance{Balance: 25, AccruedThrough: "2024-01-03"},
+			leaveType:       &model.LeaveType{AccrualDays: 1.5, AccrualPeriod: model.AccrualPeriodMonthly, MaxBalance: &maxBalance},
+			anchorDay:       3,
+			expectedBalance: 25,
+			expectedThrough: "2024-06-03",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			accrue(tc.balance, tc.leaveType, tc.anchorDay, today)
+
+			assert.Equal(t, tc.expectedBalance, tc.balance.Balance)
+			assert.Equal(t, tc.expectedThrough, tc.balance.AccruedThrough)
+		})
+	}
+}
+
+func TestAccrueMonthEnd(t *testing.T) {
+	monthly := &model.LeaveType{AccrualDays: 1, AccrualPeriod: model.AccrualPeriodMonthly}
+	yearly := &model.LeaveType{AccrualDays: 1, AccrualPeriod: model.AccrualPeriodYearly}
+
+	testCases := []struct {
+		name            string
+		accruedThrough  string
+		leaveType       *model.LeaveType
+		anchorDay       int
+		today           string
+		expectedBalance float64
+		expectedThrough string
+	}{
+		{
+			name:            "hired on the 31st accrues on the last day of february",
+			accruedThrough:  "2023-01-31",
+			leaveType:       monthly,
+			anchorDay:       31,
+			today:           "2023-02-28",
+			expectedBalance: 1,
+			expectedThrough: "2023-02-28",
+		},
+		{
+			name:            "hired on the 31st goes back to the 31st after a short month",
+			accruedThrough:  "2023-02-28",
+			leaveType:       monthly,
+			anchorDay:       31,
+			today:           "2023-03-31",
+			expectedBalance: 1,
+			expectedThrough: "2023-03-31",
+		},
+		{
+			name:            "hired on the 31st does not credit before the month ends",
+			accruedThrough:  "2023-03-31",
+			leaveType:       monthly,
+			anchorDay:       31,
+			today:           "2023-04-29",
+			expectedBalance: 0,
+			expectedThrough: "2023-03-31",
+		},
+		{
+			name:            "hired on the 31st accrues over several months",
+			accruedThrough:  "2024-01-31",
+			leaveType:       monthly,
+			anchorDay:       31,
+			today:           "2024-06-10",
+			expectedBalance: 4,
+			expectedThrough: "2024-05-31",
+		},
+		{
+			name:            "hired on the 30th accrues on the 28th of february",
+			accruedThrough:  "2023-01-30",
+			leaveType:       monthly,
+			anchorDay:       30,
+			today:           "2023-03-01",
+			expectedBalance: 1,
+			expectedThrough: "2023-02-28",
+		},
+		{
+			name:            "hired on the 30th goes back to the 30th",
+			accruedThrough:  "2023-02-28",
+			leaveType:       monthly,
+			anchorDay:       30,
+			today:           "2023-03-30",
+			expectedBalance: 1,
+			expectedThrough: "2023-03-30",
+		},
+		{
+			name:            "hired on the 29th accrues on the 29th of a leap february",
+			accruedThrough:  "2024-01-29",
+			leaveType:       monthly,
+			anchorDay:       29,
+			today:           "2024-02-29",
+			expectedBalance: 1,
+			expectedThrough: "2024-02-29",
+		},
+		{
+			name:            "hired on the 29th accrues on the 28th of a common february",
+			accruedThrough:  "2023-01-29",
+			leaveType:       monthly,
+			anchorDay:       29,
+			today:           "2023-02-28",
+			expectedBalance: 1,
+			expectedThrough: "2023-02-28",
+		},
+		{
+			name:            "hired on the 29th of february accrues yearly on the 28th",
+			accruedThrough:  "2020-02-29",
+			leaveType:       yearly,
+			anchorDay:       29,
+			today:           "2021-03-01",
+			expectedBalance: 1,
+			expectedThrough: "2021-02-28",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			today, err := time.Parse(dateLayout, tc.today)
+			require.NoError(t, err)
+
+			balance := &model.LeaveBalance{AccruedThrough: tc.accruedThrough}
+			accrue(balance, tc.leaveType, tc.anchorDay, today)
+
+			assert.Equal(t, tc.expectedBalance, balance.Balance)
+			assert.Equal(t, tc.expectedThrough, balance.AccruedThrough)
+		})
+	}
+}
+
+func TestWorkingDays(t *testing.T) {
+	assert.Equal(t, 5.0, workingDays("2024-07-01", "2024-07-05"))
+	assert.Equal(t, 6.0, workingDays("2024-07-05", "2024-07-12"))
+	assert.Equal(t, 0.0, workingDays("2024-07-06", "2024-07-07"))
+}
+
+func TestCreateLeaveRequest(t *testing.T) {
+	// a year and a day of service has completed twelve monthly accruals
+	hireDate := time.Now().AddDate(-1, 0, -1).Format(dateLayout)
+	employee := &model.Employee{ID: 3, HireDate: hireDate + "T00:00:00Z", Status: model.EmploymentStatusActive}
+	annual := &model.LeaveType{ID: 1, Name: "Annual", AccrualDays: 1.5, AccrualPeriod: model.AccrualPeriodMonthly}
+
+	payload := &transport.CreateLeaveRequestReq{EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-01", EndDate: "2024-07-05", Reason: "holiday"}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.CreateLeaveRequestReq
+		buildStub   func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock)
+	}{
+		{
+			name:      "error when end date is before start date",
+			payload:   &transport.CreateLeaveRequestReq{EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-05", EndDate: "2024-07-01"},
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrInvalidRange)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:      "error when leave covers a weekend only",
+			payload:   &transport.CreateLeaveRequestReq{EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-06", EndDate: "2024-07-07"},
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrNoWorkingDays)
+			},
+		},
+		{
+			name:    "error when employee not found",
+			payload: payload,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(false, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrEmployeeNotFound)
+			},
+		},
+		{
+			name:    "error when it overlaps approved leave",
+			payload: payload,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(1, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrLeaveOverlap)
+				leaveRepo.AssertNotCalled(t, "CreateLeaveRequest", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "error when leave type does not exist",
+			payload: payload,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return((*model.LeaveType)(nil), nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrLeaveTypeNotFound)
+			},
+		},
+		{
+			name:    "error when balance is not sufficient",
+			payload: payload,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return(annual, nil)
+				leaveRepo.On("GetBalance", mock.Anything, 3, 1).
+					Return(&model.LeaveBalance{EmployeeID: 3, LeaveTypeID: 1, Balance: 4, AccruedThrough: time.Now().Format(dateLayout)}, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrInsufficientBalance)
+			},
+		},
+		{
+			name:    "success with the balance accrued since the hire date",
+			payload: payload,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return(annual, nil)
+				leaveRepo.On("GetBalance", mock.Anything, 3, 1).Return((*model.LeaveBalance)(nil), nil)
+				leaveRepo.On("CreateLeaveRequest", mock.Anything, mock.MatchedBy(func(request *model.LeaveRequest) bool {
+					return request.Days == 5 && request.Reason == "holiday"
+				})).Run(func(args mock.Arguments) {
+					request := args.Get(1).(*model.LeaveRequest)
+					request.ID = 10
+					request.Status = model.LeaveStatusPending
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(10), result.ID)
+				assert.Equal(t, model.LeaveStatusPending, result.Status)
+				leaveRepo.AssertNotCalled(t, "SaveBalance", mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			leaveRepository := new(leaveRepoMock.DBMock)
+			employeeRepository := new(employeeRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(leaveRepository, employeeRepository)
+
+			u := NewUseCaseLeave(leaveRepository, employeeRepository, new(auditRepoMock.DBMock), transactor)
+			result, err := u.CreateLeaveRequest(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err, leaveRepository)
+		})
+	}
+}
+
+func TestApproveLeaveRequest(t *testing.T) {
+	managerID := 2
+	employee := &model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z", ManagerID: &managerID, Status: model.EmploymentStatusActive}
+	annual := &model.LeaveType{ID: 1, Name: "Annual", AccrualDays: 1.5, AccrualPeriod: model.AccrualPeriodMonthly}
+
+	pending := func() *model.LeaveRequest {
+		return &model.LeaveRequest{ID: 10, EmployeeID: 3, LeaveTypeID: 1, StartDate: "2024-07-01", EndDate: "2024-07-05", Days: 5, Status: model.LeaveStatusPending}
+	}
+
+	balance := func(days float64) *model.LeaveBalance {
+		return &model.LeaveBalance{EmployeeID: 3, LeaveTypeID: 1, Balance: days, AccruedThrough: time.Now().Format(dateLayout)}
+	}
+
+	manager := &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: 2, RegisteredClaims: jwt.RegisteredClaims{Subject: "7"}}
+
+	testCases := []struct {
+		name        string
+		claims      *pkg.JWTClaim
+		buildStub   func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock)
+		checkReturn func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock)
+	}{
+		{
+			name:   "error when leave request not found",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return((*model.LeaveRequest)(nil), nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrLeaveRequestNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:   "error when caller is not the manager of the employee",
+			claims: &pkg.JWTClaim{Role: constant.RoleManager, EmployeeID: 5, RegisteredClaims: jwt.RegisteredClaims{Subject: "8"}},
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(pending(), nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrNotManager)
+				leaveRepo.AssertNotCalled(t, "DecideLeaveRequest", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:   "error when leave request is already decided",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				request := pending()
+				request.Status = model.LeaveStatusRejected
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(request, nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrNotPending)
+			},
+		},
+		{
+			name:   "error when other leave was approved meanwhile",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(pending(), nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(1, nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrLeaveOverlap)
+				leaveRepo.AssertNotCalled(t, "SaveBalance", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:   "error when balance is no longer sufficient",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(pending(), nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return(annual, nil)
+				leaveRepo.On("GetBalance", mock.Anything, 3, 1).Return(balance(4.5), nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrInsufficientBalance)
+				leaveRepo.AssertNotCalled(t, "DecideLeaveRequest", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:   "error when decided by a concurrent request",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(pending(), nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return(annual, nil)
+				leaveRepo.On("GetBalance", mock.Anything, 3, 1).Return(balance(8), nil)
+				leaveRepo.On("SaveBalance", mock.Anything, mock.Anything).Return(nil)
+				leaveRepo.On("DecideLeaveRequest", mock.Anything, mock.Anything).Return(lRepo.ErrLeaveRequestDecided)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				assert.ErrorIs(t, err, lRepo.ErrLeaveRequestDecided)
+			},
+		},
+		{
+			name:   "success deducts the days from the balance",
+			claims: manager,
+			buildStub: func(leaveRepo *leaveRepoMock.DBMock, employeeRepo *employeeRepoMock.DBMock) {
+				leaveRepo.On("GetLeaveRequestByID", mock.Anything, int64(10)).Return(pending(), nil)
+				leaveRepo.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+				employeeRepo.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+				leaveRepo.On("CountApprovedOverlaps", mock.Anything, 3, "2024-07-01", "2024-07-05").Return(0, nil)
+				leaveRepo.On("GetLeaveTypeByID", mock.Anything, 1).Return(annual, nil)
+				leaveRepo.On("GetBalance", mock.Anything, 3, 1).Return(balance(8.25), nil)
+				leaveRepo.On("SaveBalance", mock.Anything, mock.MatchedBy(func(balance *model.LeaveBalance) bool {
+					return balance.Balance == 3.25
+				})).Return(nil)
+				leaveRepo.On("DecideLeaveRequest", mock.Anything, mock.MatchedBy(func(request *model.LeaveRequest) bool {
+					return request.Status == model.LeaveStatusApproved && *request.DecidedBy == "7" && *request.DecisionNote == "enjoy"
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.LeaveRequestRes, err error, leaveRepo *leaveRepoMock.DBMock) {
+				require.NoError(t, err)
+				assert.Equal(t, model.LeaveStatusApproved, result.Status)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			leaveRepository := new(leaveRepoMock.DBMock)
+			employeeRepository := new(employeeRepoMock.DBMock)
+			auditRepository := new(auditRepoMock.DBMock)
+			auditRepository.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+				return audit.EmployeeID == 3 && audit.Action == model.AuditActionApproveLeave && audit.ActorID == "7"
+			})).Return(nil)
+			transactor := new(txMock.TransactorMock)
+			transactor.On("WithinTransaction", mock.Anything).Return(nil)
+			tc.buildStub(leaveRepository, employeeRepository)
+
+			ctx := pkg.ContextWithClaims(context.TODO(), tc.claims)
+
+			u := NewUseCaseLeave(leaveRepository, employeeRepository, auditRepository, transactor)
+			result, err := u.ApproveLeaveRequest(ctx, &transport.DecideLeaveRequestReq{LeaveRequestID: 10, Note: "enjoy"})
+
+			tc.checkReturn(result, err, leaveRepository)
+			if err == nil {
+				auditRepository.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestRejectLeaveRequest(t *testing.T) {
+	employee := &model.Employee{ID: 3, HireDate: "2023-05-03T00:00:00Z"}
+
+	leaveRepository := new(leaveRepoMock.DBMock)
+	leaveRepository.On("GetLeaveRequestByID", mock.Anything, int64(10)).
+		Return(&model.LeaveRequest{ID: 10, EmployeeID: 3, LeaveTypeID: 1, Days: 5, Status: model.LeaveStatusPending}, nil)
+	leaveRepository.On("LockEmployee", mock.Anything, 3).Return(true, nil)
+	leaveRepository.On("DecideLeaveRequest", mock.Anything, mock.MatchedBy(func(request *model.LeaveRequest) bool {
+		return request.Status == model.LeaveStatusRejected && request.DecisionNote == nil
+	})).Return(nil)
+
+	employeeRepository := new(employeeRepoMock.DBMock)
+	employeeRepository.On("GetEmployeeByID", mock.Anything, 3, false).Return(employee, nil)
+
+	auditRepository := new(auditRepoMock.DBMock)
+	auditRepository.On("CreateAudit", mock.Anything, mock.MatchedBy(func(audit *model.Audit) bool {
+		return audit.EmployeeID == 3 && audit.Action == model.AuditActionRejectLeave &&
+			string(audit.Diff) == `{"decided_by":{"from":null,"to":"1"},"status":{"from":"pending","to":"rejected"}}`
+	})).Return(nil)
+
+	transactor := new(txMock.TransactorMock)
+	transactor.On("WithinTransaction", mock.Anything).Return(nil)
+
+	// an admin decides for employees without a manager
+	ctx := pkg.ContextWithClaims(context.TODO(), &pkg.JWTClaim{Role: constant.RoleAdmin, RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+
+	u := NewUseCaseLeave(leaveRepository, employeeRepository, auditRepository, transactor)
+	result, err := u.RejectLeaveRequest(ctx, &transport.DecideLeaveRequestReq{LeaveRequestID: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.LeaveStatusRejected, result.Status)
+	leaveRepository.AssertNotCalled(t, "SaveBalance", mock.Anything, mock.Anything)
+	auditRepository.AssertExpectations(t)
+}
+
+func TestGetBalances(t *testing.T) {
+	hireDate := time.Now().AddDate(0, -2, -1).Format(dateLayout)
+	throughDate := time.Now().AddDate(0, -1, -1).Format(dateLayout)
+
+	employeeRepository := new(employeeRepoMock.DBMock)
+	employeeRepository.On("GetEmployeeByID", mock.Anything, 3, false).Return(&model.Employee{ID: 3, HireDate: hireDate + "T00:00:00Z"}, nil)
+
+	leaveRepository := new(leaveRepoMock.DBMock)
+	leaveRepository.On("GetLeaveTypes", mock.Anything).Return([]*model.LeaveType{
+		{ID: 1, Name: "Annual", AccrualDays: 2, AccrualPeriod: model.AccrualPeriodMonthly},
+		{ID: 2, Name: "Sick", AccrualDays: 1, AccrualPeriod: model.AccrualPeriodMonthly},
+	}, nil)
+	leaveRepository.On("GetBalances", mock.Anything, 3).Return([]*model.LeaveBalance{
+		{EmployeeID: 3, LeaveTypeID: 1, Balance: 0.5, AccruedThrough: throughDate},
+	}, nil)
+
+	u := NewUseCaseLeave(leaveRepository, employeeRepository, new(auditRepoMock.DBMock), new(txMock.TransactorMock))
+	result, err := u.GetBalances(context.TODO(), 3)
+
+	require.NoError(t, err)
+	require.Len(t, result.Balances, 2)
+	assert.Equal(t, 2.5, result.Balances[0].Balance)
+	assert.Equal(t, "Sick", result.Balances[1].LeaveType)
+	assert.Equal(t, 2.0, result.Balances[1].Balance)
+	leaveRepository.AssertNotCalled(t, "SaveBalance", mock.Anything, mock.Anything)
+}
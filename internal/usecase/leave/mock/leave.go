@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type LeaveUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *LeaveUseCaseMock) CreateLeaveType(ctx context.Context, payload *transport.CreateLeaveTypeReq) (*transport.LeaveTypeRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.LeaveTypeRes), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) GetLeaveTypes(ctx context.Context) (*transport.ListLeaveTypes, error) {
+	args := m.Called(ctx)
+
+	return args.Get(0).(*transport.ListLeaveTypes), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) GetBalances(ctx context.Context, employeeID int) (*transport.ListLeaveBalances, error) {
+	args := m.Called(ctx, employeeID)
+
+	return args.Get(0).(*transport.ListLeaveBalances), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) CreateLeaveRequest(ctx context.Context, payload *transport.CreateLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.LeaveRequestRes), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) GetLeaveRequests(ctx context.Context, employeeID int, payload *transport.ListLeaveRequestsReq) (*transport.ListLeaveRequests, error) {
+	args := m.Called(ctx, employeeID, payload)
+
+	return args.Get(0).(*transport.ListLeaveRequests), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) ApproveLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.LeaveRequestRes), args.Error(1)
+}
+
+func (m *LeaveUseCaseMock) RejectLeaveRequest(ctx context.Context, payload *transport.DecideLeaveRequestReq) (*transport.LeaveRequestRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.LeaveRequestRes), args.Error(1)
+}
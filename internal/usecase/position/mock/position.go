@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/mock"
+)
+
+type PositionUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *PositionUseCaseMock) CreatePosition(ctx context.Context, payload *transport.CreatePositionReq) (*transport.PositionRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.PositionRes), args.Error(1)
+}
+
+func (m *PositionUseCaseMock) GetPositions(ctx context.Context, payload *transport.ListPositionsReq) (*transport.ListPositions, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.ListPositions), args.Error(1)
+}
+
+func (m *PositionUseCaseMock) GetPositionByID(ctx context.Context, positionID int) (*transport.PositionRes, error) {
+	args := m.Called(ctx, positionID)
+
+	return args.Get(0).(*transport.PositionRes), args.Error(1)
+}
+
+func (m *PositionUseCaseMock) UpdatePosition(ctx context.Context, payload *transport.UpdatePositionReq) (*transport.PositionRes, error) {
+	args := m.Called(ctx, payload)
+
+	return args.Get(0).(*transport.PositionRes), args.Error(1)
+}
+
+func (m *PositionUseCaseMock) DeletePosition(ctx context.Context, positionID int) error {
+	args := m.Called(ctx, positionID)
+
+	return args.Error(0)
+}
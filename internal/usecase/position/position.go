@@ -0,0 +1,197 @@
+package position
+
+import (
+	"context"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	"employee/internal/repository"
+	pRepo "employee/internal/repository/position"
+	"employee/internal/transport"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	logger = log.WithField("useCase", "useCase.Position")
+)
+
+const (
+	defaultPageLimit = 20
+)
+
+var (
+	ErrPositionNotFound = apperror.NotFound("position not found")
+)
+
+type UseCasePosition interface {
+	CreatePosition(ctx context.Context, payload *transport.CreatePositionReq) (*transport.PositionRes, error)
+	GetPositions(ctx context.Context, payload *transport.ListPositionsReq) (*transport.ListPositions, error)
+	GetPositionByID(ctx context.Context, positionID int) (*transport.PositionRes, error)
+	UpdatePosition(ctx context.Context, payload *transport.UpdatePositionReq) (*transport.PositionRes, error)
+	DeletePosition(ctx context.Context, positionID int) error
+}
+
+type useCasePosition struct {
+	positionRepo pRepo.PositionRepo
+	transactor   repository.Transactor
+}
+
+func NewUseCasePosition(positionRepo pRepo.PositionRepo, transactor repository.Transactor) UseCasePosition {
+	return &useCasePosition{positionRepo: positionRepo, transactor: transactor}
+}
+
+func (u *useCasePosition) CreatePosition(ctx context.Context, payload *transport.CreatePositionReq) (*transport.PositionRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "CreatePosition")
+
+	position := &model.Position{
+		Title:     payload.Title,
+		Grade:     payload.Grade,
+		JobFamily: payload.JobFamily,
+	}
+
+	if err := u.positionRepo.CreatePosition(ctx, position); err != nil {
+		uLog.Errorf("error when call positionRepo.CreatePosition got %s", err.Error())
+		return nil, err
+	}
+
+	return toPositionRes(position), nil
+}
+
+func (u *useCasePosition) GetPositions(ctx context.Context, payload *transport.ListPositionsReq) (*transport.ListPositions, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetPositions")
+
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	filter := &model.PositionFilter{Title: payload.Title, JobFamily: payload.JobFamily}
+
+	total, err := u.positionRepo.CountPositions(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call positionRepo.CountPositions got %s", err.Error())
+		return nil, err
+	}
+
+	filter.Limit = limit
+	filter.Offset = payload.Offset
+
+	positions, err := u.positionRepo.GetPositions(ctx, filter)
+	if err != nil {
+		uLog.Errorf("error when call positionRepo.GetPositions got %s", err.Error())
+		return nil, err
+	}
+
+	positionsResData := make([]*transport.PositionRes, 0)
+	for _, position := range positions {
+		positionsResData = append(positionsResData, toPositionRes(position))
+	}
+
+	return &transport.ListPositions{
+		Positions: positionsResData,
+		Pagination: &transport.Pagination{
+			Total:  total,
+			Limit:  limit,
+			Offset: payload.Offset,
+		},
+	}, nil
+}
+
+func (u *useCasePosition) GetPositionByID(ctx context.Context, positionID int) (*transport.PositionRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "GetPositionByID")
+
+	position, err := u.positionRepo.GetPositionByID(ctx, positionID)
+	if err != nil {
+		uLog.Errorf("error when call positionRepo.GetPositionByID got %s", err.Error())
+		return nil, err
+	}
+
+	if position == nil {
+		return nil, ErrPositionNotFound
+	}
+
+	return toPositionRes(position), nil
+}
+
+func (u *useCasePosition) UpdatePosition(ctx context.Context, payload *transport.UpdatePositionReq) (*transport.PositionRes, error) {
+	uLog := logger.WithContext(ctx).WithField("function", "UpdatePosition")
+
+	var res *transport.PositionRes
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		position, err := u.positionRepo.GetPositionByID(ctx, payload.ID)
+		if err != nil {
+			uLog.Errorf("error when call positionRepo.GetPositionByID got %s", err.Error())
+			return err
+		}
+
+		if position == nil {
+			uLog.Errorf("error when call positionRepo.GetPositionByID got %s", ErrPositionNotFound.Error())
+			return ErrPositionNotFound
+		}
+
+		position.Title = payload.Title
+		position.Grade = payload.Grade
+		position.JobFamily = payload.JobFamily
+
+		if err := u.positionRepo.UpdatePosition(ctx, position); err != nil {
+			uLog.Errorf("error when call positionRepo.UpdatePosition got %s", err.Error())
+			return err
+		}
+
+		res = toPositionRes(position)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeletePosition deletes a position nobody holds or held, the position
+// history keeps referencing the positions employees moved out of.
+func (u *useCasePosition) DeletePosition(ctx context.Context, positionID int) error {
+	uLog := logger.WithContext(ctx).WithField("function", "DeletePosition")
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		position, err := u.positionRepo.GetPositionByID(ctx, positionID)
+		if err != nil {
+			uLog.Errorf("error when call positionRepo.GetPositionByID got %s", err.Error())
+			return err
+		}
+
+		if position == nil {
+			uLog.Errorf("error when call positionRepo.GetPositionByID got %s", ErrPositionNotFound.Error())
+			return ErrPositionNotFound
+		}
+
+		holders, err := u.positionRepo.CountHolders(ctx, positionID)
+		if err != nil {
+			uLog.Errorf("error when call positionRepo.CountHolders got %s", err.Error())
+			return err
+		}
+
+		if holders > 0 {
+			uLog.Errorf("position %d is referenced by %d employees", positionID, holders)
+			return pRepo.ErrPositionInUse
+		}
+
+		if err := u.positionRepo.DeletePosition(ctx, positionID); err != nil {
+			uLog.Errorf("error when call positionRepo.DeletePosition got %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+func toPositionRes(position *model.Position) *transport.PositionRes {
+	return &transport.PositionRes{
+		ID:        position.ID,
+		Title:     position.Title,
+		Grade:     position.Grade,
+		JobFamily: position.JobFamily,
+		CreatedAt: position.CreatedAt,
+		UpdatedAt: position.UpdatedAt,
+	}
+}
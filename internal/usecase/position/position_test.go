@@ -0,0 +1,270 @@
+package position
+
+import (
+	"context"
+	"database/sql"
+	"employee/internal/apperror"
+	"employee/internal/model"
+	txMock "employee/internal/repository/mock"
+	pRepo "employee/internal/repository/position"
+	positionRepoMock "employee/internal/repository/position/mock"
+	"employee/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreatePosition(t *testing.T) {
+
+	payload := &transport.CreatePositionReq{Title: "Software Engineer II", Grade: 3, JobFamily: "Engineering"}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(positionRepo *positionRepoMock.DBMock)
+		checkReturn func(result *transport.PositionRes, err error)
+	}{
+		{
+			name: "error when title is taken",
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("CreatePosition", mock.Anything, mock.Anything).Return(apperror.Conflict("title", "title already exists"))
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("CreatePosition", mock.Anything, mock.MatchedBy(func(position *model.Position) bool {
+					return position.Title == "Software Engineer II" && position.Grade == 3 && position.JobFamily == "Engineering"
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Position).ID = 1
+				}).Return(nil)
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, result.ID)
+				assert.Equal(t, 3, result.Grade)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positionRepository := new(positionRepoMock.DBMock)
+			tc.buildStub(positionRepository)
+
+			u := NewUseCasePosition(positionRepository, new(txMock.TransactorMock))
+			result, err := u.CreatePosition(context.TODO(), payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetPositions(t *testing.T) {
+
+	mockPositions := []*model.Position{
+		{ID: 1, Title: "Software Engineer I", Grade: 2, JobFamily: "Engineering"},
+		{ID: 2, Title: "Software Engineer II", Grade: 3, JobFamily: "Engineering"},
+	}
+
+	testCases := []struct {
+		name        string
+		payload     *transport.ListPositionsReq
+		buildStub   func(positionRepo *positionRepoMock.DBMock)
+		checkReturn func(result *transport.ListPositions, err error)
+	}{
+		{
+			name:    "error when count positions",
+			payload: &transport.ListPositionsReq{},
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("CountPositions", mock.Anything, mock.Anything).Return(0, sql.ErrConnDone)
+			},
+			checkReturn: func(result *transport.ListPositions, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name:    "success with default limit",
+			payload: &transport.ListPositionsReq{JobFamily: "engineering"},
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("CountPositions", mock.Anything, mock.Anything).Return(2, nil)
+				positionRepo.On("GetPositions", mock.Anything, mock.MatchedBy(func(filter *model.PositionFilter) bool {
+					return filter.JobFamily == "engineering" && filter.Limit == defaultPageLimit
+				})).Return(mockPositions, nil)
+			},
+			checkReturn: func(result *transport.ListPositions, err error) {
+				assert.NoError(t, err)
+				require.Len(t, result.Positions, 2)
+				assert.Equal(t, 2, result.Pagination.Total)
+				assert.Equal(t, defaultPageLimit, result.Pagination.Limit)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positionRepository := new(positionRepoMock.DBMock)
+			tc.buildStub(positionRepository)
+
+			u := NewUseCasePosition(positionRepository, new(txMock.TransactorMock))
+			result, err := u.GetPositions(context.TODO(), tc.payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestGetPositionByID(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(positionRepo *positionRepoMock.DBMock)
+		checkReturn func(result *transport.PositionRes, err error)
+	}{
+		{
+			name: "error when position not found",
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return((*model.Position)(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.ErrorIs(t, err, ErrPositionNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(positionRepo *positionRepoMock.DBMock) {
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1, Title: "Software Engineer II"}, nil)
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Software Engineer II", result.Title)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positionRepository := new(positionRepoMock.DBMock)
+			tc.buildStub(positionRepository)
+
+			u := NewUseCasePosition(positionRepository, new(txMock.TransactorMock))
+			result, err := u.GetPositionByID(context.TODO(), 1)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestUpdatePosition(t *testing.T) {
+
+	payload := &transport.UpdatePositionReq{ID: 1, Title: "Senior Software Engineer", Grade: 4, JobFamily: "Engineering"}
+
+	testCases := []struct {
+		name        string
+		buildStub   func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(result *transport.PositionRes, err error)
+	}{
+		{
+			name: "error when position not found",
+			buildStub: func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return((*model.Position)(nil), nil)
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.ErrorIs(t, err, ErrPositionNotFound)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1, Title: "Software Engineer II", Grade: 3}, nil)
+				positionRepo.On("UpdatePosition", mock.Anything, mock.MatchedBy(func(position *model.Position) bool {
+					return position.ID == 1 && position.Title == "Senior Software Engineer" && position.Grade == 4
+				})).Return(nil)
+			},
+			checkReturn: func(result *transport.PositionRes, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, result.Grade)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positionRepository := new(positionRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(positionRepository, transactor)
+
+			u := NewUseCasePosition(positionRepository, transactor)
+			result, err := u.UpdatePosition(context.TODO(), payload)
+
+			tc.checkReturn(result, err)
+		})
+	}
+}
+
+func TestDeletePosition(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		buildStub   func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock)
+		checkReturn func(err error, positionRepo *positionRepoMock.DBMock)
+	}{
+		{
+			name: "error when position not found",
+			buildStub: func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return((*model.Position)(nil), nil)
+			},
+			checkReturn: func(err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, ErrPositionNotFound)
+			},
+		},
+		{
+			name: "error when position is still referenced",
+			buildStub: func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1}, nil)
+				positionRepo.On("CountHolders", mock.Anything, 1).Return(1, nil)
+			},
+			checkReturn: func(err error, positionRepo *positionRepoMock.DBMock) {
+				assert.ErrorIs(t, err, pRepo.ErrPositionInUse)
+				positionRepo.AssertNotCalled(t, "DeletePosition", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "success",
+			buildStub: func(positionRepo *positionRepoMock.DBMock, transactor *txMock.TransactorMock) {
+				transactor.On("WithinTransaction", mock.Anything).Return(nil)
+				positionRepo.On("GetPositionByID", mock.Anything, 1).Return(&model.Position{ID: 1}, nil)
+				positionRepo.On("CountHolders", mock.Anything, 1).Return(0, nil)
+				positionRepo.On("DeletePosition", mock.Anything, 1).Return(nil)
+			},
+			checkReturn: func(err error, positionRepo *positionRepoMock.DBMock) {
+				assert.NoError(t, err)
+				positionRepo.AssertCalled(t, "DeletePosition", mock.Anything, 1)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			positionRepository := new(positionRepoMock.DBMock)
+			transactor := new(txMock.TransactorMock)
+			tc.buildStub(positionRepository, transactor)
+
+			u := NewUseCasePosition(positionRepository, transactor)
+			err := u.DeletePosition(context.TODO(), 1)
+
+			tc.checkReturn(err, positionRepository)
+		})
+	}
+}